@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/softcane/spot-vortex-agent/internal/config"
+	"github.com/softcane/spot-vortex-agent/internal/controller"
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+	"github.com/spf13/cobra"
+)
+
+var (
+	backtestTracePath   string
+	backtestRuntimePath string
+	backtestOutput      string
+)
+
+var backtestCmd = &cobra.Command{
+	Use:   "backtest",
+	Short: "Replay a recorded trace through the controller offline",
+	Long: `Backtest replays a recorded timeline of node metrics, pods/PDBs and
+spot prices through the real controller reconcile path, using fake
+Kubernetes clients and recorded prices. No cluster or cloud API is touched.
+
+The trace is a JSON timeline, or the output of the trace recorder
+(recorder.dir): one spotvortex-trace-*.jsonl.gz file or the whole directory.
+Recorder traces replay the runtime config each tick ran with unless
+--runtime-config is given.
+
+Use it to validate a runtime policy change before rolling it out.
+
+Example:
+  agent backtest --trace traces/week.json
+  agent backtest --trace /var/lib/spotvortex/traces
+  agent backtest --trace traces/week.json --runtime-config candidate.json --output json`,
+	RunE: runBacktest,
+}
+
+func init() {
+	rootCmd.AddCommand(backtestCmd)
+
+	backtestCmd.Flags().StringVar(&backtestTracePath, "trace", "",
+		"Path to the recorded trace (JSON, recorder file or recorder directory)")
+	backtestCmd.Flags().StringVar(&backtestRuntimePath, "runtime-config", "",
+		"Runtime policy config to evaluate (default: the recorded config, else config/runtime.json)")
+	backtestCmd.Flags().StringVar(&backtestOutput, "output", "table",
+		"Output format: table, json")
+	_ = backtestCmd.MarkFlagRequired("trace")
+}
+
+func runBacktest(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if cfgFile == "" {
+		cfgFile = "config/default.yaml"
+	}
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	trace, err := controller.LoadBacktestTrace(backtestTracePath)
+	if err != nil {
+		return err
+	}
+
+	// A nil runtime config replays the config recorded with each tick.
+	var runtimeCfg *config.RuntimeConfig
+	runtimePath := backtestRuntimePath
+	if runtimePath == "" && !trace.HasRuntimeConfig() {
+		runtimePath = "config/runtime.json"
+	}
+	if runtimePath != "" {
+		runtimeCfg, err = config.LoadRuntimeConfig(runtimePath)
+		if err != nil {
+			return fmt.Errorf("invalid runtime config %s: %w", runtimePath, err)
+		}
+	}
+
+	// Controller logs go to stderr so the report on stdout stays parseable.
+	level := slog.LevelWarn
+	if verbose {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+
+	infEngine, err := inference.NewInferenceEngine(inference.EngineConfig{
+		TFTModelPath:         cfg.Inference.TFTModelPath,
+		RLModelPath:          cfg.Inference.RLModelPath,
+		ModelManifestPath:    cfg.Inference.ModelManifestPath,
+		ExpectedCloud:        cfg.Inference.ExpectedCloud,
+		RequireModelContract: true,
+		Logger:               logger,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize inference engine: %w", err)
+	}
+	defer infEngine.Close()
+
+	report, err := controller.RunBacktest(ctx, controller.BacktestConfig{
+		Trace:               trace,
+		RuntimeConfig:       runtimeCfg,
+		Inference:           infEngine,
+		Karpenter:           cfg.Karpenter,
+		Logger:              logger,
+		RiskThreshold:       cfg.Controller.RiskThreshold,
+		MaxDrainRatio:       cfg.Controller.MaxDrainRatio,
+		ConfidenceThreshold: cfg.Controller.ConfidenceThreshold,
+	})
+	if err != nil {
+		return fmt.Errorf("backtest failed: %w", err)
+	}
+
+	switch backtestOutput {
+	case "json":
+		return outputBacktestJSON(os.Stdout, report)
+	default:
+		return outputBacktestTable(os.Stdout, report)
+	}
+}
+
+func outputBacktestJSON(w io.Writer, report *controller.BacktestReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+func outputBacktestTable(w io.Writer, report *controller.BacktestReport) error {
+	fmt.Fprintf(w, "%-5s %-20s %-30s %-28s %-16s %-8s %-8s\n",
+		"TICK", "TIME", "NODE", "POOL", "ACTION", "CAP", "RUNTIME")
+	fmt.Fprintln(w, strings.Repeat("-", 120))
+
+	for _, tick := range report.Ticks {
+		ts := tick.Timestamp.UTC().Format("2006-01-02T15:04:05")
+		for _, d := range tick.Decisions {
+			fmt.Fprintf(w, "%-5d %-20s %-30s %-28s %-16s %-8.3f %-8.3f\n",
+				tick.Index, ts, d.NodeID, d.Pool, d.Action, d.CapacityScore, d.RuntimeScore)
+		}
+		for _, node := range tick.Migrations {
+			fmt.Fprintf(w, "%-5d %-20s %-30s migrated\n", tick.Index, ts, node)
+		}
+		for _, node := range tick.Outages {
+			fmt.Fprintf(w, "%-5d %-20s %-30s OUTAGE (interrupted before migration)\n", tick.Index, ts, node)
+		}
+	}
+
+	s := report.Summary
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "SUMMARY")
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+	fmt.Fprintf(w, "%-24s %d (%.1fh)\n", "ticks", s.Ticks, s.DurationHours)
+	fmt.Fprintf(w, "%-24s %.1f%%\n", "spot residency", s.SpotResidency*100)
+	fmt.Fprintf(w, "%-24s %d\n", "migrations", s.Migrations)
+	fmt.Fprintf(w, "%-24s %d\n", "interruptions", s.Interruptions)
+	fmt.Fprintf(w, "%-24s %d\n", "interruptions avoided", s.InterruptionsAvoided)
+	fmt.Fprintf(w, "%-24s %d\n", "simulated outages", s.SimulatedOutages)
+	fmt.Fprintf(w, "%-24s $%.2f\n", "on-demand cost", s.OnDemandCostUSD)
+	fmt.Fprintf(w, "%-24s $%.2f\n", "policy cost", s.CostUSD)
+	fmt.Fprintf(w, "%-24s $%.2f (%.1f%%)\n", "savings", s.SavingsUSD, s.SavingsPercent)
+
+	actions := make([]string, 0, len(s.ActionCounts))
+	for action := range s.ActionCounts {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	for _, action := range actions {
+		fmt.Fprintf(w, "%-24s %d\n", "action "+action, s.ActionCounts[action])
+	}
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"log/slog"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/softcane/spot-vortex-agent/internal/cloudapi"
 	"github.com/softcane/spot-vortex-agent/internal/config"
@@ -60,6 +62,14 @@ func resolveRuntimePriceProvider(ctx context.Context, cfg *config.Config, logger
 		return runtimePriceProvider{provider: provider, isFake: true}, nil
 	}
 
+	if cfg != nil && strings.TrimSpace(cfg.Azure.Region) != "" {
+		provider, err := cloudapi.NewAzurePriceProvider(cfg.Azure.Region, logger)
+		if err != nil {
+			return runtimePriceProvider{}, fmt.Errorf("initialize Azure price provider: %w", err)
+		}
+		return runtimePriceProvider{provider: provider, isFake: false}, nil
+	}
+
 	priceProvider, _, err := cloudapi.NewAutoDetectedPriceProvider(ctx, logger)
 	if err != nil {
 		logger.Warn("failed to auto-detect cloud provider, attempting AWS fallback", "error", err)
@@ -72,6 +82,74 @@ func resolveRuntimePriceProvider(ctx context.Context, cfg *config.Config, logger
 	return runtimePriceProvider{provider: priceProvider, isFake: false}, nil
 }
 
+// priceCanaryRetryInterval spaces out canary retries from readiness probes
+// while the provider is failing.
+const priceCanaryRetryInterval = time.Minute
+
+// priceCanary is a single spot price query that proves the provider's
+// credentials and permissions work. It runs at startup and backs the
+// price_provider readiness check: once it passes it stays passed, and while
+// failing it is retried at most once per priceCanaryRetryInterval.
+type priceCanary struct {
+	provider     cloudapi.PriceProvider
+	instanceType string
+	zone         string
+
+	mu          sync.Mutex
+	passed      bool
+	lastErr     error
+	lastAttempt time.Time
+}
+
+func newPriceCanary(provider cloudapi.PriceProvider, instanceType, zone string) *priceCanary {
+	return &priceCanary{provider: provider, instanceType: instanceType, zone: zone}
+}
+
+// Run queries the provider once and records the result.
+func (p *priceCanary) Run(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.runLocked(ctx)
+}
+
+func (p *priceCanary) runLocked(ctx context.Context) error {
+	p.lastAttempt = time.Now()
+	if _, err := p.provider.GetSpotPrice(ctx, p.instanceType, p.zone); err != nil {
+		p.lastErr = fmt.Errorf("spot price canary for %s in %s failed: %w", p.instanceType, p.zone, err)
+		return p.lastErr
+	}
+	p.passed = true
+	p.lastErr = nil
+	return nil
+}
+
+// Check is the readiness check.
+func (p *priceCanary) Check(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.passed {
+		return nil
+	}
+	if p.lastAttempt.IsZero() || time.Since(p.lastAttempt) >= priceCanaryRetryInterval {
+		return p.runLocked(ctx)
+	}
+	return p.lastErr
+}
+
+// priceCanaryTarget picks the instance type and zone the canary queries.
+// Azure uses a common general-purpose size in the region's first zone; AWS
+// uses the first configured AZ or region + "a".
+func priceCanaryTarget(cfg *config.Config) (instanceType, zone string) {
+	if cfg != nil && strings.TrimSpace(cfg.Azure.Region) != "" {
+		return "Standard_D2s_v5", cfg.Azure.Region + "-1"
+	}
+	zone = awsRegionFromConfig(cfg) + "a"
+	if cfg != nil && len(cfg.AWS.AvailabilityZones) > 0 {
+		zone = cfg.AWS.AvailabilityZones[0]
+	}
+	return "m5.large", zone
+}
+
 func awsRegionFromConfig(cfg *config.Config) string {
 	if cfg != nil && strings.TrimSpace(cfg.AWS.Region) != "" {
 		return cfg.AWS.Region
@@ -2,11 +2,13 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"os"
 	"strings"
 	"testing"
 
+	"github.com/softcane/spot-vortex-agent/internal/cloudapi"
 	"github.com/softcane/spot-vortex-agent/internal/config"
 )
 
@@ -91,3 +93,53 @@ func TestResolveRuntimePriceProvider_RejectsDualFakeSources(t *testing.T) {
 		t.Fatalf("expected dual-source validation error, got: %v", err)
 	}
 }
+
+type flakyPriceProvider struct {
+	cloudapi.PriceProvider
+	err   error
+	calls int
+}
+
+func (p *flakyPriceProvider) GetSpotPrice(context.Context, string, string) (cloudapi.SpotPriceData, error) {
+	p.calls++
+	return cloudapi.SpotPriceData{CurrentPrice: 0.2, OnDemandPrice: 1.0}, p.err
+}
+
+func TestPriceCanary_RetriesUntilPassed(t *testing.T) {
+	provider := &flakyPriceProvider{err: errors.New("AccessDenied")}
+	canary := newPriceCanary(provider, "m5.large", "us-east-1a")
+
+	if err := canary.Run(context.Background()); err == nil {
+		t.Fatal("expected startup canary failure")
+	}
+	if err := canary.Check(context.Background()); err == nil || provider.calls != 1 {
+		t.Fatalf("failing canary should report the cached error without retrying: err=%v calls=%d", err, provider.calls)
+	}
+
+	provider.err = nil
+	canary.lastAttempt = canary.lastAttempt.Add(-priceCanaryRetryInterval)
+	if err := canary.Check(context.Background()); err != nil {
+		t.Fatalf("retry after interval should pass: %v", err)
+	}
+	provider.err = errors.New("throttled")
+	if err := canary.Check(context.Background()); err != nil || provider.calls != 2 {
+		t.Fatalf("passed canary should stay passed: err=%v calls=%d", err, provider.calls)
+	}
+}
+
+func TestPriceCanaryTarget(t *testing.T) {
+	cfg := &config.Config{AWS: config.AWSConfig{Region: "eu-west-1"}}
+	if typ, zone := priceCanaryTarget(cfg); typ != "m5.large" || zone != "eu-west-1a" {
+		t.Fatalf("aws target = %s %s", typ, zone)
+	}
+
+	cfg.AWS.AvailabilityZones = []string{"eu-west-1b"}
+	if _, zone := priceCanaryTarget(cfg); zone != "eu-west-1b" {
+		t.Fatalf("aws configured zone = %s", zone)
+	}
+
+	cfg.Azure.Region = "westeurope"
+	if typ, zone := priceCanaryTarget(cfg); typ != "Standard_D2s_v5" || zone != "westeurope-1" {
+		t.Fatalf("azure target = %s %s", typ, zone)
+	}
+}
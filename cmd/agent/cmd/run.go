@@ -9,16 +9,23 @@ import (
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/softcane/spot-vortex-agent/internal/capacity"
 	"github.com/softcane/spot-vortex-agent/internal/cloudapi"
+	"github.com/softcane/spot-vortex-agent/internal/collector"
 	"github.com/softcane/spot-vortex-agent/internal/config"
 	"github.com/softcane/spot-vortex-agent/internal/controller"
 	"github.com/softcane/spot-vortex-agent/internal/inference"
+	"github.com/softcane/spot-vortex-agent/internal/interruption"
 	"github.com/softcane/spot-vortex-agent/internal/karpenter"
+	"github.com/softcane/spot-vortex-agent/internal/kubecache"
+	"github.com/softcane/spot-vortex-agent/internal/leader"
 	"github.com/softcane/spot-vortex-agent/internal/metrics"
+	"github.com/softcane/spot-vortex-agent/internal/spotpolicy"
+	"github.com/softcane/spot-vortex-agent/internal/state"
 	"github.com/spf13/cobra"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -89,13 +96,16 @@ func runAgent(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
 
-	// 2.5. Initialize Dynamic Client for Karpenter integration
+	// 2.5. Initialize Dynamic Client for Karpenter integration and policy CRDs
 	var dynamicClient dynamic.Interface
-	if cfg.Karpenter.Enabled {
+	if cfg.Karpenter.Enabled || cfg.Policy.CRDEnabled {
 		dynamicClient, err = dynamic.NewForConfig(k8sConfig)
 		if err != nil {
-			slog.Warn("failed to create dynamic client for Karpenter integration", "error", err)
-		} else {
+			slog.Warn("failed to create dynamic client", "error", err)
+		}
+	}
+	if cfg.Karpenter.Enabled {
+		if dynamicClient != nil {
 			slog.Info("Karpenter integration enabled")
 		}
 
@@ -127,6 +137,47 @@ func runAgent(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to initialize inference engine: %w", err)
 	}
 	defer infEngine.Close()
+	metrics.SetActiveBundleVersion(infEngine.Version())
+
+	// 4.05. Hot-swapped bundles from a watched directory.
+	var bundles controller.BundleRollout
+	var bundleManager *inference.BundleManager
+	if rollout := cfg.Inference.BundleRollout; rollout.Dir != "" {
+		bundleManager, err = inference.NewBundleManager(inference.BundleManagerConfig{
+			Engine:               infEngine,
+			Dir:                  rollout.Dir,
+			PollInterval:         time.Duration(rollout.PollIntervalSeconds) * time.Second,
+			ShadowTicks:          rollout.ShadowTicks,
+			ProbationTicks:       rollout.ProbationTicks,
+			MaxDisagreementRatio: rollout.MaxDisagreementRatio,
+			MaxErrorRatio:        rollout.MaxErrorRatio,
+			ExpectedCloud:        cfg.Inference.ExpectedCloud,
+			Logger:               slog.Default().With("component", "bundle_rollout"),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to initialize bundle rollout: %w", err)
+		}
+		bundles = bundleManager
+		go bundleManager.Run(ctx)
+	}
+
+	// 4.1. Candidate bundles for shadow evaluators.
+	shadowBundles := make(map[string]controller.ShadowPredictor, len(cfg.Inference.ShadowBundles))
+	for _, b := range cfg.Inference.ShadowBundles {
+		engine, err := inference.NewInferenceEngine(inference.EngineConfig{
+			TFTModelPath:         b.TFTModelPath,
+			RLModelPath:          b.RLModelPath,
+			ModelManifestPath:    b.ModelManifestPath,
+			ExpectedCloud:        cfg.Inference.ExpectedCloud,
+			RequireModelContract: true,
+			Logger:               slog.Default().With("shadow_bundle", b.Name),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to load shadow bundle %q: %w", b.Name, err)
+		}
+		defer engine.Close()
+		shadowBundles[b.Name] = engine
+	}
 
 	// 5. Initialize Price Provider (required for inference market telemetry).
 	priceProviderSelection, err := resolveRuntimePriceProvider(ctx, cfg, slog.Default(), IsDryRun())
@@ -135,15 +186,15 @@ func runAgent(cmd *cobra.Command, args []string) error {
 	}
 	priceProvider := priceProviderSelection.provider
 
-	// 5.5. IAM canary for real providers only.
+	// 5.5. IAM canary for real providers only. It also backs the
+	// price_provider readiness check.
+	var canary *priceCanary
 	if !priceProviderSelection.isFake {
 		// Use first configured AZ or fall back to region + "a". Real AZs are configured
-		// in values.yaml under aws.availabilityZones.
-		canaryAZ := cfg.AWS.Region + "a"
-		if len(cfg.AWS.AvailabilityZones) > 0 {
-			canaryAZ = cfg.AWS.AvailabilityZones[0]
-		}
-		if _, err := priceProvider.GetSpotPrice(ctx, "m5.large", canaryAZ); err != nil {
+		// in values.yaml under aws.availabilityZones; Azure uses azure.region.
+		canaryType, canaryAZ := priceCanaryTarget(cfg)
+		canary = newPriceCanary(priceProvider, canaryType, canaryAZ)
+		if err := canary.Run(ctx); err != nil {
 			slog.Warn("IAM canary failed: spot price query returned an error; "+
 				"verify IAM permissions per docs/IAM_PERMISSIONS.md",
 				"error", err,
@@ -178,6 +229,186 @@ func runAgent(cmd *cobra.Command, args []string) error {
 		slog.Info("ASG client initialized", "region", cfg.AWS.Region)
 	}
 
+	// 5.7.1. EKS twin managed node groups when the cluster name is configured.
+	var eksClient capacity.EKSClient
+	if cfg.Autoscaling.Enabled && cfg.AWS.EKSConfigured() {
+		realEKS, eksErr := capacity.NewAWSEKSClient(ctx, capacity.AWSEKSClientConfig{
+			Region:       cfg.AWS.Region,
+			ClusterName:  cfg.AWS.ClusterName,
+			PoolLabelKey: cfg.Autoscaling.DiscoveryTags.Pool,
+		})
+		if eksErr != nil {
+			return fmt.Errorf("failed to initialize EKS node group client: %w", eksErr)
+		}
+		eksClient = realEKS
+		slog.Info("EKS node group client initialized",
+			"region", cfg.AWS.Region,
+			"cluster", cfg.AWS.ClusterName,
+		)
+	}
+
+	// 5.7.2. AKS twin Spot/Regular node pools when the cluster is configured.
+	var vmssClient capacity.VMSSClient
+	if cfg.Autoscaling.Enabled && cfg.Azure.AKSConfigured() {
+		realVMSS, vmssErr := capacity.NewAzureVMSSClient(capacity.AzureVMSSClientConfig{
+			SubscriptionID: cfg.Azure.SubscriptionID,
+			ResourceGroup:  cfg.Azure.ResourceGroup,
+			ClusterName:    cfg.Azure.ClusterName,
+			PoolTagKey:     cfg.Autoscaling.DiscoveryTags.Pool,
+			Logger:         slog.Default(),
+		})
+		if vmssErr != nil {
+			return fmt.Errorf("failed to initialize AKS node pool client: %w", vmssErr)
+		}
+		vmssClient = realVMSS
+		slog.Info("AKS node pool client initialized",
+			"resource_group", cfg.Azure.ResourceGroup,
+			"cluster", cfg.Azure.ClusterName,
+		)
+	}
+
+	// 5.8. Optional reconcile trace recorder for offline replay/incident analysis
+	var recorder *controller.TraceRecorder
+	if cfg.Recorder.Enabled {
+		recorder, err = controller.NewTraceRecorder(controller.TraceRecorderConfig{
+			Dir:           cfg.Recorder.Dir,
+			MaxFileBytes:  cfg.Recorder.MaxFileBytes(),
+			MaxTotalBytes: cfg.Recorder.MaxTotalBytes(),
+			Logger:        slog.Default(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to initialize trace recorder: %w", err)
+		}
+		defer func() {
+			if err := recorder.Close(); err != nil {
+				slog.Warn("failed to close trace recorder", "error", err)
+			}
+		}()
+		slog.Info("trace recorder enabled", "dir", cfg.Recorder.Dir)
+	}
+
+	// 5.9. Runtime config from SpotVortexPolicy resources (falls back to config/runtime.json)
+	var runtimeSource controller.RuntimeConfigSource
+	var recoveryBudgets collector.RecoveryBudgetSource
+	if cfg.Policy.CRDEnabled && dynamicClient != nil {
+		policySource, err := spotpolicy.NewSource(spotpolicy.SourceConfig{
+			DynamicClient: dynamicClient,
+			PolicyName:    cfg.Policy.Name,
+			Logger:        slog.Default(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to initialize policy source: %w", err)
+		}
+		if err := policySource.Start(ctx); err != nil {
+			slog.Warn("SpotVortexPolicy watch unavailable; using runtime config file", "error", err)
+		} else {
+			runtimeSource = policySource
+			recoveryBudgets = policySource
+		}
+	}
+
+	// 5.10. Shared informer cache for nodes, pods, PDBs and workload owners.
+	// A slow initial sync is not fatal: reads fall back to the API server
+	// until the caches catch up, and /readyz reports them as not synced.
+	kubeCache, err := kubecache.New(k8sClient, kubecache.Config{
+		ResyncPeriod: cfg.Informers.ResyncPeriod(),
+		Logger:       slog.Default(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize informer cache: %w", err)
+	}
+
+	// 5.11. Spot interruption sources (handled immediately, outside the tick)
+	var interruptionSources []interruption.Source
+	var interruptionHTTP *interruption.HTTPSource
+	if cfg.Interruption.Enabled {
+		if cfg.Interruption.SQSQueueURL != "" {
+			sqsClient, err := interruption.NewAWSSQSClient(ctx, cfg.AWS.Region)
+			if err != nil {
+				return fmt.Errorf("failed to initialize interruption queue client: %w", err)
+			}
+			src, err := interruption.NewSQSSource(interruption.SQSSourceConfig{
+				Client:   sqsClient,
+				QueueURL: cfg.Interruption.SQSQueueURL,
+				Logger:   slog.Default(),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to initialize interruption queue source: %w", err)
+			}
+			interruptionSources = append(interruptionSources, src)
+		}
+		if cfg.Interruption.EventFile != "" {
+			src, err := interruption.NewFileSource(interruption.FileSourceConfig{
+				Path:   cfg.Interruption.EventFile,
+				Logger: slog.Default(),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to initialize interruption file source: %w", err)
+			}
+			interruptionSources = append(interruptionSources, src)
+		}
+		if cfg.Interruption.HTTPEnabled {
+			interruptionHTTP = interruption.NewHTTPSource()
+			interruptionSources = append(interruptionSources, interruptionHTTP)
+		}
+		if cfg.Interruption.NodeMarkers {
+			src, err := interruption.NewNodeSource(interruption.NodeSourceConfig{
+				K8sClient:    k8sClient,
+				Cache:        kubeCache,
+				PollInterval: cfg.Interruption.NodePollInterval(),
+				Logger:       slog.Default(),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to initialize interruption node source: %w", err)
+			}
+			interruptionSources = append(interruptionSources, src)
+		}
+		names := make([]string, 0, len(interruptionSources))
+		for _, src := range interruptionSources {
+			names = append(names, src.Name())
+		}
+		slog.Info("interruption handling enabled", "sources", names)
+	}
+
+	// 5.12. State checkpoint store (restored in controller.New)
+	var stateStore controller.StateStore
+	stateNamespace := cfg.State.Namespace
+	if stateNamespace == "" {
+		stateNamespace = leader.PodNamespace()
+	}
+	store, err := state.New(state.Config{
+		Backend:   cfg.State.Backend,
+		K8sClient: k8sClient,
+		Namespace: stateNamespace,
+		Name:      cfg.State.Name,
+		Path:      cfg.State.Path,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize state store: %w", err)
+	}
+	if store != nil {
+		stateStore = store
+		slog.Info("controller state checkpointing enabled", "backend", store.Name())
+	}
+
+	// 5.13. Leader election: only the leader actuates; followers keep warm caches
+	var elector *leader.Elector
+	if cfg.LeaderElection.Enabled {
+		elector, err = leader.New(leader.Config{
+			K8sClient:     k8sClient,
+			Namespace:     cfg.LeaderElection.Namespace,
+			LeaseName:     cfg.LeaderElection.LeaseName,
+			Identity:      os.Getenv("POD_NAME"),
+			LeaseDuration: time.Duration(cfg.LeaderElection.LeaseDurationSeconds) * time.Second,
+			RenewDeadline: time.Duration(cfg.LeaderElection.RenewDeadlineSeconds) * time.Second,
+			RetryPeriod:   time.Duration(cfg.LeaderElection.RetryPeriodSeconds) * time.Second,
+			Logger:        slog.Default(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to initialize leader election: %w", err)
+		}
+	}
+
 	// 6. Initialize Controller
 	ctrl, err := controller.New(controller.Config{
 		Cloud:                         cloudWrapper,
@@ -194,24 +425,93 @@ func runAgent(cmd *cobra.Command, args []string) error {
 		Karpenter:                     cfg.Karpenter,
 		Autoscaling:                   cfg.Autoscaling,
 		ASGClient:                     asgClient,
-		ReliabilityTelemetryCollector: controller.NewKubernetesReliabilityTelemetryCollector(k8sClient, slog.Default()),
+		VMSSClient:                    vmssClient,
+		EKSClient:                     eksClient,
+		ReliabilityTelemetryCollector: controller.NewKubernetesReliabilityTelemetryCollector(k8sClient, slog.Default()).WithCache(kubeCache),
+		Recorder:                      recorder,
+		RuntimeSource:                 runtimeSource,
+		RecoveryBudgets:               recoveryBudgets,
+		ShadowBundles:                 shadowBundles,
+		Bundles:                       bundles,
+		InterruptionSources:           interruptionSources,
+		InterruptionRiskHalfLife:      cfg.Interruption.RiskHalfLife(),
+		LeaderElection:                elector != nil,
+		StateStore:                    stateStore,
+		ReadyReconcileIntervals:       cfg.Server.ReadyReconcileIntervals,
+		KubeCache:                     kubeCache,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create controller: %w", err)
 	}
 
+	// Start informers after the controller registered its handlers so the
+	// collector sees the initial adds.
+	kubeCache.Start(ctx)
+	syncCtx, cancelSync := context.WithTimeout(ctx, cfg.Informers.SyncTimeout())
+	if err := kubeCache.WaitForSync(syncCtx); err != nil {
+		slog.Warn("informer caches not synced, reading from the API server until they are", "error", err)
+	}
+	cancelSync()
+
 	slog.Info("agent ready, starting reconciliation loop...")
 
+	// 6.5. Readiness checks for the dependencies a tick needs
+	ctrl.AddReadinessCheck("model_contract", func(context.Context) error {
+		return infEngine.Ready()
+	})
+	if !useSyntheticMetrics {
+		ctrl.AddReadinessCheck("prometheus", promClient.Ping)
+	}
+	if canary != nil {
+		ctrl.AddReadinessCheck("price_provider", canary.Check)
+	}
+	ctrl.AddReadinessCheck("informer_cache", kubeCache.Ready)
+
 	// 7. Start Metrics Server (Non-blocking)
 	go func() {
 		mux := http.NewServeMux()
 		mux.Handle("/metrics", promhttp.Handler())
-		slog.Info("starting metrics server", "port", 8080)
-		if err := http.ListenAndServe(":8080", mux); err != nil {
+		mux.Handle("/healthz", ctrl.LivenessHandler())
+		mux.Handle("/readyz", ctrl.ReadinessHandler())
+		mux.Handle("/debug/state", ctrl.StateHandler())
+		mux.Handle("/debug/decisions", ctrl.DecisionExplanations())
+		mux.Handle("/debug/shadows", ctrl.ShadowReportHandler())
+		if bundleManager != nil {
+			mux.Handle("/debug/bundles", bundleManager.Handler())
+		}
+		addr := cfg.Server.Address()
+		slog.Info("starting metrics server", "address", addr)
+		if err := http.ListenAndServe(addr, mux); err != nil {
 			slog.Error("metrics server failed", "error", err)
 		}
 	}()
 
+	// 7.1. Interruption stand-in endpoint. It drains nodes without
+	// authentication, so it gets its own loopback-only listener.
+	if interruptionHTTP != nil {
+		go func() {
+			mux := http.NewServeMux()
+			mux.Handle("/debug/interruptions", interruptionHTTP)
+			addr := cfg.Interruption.HTTPListenAddress()
+			slog.Info("starting interruption event listener", "address", addr)
+			if err := http.ListenAndServe(addr, mux); err != nil {
+				slog.Error("interruption event listener failed", "error", err)
+			}
+		}()
+	}
+
+	// 7.5. Contend for leadership; the controller stays a follower until elected
+	if elector != nil {
+		go func() {
+			if err := elector.Run(ctx, leader.Callbacks{
+				OnStartedLeading: ctrl.StartLeading,
+				OnStoppedLeading: ctrl.StopLeading,
+			}); err != nil {
+				slog.Error("leader election failed", "error", err)
+			}
+		}()
+	}
+
 	// 8. Start the Controller
 	if err := ctrl.Start(ctx); err != nil {
 		return fmt.Errorf("controller failure: %w", err)
@@ -2,10 +2,15 @@ package capacity
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 )
 
+// ErrASGNotFound is returned (wrapped) when no ASG of the requested layout
+// is tagged for a workload pool, as opposed to an Auto Scaling API failure.
+var ErrASGNotFound = errors.New("ASG not found")
+
 // ASGInfo describes an Auto Scaling Group discovered for SpotVortex management.
 type ASGInfo struct {
 	// ASGID is the ASG name or ARN.
@@ -27,6 +32,19 @@ type ASGInfo struct {
 	MaxSize int32
 }
 
+// MixedASGInfo describes a single ASG that runs both spot and on-demand
+// instances through a MixedInstancesPolicy.
+type MixedASGInfo struct {
+	ASGInfo
+
+	// OnDemandBaseCapacity is the number of instances always launched on demand.
+	OnDemandBaseCapacity int32
+
+	// OnDemandPercentageAboveBaseCapacity is the share (0-100) of instances
+	// above the base that are launched on demand; the rest are spot.
+	OnDemandPercentageAboveBaseCapacity int32
+}
+
 // ASGClient abstracts AWS Auto Scaling Group operations.
 // This interface enables testing with a fake client in Kind clusters.
 type ASGClient interface {
@@ -42,18 +60,42 @@ type ASGClient interface {
 
 	// GetInstanceASG returns the ASG ID for a given EC2 instance ID.
 	GetInstanceASG(ctx context.Context, instanceID string) (string, error)
+
+	// DiscoverMixedASG finds the single ASG of a workload pool that mixes spot
+	// and on-demand instances with a MixedInstancesPolicy.
+	// Discovery uses the spotvortex.io/pool=<pool> tag.
+	DiscoverMixedASG(ctx context.Context, pool string) (*MixedASGInfo, error)
+
+	// SetOnDemandDistribution updates a mixed ASG's on-demand base capacity and
+	// on-demand percentage above base. Only new launches follow the change.
+	SetOnDemandDistribution(ctx context.Context, asgID string, base, percentAboveBase int32) error
+
+	// StartInstanceRefresh starts a rolling replacement of an ASG's instances,
+	// keeping minHealthyPercent of capacity in service, and returns its ID.
+	StartInstanceRefresh(ctx context.Context, asgID string, minHealthyPercent int32) (string, error)
+
+	// InstanceRefreshInProgress reports whether an instance refresh of the ASG
+	// has not finished yet.
+	InstanceRefreshInProgress(ctx context.Context, asgID string) (bool, error)
 }
 
 // FakeASGClient implements ASGClient for testing in Kind clusters.
 // It simulates Twin ASG discovery and scaling operations in memory.
 type FakeASGClient struct {
-	mu   sync.Mutex
-	asgs map[string]*ASGInfo // asgID -> info
+	mu    sync.Mutex
+	asgs  map[string]*ASGInfo      // asgID -> info
+	mixed map[string]*MixedASGInfo // asgID -> mixed-instances info
 
 	// ScaleUpCalls tracks calls to SetDesiredCapacity for assertions.
 	ScaleUpCalls []fakeScaleCall
 	// TerminateCalls tracks calls to TerminateInstance for assertions.
 	TerminateCalls []fakeTerminateCall
+	// DistributionCalls tracks calls to SetOnDemandDistribution for assertions.
+	DistributionCalls []fakeDistributionCall
+	// RefreshCalls tracks the ASGs StartInstanceRefresh was called for.
+	RefreshCalls []string
+
+	refreshing map[string]bool // asgID -> refresh not yet finished
 }
 
 type fakeScaleCall struct {
@@ -67,10 +109,18 @@ type fakeTerminateCall struct {
 	Decrement  bool
 }
 
+type fakeDistributionCall struct {
+	ASGID            string
+	Base             int32
+	PercentAboveBase int32
+}
+
 // NewFakeASGClient creates a fake ASG client pre-populated with twin ASG pairs.
 func NewFakeASGClient() *FakeASGClient {
 	return &FakeASGClient{
-		asgs: make(map[string]*ASGInfo),
+		asgs:       make(map[string]*ASGInfo),
+		mixed:      make(map[string]*MixedASGInfo),
+		refreshing: make(map[string]bool),
 	}
 }
 
@@ -111,7 +161,7 @@ func (f *FakeASGClient) DiscoverTwinASGs(ctx context.Context, pool string) (*ASG
 	od, odOK := f.asgs[odID]
 
 	if !spotOK || !odOK {
-		return nil, nil, fmt.Errorf("twin ASG pair not found for pool %q", pool)
+		return nil, nil, fmt.Errorf("twin ASG pair not found for pool %q: %w", pool, ErrASGNotFound)
 	}
 
 	// Return copies to avoid data races
@@ -175,6 +225,92 @@ func (f *FakeASGClient) GetInstanceASG(ctx context.Context, instanceID string) (
 	return "", fmt.Errorf("fake client: use node labels for ASG discovery")
 }
 
+// AddMixedASG registers a mixed-instances ASG for a workload pool.
+func (f *FakeASGClient) AddMixedASG(pool string, desired, odBase, odPercentAboveBase int32) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := pool + "-mixed-asg"
+	f.asgs[id] = &ASGInfo{
+		ASGID:           id,
+		Pool:            pool,
+		DesiredCapacity: desired,
+		CurrentCount:    desired,
+		MaxSize:         desired + 5,
+	}
+	f.mixed[id] = &MixedASGInfo{
+		OnDemandBaseCapacity:                odBase,
+		OnDemandPercentageAboveBaseCapacity: odPercentAboveBase,
+	}
+}
+
+func (f *FakeASGClient) DiscoverMixedASG(ctx context.Context, pool string) (*MixedASGInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := pool + "-mixed-asg"
+	mixed, ok := f.mixed[id]
+	if !ok {
+		return nil, fmt.Errorf("mixed-instances ASG not found for pool %q: %w", pool, ErrASGNotFound)
+	}
+
+	info := *mixed
+	info.ASGInfo = *f.asgs[id]
+	return &info, nil
+}
+
+func (f *FakeASGClient) SetOnDemandDistribution(ctx context.Context, asgID string, base, percentAboveBase int32) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	mixed, ok := f.mixed[asgID]
+	if !ok {
+		return fmt.Errorf("mixed-instances ASG %q not found", asgID)
+	}
+	mixed.OnDemandBaseCapacity = base
+	mixed.OnDemandPercentageAboveBaseCapacity = percentAboveBase
+
+	f.DistributionCalls = append(f.DistributionCalls, fakeDistributionCall{
+		ASGID:            asgID,
+		Base:             base,
+		PercentAboveBase: percentAboveBase,
+	})
+
+	return nil
+}
+
+func (f *FakeASGClient) StartInstanceRefresh(ctx context.Context, asgID string, minHealthyPercent int32) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.asgs[asgID]; !ok {
+		return "", fmt.Errorf("ASG %q not found", asgID)
+	}
+	if f.refreshing[asgID] {
+		return "", fmt.Errorf("ASG %q already has an instance refresh in progress", asgID)
+	}
+	f.refreshing[asgID] = true
+	f.RefreshCalls = append(f.RefreshCalls, asgID)
+	return fmt.Sprintf("%s-refresh-%d", asgID, len(f.RefreshCalls)), nil
+}
+
+func (f *FakeASGClient) InstanceRefreshInProgress(ctx context.Context, asgID string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.asgs[asgID]; !ok {
+		return false, fmt.Errorf("ASG %q not found", asgID)
+	}
+	return f.refreshing[asgID], nil
+}
+
+// CompleteInstanceRefresh finishes an ASG's instance refresh (for tests).
+func (f *FakeASGClient) CompleteInstanceRefresh(asgID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.refreshing, asgID)
+}
+
 // GetASG returns the current state of an ASG (for test assertions).
 func (f *FakeASGClient) GetASG(asgID string) *ASGInfo {
 	f.mu.Lock()
@@ -158,14 +158,29 @@ func (m *ASGManager) PrepareSwap(ctx context.Context, pool PoolInfo, direction S
 
 // waitForNewNode polls Kubernetes until a new Ready node appears that matches the pool.
 func (m *ASGManager) waitForNewNode(ctx context.Context, pool PoolInfo, direction SwapDirection) (string, error) {
-	if m.k8sClient == nil {
+	return waitForReplacementNode(ctx, m.k8sClient, m.logger, pool, direction, m.nodeReadyTimeout, m.pollInterval)
+}
+
+// waitForReplacementNode polls Kubernetes until a node that was not present
+// at the first poll becomes Ready with the pool's spotvortex.io/pool label
+// and the capacity type the swap direction asks for. Shared by the twin ASG
+// and twin AKS node pool managers.
+func waitForReplacementNode(
+	ctx context.Context,
+	k8sClient kubernetes.Interface,
+	logger *slog.Logger,
+	pool PoolInfo,
+	direction SwapDirection,
+	timeout, pollInterval time.Duration,
+) (string, error) {
+	if k8sClient == nil {
 		// No K8s client = testing mode, assume instant readiness
 		return "fake-replacement-node", nil
 	}
 
 	// Record existing nodes before scaling
 	existingNodes := make(map[string]bool)
-	nodes, err := m.k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	nodes, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return "", fmt.Errorf("failed to list nodes: %w", err)
 	}
@@ -174,8 +189,8 @@ func (m *ASGManager) waitForNewNode(ctx context.Context, pool PoolInfo, directio
 	}
 
 	// Poll until new node appears and is Ready
-	deadline := time.After(m.nodeReadyTimeout)
-	ticker := time.NewTicker(m.pollInterval)
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(pollInterval)
 	defer ticker.Stop()
 
 	expectedCapType := "on-demand"
@@ -189,11 +204,11 @@ func (m *ASGManager) waitForNewNode(ctx context.Context, pool PoolInfo, directio
 			return "", ctx.Err()
 		case <-deadline:
 			return "", fmt.Errorf("timeout after %v waiting for new %s node in pool %q",
-				m.nodeReadyTimeout, expectedCapType, pool.Name)
+				timeout, expectedCapType, pool.Name)
 		case <-ticker.C:
-			nodes, err := m.k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+			nodes, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 			if err != nil {
-				m.logger.Warn("failed to list nodes during wait", "error", err)
+				logger.Warn("failed to list nodes during wait", "error", err)
 				continue
 			}
 
@@ -96,8 +96,8 @@ func (c *AWSASGClient) DiscoverTwinASGs(ctx context.Context, pool string) (*ASGI
 	}
 
 	if spot == nil || od == nil {
-		return nil, nil, fmt.Errorf("twin ASG pair not found for pool %q (spot=%v, od=%v)",
-			pool, spot != nil, od != nil)
+		return nil, nil, fmt.Errorf("twin ASG pair not found for pool %q (spot=%v, od=%v): %w",
+			pool, spot != nil, od != nil, ErrASGNotFound)
 	}
 
 	c.logger.Info("discovered twin ASG pair",
@@ -173,6 +173,136 @@ func (c *AWSASGClient) GetInstanceASG(ctx context.Context, instanceID string) (s
 	return *asgName, nil
 }
 
+// DiscoverMixedASG finds the ASG tagged for a workload pool that has a
+// MixedInstancesPolicy. Its capacity-type tag, if any, is ignored.
+func (c *AWSASGClient) DiscoverMixedASG(ctx context.Context, pool string) (*MixedASGInfo, error) {
+	input := &autoscaling.DescribeAutoScalingGroupsInput{
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("tag:" + c.poolTagKey),
+				Values: []string{pool},
+			},
+		},
+	}
+
+	for {
+		result, err := c.asgClient.DescribeAutoScalingGroups(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe ASGs for pool %q: %w", pool, err)
+		}
+
+		for _, asg := range result.AutoScalingGroups {
+			if info := mixedASGInfoFromAWS(asg, pool); info != nil {
+				c.logger.Info("discovered mixed-instances ASG",
+					"pool", pool,
+					"asg", info.ASGID,
+					"od_base", info.OnDemandBaseCapacity,
+					"od_percent_above_base", info.OnDemandPercentageAboveBaseCapacity,
+				)
+				return info, nil
+			}
+		}
+
+		if result.NextToken == nil {
+			break
+		}
+		input.NextToken = result.NextToken
+	}
+
+	return nil, fmt.Errorf("mixed-instances ASG not found for pool %q: %w", pool, ErrASGNotFound)
+}
+
+// SetOnDemandDistribution updates the instances distribution of a mixed ASG.
+// Only the on-demand base and percentage are sent, so the launch template and
+// overrides are left as they are.
+func (c *AWSASGClient) SetOnDemandDistribution(ctx context.Context, asgID string, base, percentAboveBase int32) error {
+	input := &autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String(asgID),
+		MixedInstancesPolicy: &types.MixedInstancesPolicy{
+			InstancesDistribution: &types.InstancesDistribution{
+				OnDemandBaseCapacity:                aws.Int32(base),
+				OnDemandPercentageAboveBaseCapacity: aws.Int32(percentAboveBase),
+			},
+		},
+	}
+
+	if _, err := c.asgClient.UpdateAutoScalingGroup(ctx, input); err != nil {
+		return fmt.Errorf("failed to set on-demand distribution for ASG %q: %w", asgID, err)
+	}
+
+	c.logger.Info("set ASG on-demand distribution",
+		"asg", asgID,
+		"od_base", base,
+		"od_percent_above_base", percentAboveBase,
+	)
+
+	return nil
+}
+
+// StartInstanceRefresh starts a rolling instance refresh of an ASG.
+func (c *AWSASGClient) StartInstanceRefresh(ctx context.Context, asgID string, minHealthyPercent int32) (string, error) {
+	input := &autoscaling.StartInstanceRefreshInput{
+		AutoScalingGroupName: aws.String(asgID),
+		Preferences: &types.RefreshPreferences{
+			MinHealthyPercentage: aws.Int32(minHealthyPercent),
+		},
+	}
+
+	result, err := c.asgClient.StartInstanceRefresh(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("failed to start instance refresh for ASG %q: %w", asgID, err)
+	}
+
+	refreshID := aws.ToString(result.InstanceRefreshId)
+	c.logger.Info("started ASG instance refresh",
+		"asg", asgID,
+		"refresh_id", refreshID,
+		"min_healthy_percent", minHealthyPercent,
+	)
+
+	return refreshID, nil
+}
+
+// InstanceRefreshInProgress reports whether the ASG has an instance refresh
+// that has not reached a final status.
+func (c *AWSASGClient) InstanceRefreshInProgress(ctx context.Context, asgID string) (bool, error) {
+	input := &autoscaling.DescribeInstanceRefreshesInput{
+		AutoScalingGroupName: aws.String(asgID),
+	}
+
+	for {
+		result, err := c.asgClient.DescribeInstanceRefreshes(ctx, input)
+		if err != nil {
+			return false, fmt.Errorf("failed to describe instance refreshes for ASG %q: %w", asgID, err)
+		}
+
+		for _, refresh := range result.InstanceRefreshes {
+			if instanceRefreshActive(refresh.Status) {
+				return true, nil
+			}
+		}
+
+		if result.NextToken == nil {
+			return false, nil
+		}
+		input.NextToken = result.NextToken
+	}
+}
+
+// instanceRefreshActive reports whether Auto Scaling is still working on a
+// refresh in this status; StartInstanceRefresh fails while one is.
+func instanceRefreshActive(status types.InstanceRefreshStatus) bool {
+	switch status {
+	case types.InstanceRefreshStatusPending,
+		types.InstanceRefreshStatusInProgress,
+		types.InstanceRefreshStatusCancelling,
+		types.InstanceRefreshStatusRollbackInProgress,
+		types.InstanceRefreshStatusBaking:
+		return true
+	}
+	return false
+}
+
 // asgInfoFromAWS converts an AWS ASG to our ASGInfo, extracting tag values.
 func asgInfoFromAWS(asg types.AutoScalingGroup, poolTagKey, capTagKey string) *ASGInfo {
 	if asg.AutoScalingGroupName == nil {
@@ -205,5 +335,33 @@ func asgInfoFromAWS(asg types.AutoScalingGroup, poolTagKey, capTagKey string) *A
 	return info
 }
 
+// mixedASGInfoFromAWS converts an AWS ASG with a MixedInstancesPolicy to
+// MixedASGInfo, or returns nil for ASGs without one. Unset distribution
+// fields take the Auto Scaling defaults (base 0, 100% on demand).
+func mixedASGInfoFromAWS(asg types.AutoScalingGroup, pool string) *MixedASGInfo {
+	if asg.AutoScalingGroupName == nil || asg.MixedInstancesPolicy == nil {
+		return nil
+	}
+
+	info := &MixedASGInfo{
+		ASGInfo: ASGInfo{
+			ASGID:           *asg.AutoScalingGroupName,
+			Pool:            pool,
+			DesiredCapacity: aws.ToInt32(asg.DesiredCapacity),
+			CurrentCount:    int32(len(asg.Instances)),
+			MaxSize:         aws.ToInt32(asg.MaxSize),
+		},
+		OnDemandPercentageAboveBaseCapacity: 100,
+	}
+	if dist := asg.MixedInstancesPolicy.InstancesDistribution; dist != nil {
+		info.OnDemandBaseCapacity = aws.ToInt32(dist.OnDemandBaseCapacity)
+		if dist.OnDemandPercentageAboveBaseCapacity != nil {
+			info.OnDemandPercentageAboveBaseCapacity = *dist.OnDemandPercentageAboveBaseCapacity
+		}
+	}
+
+	return info
+}
+
 // Compile-time interface check.
 var _ ASGClient = (*AWSASGClient)(nil)
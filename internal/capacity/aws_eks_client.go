@@ -0,0 +1,205 @@
+package capacity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+)
+
+// AWSEKSClientConfig configures the real EKS managed node group client.
+type AWSEKSClientConfig struct {
+	// Region is the AWS region for API calls.
+	Region string
+
+	// ClusterName is the EKS cluster whose node groups are managed.
+	ClusterName string
+
+	// PoolLabelKey is the node group Kubernetes label for workload pool name.
+	// Default: "spotvortex.io/pool"
+	PoolLabelKey string
+}
+
+// AWSEKSClient implements EKSClient using the EKS API. Node groups are scaled
+// with UpdateNodegroupConfig; single instances are terminated through their
+// underlying Auto Scaling Group, which EKS does not expose.
+type AWSEKSClient struct {
+	eksClient    *eks.Client
+	asgClient    *autoscaling.Client
+	logger       *slog.Logger
+	clusterName  string
+	poolLabelKey string
+}
+
+// NewAWSEKSClient creates a real EKS managed node group client.
+func NewAWSEKSClient(ctx context.Context, cfg AWSEKSClientConfig) (*AWSEKSClient, error) {
+	if cfg.ClusterName == "" {
+		return nil, fmt.Errorf("EKS cluster name is required")
+	}
+	if cfg.PoolLabelKey == "" {
+		cfg.PoolLabelKey = "spotvortex.io/pool"
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.Region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return &AWSEKSClient{
+		eksClient:    eks.NewFromConfig(awsCfg),
+		asgClient:    autoscaling.NewFromConfig(awsCfg),
+		logger:       slog.Default(),
+		clusterName:  cfg.ClusterName,
+		poolLabelKey: cfg.PoolLabelKey,
+	}, nil
+}
+
+// DiscoverTwinNodegroups lists the cluster's managed node groups and pairs the
+// spot and on-demand ones labeled for the workload pool.
+// Uses pagination to handle clusters with many node groups.
+func (c *AWSEKSClient) DiscoverTwinNodegroups(ctx context.Context, pool string) (*NodegroupInfo, *NodegroupInfo, error) {
+	var spot, od *NodegroupInfo
+	paginator := eks.NewListNodegroupsPaginator(c.eksClient, &eks.ListNodegroupsInput{
+		ClusterName: aws.String(c.clusterName),
+	})
+	for paginator.HasMorePages() && (spot == nil || od == nil) {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list node groups of cluster %q: %w", c.clusterName, err)
+		}
+
+		for _, name := range page.Nodegroups {
+			result, err := c.eksClient.DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
+				ClusterName:   aws.String(c.clusterName),
+				NodegroupName: aws.String(name),
+			})
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to describe node group %q: %w", name, err)
+			}
+
+			info := nodegroupInfoFromAWS(result.Nodegroup, c.poolLabelKey)
+			if info == nil || info.Pool != pool {
+				continue
+			}
+			switch info.CapacityType {
+			case "spot":
+				spot = info
+			case "on-demand":
+				od = info
+			}
+		}
+	}
+
+	if spot == nil || od == nil {
+		return nil, nil, fmt.Errorf("twin node group pair not found for pool %q (spot=%v, od=%v): %w",
+			pool, spot != nil, od != nil, ErrNodegroupNotFound)
+	}
+
+	c.logger.Info("discovered twin node group pair",
+		"pool", pool,
+		"spot_nodegroup", spot.Name,
+		"od_nodegroup", od.Name,
+	)
+
+	return spot, od, nil
+}
+
+// SetDesiredSize updates the desired size of a node group. EKS applies the
+// update asynchronously and rejects it while another update is in progress.
+func (c *AWSEKSClient) SetDesiredSize(ctx context.Context, nodegroup string, desired int32) error {
+	input := &eks.UpdateNodegroupConfigInput{
+		ClusterName:   aws.String(c.clusterName),
+		NodegroupName: aws.String(nodegroup),
+		ScalingConfig: &ekstypes.NodegroupScalingConfig{
+			DesiredSize: aws.Int32(desired),
+		},
+	}
+
+	result, err := c.eksClient.UpdateNodegroupConfig(ctx, input)
+	if err != nil {
+		return setDesiredSizeError(nodegroup, desired, err)
+	}
+
+	var updateID string
+	if result.Update != nil {
+		updateID = aws.ToString(result.Update.Id)
+	}
+	c.logger.Info("set node group desired size",
+		"nodegroup", nodegroup,
+		"desired", desired,
+		"update_id", updateID,
+	)
+
+	return nil
+}
+
+// setDesiredSizeError wraps an UpdateNodegroupConfig failure, mapping EKS's
+// ResourceInUseException to ErrNodegroupUpdateInProgress.
+func setDesiredSizeError(nodegroup string, desired int32, err error) error {
+	var inUse *ekstypes.ResourceInUseException
+	if errors.As(err, &inUse) {
+		return fmt.Errorf("failed to set desired size for node group %q to %d (%v): %w", nodegroup, desired, err, ErrNodegroupUpdateInProgress)
+	}
+	return fmt.Errorf("failed to set desired size for node group %q to %d: %w", nodegroup, desired, err)
+}
+
+// TerminateNode terminates an instance of a node group and decrements the
+// desired capacity of its Auto Scaling Group, which EKS reflects in the node
+// group's desired size.
+func (c *AWSEKSClient) TerminateNode(ctx context.Context, nodegroup string, instanceID string) error {
+	input := &autoscaling.TerminateInstanceInAutoScalingGroupInput{
+		InstanceId:                     aws.String(instanceID),
+		ShouldDecrementDesiredCapacity: aws.Bool(true),
+	}
+
+	if _, err := c.asgClient.TerminateInstanceInAutoScalingGroup(ctx, input); err != nil {
+		return fmt.Errorf("failed to terminate instance %s in node group %s: %w", instanceID, nodegroup, err)
+	}
+
+	c.logger.Info("terminated instance in node group",
+		"nodegroup", nodegroup,
+		"instance", instanceID,
+	)
+
+	return nil
+}
+
+// nodegroupInfoFromAWS converts an EKS node group to our NodegroupInfo, or
+// returns nil when it has no pool label or is neither spot nor on-demand
+// (e.g. CAPACITY_BLOCK).
+func nodegroupInfoFromAWS(ng *ekstypes.Nodegroup, poolLabelKey string) *NodegroupInfo {
+	if ng == nil || ng.NodegroupName == nil {
+		return nil
+	}
+
+	info := &NodegroupInfo{
+		Name:         *ng.NodegroupName,
+		Pool:         ng.Labels[poolLabelKey],
+		CapacityType: NormalizeCapacityType(string(ng.CapacityType)),
+		Updating:     ng.Status == ekstypes.NodegroupStatusUpdating,
+	}
+	if ng.CapacityType == "" {
+		// Node groups created before capacity types existed are on-demand.
+		info.CapacityType = "on-demand"
+	}
+	if sc := ng.ScalingConfig; sc != nil {
+		info.DesiredSize = aws.ToInt32(sc.DesiredSize)
+		info.MinSize = aws.ToInt32(sc.MinSize)
+		info.MaxSize = aws.ToInt32(sc.MaxSize)
+	}
+
+	if info.Pool == "" || (info.CapacityType != "spot" && info.CapacityType != "on-demand") {
+		return nil
+	}
+
+	return info
+}
+
+// Compile-time interface check.
+var _ EKSClient = (*AWSEKSClient)(nil)
@@ -0,0 +1,94 @@
+package capacity
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+)
+
+func TestNodegroupInfoFromAWS(t *testing.T) {
+	ng := &ekstypes.Nodegroup{
+		NodegroupName: aws.String("api-spot"),
+		CapacityType:  ekstypes.CapacityTypesSpot,
+		Labels:        map[string]string{"spotvortex.io/pool": "api"},
+		ScalingConfig: &ekstypes.NodegroupScalingConfig{
+			DesiredSize: aws.Int32(3),
+			MinSize:     aws.Int32(1),
+			MaxSize:     aws.Int32(10),
+		},
+	}
+
+	info := nodegroupInfoFromAWS(ng, "spotvortex.io/pool")
+	if info == nil {
+		t.Fatal("expected non-nil NodegroupInfo")
+	}
+	want := NodegroupInfo{Name: "api-spot", Pool: "api", CapacityType: "spot", DesiredSize: 3, MinSize: 1, MaxSize: 10}
+	if *info != want {
+		t.Errorf("info=%+v, want %+v", *info, want)
+	}
+}
+
+func TestNodegroupInfoFromAWS_CapacityTypes(t *testing.T) {
+	tests := []struct {
+		name         string
+		capacityType ekstypes.CapacityTypes
+		want         string
+	}{
+		{"on-demand", ekstypes.CapacityTypesOnDemand, "on-demand"},
+		{"unset defaults to on-demand", "", "on-demand"},
+		{"capacity block is skipped", ekstypes.CapacityTypesCapacityBlock, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ng := &ekstypes.Nodegroup{
+				NodegroupName: aws.String("api-od"),
+				CapacityType:  tt.capacityType,
+				Labels:        map[string]string{"spotvortex.io/pool": "api"},
+			}
+			info := nodegroupInfoFromAWS(ng, "spotvortex.io/pool")
+			if tt.want == "" {
+				if info != nil {
+					t.Fatalf("expected nil, got %+v", info)
+				}
+				return
+			}
+			if info == nil || info.CapacityType != tt.want {
+				t.Fatalf("info=%+v, want capacity type %q", info, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodegroupInfoFromAWS_MissingPoolLabel(t *testing.T) {
+	ng := &ekstypes.Nodegroup{
+		NodegroupName: aws.String("system"),
+		CapacityType:  ekstypes.CapacityTypesOnDemand,
+	}
+	if info := nodegroupInfoFromAWS(ng, "spotvortex.io/pool"); info != nil {
+		t.Errorf("expected nil for node group without pool label, got %+v", info)
+	}
+}
+
+func TestNodegroupInfoFromAWS_Updating(t *testing.T) {
+	ng := &ekstypes.Nodegroup{
+		NodegroupName: aws.String("api-od"),
+		CapacityType:  ekstypes.CapacityTypesOnDemand,
+		Labels:        map[string]string{"spotvortex.io/pool": "api"},
+		Status:        ekstypes.NodegroupStatusUpdating,
+	}
+	if info := nodegroupInfoFromAWS(ng, "spotvortex.io/pool"); info == nil || !info.Updating {
+		t.Fatalf("info=%+v, want Updating for status UPDATING", info)
+	}
+}
+
+func TestSetDesiredSizeError_ResourceInUse(t *testing.T) {
+	inUse := &ekstypes.ResourceInUseException{Message: aws.String("update in progress")}
+	if err := setDesiredSizeError("api-od", 2, inUse); !errors.Is(err, ErrNodegroupUpdateInProgress) {
+		t.Fatalf("err=%v, want ErrNodegroupUpdateInProgress", err)
+	}
+	if err := setDesiredSizeError("api-od", 2, errors.New("throttled")); errors.Is(err, ErrNodegroupUpdateInProgress) {
+		t.Fatalf("err=%v, want other failures left unmapped", err)
+	}
+}
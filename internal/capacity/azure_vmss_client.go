@@ -0,0 +1,403 @@
+package capacity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultARMEndpoint is the Azure Resource Manager endpoint.
+	DefaultARMEndpoint = "https://management.azure.com"
+
+	// aksAPIVersion is the Microsoft.ContainerService API version used for
+	// agent pool operations.
+	aksAPIVersion = "2024-05-01"
+
+	armScope = "https://management.azure.com/.default"
+)
+
+// AzureTokenSource returns bearer tokens for Azure Resource Manager.
+type AzureTokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// AzureVMSSClientConfig configures the real AKS node pool client.
+type AzureVMSSClientConfig struct {
+	// SubscriptionID, ResourceGroup and ClusterName locate the AKS cluster.
+	SubscriptionID string
+	ResourceGroup  string
+	ClusterName    string
+
+	// PoolTagKey is the node pool tag (or node label) key for workload pool name.
+	// Default: "spotvortex.io/pool"
+	PoolTagKey string
+
+	// BaseURL overrides the ARM endpoint (tests, sovereign clouds).
+	BaseURL string
+
+	// HTTPClient overrides the HTTP client. Default: 30s timeout.
+	HTTPClient *http.Client
+
+	// TokenSource overrides credential discovery. Default: workload identity
+	// when AZURE_FEDERATED_TOKEN_FILE is set, otherwise managed identity.
+	TokenSource AzureTokenSource
+
+	Logger *slog.Logger
+}
+
+// AzureVMSSClient implements VMSSClient using the AKS agentPools ARM API.
+// Node pools are scaled through AKS rather than the underlying VMSS, which
+// AKS does not support modifying directly.
+type AzureVMSSClient struct {
+	clusterURL string
+	httpClient *http.Client
+	tokens     AzureTokenSource
+	logger     *slog.Logger
+	poolTagKey string
+}
+
+// NewAzureVMSSClient creates a real AKS node pool client.
+func NewAzureVMSSClient(cfg AzureVMSSClientConfig) (*AzureVMSSClient, error) {
+	if cfg.SubscriptionID == "" || cfg.ResourceGroup == "" || cfg.ClusterName == "" {
+		return nil, fmt.Errorf("azure subscription, resource group and cluster name are required")
+	}
+	if cfg.PoolTagKey == "" {
+		cfg.PoolTagKey = "spotvortex.io/pool"
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultARMEndpoint
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if cfg.TokenSource == nil {
+		cfg.TokenSource = newDefaultAzureTokenSource(cfg.HTTPClient)
+	}
+
+	return &AzureVMSSClient{
+		clusterURL: fmt.Sprintf("%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ContainerService/managedClusters/%s",
+			strings.TrimRight(cfg.BaseURL, "/"),
+			url.PathEscape(cfg.SubscriptionID),
+			url.PathEscape(cfg.ResourceGroup),
+			url.PathEscape(cfg.ClusterName),
+		),
+		httpClient: cfg.HTTPClient,
+		tokens:     cfg.TokenSource,
+		logger:     cfg.Logger,
+		poolTagKey: cfg.PoolTagKey,
+	}, nil
+}
+
+// agentPool is the subset of an AKS agent pool resource we read.
+type agentPool struct {
+	Name       string `json:"name"`
+	Properties struct {
+		Count             int32             `json:"count"`
+		MaxCount          *int32            `json:"maxCount"`
+		EnableAutoScaling bool              `json:"enableAutoScaling"`
+		ScaleSetPriority  string            `json:"scaleSetPriority"`
+		NodeLabels        map[string]string `json:"nodeLabels"`
+		Tags              map[string]string `json:"tags"`
+	} `json:"properties"`
+}
+
+type agentPoolList struct {
+	Value    []agentPool `json:"value"`
+	NextLink string      `json:"nextLink"`
+}
+
+// DiscoverTwinScaleSets lists the cluster's agent pools and pairs the Spot
+// and Regular pools whose tag (or node label) names the workload pool.
+func (c *AzureVMSSClient) DiscoverTwinScaleSets(ctx context.Context, pool string) (*ScaleSetInfo, *ScaleSetInfo, error) {
+	var spot, regular *ScaleSetInfo
+	next := c.clusterURL + "/agentPools?api-version=" + aksAPIVersion
+	for next != "" {
+		var page agentPoolList
+		if err := c.do(ctx, http.MethodGet, next, nil, &page); err != nil {
+			return nil, nil, fmt.Errorf("failed to list AKS node pools for pool %q: %w", pool, err)
+		}
+		for _, ap := range page.Value {
+			info := scaleSetInfoFromAgentPool(ap, c.poolTagKey)
+			if info == nil || info.Pool != pool {
+				continue
+			}
+			switch info.CapacityType {
+			case "spot":
+				spot = info
+			case "on-demand":
+				regular = info
+			}
+		}
+		if spot != nil && regular != nil {
+			break
+		}
+		next = page.NextLink
+	}
+
+	if spot == nil || regular == nil {
+		return nil, nil, fmt.Errorf("twin node pool pair not found for pool %q (spot=%v, regular=%v)",
+			pool, spot != nil, regular != nil)
+	}
+
+	c.logger.Info("discovered twin AKS node pool pair",
+		"pool", pool,
+		"spot_node_pool", spot.Name,
+		"regular_node_pool", regular.Name,
+	)
+	return spot, regular, nil
+}
+
+// SetNodeCount updates an agent pool's count. The pool is read and written
+// back whole because the agentPools API has no partial update.
+func (c *AzureVMSSClient) SetNodeCount(ctx context.Context, name string, count int32) error {
+	poolURL := c.agentPoolURL(name, "")
+
+	var raw map[string]any
+	if err := c.do(ctx, http.MethodGet, poolURL, nil, &raw); err != nil {
+		return fmt.Errorf("failed to get AKS node pool %q: %w", name, err)
+	}
+	props, ok := raw["properties"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("AKS node pool %q has no properties", name)
+	}
+	if autoscaled, _ := props["enableAutoScaling"].(bool); autoscaled {
+		return fmt.Errorf("AKS node pool %q has the cluster autoscaler enabled; manual count changes are rejected", name)
+	}
+	props["count"] = count
+
+	// Only properties are writable; drop read-only envelope fields.
+	body := map[string]any{"properties": props}
+	if err := c.do(ctx, http.MethodPut, poolURL, body, nil); err != nil {
+		return fmt.Errorf("failed to set count for AKS node pool %q to %d: %w", name, count, err)
+	}
+
+	c.logger.Info("set AKS node pool count",
+		"node_pool", name,
+		"count", count,
+	)
+	return nil
+}
+
+// DeleteNode deletes a node's machine from its agent pool, which also
+// decrements the pool's count.
+func (c *AzureVMSSClient) DeleteNode(ctx context.Context, name string, nodeName string) error {
+	body := map[string]any{"machineNames": []string{nodeName}}
+	if err := c.do(ctx, http.MethodPost, c.agentPoolURL(name, "/deleteMachines"), body, nil); err != nil {
+		return fmt.Errorf("failed to delete node %s from AKS node pool %s: %w", nodeName, name, err)
+	}
+
+	c.logger.Info("deleted node from AKS node pool",
+		"node_pool", name,
+		"node", nodeName,
+	)
+	return nil
+}
+
+func (c *AzureVMSSClient) agentPoolURL(name, suffix string) string {
+	return c.clusterURL + "/agentPools/" + url.PathEscape(name) + suffix + "?api-version=" + aksAPIVersion
+}
+
+// do sends an authenticated ARM request. 200, 201 and 202 are success;
+// long-running operations are not polled, the replacement-node wait covers
+// their completion.
+func (c *AzureVMSSClient) do(ctx context.Context, method, rawURL string, in, out any) error {
+	var reqBody io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire ARM token: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted:
+	default:
+		return fmt.Errorf("ARM returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// scaleSetInfoFromAgentPool converts an AKS agent pool to our ScaleSetInfo.
+// The pool name is read from the agent pool tags, falling back to its node
+// labels. Returns nil for pools not tagged for SpotVortex.
+func scaleSetInfoFromAgentPool(ap agentPool, poolTagKey string) *ScaleSetInfo {
+	if ap.Name == "" {
+		return nil
+	}
+	pool := ap.Properties.Tags[poolTagKey]
+	if pool == "" {
+		pool = ap.Properties.NodeLabels[poolTagKey]
+	}
+	if pool == "" {
+		return nil
+	}
+
+	capacityType := "on-demand"
+	if strings.EqualFold(ap.Properties.ScaleSetPriority, "Spot") {
+		capacityType = "spot"
+	}
+
+	info := &ScaleSetInfo{
+		Name:         ap.Name,
+		Pool:         pool,
+		CapacityType: capacityType,
+		Count:        ap.Properties.Count,
+	}
+	if ap.Properties.MaxCount != nil {
+		info.MaxCount = *ap.Properties.MaxCount
+	}
+	return info
+}
+
+// cachedAzureToken caches a bearer token until shortly before it expires.
+type cachedAzureToken struct {
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+	fetch   func(ctx context.Context) (string, time.Time, error)
+}
+
+func (t *cachedAzureToken) Token(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.token != "" && time.Until(t.expires) > 5*time.Minute {
+		return t.token, nil
+	}
+	token, expires, err := t.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	t.token, t.expires = token, expires
+	return token, nil
+}
+
+// newDefaultAzureTokenSource uses AKS workload identity when its projected
+// token is mounted, otherwise the node's managed identity via IMDS.
+func newDefaultAzureTokenSource(httpClient *http.Client) AzureTokenSource {
+	tokenFile := os.Getenv("AZURE_FEDERATED_TOKEN_FILE")
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	if tokenFile != "" && clientID != "" && tenantID != "" {
+		authority := os.Getenv("AZURE_AUTHORITY_HOST")
+		if authority == "" {
+			authority = "https://login.microsoftonline.com/"
+		}
+		return &cachedAzureToken{fetch: func(ctx context.Context) (string, time.Time, error) {
+			assertion, err := os.ReadFile(tokenFile)
+			if err != nil {
+				return "", time.Time{}, fmt.Errorf("failed to read federated token: %w", err)
+			}
+			form := url.Values{
+				"grant_type":            {"client_credentials"},
+				"client_id":             {clientID},
+				"scope":                 {armScope},
+				"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+				"client_assertion":      {strings.TrimSpace(string(assertion))},
+			}
+			endpoint := strings.TrimRight(authority, "/") + "/" + url.PathEscape(tenantID) + "/oauth2/v2.0/token"
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+			if err != nil {
+				return "", time.Time{}, err
+			}
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			return fetchAzureToken(httpClient, req)
+		}}
+	}
+
+	return &cachedAzureToken{fetch: func(ctx context.Context) (string, time.Time, error) {
+		q := url.Values{
+			"api-version": {"2018-02-01"},
+			"resource":    {"https://management.azure.com/"},
+		}
+		if clientID != "" {
+			q.Set("client_id", clientID)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+			"http://169.254.169.254/metadata/identity/oauth2/token?"+q.Encode(), nil)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		req.Header.Set("Metadata", "true")
+		return fetchAzureToken(httpClient, req)
+	}}
+}
+
+// fetchAzureToken performs a token request. Entra ID returns expires_in as
+// a number, IMDS as a string.
+func fetchAzureToken(httpClient *http.Client, req *http.Request) (string, time.Time, error) {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var body struct {
+		AccessToken string          `json:"access_token"`
+		ExpiresIn   json.RawMessage `json:"expires_in"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if body.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("token response has no access_token")
+	}
+	seconds, err := strconv.Atoi(strings.Trim(string(body.ExpiresIn), `"`))
+	if err != nil || seconds <= 0 {
+		seconds = 3600
+	}
+	return body.AccessToken, time.Now().Add(time.Duration(seconds) * time.Second), nil
+}
+
+// Compile-time interface check.
+var _ VMSSClient = (*AzureVMSSClient)(nil)
@@ -31,7 +31,7 @@ func NormalizeCapacityType(value string) string {
 	switch normalized {
 	case "spot", "ec2spot":
 		return "spot"
-	case "on-demand", "ondemand", "ec2ondemand", "ec2-on-demand", "normal":
+	case "on-demand", "ondemand", "ec2ondemand", "ec2-on-demand", "normal", "regular":
 		return "on-demand"
 	case "reserved", "capacity-block", "capacityblock":
 		return "reserved"
@@ -50,6 +50,7 @@ func CapacityTypeFromLabels(labels map[string]string) string {
 		LabelKarpenterCapacity,
 		LabelSpotVortexCapacity,
 		LabelEKSCapacityType,
+		LabelAKSScaleSetPriority,
 		LabelNodeLifecycle,
 	} {
 		if value, ok := labels[key]; ok {
@@ -58,6 +59,11 @@ func CapacityTypeFromLabels(labels map[string]string) string {
 			}
 		}
 	}
+	// AKS only labels Spot pools; an agent pool node without a priority
+	// label is Regular (on-demand) capacity.
+	if _, ok := labels[LabelAKSAgentPool]; ok {
+		return "on-demand"
+	}
 	return ""
 }
 
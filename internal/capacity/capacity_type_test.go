@@ -54,6 +54,21 @@ func TestCapacityTypeFromLabels(t *testing.T) {
 			},
 			want: "spot",
 		},
+		{
+			name: "aks spot priority",
+			labels: map[string]string{
+				LabelAKSAgentPool:        "webspot",
+				LabelAKSScaleSetPriority: "spot",
+			},
+			want: "spot",
+		},
+		{
+			name: "aks regular pool has no priority label",
+			labels: map[string]string{
+				LabelAKSAgentPool: "webregular",
+			},
+			want: "on-demand",
+		},
 		{
 			name: "lifecycle fallback",
 			labels: map[string]string{
@@ -15,6 +15,11 @@ const (
 	// EKS Managed Nodegroup labels
 	LabelEKSNodegroup = "eks.amazonaws.com/nodegroup"
 
+	// AKS node pool labels. Spot pools carry scalesetpriority=spot; regular
+	// pools carry no priority label.
+	LabelAKSAgentPool        = "kubernetes.azure.com/agentpool"
+	LabelAKSScaleSetPriority = "kubernetes.azure.com/scalesetpriority"
+
 	// SpotVortex explicit override
 	LabelManagerOverride = "spotvortex.io/manager"
 
@@ -30,9 +35,14 @@ const (
 //  1. Explicit override: spotvortex.io/manager label
 //  2. Karpenter: karpenter.sh/nodepool label present
 //  3. EKS Managed Nodegroup: eks.amazonaws.com/nodegroup label present
-//  4. Unknown: no recognized provisioner labels
+//  4. AKS node pool: kubernetes.azure.com/agentpool label present
+//  5. Unknown: no recognized provisioner labels
+//
+// With a TwinLookup set, ASG-backed (CA or MNG) nodes whose spotvortex.io/pool
+// has no twin spot/on-demand ASG pair are detected as mixed-instances ASG nodes.
 type Detector struct {
-	logger *slog.Logger
+	logger     *slog.Logger
+	twinLookup TwinLookup
 }
 
 // NewDetector creates a new provisioner detector.
@@ -43,8 +53,27 @@ func NewDetector(logger *slog.Logger) *Detector {
 	return &Detector{logger: logger}
 }
 
+// SetTwinLookup enables detection of mixed-instances ASG pools. Not safe to
+// call concurrently with DetectManager.
+func (d *Detector) SetTwinLookup(lookup TwinLookup) {
+	d.twinLookup = lookup
+}
+
 // DetectManager returns the ManagerType for a given node.
 func (d *Detector) DetectManager(node *corev1.Node) ManagerType {
+	mgrType := d.detectProvisioner(node)
+	if mgrType != ManagerClusterAutoscaler && mgrType != ManagerManagedNodegroup {
+		return mgrType
+	}
+	// Both use ASGs: a pool without a twin pair is a single mixed ASG.
+	if pool := node.Labels[TagPool]; pool != "" && d.twinLookup != nil && !d.twinLookup(pool) {
+		return ManagerMixedInstancesASG
+	}
+	return mgrType
+}
+
+// detectProvisioner returns the ManagerType a node's labels name.
+func (d *Detector) detectProvisioner(node *corev1.Node) ManagerType {
 	if node == nil || node.Labels == nil {
 		return ManagerUnknown
 	}
@@ -59,6 +88,10 @@ func (d *Detector) DetectManager(node *corev1.Node) ManagerType {
 			return ManagerClusterAutoscaler
 		case ManagerManagedNodegroup:
 			return ManagerManagedNodegroup
+		case ManagerAKSNodePool:
+			return ManagerAKSNodePool
+		case ManagerMixedInstancesASG:
+			return ManagerMixedInstancesASG
 		default:
 			d.logger.Warn("unknown manager override, falling through",
 				"node", node.Name,
@@ -84,6 +117,11 @@ func (d *Detector) DetectManager(node *corev1.Node) ManagerType {
 		return ManagerManagedNodegroup
 	}
 
+	// Priority 4: AKS node pool
+	if _, ok := labels[LabelAKSAgentPool]; ok {
+		return ManagerAKSNodePool
+	}
+
 	return ManagerUnknown
 }
 
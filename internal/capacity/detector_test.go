@@ -56,6 +56,14 @@ func TestDetector_DetectManager(t *testing.T) {
 			},
 			expected: ManagerManagedNodegroup,
 		},
+		{
+			name: "aks spot node pool",
+			labels: map[string]string{
+				"kubernetes.azure.com/agentpool":        "webspot",
+				"kubernetes.azure.com/scalesetpriority": "spot",
+			},
+			expected: ManagerAKSNodePool,
+		},
 		{
 			name: "explicit override karpenter",
 			labels: map[string]string{
@@ -170,3 +178,38 @@ func TestDetector_GroupNodesByManager(t *testing.T) {
 		t.Errorf("expected 1 unknown node, got %d", len(groups[ManagerUnknown]))
 	}
 }
+
+func TestDetector_TwinLookupDetectsMixedASGPools(t *testing.T) {
+	d := NewDetector(slog.Default())
+	caNode := func(pool string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   pool + "-node",
+			Labels: map[string]string{LabelManagerOverride: "cluster-autoscaler", TagPool: pool},
+		}}
+	}
+	if got := d.DetectManager(caNode("batch")); got != ManagerClusterAutoscaler {
+		t.Fatalf("without a twin lookup got %q, want %q", got, ManagerClusterAutoscaler)
+	}
+
+	d.SetTwinLookup(func(pool string) bool { return pool == "web" })
+	if got := d.DetectManager(caNode("web")); got != ManagerClusterAutoscaler {
+		t.Errorf("twin pool got %q, want %q", got, ManagerClusterAutoscaler)
+	}
+	if got := d.DetectManager(caNode("batch")); got != ManagerMixedInstancesASG {
+		t.Errorf("pool without twin got %q, want %q", got, ManagerMixedInstancesASG)
+	}
+	mng := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "mng-node",
+		Labels: map[string]string{LabelEKSNodegroup: "ng", TagPool: "batch"},
+	}}
+	if got := d.DetectManager(mng); got != ManagerMixedInstancesASG {
+		t.Errorf("MNG pool without twin got %q, want %q", got, ManagerMixedInstancesASG)
+	}
+	karp := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "karpenter-node",
+		Labels: map[string]string{LabelKarpenterNodePool: "batch", TagPool: "batch"},
+	}}
+	if got := d.DetectManager(karp); got != ManagerKarpenter {
+		t.Errorf("karpenter node got %q, want %q", got, ManagerKarpenter)
+	}
+}
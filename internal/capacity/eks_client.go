@@ -0,0 +1,188 @@
+package capacity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNodegroupNotFound is returned (wrapped) when a workload pool has no
+// twin spot/on-demand managed node group pair, as opposed to an EKS API failure.
+var ErrNodegroupNotFound = errors.New("managed node group not found")
+
+// ErrNodegroupUpdateInProgress is returned (wrapped) when EKS rejects a node
+// group update because another update of the node group is still running.
+var ErrNodegroupUpdateInProgress = errors.New("managed node group update in progress")
+
+// NodegroupInfo describes an EKS managed node group discovered for SpotVortex
+// management.
+type NodegroupInfo struct {
+	// Name is the node group name (eks.amazonaws.com/nodegroup on its nodes).
+	Name string
+
+	// Pool is the workload pool name (from the node group's spotvortex.io/pool
+	// Kubernetes label).
+	Pool string
+
+	// CapacityType is "spot" or "on-demand" (EKS SPOT / ON_DEMAND, also
+	// eks.amazonaws.com/capacityType on its nodes).
+	CapacityType string
+
+	// DesiredSize, MinSize and MaxSize are the node group's scaling config.
+	DesiredSize int32
+	MinSize     int32
+	MaxSize     int32
+
+	// Updating is set while the node group has an update in progress (status
+	// UPDATING); EKS rejects UpdateNodegroupConfig until it finishes.
+	Updating bool
+}
+
+// EKSClient abstracts EKS managed node group operations.
+// This interface enables testing with a fake client in Kind clusters.
+type EKSClient interface {
+	// DiscoverTwinNodegroups finds paired Spot/On-Demand managed node groups
+	// for a workload pool. Discovery uses the spotvortex.io/pool Kubernetes
+	// label of the node groups; capacity type comes from their capacityType.
+	DiscoverTwinNodegroups(ctx context.Context, pool string) (spot *NodegroupInfo, od *NodegroupInfo, err error)
+
+	// SetDesiredSize updates a node group's desired size via
+	// UpdateNodegroupConfig. It returns ErrNodegroupUpdateInProgress (wrapped)
+	// while another update of the node group is running.
+	SetDesiredSize(ctx context.Context, nodegroup string, desired int32) error
+
+	// TerminateNode terminates a node group's instance and decrements its
+	// desired size, so the node group does not replace it.
+	TerminateNode(ctx context.Context, nodegroup string, instanceID string) error
+}
+
+// FakeEKSClient implements EKSClient for testing in Kind clusters.
+// It simulates twin node group discovery and scaling operations in memory.
+type FakeEKSClient struct {
+	mu         sync.Mutex
+	nodegroups map[string]*NodegroupInfo // name -> info
+
+	// ScaleCalls tracks calls to SetDesiredSize for assertions.
+	ScaleCalls []fakeScaleCall
+	// TerminateCalls tracks calls to TerminateNode for assertions.
+	TerminateCalls []fakeTerminateCall
+}
+
+// NewFakeEKSClient creates an empty fake EKS client.
+func NewFakeEKSClient() *FakeEKSClient {
+	return &FakeEKSClient{
+		nodegroups: make(map[string]*NodegroupInfo),
+	}
+}
+
+// AddTwinPair registers a spot/on-demand node group pair for a workload pool,
+// named "<pool>-spot" and "<pool>-od".
+func (f *FakeEKSClient) AddTwinPair(pool string, spotDesired, odDesired int32) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	spotName := pool + "-spot"
+	odName := pool + "-od"
+
+	f.nodegroups[spotName] = &NodegroupInfo{
+		Name:         spotName,
+		Pool:         pool,
+		CapacityType: "spot",
+		DesiredSize:  spotDesired,
+		MaxSize:      spotDesired + 5,
+	}
+	f.nodegroups[odName] = &NodegroupInfo{
+		Name:         odName,
+		Pool:         pool,
+		CapacityType: "on-demand",
+		DesiredSize:  odDesired,
+		MaxSize:      odDesired + 5,
+	}
+}
+
+func (f *FakeEKSClient) DiscoverTwinNodegroups(ctx context.Context, pool string) (*NodegroupInfo, *NodegroupInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	spot, spotOK := f.nodegroups[pool+"-spot"]
+	od, odOK := f.nodegroups[pool+"-od"]
+	if !spotOK || !odOK {
+		return nil, nil, fmt.Errorf("twin node group pair not found for pool %q: %w", pool, ErrNodegroupNotFound)
+	}
+
+	// Return copies to avoid data races
+	spotCopy := *spot
+	odCopy := *od
+	return &spotCopy, &odCopy, nil
+}
+
+func (f *FakeEKSClient) SetDesiredSize(ctx context.Context, nodegroup string, desired int32) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ng, ok := f.nodegroups[nodegroup]
+	if !ok {
+		return fmt.Errorf("node group %q not found", nodegroup)
+	}
+	if ng.Updating {
+		return fmt.Errorf("node group %q: %w", nodegroup, ErrNodegroupUpdateInProgress)
+	}
+	if desired < ng.MinSize || desired > ng.MaxSize {
+		return fmt.Errorf("desired %d outside [%d, %d] for node group %q", desired, ng.MinSize, ng.MaxSize, nodegroup)
+	}
+	ng.DesiredSize = desired
+
+	f.ScaleCalls = append(f.ScaleCalls, fakeScaleCall{
+		ASGID:   nodegroup,
+		Desired: desired,
+	})
+	return nil
+}
+
+func (f *FakeEKSClient) TerminateNode(ctx context.Context, nodegroup string, instanceID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ng, ok := f.nodegroups[nodegroup]
+	if !ok {
+		return fmt.Errorf("node group %q not found", nodegroup)
+	}
+	if ng.DesiredSize > 0 {
+		ng.DesiredSize--
+	}
+
+	f.TerminateCalls = append(f.TerminateCalls, fakeTerminateCall{
+		ASGID:      nodegroup,
+		InstanceID: instanceID,
+		Decrement:  true,
+	})
+	return nil
+}
+
+// SetUpdating marks a node group as having an update in progress, so
+// discovery reports it and SetDesiredSize rejects changes.
+func (f *FakeEKSClient) SetUpdating(nodegroup string, updating bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if ng, ok := f.nodegroups[nodegroup]; ok {
+		ng.Updating = updating
+	}
+}
+
+// GetNodegroup returns the current state of a node group (for test assertions).
+func (f *FakeEKSClient) GetNodegroup(name string) *NodegroupInfo {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if ng, ok := f.nodegroups[name]; ok {
+		copy := *ng
+		return &copy
+	}
+	return nil
+}
+
+// Compile-time interface check.
+var _ EKSClient = (*FakeEKSClient)(nil)
@@ -0,0 +1,213 @@
+package capacity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// EKSNodegroupManager implements CapacityManager for EKS managed node groups
+// through the EKS API, so node groups do not need twin-tagged ASGs.
+//
+// Swap strategy mirrors the Twin ASG model: each workload pool has a SPOT and
+// an ON_DEMAND managed node group whose nodes carry the same spotvortex.io/pool
+// label (and eks.amazonaws.com/capacityType, eks.amazonaws.com/nodegroup).
+//  1. PrepareSwap: Raise the twin node group's desired size with
+//     UpdateNodegroupConfig, wait for the new node to become Ready.
+//  2. Drain proceeds normally via controller.
+//  3. PostDrainCleanup: Terminate the drained instance from the node group
+//     named by its eks.amazonaws.com/nodegroup label.
+type EKSNodegroupManager struct {
+	eksClient EKSClient
+	k8sClient kubernetes.Interface
+	logger    *slog.Logger
+
+	// Config
+	nodeReadyTimeout time.Duration
+	pollInterval     time.Duration
+}
+
+// EKSNodegroupManagerConfig configures the EKS managed node group capacity manager.
+type EKSNodegroupManagerConfig struct {
+	EKSClient        EKSClient
+	K8sClient        kubernetes.Interface
+	Logger           *slog.Logger
+	NodeReadyTimeout time.Duration
+	PollInterval     time.Duration
+}
+
+// NewEKSNodegroupManager creates a new EKS managed node group capacity manager.
+func NewEKSNodegroupManager(cfg EKSNodegroupManagerConfig) *EKSNodegroupManager {
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if cfg.NodeReadyTimeout <= 0 {
+		cfg.NodeReadyTimeout = 5 * time.Minute
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 10 * time.Second
+	}
+
+	return &EKSNodegroupManager{
+		eksClient:        cfg.EKSClient,
+		k8sClient:        cfg.K8sClient,
+		logger:           cfg.Logger,
+		nodeReadyTimeout: cfg.NodeReadyTimeout,
+		pollInterval:     cfg.PollInterval,
+	}
+}
+
+func (m *EKSNodegroupManager) Type() ManagerType {
+	return ManagerManagedNodegroup
+}
+
+// PrepareSwap raises the twin node group for the target direction by one
+// node and waits for it to become Ready. On timeout the desired size is
+// restored and the drain is aborted. While the node group has another update
+// in progress nothing changes and the result has SkipDrain set, so the next
+// tick retries.
+func (m *EKSNodegroupManager) PrepareSwap(ctx context.Context, pool PoolInfo, direction SwapDirection) (*SwapResult, error) {
+	start := time.Now()
+
+	if m.eksClient == nil {
+		return nil, fmt.Errorf("EKS client not configured")
+	}
+
+	spotNG, odNG, err := m.eksClient.DiscoverTwinNodegroups(ctx, pool.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover twin node groups for pool %q: %w", pool.Name, err)
+	}
+
+	var target *NodegroupInfo
+	switch direction {
+	case SwapToOnDemand:
+		target = odNG
+	case SwapToSpot:
+		target = spotNG
+	default:
+		return nil, fmt.Errorf("unknown swap direction: %d", direction)
+	}
+
+	if target.Updating {
+		m.logger.Info("node group update in progress; skipping swap",
+			"pool", pool.Name,
+			"target_nodegroup", target.Name,
+		)
+		return &SwapResult{SkipDrain: true, Duration: time.Since(start)}, nil
+	}
+
+	newDesired := target.DesiredSize + 1
+	if newDesired > target.MaxSize {
+		return nil, fmt.Errorf("node group %q is at its max size %d", target.Name, target.MaxSize)
+	}
+
+	m.logger.Info("preparing EKS node group swap",
+		"pool", pool.Name,
+		"direction", direction.String(),
+		"target_nodegroup", target.Name,
+		"current_desired", target.DesiredSize,
+		"new_desired", newDesired,
+	)
+
+	if err := m.eksClient.SetDesiredSize(ctx, target.Name, newDesired); err != nil {
+		if errors.Is(err, ErrNodegroupUpdateInProgress) {
+			// An update started after discovery; the scale-up never applied.
+			m.logger.Info("node group update in progress; skipping swap",
+				"pool", pool.Name,
+				"target_nodegroup", target.Name,
+			)
+			return &SwapResult{SkipDrain: true, Duration: time.Since(start)}, nil
+		}
+		return nil, fmt.Errorf("failed to scale up node group %q: %w", target.Name, err)
+	}
+
+	nodeName, err := waitForReplacementNode(ctx, m.k8sClient, m.logger, pool, direction, m.nodeReadyTimeout, m.pollInterval)
+	if err != nil {
+		m.logger.Warn("new node did not become Ready, aborting swap",
+			"pool", pool.Name,
+			"target_nodegroup", target.Name,
+			"error", err,
+		)
+		m.restore(target)
+		return nil, fmt.Errorf("timeout waiting for replacement node: %w", err)
+	}
+
+	m.logger.Info("replacement node ready",
+		"pool", pool.Name,
+		"replacement_node", nodeName,
+		"duration", time.Since(start),
+	)
+
+	return &SwapResult{
+		Ready:               true,
+		ReplacementNodeName: nodeName,
+		Duration:            time.Since(start),
+	}, nil
+}
+
+// restore puts a node group back to the desired size it had before
+// PrepareSwap.
+func (m *EKSNodegroupManager) restore(target *NodegroupInfo) {
+	// Not PrepareSwap's context: its cancellation may be what ended the wait.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := m.eksClient.SetDesiredSize(ctx, target.Name, target.DesiredSize); err != nil {
+		m.logger.Error("failed to rollback node group scale-up",
+			"nodegroup", target.Name,
+			"error", err,
+		)
+	}
+}
+
+// PostDrainCleanup terminates the drained instance from the node group named
+// by its eks.amazonaws.com/nodegroup label, decrementing the node group's
+// desired size so EKS does not replace it.
+func (m *EKSNodegroupManager) PostDrainCleanup(ctx context.Context, nodeName string, pool PoolInfo) error {
+	if m.eksClient == nil {
+		m.logger.Debug("no EKS client, skipping post-drain cleanup", "node", nodeName)
+		return nil
+	}
+	if m.k8sClient == nil {
+		m.logger.Debug("no k8s client, skipping post-drain cleanup", "node", nodeName)
+		return nil
+	}
+
+	node, err := m.k8sClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch drained node %q for cleanup: %w", nodeName, err)
+	}
+	nodegroup := node.Labels[LabelEKSNodegroup]
+	if nodegroup == "" {
+		return fmt.Errorf("node %q has no %s label", nodeName, LabelEKSNodegroup)
+	}
+	instanceID := instanceIDFromProviderID(node.Spec.ProviderID)
+	if instanceID == "" {
+		return fmt.Errorf("node %q providerID %q does not contain an instance id", nodeName, node.Spec.ProviderID)
+	}
+
+	if err := m.eksClient.TerminateNode(ctx, nodegroup, instanceID); err != nil {
+		return fmt.Errorf("failed to terminate instance %q from node group %q: %w", instanceID, nodegroup, err)
+	}
+
+	m.logger.Info("post-drain cleanup complete",
+		"node", nodeName,
+		"instance_id", instanceID,
+		"pool", pool.Name,
+		"nodegroup", nodegroup,
+		"manager", ManagerManagedNodegroup,
+	)
+	return nil
+}
+
+func (m *EKSNodegroupManager) IsAvailable(ctx context.Context) bool {
+	return m.eksClient != nil
+}
+
+// Compile-time interface check.
+var _ CapacityManager = (*EKSNodegroupManager)(nil)
@@ -0,0 +1,231 @@
+package capacity
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEKSNodegroupManager_PrepareSwap_ToOnDemand(t *testing.T) {
+	client := NewFakeEKSClient()
+	client.AddTwinPair("api", 3, 1)
+
+	mgr := NewEKSNodegroupManager(EKSNodegroupManagerConfig{
+		EKSClient:        client,
+		Logger:           slog.Default(),
+		NodeReadyTimeout: 2 * time.Second,
+		PollInterval:     100 * time.Millisecond,
+	})
+	if mgr.Type() != ManagerManagedNodegroup {
+		t.Errorf("Type() = %q, want %q", mgr.Type(), ManagerManagedNodegroup)
+	}
+
+	result, err := mgr.PrepareSwap(context.Background(), PoolInfo{Name: "api", Zone: "us-east-1a"}, SwapToOnDemand)
+	if err != nil {
+		t.Fatalf("PrepareSwap: %v", err)
+	}
+	if !result.Ready {
+		t.Error("expected Ready=true")
+	}
+	if got := client.GetNodegroup("api-od").DesiredSize; got != 2 {
+		t.Errorf("on-demand desired=%d, want 2", got)
+	}
+	if got := client.GetNodegroup("api-spot").DesiredSize; got != 3 {
+		t.Errorf("spot desired=%d, want 3 (unchanged)", got)
+	}
+}
+
+func TestEKSNodegroupManager_PrepareSwap_NoTwinPair(t *testing.T) {
+	mgr := NewEKSNodegroupManager(EKSNodegroupManagerConfig{EKSClient: NewFakeEKSClient()})
+	if _, err := mgr.PrepareSwap(context.Background(), PoolInfo{Name: "api"}, SwapToSpot); err == nil {
+		t.Fatal("expected error for pool without twin node groups")
+	}
+}
+
+func TestEKSNodegroupManager_PrepareSwap_AtMaxSize(t *testing.T) {
+	client := NewFakeEKSClient()
+	client.AddTwinPair("api", 5, 0) // spot max = 10
+
+	mgr := NewEKSNodegroupManager(EKSNodegroupManagerConfig{EKSClient: client})
+	if err := client.SetDesiredSize(context.Background(), "api-spot", 10); err != nil {
+		t.Fatalf("SetDesiredSize: %v", err)
+	}
+
+	if _, err := mgr.PrepareSwap(context.Background(), PoolInfo{Name: "api"}, SwapToSpot); err == nil {
+		t.Fatal("expected error when node group is at max size")
+	}
+}
+
+func TestEKSNodegroupManager_PrepareSwap_TimeoutRollsBack(t *testing.T) {
+	client := NewFakeEKSClient()
+	client.AddTwinPair("api", 3, 1)
+
+	mgr := NewEKSNodegroupManager(EKSNodegroupManagerConfig{
+		EKSClient:        client,
+		K8sClient:        k8sfake.NewSimpleClientset(),
+		NodeReadyTimeout: 200 * time.Millisecond,
+		PollInterval:     50 * time.Millisecond,
+	})
+
+	if _, err := mgr.PrepareSwap(context.Background(), PoolInfo{Name: "api"}, SwapToOnDemand); err == nil {
+		t.Fatal("expected timeout error")
+	}
+	if got := client.GetNodegroup("api-od").DesiredSize; got != 1 {
+		t.Errorf("on-demand desired=%d, want 1 after rollback", got)
+	}
+}
+
+func TestEKSNodegroupManager_PrepareSwap_RollbackOutlivesCallerContext(t *testing.T) {
+	client := NewFakeEKSClient()
+	client.AddTwinPair("api", 3, 1)
+
+	mgr := NewEKSNodegroupManager(EKSNodegroupManagerConfig{
+		EKSClient:        client,
+		K8sClient:        k8sfake.NewSimpleClientset(),
+		NodeReadyTimeout: 5 * time.Second,
+		PollInterval:     50 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := mgr.PrepareSwap(ctx, PoolInfo{Name: "api"}, SwapToOnDemand); err == nil {
+		t.Fatal("expected the wait to fail with the caller's context")
+	}
+	if got := client.GetNodegroup("api-od").DesiredSize; got != 1 {
+		t.Errorf("on-demand desired=%d, want 1 restored after the context ended", got)
+	}
+}
+
+func TestEKSNodegroupManager_PrepareSwap_UpdateInProgressSkips(t *testing.T) {
+	client := NewFakeEKSClient()
+	client.AddTwinPair("api", 3, 1)
+	client.SetUpdating("api-od", true)
+
+	mgr := NewEKSNodegroupManager(EKSNodegroupManagerConfig{
+		EKSClient:        client,
+		K8sClient:        k8sfake.NewSimpleClientset(),
+		NodeReadyTimeout: 200 * time.Millisecond,
+		PollInterval:     50 * time.Millisecond,
+	})
+
+	result, err := mgr.PrepareSwap(context.Background(), PoolInfo{Name: "api"}, SwapToOnDemand)
+	if err != nil || result.Ready || !result.SkipDrain {
+		t.Fatalf("PrepareSwap = %+v, %v; want SkipDrain without error", result, err)
+	}
+	if len(client.ScaleCalls) != 0 {
+		t.Fatalf("scale calls=%v, want none while the node group updates", client.ScaleCalls)
+	}
+
+	// An update that starts after discovery is rejected by EKS, not waited on.
+	mgr.eksClient = staleDiscoveryEKSClient{client}
+	result, err = mgr.PrepareSwap(context.Background(), PoolInfo{Name: "api"}, SwapToOnDemand)
+	if err != nil || result.Ready || !result.SkipDrain {
+		t.Fatalf("PrepareSwap after a stale discovery = %+v, %v; want SkipDrain without error", result, err)
+	}
+	if got := client.GetNodegroup("api-od").DesiredSize; got != 1 {
+		t.Errorf("on-demand desired=%d, want 1 unchanged", got)
+	}
+}
+
+// staleDiscoveryEKSClient reports node groups as idle although the fake
+// rejects their updates, like an update starting between discovery and
+// UpdateNodegroupConfig.
+type staleDiscoveryEKSClient struct {
+	*FakeEKSClient
+}
+
+func (c staleDiscoveryEKSClient) DiscoverTwinNodegroups(ctx context.Context, pool string) (*NodegroupInfo, *NodegroupInfo, error) {
+	spot, od, err := c.FakeEKSClient.DiscoverTwinNodegroups(ctx, pool)
+	if err != nil {
+		return nil, nil, err
+	}
+	spot.Updating, od.Updating = false, false
+	return spot, od, nil
+}
+
+func TestEKSNodegroupManager_WaitsForEKSNode(t *testing.T) {
+	client := NewFakeEKSClient()
+	client.AddTwinPair("api", 3, 1)
+	k8s := k8sfake.NewSimpleClientset()
+
+	mgr := NewEKSNodegroupManager(EKSNodegroupManagerConfig{
+		EKSClient:        client,
+		K8sClient:        k8s,
+		NodeReadyTimeout: 2 * time.Second,
+		PollInterval:     50 * time.Millisecond,
+	})
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_, _ = k8s.CoreV1().Nodes().Create(context.Background(), &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "ip-10-0-1-20.ec2.internal",
+				Labels: map[string]string{
+					"spotvortex.io/pool": "api",
+					LabelEKSNodegroup:    "api-spot",
+					LabelEKSCapacityType: "SPOT",
+				},
+			},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			}},
+		}, metav1.CreateOptions{})
+	}()
+
+	result, err := mgr.PrepareSwap(context.Background(), PoolInfo{Name: "api"}, SwapToSpot)
+	if err != nil {
+		t.Fatalf("PrepareSwap: %v", err)
+	}
+	if result.ReplacementNodeName != "ip-10-0-1-20.ec2.internal" {
+		t.Errorf("replacement=%q", result.ReplacementNodeName)
+	}
+}
+
+func TestEKSNodegroupManager_PostDrainCleanup_TerminatesFromSourceNodegroup(t *testing.T) {
+	client := NewFakeEKSClient()
+	client.AddTwinPair("api", 3, 1)
+	k8s := k8sfake.NewSimpleClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ip-10-0-1-10.ec2.internal",
+			Labels: map[string]string{
+				LabelEKSNodegroup:    "api-spot",
+				LabelEKSCapacityType: "SPOT",
+			},
+		},
+		Spec: corev1.NodeSpec{ProviderID: "aws:///us-east-1a/i-0abc123"},
+	})
+
+	mgr := NewEKSNodegroupManager(EKSNodegroupManagerConfig{EKSClient: client, K8sClient: k8s})
+	if err := mgr.PostDrainCleanup(context.Background(), "ip-10-0-1-10.ec2.internal", PoolInfo{Name: "api"}); err != nil {
+		t.Fatalf("PostDrainCleanup: %v", err)
+	}
+
+	if len(client.TerminateCalls) != 1 {
+		t.Fatalf("terminate calls=%d, want 1", len(client.TerminateCalls))
+	}
+	if call := client.TerminateCalls[0]; call.ASGID != "api-spot" || call.InstanceID != "i-0abc123" || !call.Decrement {
+		t.Errorf("terminate call=%+v", call)
+	}
+	if got := client.GetNodegroup("api-spot").DesiredSize; got != 2 {
+		t.Errorf("spot desired=%d, want 2", got)
+	}
+}
+
+func TestEKSNodegroupManager_PostDrainCleanup_MissingNodegroupLabel(t *testing.T) {
+	client := NewFakeEKSClient()
+	client.AddTwinPair("api", 3, 1)
+	k8s := k8sfake.NewSimpleClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec:       corev1.NodeSpec{ProviderID: "aws:///us-east-1a/i-0abc123"},
+	})
+
+	mgr := NewEKSNodegroupManager(EKSNodegroupManagerConfig{EKSClient: client, K8sClient: k8s})
+	if err := mgr.PostDrainCleanup(context.Background(), "node-1", PoolInfo{Name: "api"}); err == nil {
+		t.Fatal("expected error for node without nodegroup label")
+	}
+}
@@ -14,6 +14,10 @@ import (
 //
 // Swap strategy:
 //   - PrepareSwap: steers NodePool weights (fast API patch, non-blocking).
+//     Pools with a single mixed-capacity NodePool instead get its
+//     capacity-type requirement set and a weight graduated by the spot-ratio
+//     gap. The controller waits for the replacement NodeClaim itself; see
+//     Controller.awaitKarpenterReplacements.
 //   - PostDrainCleanup: no-op (Karpenter manages node lifecycle after drain).
 //
 // Per integration_strategy.md Section 3: Karpenter is the "happy path" -
@@ -28,6 +32,11 @@ type KarpenterManager struct {
 	spotWeight int32
 	odWeight   int32
 
+	// singleNodePool reports whether a workload pool uses one NodePool with
+	// both capacity types instead of spot/on-demand twins.
+	singleNodePool func(workloadPool string) bool
+	maxDriftNodes  int
+
 	// Cooldown state
 	mu               sync.Mutex
 	lastWeightChange map[string]time.Time
@@ -43,6 +52,12 @@ type KarpenterManagerConfig struct {
 	SpotWeight             int32
 	OnDemandWeight         int32
 	CooldownSeconds        int
+	// SingleNodePool selects the single-NodePool layout per workload pool.
+	// Nil means every pool uses twin NodePools.
+	SingleNodePool func(workloadPool string) bool
+	// MaxDriftNodes caps how many NodeClaims a single NodePool replaces at
+	// once after spot is removed from it. Default: 1.
+	MaxDriftNodes int
 }
 
 // NewKarpenterManager creates a new Karpenter capacity manager.
@@ -62,6 +77,9 @@ func NewKarpenterManager(cfg KarpenterManagerConfig) *KarpenterManager {
 	if cfg.OnDemandWeight == 0 {
 		cfg.OnDemandWeight = 10
 	}
+	if cfg.MaxDriftNodes <= 0 {
+		cfg.MaxDriftNodes = 1
+	}
 	cooldown := time.Duration(cfg.CooldownSeconds) * time.Second
 	if cooldown <= 0 {
 		cooldown = 60 * time.Second
@@ -74,6 +92,8 @@ func NewKarpenterManager(cfg KarpenterManagerConfig) *KarpenterManager {
 		odSuffix:         cfg.OnDemandNodePoolSuffix,
 		spotWeight:       cfg.SpotWeight,
 		odWeight:         cfg.OnDemandWeight,
+		singleNodePool:   cfg.SingleNodePool,
+		maxDriftNodes:    cfg.MaxDriftNodes,
 		lastWeightChange: make(map[string]time.Time),
 		cooldown:         cooldown,
 	}
@@ -103,6 +123,18 @@ func (m *KarpenterManager) PrepareSwap(ctx context.Context, pool PoolInfo, direc
 		return &SwapResult{Ready: true, Duration: time.Since(start)}, nil
 	}
 
+	var steered bool
+	if m.singleNodePool != nil && m.singleNodePool(pool.Name) {
+		steered = m.steerSingleNodePool(ctx, pool, direction)
+	} else {
+		steered = m.steerTwinNodePools(ctx, pool, direction)
+	}
+	return &SwapResult{Ready: steered, Duration: time.Since(start)}, nil
+}
+
+// steerTwinNodePools patches the weights of a pool's spot and on-demand
+// NodePools and reports whether at least one patch succeeded.
+func (m *KarpenterManager) steerTwinNodePools(ctx context.Context, pool PoolInfo, direction SwapDirection) bool {
 	// Determine weights based on direction
 	spotPoolName := pool.Name + m.spotSuffix
 	odPoolName := pool.Name + m.odSuffix
@@ -144,12 +176,32 @@ func (m *KarpenterManager) PrepareSwap(ctx context.Context, pool PoolInfo, direc
 		m.mu.Lock()
 		m.lastWeightChange[pool.Name] = time.Now()
 		m.mu.Unlock()
+		return true
 	}
+	return false
+}
 
-	return &SwapResult{
-		Ready:    spotErr == nil || odErr == nil,
-		Duration: time.Since(start),
-	}, nil
+// steerSingleNodePool steers a pool's one mixed-capacity NodePool; see
+// karpenter.NodePoolManager.SteerMixedNodePool.
+func (m *KarpenterManager) steerSingleNodePool(ctx context.Context, pool PoolInfo, direction SwapDirection) bool {
+	err := m.nodePoolMgr.SteerMixedNodePool(ctx, pool.Name, karpenter.MixedSteering{
+		FavorSpot:        direction == SwapToSpot,
+		SpotWeight:       m.spotWeight,
+		OnDemandWeight:   m.odWeight,
+		RatioKnown:       pool.SpotRatioKnown,
+		CurrentSpotRatio: pool.CurrentSpotRatio,
+		TargetSpotRatio:  pool.TargetSpotRatio,
+		MaxDriftNodes:    m.maxDriftNodes,
+	})
+	if err != nil {
+		m.logger.Warn("failed to steer single NodePool", "pool", pool.Name, "error", err)
+		return false
+	}
+
+	m.mu.Lock()
+	m.lastWeightChange[pool.Name] = time.Now()
+	m.mu.Unlock()
+	return true
 }
 
 func (m *KarpenterManager) PostDrainCleanup(ctx context.Context, nodeName string, pool PoolInfo) error {
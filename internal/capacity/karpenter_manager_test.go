@@ -169,3 +169,35 @@ func TestKarpenterManager_PrepareSwap_PartialPatchSuccessStillReady(t *testing.T
 		t.Fatalf("unexpected weights after partial success, got spot=%d od=%d", spot, od)
 	}
 }
+
+func TestKarpenterManager_PrepareSwap_SingleNodePool(t *testing.T) {
+	dyn := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), makeNodePool("batch", 50))
+	nodePoolMgr := karpenter.NewNodePoolManager(dyn, slog.Default())
+	mgr := NewKarpenterManager(KarpenterManagerConfig{
+		NodePoolManager: nodePoolMgr,
+		Logger:          slog.Default(),
+		SpotWeight:      80,
+		OnDemandWeight:  20,
+		CooldownSeconds: 1,
+		SingleNodePool:  func(workloadPool string) bool { return workloadPool == "batch" },
+	})
+
+	// 80% spot against a 50% target: On-Demand only, weight 30% of the way
+	// from 20 to 80.
+	result, err := mgr.PrepareSwap(context.Background(), PoolInfo{
+		Name:             "batch",
+		CurrentSpotRatio: 0.8,
+		TargetSpotRatio:  0.5,
+		SpotRatioKnown:   true,
+	}, SwapToOnDemand)
+	if err != nil || !result.Ready {
+		t.Fatalf("PrepareSwap = %+v, %v; want ready", result, err)
+	}
+	types, err := nodePoolMgr.GetCapacityTypes(context.Background(), "batch")
+	if err != nil || len(types) != 1 || types[0] != karpenter.CapacityTypeOnDemand {
+		t.Fatalf("capacity types=%v, %v; want [on-demand]", types, err)
+	}
+	if weight, _ := nodePoolMgr.GetWeight(context.Background(), "batch"); weight != 32 {
+		t.Fatalf("weight=%d, want 32", weight)
+	}
+}
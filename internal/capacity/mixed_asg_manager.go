@@ -0,0 +1,372 @@
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Convergence strategies for mixed-instances ASGs. Changing an ASG's
+// instances distribution only affects new launches, so existing instances
+// must be replaced for the ASG to reach it.
+const (
+	// ConvergeByTermination launches one instance under the new distribution,
+	// waits for it to become Ready, and terminates the drained instance after
+	// the drain (the twin ASG Scale-Wait-Drain flow on a single ASG).
+	ConvergeByTermination = "terminate"
+
+	// ConvergeByInstanceRefresh starts an instance refresh so Auto Scaling
+	// replaces the ASG's instances under the new distribution at its own pace.
+	ConvergeByInstanceRefresh = "instance-refresh"
+)
+
+// MixedASGManager implements CapacityManager for ASG-backed nodes whose pool
+// is a single ASG with a MixedInstancesPolicy instead of a twin spot/OD pair.
+//
+// Swap strategy:
+//  1. PrepareSwap: Move the ASG's OnDemandBaseCapacity and
+//     OnDemandPercentageAboveBaseCapacity one instance (or, when the pool's
+//     target spot ratio is known, all the way) toward the target mix.
+//  2. Converge: with ConvergeByTermination, scale up by 1 and wait for a
+//     Ready node of the new capacity type; with ConvergeByInstanceRefresh,
+//     start an instance refresh and return SkipDrain, since the refresh
+//     replaces the instances itself. No swap starts while a refresh runs.
+//  3. PostDrainCleanup: Terminate the drained instance, decrementing desired
+//     capacity only when PrepareSwap scaled up.
+type MixedASGManager struct {
+	asgClient ASGClient
+	k8sClient kubernetes.Interface
+	logger    *slog.Logger
+
+	// Config
+	convergence       string
+	minHealthyPercent int32
+	nodeReadyTimeout  time.Duration
+	pollInterval      time.Duration
+}
+
+// MixedASGManagerConfig configures the mixed-instances ASG capacity manager.
+type MixedASGManagerConfig struct {
+	ASGClient ASGClient
+	K8sClient kubernetes.Interface
+	Logger    *slog.Logger
+
+	// Convergence is ConvergeByTermination (default) or ConvergeByInstanceRefresh.
+	Convergence string
+
+	// MinHealthyPercent is the share of capacity an instance refresh keeps in
+	// service. Default: 90.
+	MinHealthyPercent int32
+
+	NodeReadyTimeout time.Duration
+	PollInterval     time.Duration
+}
+
+// NewMixedASGManager creates a new mixed-instances ASG capacity manager.
+func NewMixedASGManager(cfg MixedASGManagerConfig) *MixedASGManager {
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if cfg.Convergence == "" {
+		cfg.Convergence = ConvergeByTermination
+	}
+	if cfg.MinHealthyPercent <= 0 || cfg.MinHealthyPercent > 100 {
+		cfg.MinHealthyPercent = 90
+	}
+	if cfg.NodeReadyTimeout <= 0 {
+		cfg.NodeReadyTimeout = 5 * time.Minute
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 10 * time.Second
+	}
+
+	return &MixedASGManager{
+		asgClient:         cfg.ASGClient,
+		k8sClient:         cfg.K8sClient,
+		logger:            cfg.Logger,
+		convergence:       cfg.Convergence,
+		minHealthyPercent: cfg.MinHealthyPercent,
+		nodeReadyTimeout:  cfg.NodeReadyTimeout,
+		pollInterval:      cfg.PollInterval,
+	}
+}
+
+func (m *MixedASGManager) Type() ManagerType {
+	return ManagerMixedInstancesASG
+}
+
+// PrepareSwap shifts the pool's mixed-instances ASG toward the swap direction
+// and converges it with the configured strategy.
+//
+// Failure modes:
+//   - Already all spot/on-demand in the swap direction: error, no drain.
+//   - Instance refresh already in progress: nothing changes, SkipDrain.
+//   - Replacement not Ready in time: distribution and desired capacity are
+//     restored, and the swap is aborted.
+func (m *MixedASGManager) PrepareSwap(ctx context.Context, pool PoolInfo, direction SwapDirection) (*SwapResult, error) {
+	start := time.Now()
+
+	if m.asgClient == nil {
+		return nil, fmt.Errorf("ASG client not configured")
+	}
+	if direction != SwapToOnDemand && direction != SwapToSpot {
+		return nil, fmt.Errorf("unknown swap direction: %d", direction)
+	}
+
+	asg, err := m.asgClient.DiscoverMixedASG(ctx, pool.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover mixed-instances ASG for pool %q: %w", pool.Name, err)
+	}
+
+	if m.convergence == ConvergeByInstanceRefresh {
+		refreshing, err := m.asgClient.InstanceRefreshInProgress(ctx, asg.ASGID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check instance refreshes of ASG %q: %w", asg.ASGID, err)
+		}
+		if refreshing {
+			// The running refresh is still replacing the pool's instances;
+			// leave the distribution alone until it finishes.
+			m.logger.Info("instance refresh in progress; skipping swap",
+				"pool", pool.Name,
+				"asg", asg.ASGID,
+			)
+			return &SwapResult{
+				SkipDrain: true,
+				Duration:  time.Since(start),
+			}, nil
+		}
+	}
+
+	base, percent, err := targetDistribution(asg, pool, direction)
+	if err != nil {
+		return nil, err
+	}
+
+	m.logger.Info("preparing mixed-instances ASG swap",
+		"pool", pool.Name,
+		"direction", direction.String(),
+		"asg", asg.ASGID,
+		"convergence", m.convergence,
+		"od_base", asg.OnDemandBaseCapacity,
+		"new_od_base", base,
+		"od_percent_above_base", asg.OnDemandPercentageAboveBaseCapacity,
+		"new_od_percent_above_base", percent,
+	)
+
+	if err := m.asgClient.SetOnDemandDistribution(ctx, asg.ASGID, base, percent); err != nil {
+		return nil, fmt.Errorf("failed to update on-demand distribution of ASG %q: %w", asg.ASGID, err)
+	}
+
+	if m.convergence == ConvergeByInstanceRefresh {
+		refreshID, err := m.asgClient.StartInstanceRefresh(ctx, asg.ASGID, m.minHealthyPercent)
+		if err != nil {
+			// The distribution still applies to launches, so draining and
+			// terminating the node replaces it under the new distribution.
+			m.logger.Warn("failed to start instance refresh; new distribution applies to new launches only",
+				"pool", pool.Name,
+				"asg", asg.ASGID,
+				"error", err,
+			)
+			return &SwapResult{
+				Ready:    true,
+				Duration: time.Since(start),
+			}, nil
+		}
+		// The refresh drains and replaces the instances itself.
+		m.logger.Info("instance refresh started",
+			"pool", pool.Name,
+			"asg", asg.ASGID,
+			"refresh_id", refreshID,
+		)
+		return &SwapResult{
+			Ready:     true,
+			SkipDrain: true,
+			Duration:  time.Since(start),
+		}, nil
+	}
+
+	// Launch one instance under the new distribution.
+	if err := m.asgClient.SetDesiredCapacity(ctx, asg.ASGID, asg.DesiredCapacity+1); err != nil {
+		m.restore(asg)
+		return nil, fmt.Errorf("failed to scale up ASG %q: %w", asg.ASGID, err)
+	}
+
+	nodeName, err := waitForReplacementNode(ctx, m.k8sClient, m.logger, pool, direction, m.nodeReadyTimeout, m.pollInterval)
+	if err != nil {
+		m.logger.Warn("new node did not become Ready, aborting swap",
+			"pool", pool.Name,
+			"asg", asg.ASGID,
+			"error", err,
+		)
+		m.restore(asg)
+		return nil, fmt.Errorf("timeout waiting for replacement node: %w", err)
+	}
+
+	m.logger.Info("replacement node ready",
+		"pool", pool.Name,
+		"replacement_node", nodeName,
+		"duration", time.Since(start),
+	)
+
+	return &SwapResult{
+		Ready:               true,
+		ReplacementNodeName: nodeName,
+		Duration:            time.Since(start),
+	}, nil
+}
+
+// restore puts a mixed ASG back to the distribution and desired capacity it
+// had before PrepareSwap.
+func (m *MixedASGManager) restore(asg *MixedASGInfo) {
+	// Runs after PrepareSwap's context may be cancelled; the ASG is restored
+	// regardless.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := m.asgClient.SetDesiredCapacity(ctx, asg.ASGID, asg.DesiredCapacity); err != nil {
+		m.logger.Error("failed to rollback ASG scale-up",
+			"asg", asg.ASGID,
+			"error", err,
+		)
+	}
+	if err := m.asgClient.SetOnDemandDistribution(ctx, asg.ASGID, asg.OnDemandBaseCapacity, asg.OnDemandPercentageAboveBaseCapacity); err != nil {
+		m.logger.Error("failed to rollback on-demand distribution",
+			"asg", asg.ASGID,
+			"error", err,
+		)
+	}
+}
+
+// PostDrainCleanup terminates the drained instance. Under ConvergeByTermination
+// desired capacity is decremented to undo PrepareSwap's scale-up; under
+// ConvergeByInstanceRefresh it is kept, so Auto Scaling replaces the instance
+// under the new distribution.
+func (m *MixedASGManager) PostDrainCleanup(ctx context.Context, nodeName string, pool PoolInfo) error {
+	if m.asgClient == nil {
+		m.logger.Debug("no ASG client, skipping post-drain cleanup", "node", nodeName)
+		return nil
+	}
+	if m.k8sClient == nil {
+		m.logger.Debug("no k8s client, skipping post-drain cleanup", "node", nodeName)
+		return nil
+	}
+
+	node, err := m.k8sClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch drained node %q for cleanup: %w", nodeName, err)
+	}
+
+	instanceID := instanceIDFromProviderID(node.Spec.ProviderID)
+	if instanceID == "" {
+		return fmt.Errorf("node %q providerID %q does not contain an instance id", nodeName, node.Spec.ProviderID)
+	}
+
+	asgID, err := m.asgClient.GetInstanceASG(ctx, instanceID)
+	if err != nil || asgID == "" {
+		if pool.Name == "" {
+			return fmt.Errorf("pool name required for ASG cleanup fallback on node %q", nodeName)
+		}
+		asg, discoverErr := m.asgClient.DiscoverMixedASG(ctx, pool.Name)
+		if discoverErr != nil {
+			return fmt.Errorf("failed to discover mixed-instances ASG for cleanup on pool %q: %w", pool.Name, discoverErr)
+		}
+		asgID = asg.ASGID
+	}
+
+	decrement := m.convergence != ConvergeByInstanceRefresh
+	if err := m.asgClient.TerminateInstance(ctx, asgID, instanceID, decrement); err != nil {
+		return fmt.Errorf("failed to terminate instance %q from ASG %q: %w", instanceID, asgID, err)
+	}
+
+	m.logger.Info("post-drain cleanup complete",
+		"node", nodeName,
+		"instance_id", instanceID,
+		"pool", pool.Name,
+		"asg", asgID,
+		"decrement_desired", decrement,
+		"manager", ManagerMixedInstancesASG,
+	)
+	return nil
+}
+
+func (m *MixedASGManager) IsAvailable(ctx context.Context) bool {
+	return m.asgClient != nil
+}
+
+// Compile-time interface check.
+var _ CapacityManager = (*MixedASGManager)(nil)
+
+// targetDistribution returns the on-demand base and percentage above base
+// the ASG should have after the swap: one more (or one fewer) instance on
+// demand at its current desired capacity, or the pool's target spot ratio
+// when that goes further in the swap direction. Either way the one instance
+// PrepareSwap launches at desired+1 gets the new capacity type.
+func targetDistribution(asg *MixedASGInfo, pool PoolInfo, direction SwapDirection) (int32, int32, error) {
+	desired := asg.DesiredCapacity
+	if desired <= 0 {
+		return 0, 0, fmt.Errorf("mixed-instances ASG %q has no capacity to swap", asg.ASGID)
+	}
+	current := onDemandCount(asg.OnDemandBaseCapacity, asg.OnDemandPercentageAboveBaseCapacity, desired)
+
+	var ratioOD int32
+	if pool.SpotRatioKnown {
+		ratioOD = int32(math.Round((1 - pool.TargetSpotRatio) * float64(desired)))
+	}
+
+	want := current
+	switch direction {
+	case SwapToOnDemand:
+		want = current + 1
+		if pool.SpotRatioKnown && ratioOD > want {
+			want = ratioOD
+		}
+		if want > desired {
+			return 0, 0, fmt.Errorf("mixed-instances ASG %q already runs all %d instances on demand", asg.ASGID, desired)
+		}
+	case SwapToSpot:
+		want = current - 1
+		if pool.SpotRatioKnown && ratioOD < want {
+			want = ratioOD
+		}
+		if want < 0 {
+			return 0, 0, fmt.Errorf("mixed-instances ASG %q already runs all %d instances on spot", asg.ASGID, desired)
+		}
+	}
+
+	base, percent := onDemandDistribution(asg.OnDemandBaseCapacity, want, desired)
+	return base, percent, nil
+}
+
+// onDemandCount returns how many of desired instances a distribution runs on
+// demand. Auto Scaling rounds the on-demand share above base up.
+func onDemandCount(base, percentAboveBase, desired int32) int32 {
+	if desired <= base {
+		return desired
+	}
+	above := desired - base
+	return base + (above*percentAboveBase+99)/100
+}
+
+// onDemandDistribution returns the base (lowered only if it exceeds
+// onDemand) and the smallest percentage above base that run onDemand of
+// desired instances on demand.
+func onDemandDistribution(base, onDemand, desired int32) (int32, int32) {
+	if onDemand < base {
+		base = onDemand
+	}
+	above := desired - base
+	if above <= 0 || onDemand >= desired {
+		return base, 100
+	}
+	onDemandAbove := onDemand - base
+	if onDemandAbove <= 0 {
+		return base, 0
+	}
+	// Smallest p with ceil(above*p/100) >= onDemandAbove.
+	return base, (onDemandAbove-1)*100/above + 1
+}
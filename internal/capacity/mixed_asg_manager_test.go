@@ -0,0 +1,193 @@
+package capacity
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestMixedASGManager_PrepareSwap_ToOnDemand(t *testing.T) {
+	client := NewFakeASGClient()
+	client.AddMixedASG("api", 4, 0, 50) // 2 of 4 on demand
+
+	mgr := NewMixedASGManager(MixedASGManagerConfig{
+		ASGClient:        client,
+		Logger:           slog.Default(),
+		NodeReadyTimeout: 2 * time.Second,
+		PollInterval:     100 * time.Millisecond,
+	})
+	if mgr.Type() != ManagerMixedInstancesASG {
+		t.Errorf("Type() = %q, want %q", mgr.Type(), ManagerMixedInstancesASG)
+	}
+
+	result, err := mgr.PrepareSwap(context.Background(), PoolInfo{Name: "api"}, SwapToOnDemand)
+	if err != nil {
+		t.Fatalf("PrepareSwap: %v", err)
+	}
+	if !result.Ready {
+		t.Error("expected Ready=true")
+	}
+
+	asg, _ := client.DiscoverMixedASG(context.Background(), "api")
+	if asg.OnDemandBaseCapacity != 0 || asg.OnDemandPercentageAboveBaseCapacity != 51 {
+		t.Fatalf("distribution=%d/%d%%, want 0/51%% (3 of 4 on demand)",
+			asg.OnDemandBaseCapacity, asg.OnDemandPercentageAboveBaseCapacity)
+	}
+	if got := onDemandCount(0, 51, 5); got != 3 {
+		t.Fatalf("on-demand at desired 5 = %d, want 3 so the launched instance is on demand", got)
+	}
+	if asg.DesiredCapacity != 5 {
+		t.Fatalf("desired=%d, want 5 (one launch under the new distribution)", asg.DesiredCapacity)
+	}
+	if len(client.RefreshCalls) != 0 {
+		t.Fatalf("refresh calls=%v, want none under targeted termination", client.RefreshCalls)
+	}
+}
+
+func TestMixedASGManager_PrepareSwap_ToSpotFollowsTargetRatio(t *testing.T) {
+	client := NewFakeASGClient()
+	client.AddMixedASG("batch", 4, 2, 100) // all 4 on demand
+
+	mgr := NewMixedASGManager(MixedASGManagerConfig{ASGClient: client, Logger: slog.Default()})
+	pool := PoolInfo{Name: "batch", CurrentSpotRatio: 0, TargetSpotRatio: 0.75, SpotRatioKnown: true}
+	if _, err := mgr.PrepareSwap(context.Background(), pool, SwapToSpot); err != nil {
+		t.Fatalf("PrepareSwap: %v", err)
+	}
+
+	// The target needs 1 of 4 on demand: the base drops below its old value.
+	call := client.DistributionCalls[0]
+	if call.Base != 1 || call.PercentAboveBase != 0 {
+		t.Fatalf("distribution=%d/%d%%, want 1/0%%", call.Base, call.PercentAboveBase)
+	}
+}
+
+func TestMixedASGManager_PrepareSwap_AllOnDemand(t *testing.T) {
+	client := NewFakeASGClient()
+	client.AddMixedASG("api", 3, 0, 100)
+
+	mgr := NewMixedASGManager(MixedASGManagerConfig{ASGClient: client, Logger: slog.Default()})
+	if _, err := mgr.PrepareSwap(context.Background(), PoolInfo{Name: "api"}, SwapToOnDemand); err == nil {
+		t.Fatal("expected an error when no spot capacity is left to move")
+	}
+	if len(client.DistributionCalls) != 0 || len(client.ScaleUpCalls) != 0 {
+		t.Fatal("a rejected swap must not touch the ASG")
+	}
+}
+
+func TestMixedASGManager_PrepareSwap_TimeoutRollsBack(t *testing.T) {
+	client := NewFakeASGClient()
+	client.AddMixedASG("api", 4, 0, 50)
+
+	mgr := NewMixedASGManager(MixedASGManagerConfig{
+		ASGClient:        client,
+		K8sClient:        k8sfake.NewSimpleClientset(), // no replacement node ever joins
+		Logger:           slog.Default(),
+		NodeReadyTimeout: 300 * time.Millisecond,
+		PollInterval:     50 * time.Millisecond,
+	})
+	if _, err := mgr.PrepareSwap(context.Background(), PoolInfo{Name: "api"}, SwapToOnDemand); err == nil {
+		t.Fatal("expected timeout error")
+	}
+
+	asg, _ := client.DiscoverMixedASG(context.Background(), "api")
+	if asg.DesiredCapacity != 4 || asg.OnDemandBaseCapacity != 0 || asg.OnDemandPercentageAboveBaseCapacity != 50 {
+		t.Fatalf("asg=%+v, want desired 4 and 0/50%% restored", asg)
+	}
+}
+
+func TestMixedASGManager_InstanceRefresh(t *testing.T) {
+	client := NewFakeASGClient()
+	client.AddMixedASG("api", 4, 0, 0)
+
+	k8sClient := k8sfake.NewSimpleClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "api-node",
+			Labels: map[string]string{"spotvortex.io/pool": "api", LabelSpotVortexCapacity: "spot"},
+		},
+		Spec: corev1.NodeSpec{ProviderID: "aws:///us-east-1a/i-0abc"},
+	})
+	mgr := NewMixedASGManager(MixedASGManagerConfig{
+		ASGClient:   client,
+		K8sClient:   k8sClient,
+		Logger:      slog.Default(),
+		Convergence: ConvergeByInstanceRefresh,
+	})
+
+	result, err := mgr.PrepareSwap(context.Background(), PoolInfo{Name: "api"}, SwapToOnDemand)
+	if err != nil {
+		t.Fatalf("PrepareSwap: %v", err)
+	}
+	if !result.Ready || !result.SkipDrain || result.ReplacementNodeName != "" {
+		t.Fatalf("result=%+v, want Ready and SkipDrain without waiting for a node", result)
+	}
+	if len(client.RefreshCalls) != 1 || client.RefreshCalls[0] != "api-mixed-asg" {
+		t.Fatalf("refresh calls=%v, want one for api-mixed-asg", client.RefreshCalls)
+	}
+	if len(client.ScaleUpCalls) != 0 {
+		t.Fatalf("scale calls=%v, want none under instance refresh", client.ScaleUpCalls)
+	}
+
+	// While the refresh runs, nothing changes and nothing is drained.
+	result, err = mgr.PrepareSwap(context.Background(), PoolInfo{Name: "api"}, SwapToOnDemand)
+	if err != nil {
+		t.Fatalf("PrepareSwap during refresh: %v", err)
+	}
+	if result.Ready || !result.SkipDrain {
+		t.Fatalf("result=%+v, want SkipDrain and not Ready during a refresh", result)
+	}
+	if len(client.RefreshCalls) != 1 || len(client.DistributionCalls) != 1 {
+		t.Fatalf("refresh calls=%v distribution calls=%v, want one each", client.RefreshCalls, client.DistributionCalls)
+	}
+
+	client.CompleteInstanceRefresh("api-mixed-asg")
+	if _, err := mgr.PrepareSwap(context.Background(), PoolInfo{Name: "api"}, SwapToOnDemand); err != nil {
+		t.Fatalf("PrepareSwap after refresh: %v", err)
+	}
+	if len(client.RefreshCalls) != 2 {
+		t.Fatalf("refresh calls=%v, want a second refresh once the first finished", client.RefreshCalls)
+	}
+
+	// An interrupted node is still drained; it is terminated without
+	// shrinking the ASG, so its replacement launches under the new
+	// distribution.
+	if err := mgr.PostDrainCleanup(context.Background(), "api-node", PoolInfo{Name: "api"}); err != nil {
+		t.Fatalf("PostDrainCleanup: %v", err)
+	}
+	if len(client.TerminateCalls) != 1 {
+		t.Fatalf("terminate calls=%d, want 1", len(client.TerminateCalls))
+	}
+	call := client.TerminateCalls[0]
+	if call.ASGID != "api-mixed-asg" || call.InstanceID != "i-0abc" || call.Decrement {
+		t.Fatalf("terminate=%+v, want i-0abc from api-mixed-asg without decrement", call)
+	}
+}
+
+func TestOnDemandDistribution(t *testing.T) {
+	tests := []struct {
+		base, onDemand, desired int32
+		wantBase, wantPercent   int32
+	}{
+		{base: 0, onDemand: 0, desired: 4, wantBase: 0, wantPercent: 0},
+		{base: 0, onDemand: 1, desired: 4, wantBase: 0, wantPercent: 1},
+		{base: 0, onDemand: 4, desired: 4, wantBase: 0, wantPercent: 100},
+		{base: 0, onDemand: 4, desired: 5, wantBase: 0, wantPercent: 61},
+		{base: 2, onDemand: 3, desired: 6, wantBase: 2, wantPercent: 1},
+		{base: 3, onDemand: 1, desired: 5, wantBase: 1, wantPercent: 0},
+		{base: 2, onDemand: 2, desired: 2, wantBase: 2, wantPercent: 100},
+	}
+	for _, tc := range tests {
+		base, percent := onDemandDistribution(tc.base, tc.onDemand, tc.desired)
+		if base != tc.wantBase || percent != tc.wantPercent {
+			t.Errorf("onDemandDistribution(%d, %d, %d) = %d/%d%%, want %d/%d%%",
+				tc.base, tc.onDemand, tc.desired, base, percent, tc.wantBase, tc.wantPercent)
+		}
+		if got := onDemandCount(base, percent, tc.desired); got != tc.onDemand {
+			t.Errorf("onDemandCount(%d, %d, %d) = %d, want %d", base, percent, tc.desired, got, tc.onDemand)
+		}
+	}
+}
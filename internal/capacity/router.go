@@ -35,6 +35,13 @@ func NewRouter(logger *slog.Logger, managers ...CapacityManager) *Router {
 	}
 }
 
+// SetTwinLookup lets the router's detector route ASG-backed nodes of pools
+// without a twin ASG pair to the mixed-instances ASG manager. Call before
+// routing starts.
+func (r *Router) SetTwinLookup(lookup TwinLookup) {
+	r.detector.SetTwinLookup(lookup)
+}
+
 // ManagerForNode returns the CapacityManager for a specific node.
 // Returns nil if no manager is registered for the node's provisioner type.
 func (r *Router) ManagerForNode(node *corev1.Node) CapacityManager {
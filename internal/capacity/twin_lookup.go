@@ -0,0 +1,102 @@
+package capacity
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// TwinLookup reports whether a workload pool has twin spot/on-demand
+// capacity (an ASG pair or a managed node group pair). See
+// Detector.SetTwinLookup.
+type TwinLookup func(pool string) bool
+
+// twinCache memoizes twin discovery per workload pool.
+type twinCache struct {
+	discover func(ctx context.Context, pool string) error
+	notFound error
+	ttl      time.Duration
+	logger   *slog.Logger
+
+	mu      sync.Mutex
+	entries map[string]twinEntry
+}
+
+type twinEntry struct {
+	hasTwin bool
+	expires time.Time
+}
+
+// NewTwinASGLookup returns a TwinLookup backed by DiscoverTwinASGs that
+// caches each pool's answer for ttl (default 5m). Discovery failures other
+// than ErrASGNotFound report a twin and are not cached, so an Auto Scaling
+// API error never moves a pool off the twin ASG manager.
+func NewTwinASGLookup(client ASGClient, ttl time.Duration, logger *slog.Logger) TwinLookup {
+	return newTwinCache(func(ctx context.Context, pool string) error {
+		_, _, err := client.DiscoverTwinASGs(ctx, pool)
+		return err
+	}, ErrASGNotFound, ttl, logger).hasTwin
+}
+
+// NewTwinNodegroupLookup is NewTwinASGLookup for EKS managed node group pairs.
+func NewTwinNodegroupLookup(client EKSClient, ttl time.Duration, logger *slog.Logger) TwinLookup {
+	return newTwinCache(func(ctx context.Context, pool string) error {
+		_, _, err := client.DiscoverTwinNodegroups(ctx, pool)
+		return err
+	}, ErrNodegroupNotFound, ttl, logger).hasTwin
+}
+
+// AnyTwin returns a TwinLookup that reports a twin when any lookup does.
+func AnyTwin(lookups ...TwinLookup) TwinLookup {
+	return func(pool string) bool {
+		for _, lookup := range lookups {
+			if lookup(pool) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func newTwinCache(discover func(ctx context.Context, pool string) error, notFound error, ttl time.Duration, logger *slog.Logger) *twinCache {
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &twinCache{
+		discover: discover,
+		notFound: notFound,
+		ttl:      ttl,
+		logger:   logger,
+		entries:  make(map[string]twinEntry),
+	}
+}
+
+func (c *twinCache) hasTwin(pool string) bool {
+	now := time.Now()
+	c.mu.Lock()
+	entry, ok := c.entries[pool]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.hasTwin
+	}
+
+	// Detection has no caller context; bound the discovery call instead.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := c.discover(ctx, pool)
+	if err != nil && !errors.Is(err, c.notFound) {
+		c.logger.Warn("twin discovery failed; assuming twin layout", "pool", pool, "error", err)
+		return true
+	}
+
+	hasTwin := err == nil
+	c.mu.Lock()
+	c.entries[pool] = twinEntry{hasTwin: hasTwin, expires: now.Add(c.ttl)}
+	c.mu.Unlock()
+	return hasTwin
+}
@@ -0,0 +1,66 @@
+package capacity
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// countingASGClient counts twin discoveries and can fail them.
+type countingASGClient struct {
+	*FakeASGClient
+	calls int
+	err   error
+}
+
+func (c *countingASGClient) DiscoverTwinASGs(ctx context.Context, pool string) (*ASGInfo, *ASGInfo, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, nil, c.err
+	}
+	return c.FakeASGClient.DiscoverTwinASGs(ctx, pool)
+}
+
+func TestTwinASGLookup(t *testing.T) {
+	fake := NewFakeASGClient()
+	fake.AddTwinPair("web", 2, 1)
+	fake.AddMixedASG("batch", 4, 0, 50)
+	client := &countingASGClient{FakeASGClient: fake}
+
+	lookup := NewTwinASGLookup(client, time.Minute, slog.Default())
+	if !lookup("web") {
+		t.Fatal("web has a twin pair")
+	}
+	if lookup("batch") || lookup("batch") {
+		t.Fatal("batch has no twin pair")
+	}
+	if client.calls != 2 {
+		t.Fatalf("discoveries=%d, want 2 (answers cached)", client.calls)
+	}
+
+	// API failures keep pools on the twin manager and are retried.
+	client.err = errors.New("throttled")
+	lookup = NewTwinASGLookup(client, time.Minute, slog.Default())
+	if !lookup("batch") || !lookup("batch") || client.calls != 4 {
+		t.Fatalf("calls=%d, want API errors reported as twin and not cached", client.calls)
+	}
+}
+
+func TestAnyTwin_NodegroupPairs(t *testing.T) {
+	asgClient := NewFakeASGClient()
+	eksClient := NewFakeEKSClient()
+	eksClient.AddTwinPair("web", 2, 1)
+
+	lookup := AnyTwin(
+		NewTwinASGLookup(asgClient, time.Minute, slog.Default()),
+		NewTwinNodegroupLookup(eksClient, time.Minute, slog.Default()),
+	)
+	if !lookup("web") {
+		t.Fatal("web has a twin node group pair")
+	}
+	if lookup("batch") {
+		t.Fatal("batch has neither twin ASGs nor twin node groups")
+	}
+}
@@ -1,6 +1,6 @@
 // Package capacity provides a unified interface for managing node capacity
 // across different Kubernetes provisioners: Karpenter, Cluster Autoscaler,
-// and EKS Managed Nodegroups.
+// EKS Managed Nodegroups, and AKS node pools.
 //
 // Design: integration_strategy.md Section 6 - CapacityManager abstraction.
 // A cluster may use multiple provisioners simultaneously (e.g., some pools
@@ -29,9 +29,24 @@ const (
 
 	// ManagerManagedNodegroup indicates nodes in EKS Managed Nodegroups.
 	// Detection: node has eks.amazonaws.com/nodegroup label.
-	// Swap strategy: Same Twin ASG workflow as Cluster Autoscaler (both use ASGs).
+	// Swap strategy: Same Twin ASG workflow as Cluster Autoscaler (both use ASGs),
+	// or twin node groups scaled via the EKS API when an EKS client is configured.
 	ManagerManagedNodegroup ManagerType = "managed-nodegroup"
 
+	// ManagerMixedInstancesASG indicates ASG-backed nodes (CA or MNG) whose
+	// workload pool is a single ASG with a MixedInstancesPolicy.
+	// Detection: CA/MNG node whose pool has no twin ASG pair (see
+	// Detector.SetTwinLookup), or spotvortex.io/manager=mixed-instances-asg.
+	// Swap strategy: shift the ASG's on-demand base/percentage, then converge
+	// by targeted termination or instance refresh.
+	ManagerMixedInstancesASG ManagerType = "mixed-instances-asg"
+
+	// ManagerAKSNodePool indicates nodes in AKS VMSS-backed node pools.
+	// Detection: node has kubernetes.azure.com/agentpool label.
+	// Swap strategy: Twin node pool - scale up the paired spot/regular pool,
+	// wait for Ready, drain, delete the drained machine from its pool.
+	ManagerAKSNodePool ManagerType = "aks-nodepool"
+
 	// ManagerUnknown indicates the provisioner could not be detected.
 	// Nodes with unknown manager are skipped for capacity operations.
 	ManagerUnknown ManagerType = "unknown"
@@ -69,6 +84,12 @@ type PoolInfo struct {
 
 	// InstanceType is the dominant instance type in the pool.
 	InstanceType string
+
+	// CurrentSpotRatio and TargetSpotRatio are the pool's spot ratios, set
+	// when SpotRatioKnown. Managers that steer proportionally use the gap.
+	CurrentSpotRatio float64
+	TargetSpotRatio  float64
+	SpotRatioKnown   bool
 }
 
 // SwapResult contains the outcome of a capacity swap preparation.
@@ -76,12 +97,17 @@ type SwapResult struct {
 	// Ready indicates replacement capacity is available.
 	Ready bool
 
-	// ReplacementNodeName is the name of the new node (for ASG swaps).
-	// Empty for Karpenter (Karpenter provisions asynchronously after drain).
+	// ReplacementNodeName is the name of the new node (for ASG swaps, and
+	// Karpenter swaps that wait for a NodeClaim). Otherwise empty for
+	// Karpenter (Karpenter provisions asynchronously after drain).
 	ReplacementNodeName string
 
 	// Duration is how long the preparation took.
 	Duration time.Duration
+
+	// SkipDrain means the manager replaces the pool's nodes itself (an
+	// instance refresh), so they should not also be drained.
+	SkipDrain bool
 }
 
 // CapacityManager provides a unified interface for managing node capacity
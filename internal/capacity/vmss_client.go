@@ -0,0 +1,161 @@
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ScaleSetInfo describes an AKS node pool (a VM Scale Set) discovered for
+// SpotVortex management.
+type ScaleSetInfo struct {
+	// Name is the AKS node pool name (kubernetes.azure.com/agentpool).
+	Name string
+
+	// Pool is the workload pool name (from the spotvortex.io/pool tag).
+	Pool string
+
+	// CapacityType is "spot" or "on-demand" (AKS "Spot" / "Regular" priority).
+	CapacityType string
+
+	// Count is the node pool's current node count.
+	Count int32
+
+	// MaxCount is the upper bound SpotVortex may scale to. Zero means unbounded.
+	MaxCount int32
+}
+
+// VMSSClient abstracts AKS node pool scaling.
+// This interface enables testing with a fake client in Kind clusters.
+type VMSSClient interface {
+	// DiscoverTwinScaleSets finds paired Spot/Regular node pools for a workload pool.
+	// Discovery uses the spotvortex.io/pool node pool tag; capacity type comes
+	// from the node pool's scale set priority.
+	DiscoverTwinScaleSets(ctx context.Context, pool string) (spot *ScaleSetInfo, regular *ScaleSetInfo, err error)
+
+	// SetNodeCount updates the node count of a node pool.
+	SetNodeCount(ctx context.Context, name string, count int32) error
+
+	// DeleteNode removes a specific node's VM from its node pool and
+	// decrements the pool's node count.
+	DeleteNode(ctx context.Context, name string, nodeName string) error
+}
+
+// FakeVMSSClient implements VMSSClient for testing in Kind clusters.
+// It simulates twin node pool discovery and scaling operations in memory.
+type FakeVMSSClient struct {
+	mu        sync.Mutex
+	scaleSets map[string]*ScaleSetInfo // name -> info
+
+	// ScaleCalls tracks calls to SetNodeCount for assertions.
+	ScaleCalls []fakeScaleCall
+	// DeleteCalls tracks calls to DeleteNode for assertions.
+	DeleteCalls []fakeDeleteNodeCall
+}
+
+type fakeDeleteNodeCall struct {
+	Name     string
+	NodeName string
+}
+
+// NewFakeVMSSClient creates an empty fake VMSS client.
+func NewFakeVMSSClient() *FakeVMSSClient {
+	return &FakeVMSSClient{
+		scaleSets: make(map[string]*ScaleSetInfo),
+	}
+}
+
+// AddTwinPair registers a spot/regular node pool pair for a workload pool.
+// Node pools are named "<pool>spot" and "<pool>reg" (AKS pool names are
+// lowercase alphanumeric).
+func (f *FakeVMSSClient) AddTwinPair(pool string, spotCount, regularCount int32) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	spotName := pool + "spot"
+	regName := pool + "reg"
+
+	f.scaleSets[spotName] = &ScaleSetInfo{
+		Name:         spotName,
+		Pool:         pool,
+		CapacityType: "spot",
+		Count:        spotCount,
+		MaxCount:     spotCount + 5,
+	}
+	f.scaleSets[regName] = &ScaleSetInfo{
+		Name:         regName,
+		Pool:         pool,
+		CapacityType: "on-demand",
+		Count:        regularCount,
+		MaxCount:     regularCount + 5,
+	}
+}
+
+func (f *FakeVMSSClient) DiscoverTwinScaleSets(ctx context.Context, pool string) (*ScaleSetInfo, *ScaleSetInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	spot, spotOK := f.scaleSets[pool+"spot"]
+	reg, regOK := f.scaleSets[pool+"reg"]
+	if !spotOK || !regOK {
+		return nil, nil, fmt.Errorf("twin node pool pair not found for pool %q", pool)
+	}
+
+	// Return copies to avoid data races
+	spotCopy := *spot
+	regCopy := *reg
+	return &spotCopy, &regCopy, nil
+}
+
+func (f *FakeVMSSClient) SetNodeCount(ctx context.Context, name string, count int32) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ss, ok := f.scaleSets[name]
+	if !ok {
+		return fmt.Errorf("node pool %q not found", name)
+	}
+	if ss.MaxCount > 0 && count > ss.MaxCount {
+		return fmt.Errorf("count %d exceeds max %d for node pool %q", count, ss.MaxCount, name)
+	}
+	ss.Count = count
+
+	f.ScaleCalls = append(f.ScaleCalls, fakeScaleCall{
+		ASGID:   name,
+		Desired: count,
+	})
+	return nil
+}
+
+func (f *FakeVMSSClient) DeleteNode(ctx context.Context, name string, nodeName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ss, ok := f.scaleSets[name]
+	if !ok {
+		return fmt.Errorf("node pool %q not found", name)
+	}
+	if ss.Count > 0 {
+		ss.Count--
+	}
+
+	f.DeleteCalls = append(f.DeleteCalls, fakeDeleteNodeCall{
+		Name:     name,
+		NodeName: nodeName,
+	})
+	return nil
+}
+
+// GetScaleSet returns the current state of a node pool (for test assertions).
+func (f *FakeVMSSClient) GetScaleSet(name string) *ScaleSetInfo {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if ss, ok := f.scaleSets[name]; ok {
+		copy := *ss
+		return &copy
+	}
+	return nil
+}
+
+// Compile-time interface check.
+var _ VMSSClient = (*FakeVMSSClient)(nil)
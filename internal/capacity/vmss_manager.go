@@ -0,0 +1,178 @@
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// VMSSManager implements CapacityManager for AKS VMSS-backed node pools.
+//
+// Swap strategy mirrors the Twin ASG model: each workload pool has a Spot
+// node pool and a Regular node pool, tagged with the same spotvortex.io/pool.
+//  1. PrepareSwap: Scale up the twin node pool, wait for the new node to become Ready.
+//  2. Drain proceeds normally via controller.
+//  3. PostDrainCleanup: Delete the drained node's VM from its node pool.
+//
+// Twin pools must not have the AKS cluster autoscaler enabled; AKS rejects
+// manual count changes on autoscaled pools.
+type VMSSManager struct {
+	vmssClient VMSSClient
+	k8sClient  kubernetes.Interface
+	logger     *slog.Logger
+
+	// Config
+	nodeReadyTimeout time.Duration
+	pollInterval     time.Duration
+}
+
+// VMSSManagerConfig configures the AKS node pool capacity manager.
+type VMSSManagerConfig struct {
+	VMSSClient       VMSSClient
+	K8sClient        kubernetes.Interface
+	Logger           *slog.Logger
+	NodeReadyTimeout time.Duration
+	PollInterval     time.Duration
+}
+
+// NewVMSSManager creates a new AKS node pool capacity manager.
+func NewVMSSManager(cfg VMSSManagerConfig) *VMSSManager {
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if cfg.NodeReadyTimeout <= 0 {
+		cfg.NodeReadyTimeout = 5 * time.Minute
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 10 * time.Second
+	}
+
+	return &VMSSManager{
+		vmssClient:       cfg.VMSSClient,
+		k8sClient:        cfg.K8sClient,
+		logger:           cfg.Logger,
+		nodeReadyTimeout: cfg.NodeReadyTimeout,
+		pollInterval:     cfg.PollInterval,
+	}
+}
+
+func (m *VMSSManager) Type() ManagerType {
+	return ManagerAKSNodePool
+}
+
+// PrepareSwap scales the twin node pool for the target direction up by one
+// node and waits for it to become Ready. On timeout the scale-up is rolled
+// back and the drain is aborted.
+func (m *VMSSManager) PrepareSwap(ctx context.Context, pool PoolInfo, direction SwapDirection) (*SwapResult, error) {
+	start := time.Now()
+
+	if m.vmssClient == nil {
+		return nil, fmt.Errorf("VMSS client not configured")
+	}
+
+	spotPool, regularPool, err := m.vmssClient.DiscoverTwinScaleSets(ctx, pool.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover twin node pools for pool %q: %w", pool.Name, err)
+	}
+
+	var target *ScaleSetInfo
+	switch direction {
+	case SwapToOnDemand:
+		target = regularPool
+	case SwapToSpot:
+		target = spotPool
+	default:
+		return nil, fmt.Errorf("unknown swap direction: %d", direction)
+	}
+
+	newCount := target.Count + 1
+	if target.MaxCount > 0 && newCount > target.MaxCount {
+		return nil, fmt.Errorf("node pool %q is at its max count %d", target.Name, target.MaxCount)
+	}
+
+	m.logger.Info("preparing AKS node pool swap",
+		"pool", pool.Name,
+		"direction", direction.String(),
+		"target_node_pool", target.Name,
+		"current_count", target.Count,
+		"new_count", newCount,
+	)
+
+	if err := m.vmssClient.SetNodeCount(ctx, target.Name, newCount); err != nil {
+		return nil, fmt.Errorf("failed to scale up node pool %q: %w", target.Name, err)
+	}
+
+	nodeName, err := waitForReplacementNode(ctx, m.k8sClient, m.logger, pool, direction, m.nodeReadyTimeout, m.pollInterval)
+	if err != nil {
+		m.logger.Warn("new node did not become Ready, aborting swap",
+			"pool", pool.Name,
+			"target_node_pool", target.Name,
+			"error", err,
+		)
+		if rollbackErr := m.vmssClient.SetNodeCount(ctx, target.Name, target.Count); rollbackErr != nil {
+			m.logger.Error("failed to rollback node pool scale-up",
+				"node_pool", target.Name,
+				"error", rollbackErr,
+			)
+		}
+		return nil, fmt.Errorf("timeout waiting for replacement node: %w", err)
+	}
+
+	m.logger.Info("replacement node ready",
+		"pool", pool.Name,
+		"replacement_node", nodeName,
+		"duration", time.Since(start),
+	)
+
+	return &SwapResult{
+		Ready:               true,
+		ReplacementNodeName: nodeName,
+		Duration:            time.Since(start),
+	}, nil
+}
+
+// PostDrainCleanup deletes the drained node's VM from the node pool named by
+// its kubernetes.azure.com/agentpool label, which also decrements the pool's
+// count so AKS does not replace it.
+func (m *VMSSManager) PostDrainCleanup(ctx context.Context, nodeName string, pool PoolInfo) error {
+	if m.vmssClient == nil {
+		m.logger.Debug("no VMSS client, skipping post-drain cleanup", "node", nodeName)
+		return nil
+	}
+	if m.k8sClient == nil {
+		m.logger.Debug("no k8s client, skipping post-drain cleanup", "node", nodeName)
+		return nil
+	}
+
+	node, err := m.k8sClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch drained node %q for cleanup: %w", nodeName, err)
+	}
+	nodePool := node.Labels[LabelAKSAgentPool]
+	if nodePool == "" {
+		return fmt.Errorf("node %q has no %s label", nodeName, LabelAKSAgentPool)
+	}
+
+	if err := m.vmssClient.DeleteNode(ctx, nodePool, nodeName); err != nil {
+		return fmt.Errorf("failed to delete node %q from node pool %q: %w", nodeName, nodePool, err)
+	}
+
+	m.logger.Info("post-drain cleanup complete",
+		"node", nodeName,
+		"pool", pool.Name,
+		"node_pool", nodePool,
+		"manager", ManagerAKSNodePool,
+	)
+	return nil
+}
+
+func (m *VMSSManager) IsAvailable(ctx context.Context) bool {
+	return m.vmssClient != nil
+}
+
+// Compile-time interface check.
+var _ CapacityManager = (*VMSSManager)(nil)
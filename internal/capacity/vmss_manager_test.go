@@ -0,0 +1,345 @@
+package capacity
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestVMSSManager_PrepareSwap_ToOnDemand(t *testing.T) {
+	client := NewFakeVMSSClient()
+	client.AddTwinPair("api", 3, 1)
+
+	mgr := NewVMSSManager(VMSSManagerConfig{
+		VMSSClient:       client,
+		Logger:           slog.Default(),
+		NodeReadyTimeout: 2 * time.Second,
+		PollInterval:     100 * time.Millisecond,
+	})
+	if mgr.Type() != ManagerAKSNodePool {
+		t.Errorf("Type() = %q, want %q", mgr.Type(), ManagerAKSNodePool)
+	}
+
+	result, err := mgr.PrepareSwap(context.Background(), PoolInfo{Name: "api", Zone: "eastus-1"}, SwapToOnDemand)
+	if err != nil {
+		t.Fatalf("PrepareSwap: %v", err)
+	}
+	if !result.Ready {
+		t.Error("expected Ready=true")
+	}
+	if got := client.GetScaleSet("apireg").Count; got != 2 {
+		t.Errorf("regular count=%d, want 2", got)
+	}
+	if got := client.GetScaleSet("apispot").Count; got != 3 {
+		t.Errorf("spot count=%d, want 3 (unchanged)", got)
+	}
+}
+
+func TestVMSSManager_PrepareSwap_AtMaxCount(t *testing.T) {
+	client := NewFakeVMSSClient()
+	client.AddTwinPair("api", 5, 0) // spot max = 10
+
+	mgr := NewVMSSManager(VMSSManagerConfig{VMSSClient: client})
+	if err := client.SetNodeCount(context.Background(), "apispot", 10); err != nil {
+		t.Fatalf("SetNodeCount: %v", err)
+	}
+
+	if _, err := mgr.PrepareSwap(context.Background(), PoolInfo{Name: "api"}, SwapToSpot); err == nil {
+		t.Fatal("expected error when node pool is at max count")
+	}
+}
+
+func TestVMSSManager_PrepareSwap_TimeoutRollsBack(t *testing.T) {
+	client := NewFakeVMSSClient()
+	client.AddTwinPair("api", 3, 1)
+
+	mgr := NewVMSSManager(VMSSManagerConfig{
+		VMSSClient:       client,
+		K8sClient:        k8sfake.NewSimpleClientset(),
+		NodeReadyTimeout: 200 * time.Millisecond,
+		PollInterval:     50 * time.Millisecond,
+	})
+
+	if _, err := mgr.PrepareSwap(context.Background(), PoolInfo{Name: "api"}, SwapToOnDemand); err == nil {
+		t.Fatal("expected timeout error")
+	}
+	if got := client.GetScaleSet("apireg").Count; got != 1 {
+		t.Errorf("regular count=%d, want 1 after rollback", got)
+	}
+}
+
+func TestVMSSManager_WaitForAKSNode(t *testing.T) {
+	client := NewFakeVMSSClient()
+	client.AddTwinPair("api", 3, 1)
+	k8s := k8sfake.NewSimpleClientset()
+
+	mgr := NewVMSSManager(VMSSManagerConfig{
+		VMSSClient:       client,
+		K8sClient:        k8s,
+		NodeReadyTimeout: 2 * time.Second,
+		PollInterval:     50 * time.Millisecond,
+	})
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_, _ = k8s.CoreV1().Nodes().Create(context.Background(), &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "aks-apireg-12345-vmss000001",
+				Labels: map[string]string{
+					"spotvortex.io/pool":     "api",
+					LabelAKSAgentPool:        "apireg",
+					LabelAKSScaleSetPriority: "regular",
+				},
+			},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			}},
+		}, metav1.CreateOptions{})
+	}()
+
+	result, err := mgr.PrepareSwap(context.Background(), PoolInfo{Name: "api"}, SwapToOnDemand)
+	if err != nil {
+		t.Fatalf("PrepareSwap: %v", err)
+	}
+	if result.ReplacementNodeName != "aks-apireg-12345-vmss000001" {
+		t.Errorf("replacement=%q", result.ReplacementNodeName)
+	}
+}
+
+func TestVMSSManager_PostDrainCleanup_DeletesFromSourcePool(t *testing.T) {
+	client := NewFakeVMSSClient()
+	client.AddTwinPair("api", 3, 1)
+	k8s := k8sfake.NewSimpleClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "aks-apispot-12345-vmss000000",
+			Labels: map[string]string{
+				LabelAKSAgentPool:        "apispot",
+				LabelAKSScaleSetPriority: "spot",
+			},
+		},
+	})
+
+	mgr := NewVMSSManager(VMSSManagerConfig{VMSSClient: client, K8sClient: k8s})
+	if err := mgr.PostDrainCleanup(context.Background(), "aks-apispot-12345-vmss000000", PoolInfo{Name: "api"}); err != nil {
+		t.Fatalf("PostDrainCleanup: %v", err)
+	}
+
+	if len(client.DeleteCalls) != 1 {
+		t.Fatalf("delete calls=%d, want 1", len(client.DeleteCalls))
+	}
+	if call := client.DeleteCalls[0]; call.Name != "apispot" || call.NodeName != "aks-apispot-12345-vmss000000" {
+		t.Errorf("delete call=%+v", call)
+	}
+	if got := client.GetScaleSet("apispot").Count; got != 2 {
+		t.Errorf("spot count=%d, want 2", got)
+	}
+}
+
+func TestVMSSManager_PostDrainCleanup_MissingAgentPoolLabel(t *testing.T) {
+	client := NewFakeVMSSClient()
+	client.AddTwinPair("api", 3, 1)
+	k8s := k8sfake.NewSimpleClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})
+
+	mgr := NewVMSSManager(VMSSManagerConfig{VMSSClient: client, K8sClient: k8s})
+	if err := mgr.PostDrainCleanup(context.Background(), "node-1", PoolInfo{Name: "api"}); err == nil {
+		t.Fatal("expected error for node without agentpool label")
+	}
+}
+
+type staticAzureToken string
+
+func (s staticAzureToken) Token(context.Context) (string, error) { return string(s), nil }
+
+// fakeARM serves the agentPools endpoints for two tagged pools and one
+// untagged system pool, paging the list across two responses.
+type fakeARM struct {
+	mu      sync.Mutex
+	pools   map[string]map[string]any
+	puts    []map[string]any
+	deletes []string
+}
+
+func newFakeARM() *fakeARM {
+	pool := func(name, priority string, count int, autoscale bool, tags map[string]any) map[string]any {
+		return map[string]any{
+			"id":   "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/aks/agentPools/" + name,
+			"name": name,
+			"properties": map[string]any{
+				"count":             count,
+				"maxCount":          10,
+				"enableAutoScaling": autoscale,
+				"scaleSetPriority":  priority,
+				"vmSize":            "Standard_D4s_v5",
+				"tags":              tags,
+			},
+		}
+	}
+	return &fakeARM{pools: map[string]map[string]any{
+		"system":  pool("system", "", 2, false, nil),
+		"apispot": pool("apispot", "Spot", 3, false, map[string]any{"spotvortex.io/pool": "api"}),
+		"apireg":  pool("apireg", "Regular", 1, false, map[string]any{"spotvortex.io/pool": "api"}),
+		"auto":    pool("auto", "Regular", 1, true, map[string]any{"spotvortex.io/pool": "batch"}),
+	}}
+}
+
+func (f *fakeARM) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer test-token" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	const prefix = "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/aks/agentPools"
+	path := strings.TrimPrefix(r.URL.Path, prefix)
+
+	switch {
+	case path == "" && r.Method == http.MethodGet:
+		var page map[string]any
+		if r.URL.Query().Get("page") == "2" {
+			page = map[string]any{"value": []any{f.pools["apireg"], f.pools["auto"]}}
+		} else {
+			page = map[string]any{
+				"value":    []any{f.pools["system"], f.pools["apispot"]},
+				"nextLink": "http://" + r.Host + prefix + "?api-version=" + aksAPIVersion + "&page=2",
+			}
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	case strings.HasSuffix(path, "/deleteMachines") && r.Method == http.MethodPost:
+		var body struct {
+			MachineNames []string `json:"machineNames"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.deletes = append(f.deletes, body.MachineNames...)
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		name := strings.TrimPrefix(path, "/")
+		pool, ok := f.pools[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(pool)
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			var body map[string]any
+			_ = json.Unmarshal(data, &body)
+			f.puts = append(f.puts, body)
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(data)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func newTestAzureVMSSClient(t *testing.T, arm *fakeARM) *AzureVMSSClient {
+	t.Helper()
+	srv := httptest.NewServer(arm)
+	t.Cleanup(srv.Close)
+
+	client, err := NewAzureVMSSClient(AzureVMSSClientConfig{
+		SubscriptionID: "sub",
+		ResourceGroup:  "rg",
+		ClusterName:    "aks",
+		BaseURL:        srv.URL,
+		TokenSource:    staticAzureToken("test-token"),
+	})
+	if err != nil {
+		t.Fatalf("NewAzureVMSSClient: %v", err)
+	}
+	return client
+}
+
+func TestAzureVMSSClient_DiscoverTwinScaleSets(t *testing.T) {
+	client := newTestAzureVMSSClient(t, newFakeARM())
+
+	spot, regular, err := client.DiscoverTwinScaleSets(context.Background(), "api")
+	if err != nil {
+		t.Fatalf("DiscoverTwinScaleSets: %v", err)
+	}
+	if spot.Name != "apispot" || spot.CapacityType != "spot" || spot.Count != 3 || spot.MaxCount != 10 {
+		t.Errorf("spot=%+v", spot)
+	}
+	if regular.Name != "apireg" || regular.CapacityType != "on-demand" || regular.Count != 1 {
+		t.Errorf("regular=%+v", regular)
+	}
+
+	if _, _, err := client.DiscoverTwinScaleSets(context.Background(), "batch"); err == nil {
+		t.Error("expected error for pool without a spot twin")
+	}
+}
+
+func TestAzureVMSSClient_SetNodeCount(t *testing.T) {
+	arm := newFakeARM()
+	client := newTestAzureVMSSClient(t, arm)
+
+	if err := client.SetNodeCount(context.Background(), "apireg", 2); err != nil {
+		t.Fatalf("SetNodeCount: %v", err)
+	}
+	if len(arm.puts) != 1 {
+		t.Fatalf("puts=%d, want 1", len(arm.puts))
+	}
+	props := arm.puts[0]["properties"].(map[string]any)
+	if props["count"].(float64) != 2 {
+		t.Errorf("count=%v, want 2", props["count"])
+	}
+	if props["vmSize"] != "Standard_D4s_v5" {
+		t.Errorf("PUT dropped existing properties: %v", props)
+	}
+	if _, ok := arm.puts[0]["id"]; ok {
+		t.Error("PUT should not echo read-only id")
+	}
+
+	if err := client.SetNodeCount(context.Background(), "auto", 2); err == nil {
+		t.Error("expected error for autoscaled node pool")
+	}
+}
+
+func TestAzureVMSSClient_DeleteNode(t *testing.T) {
+	arm := newFakeARM()
+	client := newTestAzureVMSSClient(t, arm)
+
+	if err := client.DeleteNode(context.Background(), "apispot", "aks-apispot-12345-vmss000000"); err != nil {
+		t.Fatalf("DeleteNode: %v", err)
+	}
+	if len(arm.deletes) != 1 || arm.deletes[0] != "aks-apispot-12345-vmss000000" {
+		t.Errorf("deletes=%v", arm.deletes)
+	}
+}
+
+func TestFetchAzureToken_ExpiresInStringOrNumber(t *testing.T) {
+	for _, body := range []string{
+		`{"access_token":"tok","expires_in":"3599"}`,
+		`{"access_token":"tok","expires_in":3599}`,
+	} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, body)
+		}))
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		token, expires, err := fetchAzureToken(srv.Client(), req)
+		srv.Close()
+		if err != nil {
+			t.Fatalf("fetchAzureToken(%s): %v", body, err)
+		}
+		if token != "tok" {
+			t.Errorf("token=%q", token)
+		}
+		if d := time.Until(expires); d < 59*time.Minute || d > time.Hour {
+			t.Errorf("expires in %v, want ~1h", d)
+		}
+	}
+}
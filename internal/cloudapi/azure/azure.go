@@ -0,0 +1,314 @@
+// Package azure provides Azure Spot VM pricing from the public Retail Prices
+// API (https://prices.azure.com/api/retail/prices). The API is anonymous, so
+// no credentials are needed for pricing.
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// CacheTTL is the duration to cache spot prices (per phase.md: 5 minutes)
+	CacheTTL = 5 * time.Minute
+
+	// HistorySteps is the number of 5-minute steps to keep (24 = 2 hours)
+	HistorySteps = 24
+
+	// DefaultRetailPricesURL is the Azure Retail Prices API endpoint.
+	DefaultRetailPricesURL = "https://prices.azure.com/api/retail/prices"
+
+	// maxRetailPages bounds pagination for a single SKU query.
+	maxRetailPages = 10
+)
+
+// SpotPriceData contains current and observed spot price information.
+type SpotPriceData struct {
+	CurrentPrice  float64
+	OnDemandPrice float64
+	PriceHistory  []float64 // Last 24 observations, padded with the current price
+	Volatility    float64   // Rolling std dev
+	LastUpdated   time.Time
+	VMSize        string
+	Zone          string
+}
+
+// ClientConfig configures the Azure price client.
+type ClientConfig struct {
+	// Region is the ARM region (e.g. "eastus") used when a zone does not
+	// carry one. AKS zones look like "eastus-1"; non-zonal nodes report "0".
+	Region string
+
+	// BaseURL overrides the Retail Prices endpoint (tests, proxies).
+	BaseURL string
+
+	// HTTPClient overrides the HTTP client. Default: 10s timeout.
+	HTTPClient *http.Client
+
+	Logger *slog.Logger
+}
+
+// PriceClient provides Azure Spot and pay-as-you-go Linux VM prices.
+type PriceClient struct {
+	baseURL    string
+	httpClient *http.Client
+	logger     *slog.Logger
+	region     string
+
+	mu      sync.RWMutex
+	cache   map[string]*SpotPriceData // key: vmSize:region
+	history map[string][]float64      // key: vmSize:region
+}
+
+// NewPriceClient creates a new Azure price client.
+func NewPriceClient(cfg ClientConfig) (*PriceClient, error) {
+	if strings.TrimSpace(cfg.Region) == "" {
+		return nil, fmt.Errorf("azure region is required")
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultRetailPricesURL
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &PriceClient{
+		baseURL:    cfg.BaseURL,
+		httpClient: cfg.HTTPClient,
+		logger:     cfg.Logger,
+		region:     strings.ToLower(strings.TrimSpace(cfg.Region)),
+		cache:      make(map[string]*SpotPriceData),
+		history:    make(map[string][]float64),
+	}, nil
+}
+
+// retailPriceItem is the subset of a Retail Prices API item we use.
+type retailPriceItem struct {
+	CurrencyCode  string  `json:"currencyCode"`
+	RetailPrice   float64 `json:"retailPrice"`
+	ArmRegionName string  `json:"armRegionName"`
+	ArmSkuName    string  `json:"armSkuName"`
+	SkuName       string  `json:"skuName"`
+	MeterName     string  `json:"meterName"`
+	ProductName   string  `json:"productName"`
+	Type          string  `json:"type"`
+	UnitOfMeasure string  `json:"unitOfMeasure"`
+}
+
+type retailPricePage struct {
+	Items        []retailPriceItem `json:"Items"`
+	NextPageLink string            `json:"NextPageLink"`
+}
+
+// GetSpotPrice returns Spot price data for the given VM size and zone.
+// Uses 5-minute TTL cache per phase.md specification.
+func (c *PriceClient) GetSpotPrice(ctx context.Context, vmSize, zone string) (*SpotPriceData, error) {
+	region := c.RegionForZone(zone)
+	cacheKey := vmSize + ":" + region
+
+	c.mu.RLock()
+	if cached, ok := c.cache[cacheKey]; ok {
+		if time.Since(cached.LastUpdated) < CacheTTL {
+			c.mu.RUnlock()
+			out := *cached
+			out.Zone = zone
+			return &out, nil
+		}
+	}
+	c.mu.RUnlock()
+
+	c.logger.Debug("fetching spot price from Azure Retail Prices API",
+		"vm_size", vmSize,
+		"region", region,
+	)
+
+	spot, onDemand, err := c.fetchPrices(ctx, vmSize, region)
+	if err != nil {
+		return nil, err
+	}
+	if spot <= 0 {
+		return nil, fmt.Errorf("no spot price published for %s in %s", vmSize, region)
+	}
+
+	c.mu.Lock()
+	observed := append(c.history[cacheKey], spot)
+	if len(observed) > HistorySteps {
+		observed = observed[len(observed)-HistorySteps:]
+	}
+	c.history[cacheKey] = observed
+	history := padHistory(observed)
+	data := &SpotPriceData{
+		CurrentPrice:  spot,
+		OnDemandPrice: onDemand,
+		PriceHistory:  history,
+		Volatility:    calculateVolatility(history),
+		LastUpdated:   time.Now(),
+		VMSize:        vmSize,
+		Zone:          zone,
+	}
+	c.cache[cacheKey] = data
+	c.mu.Unlock()
+
+	c.logger.Info("azure spot price updated",
+		"vm_size", vmSize,
+		"region", region,
+		"current_price", spot,
+		"ondemand_price", onDemand,
+		"volatility", data.Volatility,
+	)
+
+	out := *data
+	return &out, nil
+}
+
+// GetOnDemandPrice returns the pay-as-you-go Linux price for a VM size.
+func (c *PriceClient) GetOnDemandPrice(ctx context.Context, vmSize, zone string) (float64, error) {
+	data, err := c.GetSpotPrice(ctx, vmSize, zone)
+	if err != nil {
+		return 0, err
+	}
+	if data.OnDemandPrice <= 0 {
+		return 0, fmt.Errorf("no pay-as-you-go price published for %s in %s", vmSize, c.RegionForZone(zone))
+	}
+	return data.OnDemandPrice, nil
+}
+
+// RegionForZone maps an AKS zone label to its ARM region. Zonal AKS nodes
+// report "<region>-<n>"; non-zonal nodes report "0" and use the configured
+// region.
+func (c *PriceClient) RegionForZone(zone string) string {
+	zone = strings.ToLower(strings.TrimSpace(zone))
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		return zone[:i]
+	}
+	return c.region
+}
+
+// fetchPrices queries all Linux consumption meters for a VM size and picks
+// the Spot and regular (pay-as-you-go) prices.
+func (c *PriceClient) fetchPrices(ctx context.Context, vmSize, region string) (spot, onDemand float64, err error) {
+	filter := fmt.Sprintf(
+		"serviceName eq 'Virtual Machines' and priceType eq 'Consumption' and armRegionName eq '%s' and armSkuName eq '%s'",
+		region, vmSize,
+	)
+	next := c.baseURL + "?$filter=" + url.QueryEscape(filter)
+
+	for page := 0; next != "" && page < maxRetailPages; page++ {
+		body, err := c.get(ctx, next)
+		if err != nil {
+			return 0, 0, err
+		}
+		var p retailPricePage
+		if err := json.Unmarshal(body, &p); err != nil {
+			return 0, 0, fmt.Errorf("failed to decode retail prices: %w", err)
+		}
+		for _, item := range p.Items {
+			if !isLinuxHourlyMeter(item) {
+				continue
+			}
+			switch {
+			case isSpotMeter(item):
+				if spot == 0 || item.RetailPrice < spot {
+					spot = item.RetailPrice
+				}
+			case isLowPriorityMeter(item):
+				// Legacy low-priority meters are not Spot pricing.
+			default:
+				if onDemand == 0 || item.RetailPrice < onDemand {
+					onDemand = item.RetailPrice
+				}
+			}
+		}
+		next = p.NextPageLink
+	}
+	return spot, onDemand, nil
+}
+
+func (c *PriceClient) get(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build retail prices request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query retail prices: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read retail prices: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("retail prices API returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+func isLinuxHourlyMeter(item retailPriceItem) bool {
+	if item.RetailPrice <= 0 || !strings.EqualFold(item.Type, "Consumption") {
+		return false
+	}
+	if item.CurrencyCode != "" && !strings.EqualFold(item.CurrencyCode, "USD") {
+		return false
+	}
+	if !strings.EqualFold(item.UnitOfMeasure, "1 Hour") {
+		return false
+	}
+	return !strings.Contains(strings.ToLower(item.ProductName), "windows")
+}
+
+func isSpotMeter(item retailPriceItem) bool {
+	return strings.HasSuffix(item.SkuName, " Spot") || strings.HasSuffix(item.MeterName, " Spot")
+}
+
+func isLowPriorityMeter(item retailPriceItem) bool {
+	return strings.HasSuffix(item.SkuName, " Low Priority") || strings.HasSuffix(item.MeterName, " Low Priority")
+}
+
+// padHistory left-pads observed prices with the oldest observation so the
+// TFT always sees HistorySteps values; the current price is the last entry.
+func padHistory(observed []float64) []float64 {
+	history := make([]float64, HistorySteps)
+	offset := HistorySteps - len(observed)
+	for i := range history {
+		if i < offset {
+			history[i] = observed[0]
+		} else {
+			history[i] = observed[i-offset]
+		}
+	}
+	return history
+}
+
+// calculateVolatility computes rolling standard deviation of prices.
+func calculateVolatility(prices []float64) float64 {
+	if len(prices) < 2 {
+		return 0
+	}
+
+	var sum float64
+	for _, p := range prices {
+		sum += p
+	}
+	mean := sum / float64(len(prices))
+
+	var variance float64
+	for _, p := range prices {
+		diff := p - mean
+		variance += diff * diff
+	}
+	variance /= float64(len(prices) - 1)
+
+	return math.Sqrt(variance)
+}
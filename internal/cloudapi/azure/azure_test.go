@@ -0,0 +1,97 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newRecordedRetailPricesServer replays two pages recorded from the Retail
+// Prices API for Standard_D4s_v3 in eastus.
+func newRecordedRetailPricesServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		fixture := "testdata/retail_prices_page1.json"
+		if r.URL.Query().Get("page") == "2" {
+			fixture = "testdata/retail_prices_page2.json"
+		} else if filter := r.URL.Query().Get("$filter"); !strings.Contains(filter, "armSkuName eq 'Standard_D4s_v3'") ||
+			!strings.Contains(filter, "armRegionName eq 'eastus'") {
+			_, _ = w.Write([]byte(`{"Items":[],"NextPageLink":null}`))
+			return
+		}
+		data, err := os.ReadFile(fixture)
+		if err != nil {
+			t.Errorf("read fixture: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(strings.ReplaceAll(string(data), "{{SERVER}}", srv.URL)))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestGetSpotPrice_RecordedFixture(t *testing.T) {
+	srv, calls := newRecordedRetailPricesServer(t)
+	client, err := NewPriceClient(ClientConfig{Region: "eastus", BaseURL: srv.URL + "/api/retail/prices"})
+	if err != nil {
+		t.Fatalf("NewPriceClient: %v", err)
+	}
+
+	data, err := client.GetSpotPrice(context.Background(), "Standard_D4s_v3", "eastus-1")
+	if err != nil {
+		t.Fatalf("GetSpotPrice: %v", err)
+	}
+	// Linux Spot and pay-as-you-go meters; Windows and Low Priority are ignored.
+	if data.CurrentPrice != 0.0384 || data.OnDemandPrice != 0.192 {
+		t.Fatalf("unexpected prices: spot=%v od=%v", data.CurrentPrice, data.OnDemandPrice)
+	}
+	if len(data.PriceHistory) != HistorySteps || data.PriceHistory[HistorySteps-1] != 0.0384 {
+		t.Fatalf("unexpected history: %v", data.PriceHistory)
+	}
+	if data.Zone != "eastus-1" {
+		t.Fatalf("zone=%q", data.Zone)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected both pages fetched, got %d calls", got)
+	}
+
+	// Served from cache, including for another zone in the same region.
+	if _, err := client.GetOnDemandPrice(context.Background(), "Standard_D4s_v3", "eastus-2"); err != nil {
+		t.Fatalf("GetOnDemandPrice: %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected cached result, got %d calls", got)
+	}
+}
+
+func TestGetSpotPrice_NoSpotMeter(t *testing.T) {
+	srv, _ := newRecordedRetailPricesServer(t)
+	client, _ := NewPriceClient(ClientConfig{Region: "eastus", BaseURL: srv.URL})
+	if _, err := client.GetSpotPrice(context.Background(), "Standard_M416ms_v2", "0"); err == nil {
+		t.Fatal("expected error when no spot meter is published")
+	}
+}
+
+func TestRegionForZone(t *testing.T) {
+	client, _ := NewPriceClient(ClientConfig{Region: "westeurope"})
+	for zone, want := range map[string]string{
+		"eastus-1":       "eastus",
+		"westeurope-3":   "westeurope",
+		"0":              "westeurope",
+		"":               "westeurope",
+		"southcentralus": "westeurope",
+	} {
+		if got := client.RegionForZone(zone); got != want {
+			t.Errorf("RegionForZone(%q)=%q, want %q", zone, got, want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ package cloudapi
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"os"
 	"strings"
@@ -150,6 +151,31 @@ func checkAzureIMDS(ctx context.Context, client *http.Client) bool {
 	return resp.StatusCode == http.StatusOK
 }
 
+// azureIMDSLocation returns the VM's region from Azure IMDS, or "" when
+// IMDS is unreachable.
+func azureIMDSLocation(ctx context.Context) string {
+	client := &http.Client{Timeout: 2 * time.Second}
+	req, err := http.NewRequestWithContext(ctx, "GET", azureIMDSEndpoint+"/compute/location?api-version=2021-02-01&format=text", nil)
+	if err != nil {
+		return ""
+	}
+	req.Header.Set("Metadata", "true")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(body))
+}
+
 // DetectCloudFromNodeLabels detects cloud from Kubernetes node labels.
 // Common patterns:
 // - AWS: topology.kubernetes.io/region starts with "us-east-", "eu-west-", etc.
@@ -8,6 +8,7 @@ import (
 	"os"
 
 	"github.com/softcane/spot-vortex-agent/internal/cloudapi/aws"
+	"github.com/softcane/spot-vortex-agent/internal/cloudapi/azure"
 	"github.com/softcane/spot-vortex-agent/internal/cloudapi/gcp"
 )
 
@@ -51,6 +52,13 @@ func NewAutoDetectedPriceProvider(ctx context.Context, logger *slog.Logger) (Pri
 		}
 		return &gcpPriceProviderAdapter{client: client}, cloud, nil
 
+	case CloudTypeAzure:
+		provider, err := NewAzurePriceProvider(getAzureRegion(ctx), logger)
+		if err != nil {
+			return nil, cloud, fmt.Errorf("failed to create Azure price client: %w", err)
+		}
+		return provider, cloud, nil
+
 	default:
 		return nil, cloud, fmt.Errorf("unsupported cloud: %s", cloud)
 	}
@@ -74,6 +82,16 @@ func NewGCPPriceProvider(ctx context.Context, project string, logger *slog.Logge
 	return &gcpPriceProviderAdapter{client: client}, nil
 }
 
+// NewAzurePriceProvider creates an Azure Retail Prices-backed provider for a
+// specific region.
+func NewAzurePriceProvider(region string, logger *slog.Logger) (PriceProvider, error) {
+	client, err := azure.NewPriceClient(azure.ClientConfig{Region: region, Logger: logger})
+	if err != nil {
+		return nil, err
+	}
+	return &azurePriceProviderAdapter{client: client}, nil
+}
+
 // getAWSRegion returns the AWS region from environment or default.
 func getAWSRegion() string {
 	if region := os.Getenv("AWS_REGION"); region != "" {
@@ -96,6 +114,21 @@ func getGCPProject() string {
 	return ""
 }
 
+// getAzureRegion returns the Azure region from environment, then the
+// instance metadata service, then a default.
+func getAzureRegion(ctx context.Context) string {
+	if region := os.Getenv("AZURE_LOCATION"); region != "" {
+		return region
+	}
+	if region := os.Getenv("AZURE_REGION"); region != "" {
+		return region
+	}
+	if region := azureIMDSLocation(ctx); region != "" {
+		return region
+	}
+	return "eastus"
+}
+
 // awsPriceProviderAdapter adapts AWS PriceClient to PriceProvider.
 type awsPriceProviderAdapter struct {
 	client *aws.PriceClient
@@ -143,3 +176,27 @@ func (g *gcpPriceProviderAdapter) GetSpotPrice(ctx context.Context, machineType,
 func (g *gcpPriceProviderAdapter) GetOnDemandPrice(ctx context.Context, machineType, zone string) (float64, error) {
 	return g.client.GetOnDemandPrice(ctx, machineType, zone)
 }
+
+// azurePriceProviderAdapter adapts Azure PriceClient to PriceProvider.
+type azurePriceProviderAdapter struct {
+	client *azure.PriceClient
+}
+
+func (a *azurePriceProviderAdapter) GetSpotPrice(ctx context.Context, vmSize, zone string) (SpotPriceData, error) {
+	data, err := a.client.GetSpotPrice(ctx, vmSize, zone)
+	if err != nil {
+		return SpotPriceData{}, err
+	}
+	return SpotPriceData{
+		CurrentPrice:  data.CurrentPrice,
+		OnDemandPrice: data.OnDemandPrice,
+		PriceHistory:  data.PriceHistory,
+		Volatility:    data.Volatility,
+		InstanceType:  data.VMSize,
+		Zone:          data.Zone,
+	}, nil
+}
+
+func (a *azurePriceProviderAdapter) GetOnDemandPrice(ctx context.Context, vmSize, zone string) (float64, error) {
+	return a.client.GetOnDemandPrice(ctx, vmSize, zone)
+}
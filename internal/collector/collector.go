@@ -16,11 +16,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/softcane/spot-vortex-agent/internal/capacity"
 	"github.com/softcane/spot-vortex-agent/internal/config"
+	"github.com/softcane/spot-vortex-agent/internal/kubecache"
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -61,11 +62,21 @@ type UtilizationProvider interface {
 // Collector gathers local cluster metrics for RL state
 type Collector struct {
 	client   kubernetes.Interface
+	cache    *kubecache.Cache // Optional: informer cache instead of Lists
 	logger   *slog.Logger
-	utilProv UtilizationProvider // Optional: for cluster utilization data
+	utilProv UtilizationProvider  // Optional: for cluster utilization data
+	budgets  RecoveryBudgetSource // Optional: RecoveryBudget resources
+	restarts RestartTimeSource    // Optional: learned restart times
 
 	mu      sync.RWMutex
 	metrics LocalMetrics
+
+	// nodeMemo holds each node's evaluated pods between cache-backed ticks
+	// (guarded by mu). Informer handlers mark nodes and namespaces dirty.
+	nodeMemo        map[string]*nodeContribution
+	dirtyMu         sync.Mutex
+	dirtyNodes      map[string]struct{}
+	dirtyNamespaces map[string]struct{}
 }
 
 // NewCollector creates a new local metrics collector
@@ -77,6 +88,9 @@ func NewCollector(client kubernetes.Interface, logger *slog.Logger) *Collector {
 			PoolFeatures:      make(map[string]WorkloadFeatures),
 			PodStartupLatency: make(map[string]float64),
 		},
+		nodeMemo:        make(map[string]*nodeContribution),
+		dirtyNodes:      make(map[string]struct{}),
+		dirtyNamespaces: make(map[string]struct{}),
 	}
 }
 
@@ -86,33 +100,58 @@ func (c *Collector) SetUtilizationProvider(prov UtilizationProvider) {
 	c.utilProv = prov
 }
 
-// Collect gathers current cluster metrics
+// Collect gathers current cluster metrics.
+//
+// With a synced informer cache, nodes and pods are read from the cache and
+// per-node pod contributions are reused until an informer event marks the
+// node (or a namespace it hosts) dirty. Otherwise the cluster is listed.
 func (c *Collector) Collect(ctx context.Context) (*LocalMetrics, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	c.logger.Debug("collecting local metrics")
 
-	// 1. List Nodes to build Pool mapping
-	nodes, err := c.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	// 1-2. Nodes, and each node's evaluated pods
+	var (
+		nodes         []*corev1.Node
+		contributions map[string][]podContribution
+		err           error
+	)
+	if c.cache.HasSynced() {
+		nodes, contributions, err = c.readCache()
+	} else {
+		nodes, contributions, err = c.listCluster(ctx)
+	}
 	if err != nil {
-		c.logger.Error("failed to list nodes", "error", err)
 		return nil, err
 	}
+	contributions = c.withLearnedRestartTimes(contributions)
+
+	// 2.6 Fetch pool utilization from metrics provider (if available)
+	poolUtilization := make(map[string]float64)
+	if c.utilProv != nil {
+		if utils, err := c.utilProv.GetPoolUtilization(ctx); err == nil {
+			poolUtilization = utils
+			c.logger.Debug("fetched pool utilization", "pools", len(utils))
+		} else {
+			c.logger.Warn("failed to fetch pool utilization", "error", err)
+		}
+	}
 
+	// 3. Aggregate per Pool
 	nodeToPools := make(map[string][]string)
 	nodeIsSpot := make(map[string]bool)
 	groupZones := make(map[string]map[string]struct{})
 	poolStats := make(map[string]*poolAccumulator)
-	for _, node := range nodes.Items {
-		simplePoolID := GetNodePoolID(&node)
-		extendedPoolID := GetExtendedPoolID(&node)
+	for _, node := range nodes {
+		simplePoolID := GetNodePoolID(node)
+		extendedPoolID := GetExtendedPoolID(node)
 		poolKeys := []string{simplePoolID}
 		if extendedPoolID != simplePoolID {
 			poolKeys = append(poolKeys, extendedPoolID)
 		}
 		nodeToPools[node.Name] = poolKeys
-		nodeIsSpot[node.Name] = capacity.IsSpotNode(&node)
+		nodeIsSpot[node.Name] = capacity.IsSpotNode(node)
 
 		zone := node.Labels["topology.kubernetes.io/zone"]
 		if zone == "" {
@@ -133,10 +172,7 @@ func (c *Collector) Collect(ctx context.Context) (*LocalMetrics, error) {
 		for _, poolID := range poolKeys {
 			acc, ok := poolStats[poolID]
 			if !ok {
-				acc = &poolAccumulator{
-					pdbNodeCounts: make(map[string]map[string]int),
-					pdbSlack:      make(map[string]int32),
-				}
+				acc = newPoolAccumulator()
 				poolStats[poolID] = acc
 			}
 			acc.utilizationKey = simplePoolID
@@ -152,141 +188,28 @@ func (c *Collector) Collect(ctx context.Context) (*LocalMetrics, error) {
 			}
 			if nodeIsSpot[node.Name] {
 				acc.spotNodes++
+				acc.spotNodeNames = append(acc.spotNodeNames, node.Name)
 			} else {
 				acc.odNodes++
+				acc.odNodeNames = append(acc.odNodeNames, node.Name)
 			}
 		}
 	}
 
-	// 2. List Pods & PDBs
-	pods, err := c.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, err
-	}
-	pdbs, err := c.client.PolicyV1().PodDisruptionBudgets("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		c.logger.Warn("failed to list PDBs", "error", err)
-	}
-
-	// Map PDBs by namespace (simplified) or label selector (complex, skip for MVP)
-	// MVP: Check if namespace has any restricted PDB
-	nsRestricted := make(map[string]bool)
-	pdbsByNamespace := make(map[string][]compiledPDB)
-	if pdbs != nil {
-		for _, pdb := range pdbs.Items {
-			if pdb.Status.CurrentHealthy <= pdb.Status.DesiredHealthy {
-				nsRestricted[pdb.Namespace] = true
+	for _, node := range nodes {
+		poolIDs := nodeToPools[node.Name]
+		for _, pod := range contributions[node.Name] {
+			for _, poolID := range poolIDs {
+				poolStats[poolID].add(pod, nodeIsSpot[node.Name])
 			}
-			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
-			if err != nil {
-				continue
-			}
-			pdbsByNamespace[pdb.Namespace] = append(pdbsByNamespace[pdb.Namespace], compiledPDB{
-				key:                pdb.Namespace + "/" + pdb.Name,
-				disruptionsAllowed: pdb.Status.DisruptionsAllowed,
-				selector:           selector,
-			})
 		}
 	}
 
-	// 2.5 List ReplicaSets to determine redundancy
-	rss, err := c.client.AppsV1().ReplicaSets("").List(ctx, metav1.ListOptions{})
-	rsReplicas := make(map[string]int32)
-	if err == nil {
-		for _, rs := range rss.Items {
-			if rs.Spec.Replicas != nil {
-				rsReplicas[rs.Name] = *rs.Spec.Replicas
-			}
-		}
-	} else {
-		c.logger.Warn("failed to list ReplicaSets", "error", err)
-	}
+	// 3.5 Scheduler-aware On-Demand headroom, when nodes report allocatable
+	headroom, headroomOK := newHeadroomEstimator(nodes, nodeIsSpot, contributions)
 
-	// 2.6 Fetch pool utilization from metrics provider (if available)
-	poolUtilization := make(map[string]float64)
-	if c.utilProv != nil {
-		if utils, err := c.utilProv.GetPoolUtilization(ctx); err == nil {
-			poolUtilization = utils
-			c.logger.Debug("fetched pool utilization", "pools", len(utils))
-		} else {
-			c.logger.Warn("failed to fetch pool utilization", "error", err)
-		}
-	}
-
-	// 3. Aggregate per Pool
-	for _, pod := range pods.Items {
-		if pod.Spec.NodeName == "" {
-			continue
-		}
-		poolIDs, ok := nodeToPools[pod.Spec.NodeName]
-		if !ok {
-			continue // Pod on unknown node?
-		}
-
-		// Startup Time (annotation override supported)
-		latency := getStartupTimeWithOverride(&pod)
-		weight := getPodWeight(&pod)
-
-		// Outage Penalty (annotation override supported, weighted by CPU for inference, MAX for guardrails)
-		penalty := getOutagePenaltyWithOverride(&pod, nsRestricted[pod.Namespace], rsReplicas)
-
-		// Priority Score (P0=1.0, P1=0.75, P2=0.5, P3=0.25)
-		// weighted by CPU for inference, MAX for guardrails
-		pScore := getPriorityScore(&pod)
-		isCritical := isCriticalServicePod(&pod, pScore)
-		isStateful := isStatefulPod(&pod)
-		workloadRelevant := isWorkloadPod(&pod)
-		matchedPDB := matchPDBForPod(&pod, pdbsByNamespace[pod.Namespace])
-		evictable := workloadRelevant && (matchedPDB == nil || matchedPDB.disruptionsAllowed > 0)
-
-		for _, poolID := range poolIDs {
-			acc, exists := poolStats[poolID]
-			if !exists {
-				acc = &poolAccumulator{
-					pdbNodeCounts: make(map[string]map[string]int),
-					pdbSlack:      make(map[string]int32),
-				}
-				poolStats[poolID] = acc
-			}
-			if latency > 0 {
-				acc.latencies = append(acc.latencies, weightedValue{val: latency, weight: weight})
-			}
-			acc.penalties = append(acc.penalties, weightedValue{val: penalty, weight: weight})
-			if penalty > acc.maxPenalty {
-				acc.maxPenalty = penalty
-			}
-			acc.priorities = append(acc.priorities, weightedValue{val: pScore, weight: weight})
-			if pScore > acc.maxPriority {
-				acc.maxPriority = pScore
-			}
-			if pScore >= 1.0 {
-				acc.hasCriticalPod = true
-			}
-
-			if workloadRelevant {
-				acc.workloadPods++
-				if isStateful {
-					acc.statefulPods++
-				}
-				if evictable {
-					acc.evictablePods++
-				}
-				if isCritical {
-					acc.criticalPods++
-					if nodeIsSpot[pod.Spec.NodeName] {
-						acc.criticalOnSpot++
-					}
-				}
-			}
-			if matchedPDB != nil {
-				if _, ok := acc.pdbNodeCounts[matchedPDB.key]; !ok {
-					acc.pdbNodeCounts[matchedPDB.key] = make(map[string]int)
-				}
-				acc.pdbNodeCounts[matchedPDB.key][pod.Spec.NodeName]++
-				acc.pdbSlack[matchedPDB.key] = matchedPDB.disruptionsAllowed
-			}
-		}
-	}
+	// 3.6 Declared recovery budgets, grouped by the pools their replicas run in
+	budgeted := c.budgetedWorkloads(nodes, contributions, nodeToPools, nodeIsSpot)
 
 	// 4. Finalize Features
 	newFeatures := make(map[string]WorkloadFeatures)
@@ -328,7 +251,23 @@ func (c *Collector) Collect(ctx context.Context) (*LocalMetrics, error) {
 			util = u // Use cluster-wide default if available
 		}
 
-		poolSafety := computePoolSafetyVector(acc, util, len(groupZones[acc.groupKey]), p95)
+		var spotLoss *spotLossEstimate
+		if headroomOK {
+			est := headroom.estimate(acc.spotNodeNames, acc.odNodeNames)
+			spotLoss = &est
+			metrics.PoolSpotLossPendingPods.WithLabelValues(poolID, "1").Set(float64(est.pendingOne))
+			metrics.PoolSpotLossPendingPods.WithLabelValues(poolID, "2").Set(float64(est.pendingTwo))
+		}
+		budgets := evaluateRecoveryBudgets(poolID, budgeted[poolID], p95)
+		if budgets != nil {
+			metrics.PoolRecoveryBudgetMaxSpotRatio.WithLabelValues(poolID).Set(budgets.maxSpotRatio)
+			metrics.PoolRecoveryBudgetsAtRisk.WithLabelValues(poolID).Set(float64(budgets.atRisk))
+		} else {
+			metrics.PoolRecoveryBudgetMaxSpotRatio.DeleteLabelValues(poolID)
+			metrics.PoolRecoveryBudgetsAtRisk.DeleteLabelValues(poolID)
+		}
+		poolSafety := computePoolSafetyVector(acc, util, len(groupZones[acc.groupKey]), p95, spotLoss, budgets)
+		metrics.PoolSpareODHeadroomNodes.WithLabelValues(poolID).Set(poolSafety.SpareODHeadroomNodes)
 
 		newFeatures[poolID] = WorkloadFeatures{
 			PodStartupTime:     p95,
@@ -342,6 +281,14 @@ func (c *Collector) Collect(ctx context.Context) (*LocalMetrics, error) {
 		}
 	}
 
+	for poolID := range c.metrics.PoolFeatures {
+		if _, ok := newFeatures[poolID]; !ok {
+			metrics.PoolSpareODHeadroomNodes.DeleteLabelValues(poolID)
+			metrics.PoolSpotLossPendingPods.DeletePartialMatch(prometheus.Labels{"pool": poolID})
+			metrics.PoolRecoveryBudgetMaxSpotRatio.DeleteLabelValues(poolID)
+			metrics.PoolRecoveryBudgetsAtRisk.DeleteLabelValues(poolID)
+		}
+	}
 	c.metrics.PoolFeatures = newFeatures
 	c.metrics.LastUpdated = time.Now()
 
@@ -366,6 +313,11 @@ const (
 	// AnnotationMigrationTier assigns an explicit migration tier (0=critical, 1=standard, 2=batch).
 	// Maps to priority scores: 0→1.0, 1→0.5, 2→0.25
 	AnnotationMigrationTier = "spotvortex.io/migration-tier"
+
+	// AnnotationMaxUnavailableSeconds declares the workload's recovery budget:
+	// how long it may run below its minimum replicas (e.g., "120"). A
+	// RecoveryBudget resource targeting the workload takes precedence.
+	AnnotationMaxUnavailableSeconds = "spotvortex.io/max-unavailable-seconds"
 )
 
 // GetNodePoolID generates the simple "InstanceType:Zone" pool ID.
@@ -452,14 +404,58 @@ type poolAccumulator struct {
 	evictablePods  int
 	criticalPods   int
 	criticalOnSpot int
+	spotNodeNames  []string
+	odNodeNames    []string
 	pdbNodeCounts  map[string]map[string]int
 	pdbSlack       map[string]int32
 }
 
-type compiledPDB struct {
-	key                string
-	disruptionsAllowed int32
-	selector           labels.Selector
+func newPoolAccumulator() *poolAccumulator {
+	return &poolAccumulator{
+		pdbNodeCounts: make(map[string]map[string]int),
+		pdbSlack:      make(map[string]int32),
+	}
+}
+
+// add folds one evaluated pod into the pool.
+func (acc *poolAccumulator) add(pod podContribution, onSpot bool) {
+	if pod.latency > 0 {
+		acc.latencies = append(acc.latencies, weightedValue{val: pod.latency, weight: pod.weight})
+	}
+	acc.penalties = append(acc.penalties, weightedValue{val: pod.penalty, weight: pod.weight})
+	if pod.penalty > acc.maxPenalty {
+		acc.maxPenalty = pod.penalty
+	}
+	acc.priorities = append(acc.priorities, weightedValue{val: pod.priority, weight: pod.weight})
+	if pod.priority > acc.maxPriority {
+		acc.maxPriority = pod.priority
+	}
+	if pod.priority >= 1.0 {
+		acc.hasCriticalPod = true
+	}
+
+	if pod.workload {
+		acc.workloadPods++
+		if pod.stateful {
+			acc.statefulPods++
+		}
+		if pod.evictable {
+			acc.evictablePods++
+		}
+		if pod.critical {
+			acc.criticalPods++
+			if onSpot {
+				acc.criticalOnSpot++
+			}
+		}
+	}
+	for _, pdb := range pod.pdbs {
+		if _, ok := acc.pdbNodeCounts[pdb.key]; !ok {
+			acc.pdbNodeCounts[pdb.key] = make(map[string]int)
+		}
+		acc.pdbNodeCounts[pdb.key][pod.nodeName]++
+		acc.pdbSlack[pdb.key] = pdb.disruptionsAllowed
+	}
 }
 
 func getPodStartupLatency(pod *corev1.Pod) float64 {
@@ -529,7 +525,11 @@ func calculateWeightedAverage(values []weightedValue) float64 {
 	return weightedSum / totalWeight
 }
 
-func computePoolSafetyVector(acc *poolAccumulator, util float64, zoneCount int, restartP95Seconds float64) config.PoolSafetyVector {
+// computePoolSafetyVector derives the pool's safety signals. spotLoss is the
+// bin-packing estimate; nil falls back to the utilization approximation.
+// budgets is the roll-up of declared recovery budgets; when present it
+// replaces the heuristic violation risk.
+func computePoolSafetyVector(acc *poolAccumulator, util float64, zoneCount int, restartP95Seconds float64, spotLoss *spotLossEstimate, budgets *recoveryBudgetEstimate) config.PoolSafetyVector {
 	vector := config.DefaultPoolSafetyVector()
 	if acc == nil {
 		return vector
@@ -537,7 +537,13 @@ func computePoolSafetyVector(acc *poolAccumulator, util float64, zoneCount int,
 
 	util = clampUnit(util)
 	vector.RestartP95Seconds = restartP95Seconds
-	vector.SpareODHeadroomNodes = math.Max(0, float64(acc.odNodes)*(1.0-util))
+	if spotLoss != nil {
+		vector.SpareODHeadroomNodes = spotLoss.headroomNodes
+		vector.PendingPodsIfOneSpotNodeLost = float64(spotLoss.pendingOne)
+		vector.PendingPodsIfTwoSpotNodesLost = float64(spotLoss.pendingTwo)
+	} else {
+		vector.SpareODHeadroomNodes = math.Max(0, float64(acc.odNodes)*(1.0-util))
+	}
 	vector.ZoneDiversificationScore = computeZoneDiversificationScore(zoneCount)
 
 	if acc.workloadPods == 0 {
@@ -552,7 +558,13 @@ func computePoolSafetyVector(acc *poolAccumulator, util float64, zoneCount int,
 		vector.EvictablePodFraction = float64(acc.evictablePods) / float64(acc.workloadPods)
 	}
 	vector.MinPDBSlackIfOneNodeLost, vector.MinPDBSlackIfTwoNodesLost = computePDBSlackBounds(acc)
-	vector.RecoveryBudgetViolationRisk = computeRecoveryBudgetViolationRisk(vector)
+	if budgets != nil {
+		vector.RecoveryBudgetCount = float64(budgets.count)
+		vector.RecoveryBudgetMaxSpotRatio = budgets.maxSpotRatio
+		vector.RecoveryBudgetViolationRisk = budgets.risk
+	} else {
+		vector.RecoveryBudgetViolationRisk = computeRecoveryBudgetViolationRisk(vector)
+	}
 	vector.SafeMaxSpotRatio = computeSafeMaxSpotRatio(vector)
 	return config.NormalizePoolSafetyVector(vector)
 }
@@ -610,6 +622,8 @@ func computeZoneDiversificationScore(zoneCount int) float64 {
 	}
 }
 
+// computeRecoveryBudgetViolationRisk is the heuristic risk used when no
+// workload in the pool declares a recovery budget.
 func computeRecoveryBudgetViolationRisk(v config.PoolSafetyVector) float64 {
 	risk := 0.0
 
@@ -687,16 +701,27 @@ func computeSafeMaxSpotRatio(v config.PoolSafetyVector) float64 {
 		cap = math.Min(cap, 0.50)
 	}
 
-	switch {
-	case v.RecoveryBudgetViolationRisk >= 0.90:
-		cap = math.Min(cap, 0.10)
-	case v.RecoveryBudgetViolationRisk >= 0.75:
-		cap = math.Min(cap, 0.25)
-	case v.RecoveryBudgetViolationRisk >= 0.60:
-		cap = math.Min(cap, 0.40)
+	// Declared budgets cap the ratio directly; the risk thresholds only
+	// apply to the heuristic risk.
+	if v.RecoveryBudgetCount > 0 {
+		cap = math.Min(cap, v.RecoveryBudgetMaxSpotRatio)
+	} else {
+		switch {
+		case v.RecoveryBudgetViolationRisk >= 0.90:
+			cap = math.Min(cap, 0.10)
+		case v.RecoveryBudgetViolationRisk >= 0.75:
+			cap = math.Min(cap, 0.25)
+		case v.RecoveryBudgetViolationRisk >= 0.60:
+			cap = math.Min(cap, 0.40)
+		}
 	}
 
-	if v.SpareODHeadroomNodes < 1 {
+	switch {
+	case v.PendingPodsIfOneSpotNodeLost > 0:
+		cap = math.Min(cap, 0.35)
+	case v.PendingPodsIfTwoSpotNodesLost > 0:
+		cap = math.Min(cap, 0.50)
+	case v.SpareODHeadroomNodes < 1:
 		cap = math.Min(cap, 0.60)
 	}
 	if v.ZoneDiversificationScore < 0.50 {
@@ -721,15 +746,6 @@ func clampUnit(v float64) float64 {
 	return v
 }
 
-func isStatefulPod(pod *corev1.Pod) bool {
-	for _, own := range pod.OwnerReferences {
-		if own.Kind == "StatefulSet" {
-			return true
-		}
-	}
-	return false
-}
-
 func isCriticalServicePod(pod *corev1.Pod, priorityScore float64) bool {
 	if pod != nil && pod.Annotations["spotvortex.io/critical"] == "true" {
 		return true
@@ -749,27 +765,11 @@ func isWorkloadPod(pod *corev1.Pod) bool {
 	return pod.Spec.NodeName != ""
 }
 
-func matchPDBForPod(pod *corev1.Pod, pdbs []compiledPDB) *compiledPDB {
-	if pod == nil {
-		return nil
-	}
-	for _, pdb := range pdbs {
-		if pdb.selector == nil || pdb.selector.Empty() {
-			continue
-		}
-		if pdb.selector.Matches(labels.Set(pod.Labels)) {
-			match := pdb
-			return &match
-		}
-	}
-	return nil
-}
-
-func calculateOutagePenalty(pod *corev1.Pod, restricted bool, rsReplicas map[string]int32) float64 {
+func calculateOutagePenalty(pod *corev1.Pod, restricted, stateful bool, replicas int32) float64 {
 	// Logic from Gap Analysis:
 	// P0=48h, P1=12h, P2=4h, P3=1h
 	// If replicas >= 2 and PDB allows eviction, halve the penalty.
-	// If PDB restricted (restricted=true) -> Double penalty
+	// If a PDB covering the pod is at its floor (restricted=true) -> Double penalty
 
 	// Determine Priority
 	pc := pod.Spec.PriorityClassName
@@ -786,20 +786,9 @@ func calculateOutagePenalty(pod *corev1.Pod, restricted bool, rsReplicas map[str
 		base *= 2.0
 	}
 
-	// Simplify: Assume stateless if not StatefulSet (checking owner refs)
-	isStateful := false
-	replicas := int32(1)
-	for _, own := range pod.OwnerReferences {
-		if own.Kind == "StatefulSet" {
-			isStateful = true
-		}
-		if own.Kind == "ReplicaSet" {
-			if r, ok := rsReplicas[own.Name]; ok {
-				replicas = r
-			}
-		}
-	}
-	if isStateful {
+	// Replicas are the owning workload's (Deployment, Rollout, ...) desired
+	// count, resolved through the owner chain.
+	if stateful {
 		base *= 2.0
 	} else if replicas >= 2 && !restricted {
 		// Redundancy bonus if not restricted
@@ -836,7 +825,7 @@ func getPriorityScore(pod *corev1.Pod) float64 {
 }
 
 // getOutagePenaltyWithOverride returns outage penalty, checking annotation override first.
-func getOutagePenaltyWithOverride(pod *corev1.Pod, restricted bool, rsReplicas map[string]int32) float64 {
+func getOutagePenaltyWithOverride(pod *corev1.Pod, restricted, stateful bool, replicas int32) float64 {
 	// Check for annotation override first
 	if penaltyStr, ok := pod.Annotations[AnnotationOutagePenalty]; ok {
 		if penalty := parseHoursDuration(penaltyStr); penalty > 0 {
@@ -844,20 +833,32 @@ func getOutagePenaltyWithOverride(pod *corev1.Pod, restricted bool, rsReplicas m
 		}
 	}
 	// Fall back to calculated penalty
-	return calculateOutagePenalty(pod, restricted, rsReplicas)
+	return calculateOutagePenalty(pod, restricted, stateful, replicas)
 }
 
 // getStartupTimeWithOverride returns startup time, checking annotation override first.
 func getStartupTimeWithOverride(pod *corev1.Pod) float64 {
 	// Check for annotation override first
+	if startup, ok := startupTimeOverride(pod); ok {
+		return startup
+	}
+	// Fall back to observed latency
+	return getPodStartupLatency(pod)
+}
+
+func startupTimeOverride(pod *corev1.Pod) (float64, bool) {
 	if startupStr, ok := pod.Annotations[AnnotationStartupTime]; ok {
 		var startup float64
 		if _, err := fmt.Sscanf(startupStr, "%f", &startup); err == nil && startup > 0 {
-			return startup
+			return startup, true
 		}
 	}
-	// Fall back to observed latency
-	return getPodStartupLatency(pod)
+	return 0, false
+}
+
+func hasStartupTimeOverride(pod *corev1.Pod) bool {
+	_, ok := startupTimeOverride(pod)
+	return ok
 }
 
 // parseHoursDuration parses a duration string like "10h", "24h", "0.5h" into hours.
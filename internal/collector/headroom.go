@@ -0,0 +1,506 @@
+package collector
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// mirrorPodAnnotation marks static pods; they are bound to their node and
+// never rescheduled.
+const mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+// maxSimulatedSpotLosses bounds how many spot nodes estimate loses: the
+// pending counts cover two, and computeSafeMaxSpotRatio only asks whether
+// the headroom reaches one node.
+const maxSimulatedSpotLosses = 2
+
+// spotLossEstimate answers "if this pool's densest spot nodes vanished now,
+// how many of their pods would fit on existing On-Demand capacity".
+type spotLossEstimate struct {
+	// headroomNodes is how many of the densest spot nodes can be lost, one
+	// after another, with every pod fitting on On-Demand nodes, up to
+	// maxSimulatedSpotLosses. The first node that does not fully fit adds
+	// the fraction of its pods that do. For a pool without spot nodes it is
+	// the pool's free On-Demand allocatable in node equivalents.
+	headroomNodes float64
+
+	// pendingOne and pendingTwo count the pods left Pending after losing the
+	// densest one and two spot nodes.
+	pendingOne int
+	pendingTwo int
+}
+
+// resources are the schedulable quantities the estimator packs.
+type resources struct {
+	cpuMilli int64
+	memBytes int64
+	pods     int64
+}
+
+func (r resources) fits(req resources) bool {
+	return req.cpuMilli <= r.cpuMilli && req.memBytes <= r.memBytes && req.pods <= r.pods
+}
+
+func (r resources) sub(req resources) resources {
+	return resources{r.cpuMilli - req.cpuMilli, r.memBytes - req.memBytes, r.pods - req.pods}
+}
+
+// headroomEstimator bin-packs the pods of lost spot nodes onto On-Demand
+// nodes, honoring requests against allocatable, taints and tolerations,
+// nodeSelector and required node affinity, and DoNotSchedule topology
+// spread constraints. It is built once per Collect and shared by all pools.
+type headroomEstimator struct {
+	nodes       []*corev1.Node
+	byName      map[string]*corev1.Node
+	spot        map[string]bool
+	pods        map[string][]podContribution
+	allocatable map[string]resources
+	free        map[string]resources // schedulable On-Demand nodes only
+	candidates  []*corev1.Node       // the nodes in free, in node order
+}
+
+// newHeadroomEstimator returns false when any node lacks allocatable CPU or
+// memory, in which case callers fall back to the utilization approximation.
+func newHeadroomEstimator(nodes []*corev1.Node, nodeIsSpot map[string]bool, pods map[string][]podContribution) (*headroomEstimator, bool) {
+	e := &headroomEstimator{
+		nodes:       nodes,
+		byName:      make(map[string]*corev1.Node, len(nodes)),
+		spot:        nodeIsSpot,
+		pods:        pods,
+		allocatable: make(map[string]resources, len(nodes)),
+		free:        make(map[string]resources),
+	}
+	for _, node := range nodes {
+		e.byName[node.Name] = node
+		cpu, okCPU := node.Status.Allocatable[corev1.ResourceCPU]
+		mem, okMem := node.Status.Allocatable[corev1.ResourceMemory]
+		if !okCPU || !okMem {
+			return nil, false
+		}
+		alloc := resources{cpuMilli: cpu.MilliValue(), memBytes: mem.Value(), pods: 110}
+		if n, ok := node.Status.Allocatable[corev1.ResourcePods]; ok {
+			alloc.pods = n.Value()
+		}
+		e.allocatable[node.Name] = alloc
+
+		if nodeIsSpot[node.Name] || !nodeSchedulable(node) {
+			continue
+		}
+		free := alloc
+		for _, pod := range pods[node.Name] {
+			if !pod.terminal {
+				free = free.sub(pod.requests)
+			}
+		}
+		e.free[node.Name] = free
+		e.candidates = append(e.candidates, node)
+	}
+	return e, true
+}
+
+// estimate simulates losing the pool's densest spot nodes, at most
+// maxSimulatedSpotLosses of them.
+func (e *headroomEstimator) estimate(spotNodes, odNodes []string) spotLossEstimate {
+	var out spotLossEstimate
+	if len(spotNodes) == 0 {
+		for _, name := range odNodes {
+			alloc, free := e.allocatable[name], e.free[name]
+			if alloc.cpuMilli <= 0 || alloc.memBytes <= 0 {
+				continue
+			}
+			frac := float64(free.cpuMilli) / float64(alloc.cpuMilli)
+			if m := float64(free.memBytes) / float64(alloc.memBytes); m < frac {
+				frac = m
+			}
+			if frac > 0 {
+				out.headroomNodes += frac
+			}
+		}
+		return out
+	}
+
+	ordered := append([]string(nil), spotNodes...)
+	density := make(map[string]resources, len(ordered))
+	for _, name := range ordered {
+		for _, pod := range e.pods[name] {
+			if pod.movable() {
+				density[name] = resources{
+					cpuMilli: density[name].cpuMilli + pod.requests.cpuMilli,
+					pods:     density[name].pods + 1,
+				}
+			}
+		}
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		a, b := density[ordered[i]], density[ordered[j]]
+		if a.pods != b.pods {
+			return a.pods > b.pods
+		}
+		if a.cpuMilli != b.cpuMilli {
+			return a.cpuMilli > b.cpuMilli
+		}
+		return ordered[i] < ordered[j]
+	})
+
+	if len(ordered) > maxSimulatedSpotLosses {
+		ordered = ordered[:maxSimulatedSpotLosses]
+	}
+
+	sim := newPackingSim(e)
+	pending := 0
+	absorbing := true
+	for i, name := range ordered {
+		placed, total := sim.loseNode(name)
+		pending += total - placed
+		if absorbing {
+			if placed == total {
+				out.headroomNodes++
+			} else {
+				out.headroomNodes += float64(placed) / float64(total)
+				absorbing = false
+			}
+		}
+		if i == 0 {
+			out.pendingOne = pending
+		}
+		out.pendingTwo = pending
+	}
+	return out
+}
+
+// packingSim is one what-if placement run over a copy of the free capacity.
+type packingSim struct {
+	e      *headroomEstimator
+	free   map[string]resources
+	lost   map[string]bool
+	placed []placement
+	spread map[spreadKey]*spreadCounts
+}
+
+type placement struct {
+	pod  *corev1.Pod
+	node *corev1.Node
+}
+
+type spreadKey struct {
+	namespace   string
+	selector    string
+	topologyKey string
+}
+
+// spreadCounts is the number of matching pods per topology domain.
+type spreadCounts struct {
+	selector labels.Selector
+	byDomain map[string]int
+}
+
+func newPackingSim(e *headroomEstimator) *packingSim {
+	free := make(map[string]resources, len(e.free))
+	for name, r := range e.free {
+		free[name] = r
+	}
+	return &packingSim{
+		e:      e,
+		free:   free,
+		lost:   make(map[string]bool),
+		spread: make(map[spreadKey]*spreadCounts),
+	}
+}
+
+// loseNode removes a node and reschedules its movable pods, largest first.
+// It returns how many of them found a place.
+func (s *packingSim) loseNode(name string) (placed, total int) {
+	s.lost[name] = true
+	node := s.e.byName[name]
+
+	var movable []podContribution
+	for _, pod := range s.e.pods[name] {
+		if pod.terminal || pod.pod == nil {
+			continue
+		}
+		s.adjustSpread(pod.pod, node, -1)
+		if pod.movable() {
+			movable = append(movable, pod)
+		}
+	}
+	sort.SliceStable(movable, func(i, j int) bool {
+		a, b := movable[i].requests, movable[j].requests
+		if a.cpuMilli != b.cpuMilli {
+			return a.cpuMilli > b.cpuMilli
+		}
+		return a.memBytes > b.memBytes
+	})
+
+	for _, pod := range movable {
+		if s.place(pod) {
+			placed++
+		}
+	}
+	return placed, len(movable)
+}
+
+// place puts the pod on the feasible On-Demand node left tightest by it.
+// Only spot nodes are lost, so every candidate is still present.
+func (s *packingSim) place(pod podContribution) bool {
+	var best *corev1.Node
+	var bestLeft int64
+	for _, node := range s.e.candidates {
+		free := s.free[node.Name]
+		if !free.fits(pod.requests) {
+			continue
+		}
+		if !toleratesNodeTaints(pod.pod, node) || !matchesNodeAffinity(pod.pod, node) || !s.spreadAllows(pod.pod, node) {
+			continue
+		}
+		left := free.cpuMilli - pod.requests.cpuMilli
+		if best == nil || left < bestLeft || (left == bestLeft && node.Name < best.Name) {
+			best, bestLeft = node, left
+		}
+	}
+	if best == nil {
+		return false
+	}
+	s.free[best.Name] = s.free[best.Name].sub(pod.requests)
+	s.placed = append(s.placed, placement{pod: pod.pod, node: best})
+	s.adjustSpread(pod.pod, best, 1)
+	return true
+}
+
+// spreadAllows checks the pod's DoNotSchedule topology spread constraints
+// as if it were placed on node.
+func (s *packingSim) spreadAllows(pod *corev1.Pod, node *corev1.Node) bool {
+	for _, c := range pod.Spec.TopologySpreadConstraints {
+		if c.WhenUnsatisfiable != corev1.DoNotSchedule {
+			continue
+		}
+		domain, ok := node.Labels[c.TopologyKey]
+		if !ok {
+			return false
+		}
+		selector, err := metav1.LabelSelectorAsSelector(c.LabelSelector)
+		if err != nil {
+			continue
+		}
+		counts := s.spreadCounts(pod.Namespace, selector, c.TopologyKey)
+
+		// Skew is measured against the emptiest domain the pod could use.
+		minCount := -1
+		for _, n := range s.e.nodes {
+			d, ok := n.Labels[c.TopologyKey]
+			if !ok || s.lost[n.Name] || !matchesNodeAffinity(pod, n) {
+				continue
+			}
+			if minCount < 0 || counts[d] < minCount {
+				minCount = counts[d]
+			}
+		}
+		if minCount < 0 {
+			minCount = 0
+		}
+		self := 0
+		if selector.Matches(labels.Set(pod.Labels)) {
+			self = 1
+		}
+		if counts[domain]+self-minCount > int(c.MaxSkew) {
+			return false
+		}
+	}
+	return true
+}
+
+// spreadCounts returns matching pods per topology domain over surviving
+// nodes, computed on first use and kept current by adjustSpread.
+func (s *packingSim) spreadCounts(namespace string, selector labels.Selector, topologyKey string) map[string]int {
+	key := spreadKey{namespace: namespace, selector: selector.String(), topologyKey: topologyKey}
+	if counts, ok := s.spread[key]; ok {
+		return counts.byDomain
+	}
+	counts := make(map[string]int)
+	count := func(pod *corev1.Pod, node *corev1.Node) {
+		if pod.Namespace != namespace || !selector.Matches(labels.Set(pod.Labels)) {
+			return
+		}
+		if d, ok := node.Labels[topologyKey]; ok {
+			counts[d]++
+		}
+	}
+	for _, node := range s.e.nodes {
+		if s.lost[node.Name] {
+			continue
+		}
+		for _, pod := range s.e.pods[node.Name] {
+			if !pod.terminal && pod.pod != nil {
+				count(pod.pod, node)
+			}
+		}
+	}
+	for _, p := range s.placed {
+		count(p.pod, p.node)
+	}
+	s.spread[key] = &spreadCounts{selector: selector, byDomain: counts}
+	return counts
+}
+
+// adjustSpread updates every computed spread count the pod contributes to.
+func (s *packingSim) adjustSpread(pod *corev1.Pod, node *corev1.Node, delta int) {
+	for key, counts := range s.spread {
+		if pod.Namespace != key.namespace {
+			continue
+		}
+		d, ok := node.Labels[key.topologyKey]
+		if !ok || !counts.selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		counts.byDomain[d] += delta
+	}
+}
+
+func nodeSchedulable(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return true
+}
+
+func toleratesNodeTaints(pod *corev1.Pod, node *corev1.Node) bool {
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect != corev1.TaintEffectNoSchedule && taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+		tolerated := false
+		for j := range pod.Spec.Tolerations {
+			if toleratesTaint(&pod.Spec.Tolerations[j], taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+// toleratesTaint follows the scheduler's matching: an empty key with Exists
+// tolerates every taint, an empty effect matches every effect.
+func toleratesTaint(t *corev1.Toleration, taint *corev1.Taint) bool {
+	if t.Effect != "" && t.Effect != taint.Effect {
+		return false
+	}
+	if t.Key != "" && t.Key != taint.Key {
+		return false
+	}
+	switch t.Operator {
+	case corev1.TolerationOpExists:
+		return true
+	case "", corev1.TolerationOpEqual:
+		return t.Value == taint.Value
+	}
+	return false
+}
+
+// matchesNodeAffinity checks nodeSelector and required node affinity.
+func matchesNodeAffinity(pod *corev1.Pod, node *corev1.Node) bool {
+	for k, v := range pod.Spec.NodeSelector {
+		if node.Labels[k] != v {
+			return false
+		}
+	}
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil {
+		return true
+	}
+	required := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil {
+		return true
+	}
+	// Terms are ORed; an empty term list matches nothing.
+	for _, term := range required.NodeSelectorTerms {
+		if nodeSelectorTermMatches(term, node) {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeSelectorTermMatches(term corev1.NodeSelectorTerm, node *corev1.Node) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	if len(term.MatchExpressions) > 0 {
+		selector, ok := nodeSelectorRequirementsAsSelector(term.MatchExpressions)
+		if !ok || !selector.Matches(labels.Set(node.Labels)) {
+			return false
+		}
+	}
+	if len(term.MatchFields) > 0 {
+		selector, ok := nodeSelectorRequirementsAsSelector(term.MatchFields)
+		if !ok || !selector.Matches(labels.Set{"metadata.name": node.Name}) {
+			return false
+		}
+	}
+	return true
+}
+
+func nodeSelectorRequirementsAsSelector(reqs []corev1.NodeSelectorRequirement) (labels.Selector, bool) {
+	selector := labels.NewSelector()
+	for _, req := range reqs {
+		var op selection.Operator
+		switch req.Operator {
+		case corev1.NodeSelectorOpIn:
+			op = selection.In
+		case corev1.NodeSelectorOpNotIn:
+			op = selection.NotIn
+		case corev1.NodeSelectorOpExists:
+			op = selection.Exists
+		case corev1.NodeSelectorOpDoesNotExist:
+			op = selection.DoesNotExist
+		case corev1.NodeSelectorOpGt:
+			op = selection.GreaterThan
+		case corev1.NodeSelectorOpLt:
+			op = selection.LessThan
+		default:
+			return nil, false
+		}
+		r, err := labels.NewRequirement(req.Key, op, req.Values)
+		if err != nil {
+			return nil, false
+		}
+		selector = selector.Add(*r)
+	}
+	return selector, true
+}
+
+// podRequests returns the pod's effective scheduling requests: the larger
+// of its containers' sum and its largest init container, plus overhead.
+func podRequests(pod *corev1.Pod) resources {
+	var cpu, mem int64
+	for _, c := range pod.Spec.Containers {
+		cpu += c.Resources.Requests.Cpu().MilliValue()
+		mem += c.Resources.Requests.Memory().Value()
+	}
+	for _, c := range pod.Spec.InitContainers {
+		if v := c.Resources.Requests.Cpu().MilliValue(); v > cpu {
+			cpu = v
+		}
+		if v := c.Resources.Requests.Memory().Value(); v > mem {
+			mem = v
+		}
+	}
+	cpu += pod.Spec.Overhead.Cpu().MilliValue()
+	mem += pod.Spec.Overhead.Memory().Value()
+	return resources{cpuMilli: cpu, memBytes: mem, pods: 1}
+}
+
+func isTerminalPod(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
@@ -0,0 +1,201 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func headroomNode(name, zone string, spot bool, cpu string) *corev1.Node {
+	node := cacheTestNode(name, zone, spot)
+	node.Status.Allocatable = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse("8Gi"),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	}
+	return node
+}
+
+func headroomPod(name, nodeName, cpu string) *corev1.Pod {
+	pod := cacheTestPod(name, "default", nodeName, "1")
+	pod.Labels = map[string]string{"app": "web"}
+	pod.Spec.Containers = []corev1.Container{{
+		Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse("256Mi"),
+		}},
+	}}
+	return pod
+}
+
+func estimateFor(t *testing.T, nodes []*corev1.Node, pods []*corev1.Pod) spotLossEstimate {
+	t.Helper()
+	e := newPodEvaluator(nil, nil, nil)
+	isSpot := make(map[string]bool)
+	var spotNames, odNames []string
+	for _, n := range nodes {
+		isSpot[n.Name] = n.Labels["karpenter.sh/capacity-type"] == "spot"
+		if isSpot[n.Name] {
+			spotNames = append(spotNames, n.Name)
+		} else {
+			odNames = append(odNames, n.Name)
+		}
+	}
+	contributions := make(map[string][]podContribution)
+	for _, pod := range pods {
+		contributions[pod.Spec.NodeName] = append(contributions[pod.Spec.NodeName], e.evaluate(pod))
+	}
+	est, ok := newHeadroomEstimator(nodes, isSpot, contributions)
+	if !ok {
+		t.Fatal("expected estimator with allocatable nodes")
+	}
+	return est.estimate(spotNames, odNames)
+}
+
+func TestHeadroom_BinPacksDensestSpotNodesFirst(t *testing.T) {
+	nodes := []*corev1.Node{
+		headroomNode("spot-a", "us-east-1a", true, "4"),
+		headroomNode("spot-b", "us-east-1a", true, "4"),
+		headroomNode("od-a", "us-east-1a", false, "2"),
+	}
+	pods := []*corev1.Pod{
+		headroomPod("a1", "spot-a", "500m"),
+		headroomPod("a2", "spot-a", "500m"),
+		headroomPod("a3", "spot-a", "500m"),
+		headroomPod("b1", "spot-b", "1"),
+		headroomPod("od1", "od-a", "500m"),
+	}
+	got := estimateFor(t, nodes, pods)
+
+	// od-a has 1.5 CPU free: spot-a's three pods fit, spot-b's does not.
+	if got.pendingOne != 0 || got.pendingTwo != 1 {
+		t.Fatalf("pending one=%d two=%d, want 0 and 1", got.pendingOne, got.pendingTwo)
+	}
+	if got.headroomNodes != 1 {
+		t.Fatalf("headroom=%v, want 1", got.headroomNodes)
+	}
+}
+
+func TestHeadroom_HonorsTaintsAndAffinity(t *testing.T) {
+	tainted := headroomNode("od-tainted", "us-east-1a", false, "8")
+	tainted.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "db", Effect: corev1.TaintEffectNoSchedule}}
+	nodes := []*corev1.Node{
+		headroomNode("spot-a", "us-east-1a", true, "4"),
+		tainted,
+		headroomNode("od-b", "us-east-1b", false, "8"),
+	}
+
+	zonal := headroomPod("zonal", "spot-a", "500m")
+	zonal.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      "topology.kubernetes.io/zone",
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{"us-east-1a"},
+				}},
+			}},
+		},
+	}}
+	tolerant := headroomPod("tolerant", "spot-a", "500m")
+	tolerant.Spec.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}}
+	tolerant.Spec.NodeSelector = map[string]string{"topology.kubernetes.io/zone": "us-east-1a"}
+	free := headroomPod("free", "spot-a", "500m")
+
+	got := estimateFor(t, nodes, []*corev1.Pod{zonal, tolerant, free})
+
+	// zonal needs us-east-1a, where the only OD node carries a taint it
+	// does not tolerate. tolerant fits there; free fits in us-east-1b.
+	if got.pendingOne != 1 {
+		t.Fatalf("pendingOne=%d, want 1", got.pendingOne)
+	}
+	if got.headroomNodes < 0.66 || got.headroomNodes > 0.67 {
+		t.Fatalf("headroom=%v, want 2/3", got.headroomNodes)
+	}
+}
+
+func TestHeadroom_HonorsTopologySpread(t *testing.T) {
+	nodes := []*corev1.Node{
+		headroomNode("spot-a", "us-east-1a", true, "8"),
+		headroomNode("spot-b", "us-east-1b", true, "8"),
+		headroomNode("od-a", "us-east-1a", false, "8"),
+	}
+	spread := func(name, node string) *corev1.Pod {
+		pod := headroomPod(name, node, "100m")
+		pod.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{
+			MaxSkew:           1,
+			TopologyKey:       "topology.kubernetes.io/zone",
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		}}
+		return pod
+	}
+	pods := []*corev1.Pod{
+		spread("a1", "spot-a"),
+		spread("a2", "spot-a"),
+		spread("a3", "spot-a"),
+		spread("b1", "spot-b"),
+	}
+	got := estimateFor(t, nodes, pods)
+
+	// With one replica left in us-east-1b, at most two can land in
+	// us-east-1a without exceeding maxSkew 1.
+	if got.pendingOne != 1 {
+		t.Fatalf("pendingOne=%d, want 1", got.pendingOne)
+	}
+}
+
+func TestHeadroom_PoolWithoutSpotReportsFreeAllocatable(t *testing.T) {
+	nodes := []*corev1.Node{
+		headroomNode("od-a", "us-east-1a", false, "4"),
+		headroomNode("od-b", "us-east-1a", false, "4"),
+	}
+	got := estimateFor(t, nodes, []*corev1.Pod{headroomPod("w", "od-a", "3")})
+	if got.headroomNodes != 1.25 {
+		t.Fatalf("headroom=%v, want 1.25", got.headroomNodes)
+	}
+}
+
+func TestHeadroom_StopsAfterMaxSimulatedLosses(t *testing.T) {
+	nodes := []*corev1.Node{headroomNode("od-a", "us-east-1a", false, "8")}
+	var pods []*corev1.Pod
+	for _, name := range []string{"spot-a", "spot-b", "spot-c", "spot-d", "spot-e"} {
+		nodes = append(nodes, headroomNode(name, "us-east-1a", true, "4"))
+		pods = append(pods, headroomPod(name+"-w", name, "100m"))
+	}
+	got := estimateFor(t, nodes, pods)
+
+	// All five would fit, but only the densest two are simulated.
+	if got.headroomNodes != maxSimulatedSpotLosses || got.pendingTwo != 0 {
+		t.Fatalf("headroom=%v pendingTwo=%d, want %d and 0", got.headroomNodes, got.pendingTwo, maxSimulatedSpotLosses)
+	}
+}
+
+func TestCollector_HeadroomFeedsSafeMaxSpotRatio(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		headroomNode("spot-a", "us-east-1a", true, "4"),
+		headroomNode("od-a", "us-east-1a", false, "1"),
+		headroomPod("big", "spot-a", "2"),
+	)
+	m, err := NewCollector(client, slog.Default()).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	ps := m.PoolFeatures["m5.large:us-east-1a"].PoolSafety
+	if ps.PendingPodsIfOneSpotNodeLost != 1 || ps.SpareODHeadroomNodes != 0 {
+		t.Fatalf("pending=%v headroom=%v, want 1 and 0", ps.PendingPodsIfOneSpotNodeLost, ps.SpareODHeadroomNodes)
+	}
+	if ps.SafeMaxSpotRatio > 0.35 {
+		t.Fatalf("safe max spot ratio=%v, want <= 0.35", ps.SafeMaxSpotRatio)
+	}
+	if got := testutil.ToFloat64(metrics.PoolSpotLossPendingPods.WithLabelValues("m5.large:us-east-1a", "1")); got != 1 {
+		t.Fatalf("pending pods gauge=%v, want 1", got)
+	}
+}
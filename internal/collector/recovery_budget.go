@@ -0,0 +1,178 @@
+package collector
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/softcane/spot-vortex-agent/internal/config"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// RecoveryBudgetSource serves budgets declared as RecoveryBudget resources,
+// keyed by the workload they target (Deployment, StatefulSet or Rollout).
+type RecoveryBudgetSource interface {
+	RecoveryBudget(namespace, kind, name string) (config.RecoveryBudget, bool)
+}
+
+// SetRecoveryBudgetSource makes Collect honour RecoveryBudget resources in
+// addition to the spotvortex.io/max-unavailable-seconds annotation. A
+// resource takes precedence over the annotation for the workload it targets.
+func (c *Collector) SetRecoveryBudgetSource(src RecoveryBudgetSource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.budgets = src
+}
+
+// budgetedWorkload is one workload with a declared recovery budget.
+type budgetedWorkload struct {
+	budget     config.RecoveryBudget
+	replicas   int     // scheduled, non-terminal pods cluster-wide
+	restartP95 float64 // 0 when no pod reported a startup time
+	pools      map[string]*budgetPoolShare
+}
+
+// budgetPoolShare is where a budgeted workload's replicas sit in one pool.
+type budgetPoolShare struct {
+	pods     int
+	spotPods int
+}
+
+// recoveryBudgetEstimate is the pool-level roll-up of declared budgets.
+type recoveryBudgetEstimate struct {
+	count int
+	// maxSpotRatio is the most constrained budget's spot cap.
+	maxSpotRatio float64
+	// risk is the worst expected-outage to budget ratio, capped at 1.
+	risk float64
+	// atRisk counts budgets a spot loss in the pool would breach now.
+	atRisk int
+}
+
+// budgetedWorkloads groups pods by owning workload and keeps those with a
+// declared budget, indexed by the pools they have pods in.
+func (c *Collector) budgetedWorkloads(nodes []*corev1.Node, contributions map[string][]podContribution, nodeToPools map[string][]string, nodeIsSpot map[string]bool) map[string][]*budgetedWorkload {
+	type workloadPods struct {
+		annotated float64 // tightest annotation budget, 0 when none
+		latencies []float64
+		replicas  int
+		pools     map[string]*budgetPoolShare
+	}
+	workloads := make(map[workloadRef]*workloadPods)
+	for _, node := range nodes {
+		nodeName := node.Name
+		for _, pod := range contributions[nodeName] {
+			if pod.terminal || pod.pod == nil || !pod.workload {
+				continue
+			}
+			w, ok := workloads[pod.owner]
+			if !ok {
+				w = &workloadPods{pools: make(map[string]*budgetPoolShare)}
+				workloads[pod.owner] = w
+			}
+			w.replicas++
+			if pod.latency > 0 {
+				w.latencies = append(w.latencies, pod.latency)
+			}
+			if pod.budgetSeconds > 0 && (w.annotated == 0 || pod.budgetSeconds < w.annotated) {
+				w.annotated = pod.budgetSeconds
+			}
+			for _, poolID := range nodeToPools[nodeName] {
+				share, ok := w.pools[poolID]
+				if !ok {
+					share = &budgetPoolShare{}
+					w.pools[poolID] = share
+				}
+				share.pods++
+				if nodeIsSpot[nodeName] {
+					share.spotPods++
+				}
+			}
+		}
+	}
+
+	byPool := make(map[string][]*budgetedWorkload)
+	for ref, w := range workloads {
+		budget, ok := config.RecoveryBudget{}, false
+		if c.budgets != nil {
+			budget, ok = c.budgets.RecoveryBudget(ref.namespace, ref.kind, ref.name)
+		}
+		if !ok && w.annotated > 0 {
+			budget, ok = config.RecoveryBudget{MaxUnavailableSeconds: w.annotated}, true
+		}
+		if !ok || budget.Validate() != nil {
+			continue
+		}
+		bw := &budgetedWorkload{
+			budget:     budget,
+			replicas:   w.replicas,
+			restartP95: percentile(w.latencies, 0.95),
+			pools:      w.pools,
+		}
+		for poolID := range w.pools {
+			byPool[poolID] = append(byPool[poolID], bw)
+		}
+	}
+	return byPool
+}
+
+// evaluateRecoveryBudgets checks each budget against losing every spot node
+// in the pool. A workload whose restart time fits its budget never
+// constrains the pool; otherwise the pool's spot share must leave it
+// MinAvailable replicas. poolRestartP95 stands in for workloads without a
+// measured restart time.
+func evaluateRecoveryBudgets(poolID string, workloads []*budgetedWorkload, poolRestartP95 float64) *recoveryBudgetEstimate {
+	if len(workloads) == 0 {
+		return nil
+	}
+	est := &recoveryBudgetEstimate{count: len(workloads), maxSpotRatio: 1}
+	for _, w := range workloads {
+		share := w.pools[poolID]
+		restart := w.restartP95
+		if restart <= 0 {
+			restart = poolRestartP95
+		}
+		minAvailable := int(w.budget.MinAvailable)
+
+		if w.replicas-share.spotPods < minAvailable {
+			ratio := restart / w.budget.MaxUnavailableSeconds
+			est.risk = math.Max(est.risk, math.Min(1, ratio))
+			if ratio >= 1 {
+				est.atRisk++
+			}
+		}
+		if restart <= w.budget.MaxUnavailableSeconds || share.pods == 0 {
+			continue
+		}
+		// Spot pods in this pool that can vanish while the rest of the
+		// workload still meets MinAvailable.
+		spareReplicas := math.Max(0, float64(w.replicas-minAvailable))
+		est.maxSpotRatio = math.Min(est.maxSpotRatio, clampUnit(spareReplicas/float64(share.pods)))
+	}
+	return est
+}
+
+// parseBudgetSeconds reads the max-unavailable-seconds annotation.
+func parseBudgetSeconds(v string) float64 {
+	if v == "" {
+		return 0
+	}
+	seconds, err := strconv.ParseFloat(v, 64)
+	if err != nil || seconds <= 0 || math.IsInf(seconds, 0) || math.IsNaN(seconds) {
+		return 0
+	}
+	return seconds
+}
+
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
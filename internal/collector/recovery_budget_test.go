@@ -0,0 +1,141 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/softcane/spot-vortex-agent/internal/config"
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type staticBudgets map[workloadRef]config.RecoveryBudget
+
+func (s staticBudgets) RecoveryBudget(namespace, kind, name string) (config.RecoveryBudget, bool) {
+	b, ok := s[workloadRef{kind: kind, namespace: namespace, name: name}]
+	return b, ok
+}
+
+func budgetedPod(name, nodeName, budget, startup string) *corev1.Pod {
+	pod := cacheTestPod(name, "default", nodeName, "1")
+	pod.OwnerReferences = ownedBy("ReplicaSet", "apps/v1", "web-abc")
+	pod.Annotations[AnnotationStartupTime] = startup
+	if budget != "" {
+		pod.Annotations[AnnotationMaxUnavailableSeconds] = budget
+	}
+	return pod
+}
+
+func collectBudgets(t *testing.T, src RecoveryBudgetSource, objects ...runtime.Object) WorkloadFeatures {
+	t.Helper()
+	objects = append(objects,
+		cacheTestNode("spot-a", "us-east-1a", true),
+		cacheTestNode("od-a", "us-east-1a", false),
+		testReplicaSet("default", "web-abc", 3, ownedBy("Deployment", "apps/v1", "web"), nil),
+	)
+	c := NewCollector(fake.NewSimpleClientset(objects...), slog.Default())
+	if src != nil {
+		c.SetRecoveryBudgetSource(src)
+	}
+	m, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	return m.PoolFeatures["m5.large:us-east-1a"]
+}
+
+func TestEvaluateRecoveryBudgets(t *testing.T) {
+	budget := config.RecoveryBudget{MaxUnavailableSeconds: 60, MinAvailable: 1}
+	tests := []struct {
+		name      string
+		replicas  int
+		pods      int
+		spotPods  int
+		restart   float64
+		wantCap   float64
+		wantRisk  float64
+		wantAtRsk int
+	}{
+		{"fast restart never caps", 2, 2, 2, 30, 1, 0.5, 0},
+		{"survivor off spot keeps budget", 3, 3, 2, 300, 2.0 / 3.0, 0, 0},
+		{"all replicas on spot breach", 2, 2, 2, 300, 0.5, 1, 1},
+		{"single replica cannot use spot", 1, 1, 0, 300, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &budgetedWorkload{
+				budget:     budget,
+				replicas:   tt.replicas,
+				restartP95: tt.restart,
+				pools:      map[string]*budgetPoolShare{"pool": {pods: tt.pods, spotPods: tt.spotPods}},
+			}
+			got := evaluateRecoveryBudgets("pool", []*budgetedWorkload{w}, 60)
+			if got.count != 1 || got.atRisk != tt.wantAtRsk {
+				t.Fatalf("count=%d atRisk=%d, want 1 and %d", got.count, got.atRisk, tt.wantAtRsk)
+			}
+			if got.maxSpotRatio < tt.wantCap-1e-9 || got.maxSpotRatio > tt.wantCap+1e-9 {
+				t.Fatalf("maxSpotRatio=%v, want %v", got.maxSpotRatio, tt.wantCap)
+			}
+			if got.risk != tt.wantRisk {
+				t.Fatalf("risk=%v, want %v", got.risk, tt.wantRisk)
+			}
+		})
+	}
+}
+
+func TestCollector_AnnotatedBudgetCapsSpotRatio(t *testing.T) {
+	feats := collectBudgets(t, nil,
+		budgetedPod("web-1", "spot-a", "60", "300"),
+		budgetedPod("web-2", "spot-a", "60", "300"),
+	)
+	ps := feats.PoolSafety
+	if ps.RecoveryBudgetCount != 1 {
+		t.Fatalf("budget count=%v, want 1", ps.RecoveryBudgetCount)
+	}
+	// Both replicas sit on spot and take 300s to restart against a 60s
+	// budget: one of the two may be spot.
+	if ps.RecoveryBudgetMaxSpotRatio != 0.5 || ps.SafeMaxSpotRatio > 0.5 {
+		t.Fatalf("budget cap=%v safe max=%v, want 0.5 and <= 0.5", ps.RecoveryBudgetMaxSpotRatio, ps.SafeMaxSpotRatio)
+	}
+	if ps.RecoveryBudgetViolationRisk != 1 {
+		t.Fatalf("violation risk=%v, want 1", ps.RecoveryBudgetViolationRisk)
+	}
+	if got := testutil.ToFloat64(metrics.PoolRecoveryBudgetsAtRisk.WithLabelValues("m5.large:us-east-1a")); got != 1 {
+		t.Fatalf("budgets at risk gauge=%v, want 1", got)
+	}
+}
+
+func TestCollector_RecoveryBudgetResourceOverridesAnnotation(t *testing.T) {
+	src := staticBudgets{
+		{kind: "Deployment", namespace: "default", name: "web"}: {MaxUnavailableSeconds: 600, MinAvailable: 1},
+	}
+	feats := collectBudgets(t, src,
+		budgetedPod("web-1", "spot-a", "60", "300"),
+		budgetedPod("web-2", "spot-a", "", "300"),
+	)
+	ps := feats.PoolSafety
+	// The 600s budget absorbs a 300s restart, so it does not cap the pool.
+	if ps.RecoveryBudgetCount != 1 || ps.RecoveryBudgetMaxSpotRatio != 1 {
+		t.Fatalf("count=%v cap=%v, want 1 and 1", ps.RecoveryBudgetCount, ps.RecoveryBudgetMaxSpotRatio)
+	}
+	if ps.RecoveryBudgetViolationRisk != 0.5 {
+		t.Fatalf("violation risk=%v, want 0.5", ps.RecoveryBudgetViolationRisk)
+	}
+}
+
+func TestCollector_NoBudgetKeepsHeuristicRisk(t *testing.T) {
+	feats := collectBudgets(t, nil,
+		budgetedPod("web-1", "spot-a", "", "300"),
+	)
+	ps := feats.PoolSafety
+	if ps.RecoveryBudgetCount != 0 || ps.RecoveryBudgetMaxSpotRatio != 1 {
+		t.Fatalf("count=%v cap=%v, want defaults", ps.RecoveryBudgetCount, ps.RecoveryBudgetMaxSpotRatio)
+	}
+	if ps.RecoveryBudgetViolationRisk == 0 {
+		t.Fatal("expected heuristic violation risk without declared budgets")
+	}
+}
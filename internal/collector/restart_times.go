@@ -0,0 +1,50 @@
+package collector
+
+// RestartTimeSource serves learned restart times: the P95 time from a
+// replica being evicted or terminated to its replacement becoming Ready,
+// per workload. ok is false until enough replacements have been observed.
+type RestartTimeSource interface {
+	RestartP95(namespace, kind, name string) (seconds float64, ok bool)
+}
+
+// SetRestartTimeSource makes Collect prefer learned restart times over the
+// pod creation-to-Ready proxy. The spotvortex.io/startup-time annotation
+// still wins over both.
+func (c *Collector) SetRestartTimeSource(src RestartTimeSource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.restarts = src
+}
+
+// withLearnedRestartTimes returns contributions with each pod's latency
+// replaced by its workload's learned restart P95, where one exists. The
+// input is left untouched because it may be the memoized node evaluation.
+func (c *Collector) withLearnedRestartTimes(contributions map[string][]podContribution) map[string][]podContribution {
+	if c.restarts == nil {
+		return contributions
+	}
+	learned := make(map[workloadRef]float64)
+	out := make(map[string][]podContribution, len(contributions))
+	for nodeName, pods := range contributions {
+		updated := make([]podContribution, len(pods))
+		copy(updated, pods)
+		for i := range updated {
+			pod := &updated[i]
+			if pod.latencyOverridden || !pod.workload {
+				continue
+			}
+			p95, ok := learned[pod.owner]
+			if !ok {
+				if seconds, found := c.restarts.RestartP95(pod.owner.namespace, pod.owner.kind, pod.owner.name); found && seconds > 0 {
+					p95 = seconds
+				}
+				learned[pod.owner] = p95
+			}
+			if p95 > 0 {
+				pod.latency = p95
+			}
+		}
+		out[nodeName] = updated
+	}
+	return out
+}
@@ -0,0 +1,46 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type staticRestarts map[workloadRef]float64
+
+func (s staticRestarts) RestartP95(namespace, kind, name string) (float64, bool) {
+	v, ok := s[workloadRef{kind: kind, namespace: namespace, name: name}]
+	return v, ok
+}
+
+func TestCollector_PrefersLearnedRestartTimeOverProxy(t *testing.T) {
+	annotated := budgetedPod("web-1", "spot-a", "", "30")
+	proxied := budgetedPod("web-2", "spot-a", "", "")
+	delete(proxied.Annotations, AnnotationStartupTime)
+	client := fake.NewSimpleClientset(
+		cacheTestNode("spot-a", "us-east-1a", true),
+		testReplicaSet("default", "web-abc", 2, ownedBy("Deployment", "apps/v1", "web"), nil),
+		annotated,
+		proxied,
+	)
+
+	c := NewCollector(client, slog.Default())
+	c.SetRestartTimeSource(staticRestarts{
+		{kind: "Deployment", namespace: "default", name: "web"}: 240,
+	})
+	m, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	// web-2 has no Ready condition, so only the learned time describes it;
+	// web-1 keeps its startup-time annotation.
+	feats := m.PoolFeatures["m5.large:us-east-1a"]
+	if feats.PodStartupTime != 240 {
+		t.Fatalf("pool P95 startup=%v, want learned 240", feats.PodStartupTime)
+	}
+	if feats.PoolSafety.RestartP95Seconds != 240 {
+		t.Fatalf("restart P95=%v, want 240", feats.PoolSafety.RestartP95Seconds)
+	}
+}
@@ -0,0 +1,379 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/softcane/spot-vortex-agent/internal/kubecache"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// podContribution is one scheduled pod's evaluated inputs to its pools.
+type podContribution struct {
+	nodeName  string
+	latency   float64
+	weight    float64
+	penalty   float64
+	priority  float64
+	critical  bool
+	stateful  bool
+	workload  bool
+	evictable bool
+	pdbs      []pdbCoverage
+
+	// latencyOverridden is set when latency comes from the
+	// spotvortex.io/startup-time annotation rather than observation.
+	latencyOverridden bool
+
+	// Recovery budget inputs: the owning workload and its
+	// max-unavailable-seconds annotation (0 when absent).
+	owner         workloadRef
+	budgetSeconds float64
+
+	// Scheduling inputs for the On-Demand headroom estimate.
+	pod      *corev1.Pod
+	requests resources
+	terminal bool
+	mirror   bool
+}
+
+// movable reports whether the pod would need a new node if its node vanished.
+func (p podContribution) movable() bool {
+	return p.workload && !p.terminal && !p.mirror && p.pod != nil
+}
+
+// pdbCoverage is one PDB whose selector matches a pod.
+type pdbCoverage struct {
+	key                string
+	disruptionsAllowed int32
+}
+
+// nodeContribution is the memoized evaluation of every pod on a node.
+type nodeContribution struct {
+	pods       []podContribution
+	namespaces map[string]struct{}
+}
+
+// podEvaluator holds the PDB and owner state pods are evaluated against.
+type podEvaluator struct {
+	pdbsByNamespace map[string][]compiledPDB
+	owners          *ownerResolver
+}
+
+func newPodEvaluator(pdbs []*policyv1.PodDisruptionBudget, rss []*appsv1.ReplicaSet, stss []*appsv1.StatefulSet) *podEvaluator {
+	return &podEvaluator{
+		pdbsByNamespace: compilePDBs(pdbs),
+		owners:          newOwnerResolver(rss, stss),
+	}
+}
+
+func (e *podEvaluator) evaluate(pod *corev1.Pod) podContribution {
+	// Outage Penalty (annotation override supported, weighted by CPU for inference, MAX for guardrails)
+	// Priority Score (P0=1.0, P1=0.75, P2=0.5, P3=0.25)
+	pScore := getPriorityScore(pod)
+	workloadRelevant := isWorkloadPod(pod)
+	owner := e.owners.resolve(pod)
+	stateful := owner.kind == "StatefulSet"
+
+	// Only PDBs whose selector covers this pod restrict it; a blocked PDB
+	// elsewhere in the namespace does not.
+	restricted := false
+	evictable := workloadRelevant
+	var coverage []pdbCoverage
+	for _, pdb := range matchPDBsForPod(pod, e.pdbsByNamespace[pod.Namespace]) {
+		if pdb.atFloor {
+			restricted = true
+		}
+		if pdb.disruptionsAllowed <= 0 {
+			evictable = false
+		}
+		coverage = append(coverage, pdbCoverage{key: pdb.key, disruptionsAllowed: pdb.disruptionsAllowed})
+	}
+
+	return podContribution{
+		nodeName:  pod.Spec.NodeName,
+		latency:   getStartupTimeWithOverride(pod), // annotation override supported
+		weight:    getPodWeight(pod),
+		penalty:   getOutagePenaltyWithOverride(pod, restricted, stateful, e.owners.desiredReplicas(owner)),
+		priority:  pScore,
+		critical:  isCriticalServicePod(pod, pScore),
+		stateful:  stateful,
+		workload:  workloadRelevant,
+		evictable: evictable,
+		pdbs:      coverage,
+		owner:     owner,
+		pod:       pod,
+		requests:  podRequests(pod),
+		terminal:  isTerminalPod(pod),
+		mirror:    pod.Annotations[mirrorPodAnnotation] != "",
+
+		latencyOverridden: hasStartupTimeOverride(pod),
+		budgetSeconds:     parseBudgetSeconds(pod.Annotations[AnnotationMaxUnavailableSeconds]),
+	}
+}
+
+// listCluster lists nodes, pods, PDBs and ReplicaSets from the API server
+// and evaluates every scheduled pod.
+func (c *Collector) listCluster(ctx context.Context) ([]*corev1.Node, map[string][]podContribution, error) {
+	nodeList, err := c.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		c.logger.Error("failed to list nodes", "error", err)
+		return nil, nil, err
+	}
+	nodes := make([]*corev1.Node, 0, len(nodeList.Items))
+	known := make(map[string]bool, len(nodeList.Items))
+	for i := range nodeList.Items {
+		nodes = append(nodes, &nodeList.Items[i])
+		known[nodeList.Items[i].Name] = true
+	}
+
+	pods, err := c.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	var pdbs []*policyv1.PodDisruptionBudget
+	if pdbList, err := c.client.PolicyV1().PodDisruptionBudgets("").List(ctx, metav1.ListOptions{}); err == nil {
+		for i := range pdbList.Items {
+			pdbs = append(pdbs, &pdbList.Items[i])
+		}
+	} else {
+		c.logger.Warn("failed to list PDBs", "error", err)
+	}
+	var rss []*appsv1.ReplicaSet
+	if rsList, err := c.client.AppsV1().ReplicaSets("").List(ctx, metav1.ListOptions{}); err == nil {
+		for i := range rsList.Items {
+			rss = append(rss, &rsList.Items[i])
+		}
+	} else {
+		c.logger.Warn("failed to list ReplicaSets", "error", err)
+	}
+	var stss []*appsv1.StatefulSet
+	if stsList, err := c.client.AppsV1().StatefulSets("").List(ctx, metav1.ListOptions{}); err == nil {
+		for i := range stsList.Items {
+			stss = append(stss, &stsList.Items[i])
+		}
+	} else {
+		c.logger.Warn("failed to list StatefulSets", "error", err)
+	}
+
+	eval := newPodEvaluator(pdbs, rss, stss)
+	contributions := make(map[string][]podContribution)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == "" || !known[pod.Spec.NodeName] {
+			continue // Unscheduled, or on an unknown node
+		}
+		contributions[pod.Spec.NodeName] = append(contributions[pod.Spec.NodeName], eval.evaluate(pod))
+	}
+	return nodes, contributions, nil
+}
+
+// readCache reads nodes from the informer cache and re-evaluates pods only
+// on nodes marked dirty since the previous tick. Callers hold c.mu.
+func (c *Collector) readCache() ([]*corev1.Node, map[string][]podContribution, error) {
+	nodes, err := c.cache.Nodes()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read cached nodes: %w", err)
+	}
+
+	c.dirtyMu.Lock()
+	dirtyNodes, dirtyNamespaces := c.dirtyNodes, c.dirtyNamespaces
+	c.dirtyNodes = make(map[string]struct{})
+	c.dirtyNamespaces = make(map[string]struct{})
+	c.dirtyMu.Unlock()
+
+	var eval *podEvaluator
+	evaluator := func() (*podEvaluator, error) {
+		if eval != nil {
+			return eval, nil
+		}
+		pdbs, err := c.cache.PDBs("")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cached PDBs: %w", err)
+		}
+		rss, err := c.cache.ReplicaSets()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cached ReplicaSets: %w", err)
+		}
+		stss, err := c.cache.StatefulSets()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cached StatefulSets: %w", err)
+		}
+		eval = newPodEvaluator(pdbs, rss, stss)
+		return eval, nil
+	}
+
+	present := make(map[string]struct{}, len(nodes))
+	contributions := make(map[string][]podContribution, len(nodes))
+	recomputed := 0
+	for _, node := range nodes {
+		present[node.Name] = struct{}{}
+		memo, ok := c.nodeMemo[node.Name]
+		if ok && !memoDirty(node.Name, memo, dirtyNodes, dirtyNamespaces) {
+			contributions[node.Name] = memo.pods
+			continue
+		}
+
+		e, err := evaluator()
+		if err != nil {
+			return nil, nil, err
+		}
+		pods, err := c.cache.PodsOnNode(node.Name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read cached pods for node %s: %w", node.Name, err)
+		}
+		memo = &nodeContribution{namespaces: make(map[string]struct{})}
+		for _, pod := range pods {
+			memo.pods = append(memo.pods, e.evaluate(pod))
+			memo.namespaces[pod.Namespace] = struct{}{}
+		}
+		c.nodeMemo[node.Name] = memo
+		contributions[node.Name] = memo.pods
+		recomputed++
+	}
+	for name := range c.nodeMemo {
+		if _, ok := present[name]; !ok {
+			delete(c.nodeMemo, name)
+		}
+	}
+
+	c.logger.Debug("read workload state from informer cache",
+		"nodes", len(nodes),
+		"nodes_recomputed", recomputed,
+	)
+	return nodes, contributions, nil
+}
+
+func memoDirty(nodeName string, memo *nodeContribution, dirtyNodes, dirtyNamespaces map[string]struct{}) bool {
+	if _, ok := dirtyNodes[nodeName]; ok {
+		return true
+	}
+	for ns := range dirtyNamespaces {
+		if _, ok := memo.namespaces[ns]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SetCache switches Collect to the informer cache and subscribes to the
+// pod, PDB and ReplicaSet events that invalidate memoized node
+// evaluations. Call before the cache is started.
+func (c *Collector) SetCache(kc *kubecache.Cache) error {
+	if kc == nil {
+		return nil
+	}
+	handlers := map[string]cache.ResourceEventHandler{
+		kubecache.ResourcePods: cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj any) {
+				if pod, ok := obj.(*corev1.Pod); ok {
+					c.markNodeDirty(pod.Spec.NodeName)
+				}
+			},
+			UpdateFunc: func(oldObj, newObj any) {
+				oldPod, ok1 := oldObj.(*corev1.Pod)
+				newPod, ok2 := newObj.(*corev1.Pod)
+				if !ok1 || !ok2 || oldPod.ResourceVersion == newPod.ResourceVersion {
+					return // periodic resync
+				}
+				c.markNodeDirty(oldPod.Spec.NodeName)
+				c.markNodeDirty(newPod.Spec.NodeName)
+			},
+			DeleteFunc: func(obj any) {
+				if pod, ok := tombstoneObject(obj).(*corev1.Pod); ok {
+					c.markNodeDirty(pod.Spec.NodeName)
+				}
+			},
+		},
+		kubecache.ResourcePDBs: namespaceInvalidator(c, func(oldObj, newObj any) bool {
+			oldPDB, ok1 := oldObj.(*policyv1.PodDisruptionBudget)
+			newPDB, ok2 := newObj.(*policyv1.PodDisruptionBudget)
+			if !ok1 || !ok2 {
+				return true
+			}
+			return oldPDB.Status.DisruptionsAllowed != newPDB.Status.DisruptionsAllowed ||
+				(oldPDB.Status.CurrentHealthy <= oldPDB.Status.DesiredHealthy) !=
+					(newPDB.Status.CurrentHealthy <= newPDB.Status.DesiredHealthy) ||
+				!apiequality.Semantic.DeepEqual(oldPDB.Spec.Selector, newPDB.Spec.Selector)
+		}),
+		kubecache.ResourceReplicaSets: namespaceInvalidator(c, func(oldObj, newObj any) bool {
+			oldRS, ok1 := oldObj.(*appsv1.ReplicaSet)
+			newRS, ok2 := newObj.(*appsv1.ReplicaSet)
+			if !ok1 || !ok2 {
+				return true
+			}
+			oldDesired, _ := desiredReplicasAnnotation(oldRS.Annotations)
+			newDesired, _ := desiredReplicasAnnotation(newRS.Annotations)
+			return oldDesired != newDesired ||
+				!apiequality.Semantic.DeepEqual(oldRS.Spec.Replicas, newRS.Spec.Replicas) ||
+				!apiequality.Semantic.DeepEqual(oldRS.OwnerReferences, newRS.OwnerReferences)
+		}),
+		kubecache.ResourceStatefulSets: namespaceInvalidator(c, func(oldObj, newObj any) bool {
+			oldSTS, ok1 := oldObj.(*appsv1.StatefulSet)
+			newSTS, ok2 := newObj.(*appsv1.StatefulSet)
+			if !ok1 || !ok2 {
+				return true
+			}
+			return !apiequality.Semantic.DeepEqual(oldSTS.Spec.Replicas, newSTS.Spec.Replicas)
+		}),
+	}
+	for resource, handler := range handlers {
+		if err := kc.AddEventHandler(resource, handler); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	c.cache = kc
+	c.mu.Unlock()
+	return nil
+}
+
+// namespaceInvalidator marks an object's namespace dirty on add, delete and
+// on updates that changed reports as relevant.
+func namespaceInvalidator(c *Collector, changed func(oldObj, newObj any) bool) cache.ResourceEventHandler {
+	mark := func(obj any) {
+		if accessor, err := meta.Accessor(obj); err == nil {
+			c.markNamespaceDirty(accessor.GetNamespace())
+		}
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: mark,
+		UpdateFunc: func(oldObj, newObj any) {
+			if changed(oldObj, newObj) {
+				mark(newObj)
+			}
+		},
+		DeleteFunc: func(obj any) { mark(tombstoneObject(obj)) },
+	}
+}
+
+func (c *Collector) markNodeDirty(nodeName string) {
+	if nodeName == "" {
+		return
+	}
+	c.dirtyMu.Lock()
+	c.dirtyNodes[nodeName] = struct{}{}
+	c.dirtyMu.Unlock()
+}
+
+func (c *Collector) markNamespaceDirty(namespace string) {
+	c.dirtyMu.Lock()
+	c.dirtyNamespaces[namespace] = struct{}{}
+	c.dirtyMu.Unlock()
+}
+
+// tombstoneObject unwraps the final state of an object deleted while the
+// watch was disconnected.
+func tombstoneObject(obj any) any {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		return tombstone.Obj
+	}
+	return obj
+}
@@ -0,0 +1,187 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/kubecache"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func cacheTestNode(name, zone string, spot bool) *corev1.Node {
+	capacityType := "on-demand"
+	if spot {
+		capacityType = "spot"
+	}
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name: name,
+		Labels: map[string]string{
+			"topology.kubernetes.io/zone":      zone,
+			"node.kubernetes.io/instance-type": "m5.large",
+			"karpenter.sh/capacity-type":       capacityType,
+			WorkloadPoolLabel:                  "api",
+		},
+	}}
+}
+
+func cacheTestPod(name, namespace, nodeName, tier string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Labels:      map[string]string{"app": name},
+			Annotations: map[string]string{AnnotationMigrationTier: tier},
+		},
+		Spec: corev1.PodSpec{NodeName: nodeName},
+	}
+}
+
+func startCollectorCache(t *testing.T, client *fake.Clientset, c *Collector) *kubecache.Cache {
+	t.Helper()
+	kc, err := kubecache.New(client, kubecache.Config{})
+	if err != nil {
+		t.Fatalf("kubecache.New: %v", err)
+	}
+	if err := c.SetCache(kc); err != nil {
+		t.Fatalf("SetCache: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	kc.Start(ctx)
+	if err := kc.WaitForSync(ctx); err != nil {
+		t.Fatalf("WaitForSync: %v", err)
+	}
+	return kc
+}
+
+func TestCollector_CacheMatchesList(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		cacheTestNode("node-a", "us-east-1a", true),
+		cacheTestNode("node-b", "us-east-1b", false),
+		cacheTestPod("web", "default", "node-a", "0"),
+		cacheTestPod("batch", "jobs", "node-b", "2"),
+		cacheTestPod("pending", "default", "", "1"),
+		&policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				MinAvailable: &intstr.IntOrString{IntVal: 1},
+				Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+			Status: policyv1.PodDisruptionBudgetStatus{CurrentHealthy: 1, DesiredHealthy: 1},
+		},
+	)
+
+	listed := NewCollector(client, slog.Default())
+	want, err := listed.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("list Collect: %v", err)
+	}
+
+	cached := NewCollector(client, slog.Default())
+	startCollectorCache(t, client, cached)
+	got, err := cached.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("cache Collect: %v", err)
+	}
+
+	if !reflect.DeepEqual(want.PoolFeatures, got.PoolFeatures) {
+		t.Fatalf("cache-backed features differ from list-backed\nlist:  %+v\ncache: %+v", want.PoolFeatures, got.PoolFeatures)
+	}
+}
+
+func TestCollector_CacheRecomputesOnlyDirtyNodes(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		cacheTestNode("node-a", "us-east-1a", true),
+		cacheTestNode("node-b", "us-east-1b", true),
+		cacheTestPod("web", "default", "node-a", "1"),
+		cacheTestPod("batch", "jobs", "node-b", "2"),
+	)
+	c := NewCollector(client, slog.Default())
+	startCollectorCache(t, client, c)
+
+	if _, err := c.Collect(context.Background()); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	memoB := c.nodeMemo["node-b"]
+
+	// A critical pod lands on node-a only.
+	_, err := client.CoreV1().Pods("default").Create(context.Background(),
+		cacheTestPod("db", "default", "node-a", "0"), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("create pod: %v", err)
+	}
+
+	var features WorkloadFeatures
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		m, err := c.Collect(context.Background())
+		if err != nil {
+			t.Fatalf("Collect: %v", err)
+		}
+		features = m.PoolFeatures["m5.large:us-east-1a"]
+		if features.HasCriticalPod || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if !features.HasCriticalPod {
+		t.Fatal("expected new critical pod on node-a to be reflected")
+	}
+	if c.nodeMemo["node-b"] != memoB {
+		t.Error("node-b was recomputed although nothing on it changed")
+	}
+}
+
+func TestCollector_CachePDBChangeInvalidatesNamespace(t *testing.T) {
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1, CurrentHealthy: 3, DesiredHealthy: 2},
+	}
+	client := fake.NewSimpleClientset(
+		cacheTestNode("node-a", "us-east-1a", true),
+		cacheTestPod("web", "default", "node-a", "1"),
+		pdb,
+	)
+	c := NewCollector(client, slog.Default())
+	startCollectorCache(t, client, c)
+
+	m, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if got := m.PoolFeatures["m5.large:us-east-1a"].PoolSafety.EvictablePodFraction; got != 1 {
+		t.Fatalf("evictable fraction=%v, want 1", got)
+	}
+
+	pdb = pdb.DeepCopy()
+	pdb.ResourceVersion = "2"
+	pdb.Status.DisruptionsAllowed = 0
+	if _, err := client.PolicyV1().PodDisruptionBudgets("default").UpdateStatus(context.Background(), pdb, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("update PDB: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		m, err = c.Collect(context.Background())
+		if err != nil {
+			t.Fatalf("Collect: %v", err)
+		}
+		if m.PoolFeatures["m5.large:us-east-1a"].PoolSafety.EvictablePodFraction == 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if got := m.PoolFeatures["m5.large:us-east-1a"].PoolSafety.EvictablePodFraction; got != 0 {
+		t.Fatalf("evictable fraction=%v after PDB blocked disruptions, want 0", got)
+	}
+}
@@ -0,0 +1,206 @@
+package collector
+
+import (
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Desired-replica annotations the Deployment and Argo Rollouts controllers
+// stamp on every ReplicaSet they own. They stay correct mid-rollout, when
+// the replicas are split across old and new ReplicaSets.
+const (
+	annotationDeploymentDesiredReplicas = "deployment.kubernetes.io/desired-replicas"
+	annotationRolloutDesiredReplicas    = "rollout.argoproj.io/desired-replicas"
+)
+
+// workloadRef identifies the top-level controller that owns a pod.
+type workloadRef struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// ownerResolver walks pod owner chains (Pod → ReplicaSet → Deployment or
+// Argo Rollout, StatefulSet, Job) and knows each workload's desired
+// replicas. Everything is keyed by namespace, so same-named workloads in
+// different namespaces never collide.
+type ownerResolver struct {
+	rsOwners map[workloadRef]workloadRef
+	replicas map[workloadRef]int32
+}
+
+func newOwnerResolver(rss []*appsv1.ReplicaSet, stss []*appsv1.StatefulSet) *ownerResolver {
+	r := &ownerResolver{
+		rsOwners: make(map[workloadRef]workloadRef, len(rss)),
+		replicas: make(map[workloadRef]int32, len(stss)),
+	}
+
+	for _, sts := range stss {
+		ref := workloadRef{kind: "StatefulSet", namespace: sts.Namespace, name: sts.Name}
+		r.replicas[ref] = 1 // Replicas defaults to 1
+		if sts.Spec.Replicas != nil {
+			r.replicas[ref] = *sts.Spec.Replicas
+		}
+	}
+
+	// Deployments and Rollouts without the desired-replicas annotation fall
+	// back to the sum over their ReplicaSets.
+	annotated := make(map[workloadRef]bool)
+	for _, rs := range rss {
+		rsRef := workloadRef{kind: "ReplicaSet", namespace: rs.Namespace, name: rs.Name}
+		top := rsRef
+		if owner := controllerOwner(rs.OwnerReferences); owner != nil && isReplicaSetOwner(*owner) {
+			top = workloadRef{kind: owner.Kind, namespace: rs.Namespace, name: owner.Name}
+			r.rsOwners[rsRef] = top
+		}
+
+		if desired, ok := desiredReplicasAnnotation(rs.Annotations); ok && top != rsRef {
+			if !annotated[top] || desired > r.replicas[top] {
+				r.replicas[top] = desired
+			}
+			annotated[top] = true
+			continue
+		}
+		if annotated[top] {
+			continue
+		}
+		if rs.Spec.Replicas != nil {
+			r.replicas[top] += *rs.Spec.Replicas
+		} else {
+			r.replicas[top]++ // Replicas defaults to 1
+		}
+	}
+	return r
+}
+
+// resolve returns the top-level workload that owns the pod. Pods without a
+// controller are their own workload.
+func (r *ownerResolver) resolve(pod *corev1.Pod) workloadRef {
+	owner := controllerOwner(pod.OwnerReferences)
+	if owner == nil {
+		return workloadRef{kind: "Pod", namespace: pod.Namespace, name: pod.Name}
+	}
+	ref := workloadRef{kind: owner.Kind, namespace: pod.Namespace, name: owner.Name}
+	if ref.kind == "ReplicaSet" {
+		if top, ok := r.rsOwners[ref]; ok {
+			return top
+		}
+	}
+	return ref
+}
+
+// desiredReplicas returns the workload's desired replica count, or 1 when
+// unknown (bare pods, Jobs, ReplicaSets or StatefulSets not yet cached).
+func (r *ownerResolver) desiredReplicas(ref workloadRef) int32 {
+	if n, ok := r.replicas[ref]; ok {
+		return n
+	}
+	return 1
+}
+
+// WorkloadOf returns the kind and name of the top-level workload that owns
+// the pod, using the same owner chain as Collect. replicaSetOwner returns the
+// controller owner of a ReplicaSet in the pod's namespace, or nil when it has
+// none or is not known.
+func WorkloadOf(pod *corev1.Pod, replicaSetOwner func(namespace, name string) *metav1.OwnerReference) (kind, name string) {
+	owner := controllerOwner(pod.OwnerReferences)
+	if owner == nil {
+		return "Pod", pod.Name
+	}
+	if owner.Kind == "ReplicaSet" && replicaSetOwner != nil {
+		if top := replicaSetOwner(pod.Namespace, owner.Name); top != nil && isReplicaSetOwner(*top) {
+			return top.Kind, top.Name
+		}
+	}
+	return owner.Kind, owner.Name
+}
+
+// ControllerOf returns the managing owner reference of an object, or nil.
+func ControllerOf(refs []metav1.OwnerReference) *metav1.OwnerReference {
+	return controllerOwner(refs)
+}
+
+// controllerOwner returns the managing owner reference, falling back to the
+// first owner for objects created without the controller flag.
+func controllerOwner(refs []metav1.OwnerReference) *metav1.OwnerReference {
+	for i := range refs {
+		if refs[i].Controller != nil && *refs[i].Controller {
+			return &refs[i]
+		}
+	}
+	if len(refs) > 0 {
+		return &refs[0]
+	}
+	return nil
+}
+
+func isReplicaSetOwner(owner metav1.OwnerReference) bool {
+	switch owner.Kind {
+	case "Deployment":
+		return owner.APIVersion == "" || strings.HasPrefix(owner.APIVersion, "apps/")
+	case "Rollout":
+		return owner.APIVersion == "" || strings.HasPrefix(owner.APIVersion, "argoproj.io/")
+	}
+	return false
+}
+
+func desiredReplicasAnnotation(annotations map[string]string) (int32, bool) {
+	for _, key := range []string{annotationDeploymentDesiredReplicas, annotationRolloutDesiredReplicas} {
+		if v, ok := annotations[key]; ok {
+			if n, err := strconv.ParseInt(v, 10, 32); err == nil && n >= 0 {
+				return int32(n), true
+			}
+		}
+	}
+	return 0, false
+}
+
+type compiledPDB struct {
+	key                string
+	disruptionsAllowed int32
+	atFloor            bool // CurrentHealthy <= DesiredHealthy
+	selector           labels.Selector
+}
+
+// compilePDBs groups PDBs by namespace with their selectors parsed. As in
+// policy/v1, a nil selector matches no pods and an empty one matches every
+// pod in the namespace.
+func compilePDBs(pdbs []*policyv1.PodDisruptionBudget) map[string][]compiledPDB {
+	byNamespace := make(map[string][]compiledPDB)
+	for _, pdb := range pdbs {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			continue
+		}
+		byNamespace[pdb.Namespace] = append(byNamespace[pdb.Namespace], compiledPDB{
+			key:                pdb.Namespace + "/" + pdb.Name,
+			disruptionsAllowed: pdb.Status.DisruptionsAllowed,
+			atFloor:            pdb.Status.CurrentHealthy <= pdb.Status.DesiredHealthy,
+			selector:           selector,
+		})
+	}
+	return byNamespace
+}
+
+// matchPDBsForPod returns every PDB whose selector matches the pod. The
+// eviction API refuses pods covered by more than one PDB, so callers treat
+// a multiply-covered pod as bound by the most restrictive.
+func matchPDBsForPod(pod *corev1.Pod, pdbs []compiledPDB) []compiledPDB {
+	if pod == nil {
+		return nil
+	}
+	var matched []compiledPDB
+	podLabels := labels.Set(pod.Labels)
+	for _, pdb := range pdbs {
+		if pdb.selector != nil && pdb.selector.Matches(podLabels) {
+			matched = append(matched, pdb)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,203 @@
+package collector
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func ownedBy(kind, apiVersion, name string) []metav1.OwnerReference {
+	controller := true
+	return []metav1.OwnerReference{{APIVersion: apiVersion, Kind: kind, Name: name, Controller: &controller}}
+}
+
+func testReplicaSet(namespace, name string, replicas int32, owner []metav1.OwnerReference, annotations map[string]string) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			OwnerReferences: owner,
+			Annotations:     annotations,
+		},
+		Spec: appsv1.ReplicaSetSpec{Replicas: &replicas},
+	}
+}
+
+func TestOwnerResolver_WalksOwnerChain(t *testing.T) {
+	rss := []*appsv1.ReplicaSet{
+		// Mid-rollout: replicas split across two ReplicaSets.
+		testReplicaSet("default", "web-old", 1, ownedBy("Deployment", "apps/v1", "web"),
+			map[string]string{annotationDeploymentDesiredReplicas: "3"}),
+		testReplicaSet("default", "web-new", 2, ownedBy("Deployment", "apps/v1", "web"),
+			map[string]string{annotationDeploymentDesiredReplicas: "3"}),
+		// Same ReplicaSet name in another namespace, single replica.
+		testReplicaSet("staging", "web-old", 1, ownedBy("Deployment", "apps/v1", "web"), nil),
+		testReplicaSet("default", "canary-abc", 4, ownedBy("Rollout", "argoproj.io/v1alpha1", "canary"), nil),
+		testReplicaSet("default", "bare-rs", 2, nil, nil),
+	}
+	three := int32(3)
+	stss := []*appsv1.StatefulSet{{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &three},
+	}}
+	r := newOwnerResolver(rss, stss)
+
+	tests := []struct {
+		name      string
+		namespace string
+		owner     []metav1.OwnerReference
+		wantKind  string
+		wantName  string
+		replicas  int32
+	}{
+		{"deployment", "default", ownedBy("ReplicaSet", "apps/v1", "web-old"), "Deployment", "web", 3},
+		{"other namespace", "staging", ownedBy("ReplicaSet", "apps/v1", "web-old"), "Deployment", "web", 1},
+		{"argo rollout", "default", ownedBy("ReplicaSet", "apps/v1", "canary-abc"), "Rollout", "canary", 4},
+		{"bare replicaset", "default", ownedBy("ReplicaSet", "apps/v1", "bare-rs"), "ReplicaSet", "bare-rs", 2},
+		{"uncached replicaset", "default", ownedBy("ReplicaSet", "apps/v1", "gone"), "ReplicaSet", "gone", 1},
+		{"statefulset", "default", ownedBy("StatefulSet", "apps/v1", "db"), "StatefulSet", "db", 3},
+		{"statefulset other namespace", "staging", ownedBy("StatefulSet", "apps/v1", "db"), "StatefulSet", "db", 1},
+		{"job", "default", ownedBy("Job", "batch/v1", "migrate"), "Job", "migrate", 1},
+		{"bare pod", "default", nil, "Pod", "pod", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:            "pod",
+				Namespace:       tt.namespace,
+				OwnerReferences: tt.owner,
+			}}
+			ref := r.resolve(pod)
+			if ref.kind != tt.wantKind || ref.name != tt.wantName || ref.namespace != tt.namespace {
+				t.Fatalf("resolve=%+v, want %s/%s/%s", ref, tt.wantKind, tt.namespace, tt.wantName)
+			}
+			if got := r.desiredReplicas(ref); got != tt.replicas {
+				t.Fatalf("desiredReplicas=%d, want %d", got, tt.replicas)
+			}
+		})
+	}
+}
+
+func TestPodEvaluator_PDBRestrictsOnlyMatchingPods(t *testing.T) {
+	rss := []*appsv1.ReplicaSet{
+		testReplicaSet("default", "web-abc", 3, ownedBy("Deployment", "apps/v1", "web"), nil),
+		testReplicaSet("default", "db-abc", 3, ownedBy("Deployment", "apps/v1", "db"), nil),
+	}
+	pdbs := []*policyv1.PodDisruptionBudget{
+		{
+			// db is at its floor; web shares the namespace but not the selector.
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			},
+			Status: policyv1.PodDisruptionBudgetStatus{CurrentHealthy: 2, DesiredHealthy: 2},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+			Status: policyv1.PodDisruptionBudgetStatus{CurrentHealthy: 3, DesiredHealthy: 2, DisruptionsAllowed: 1},
+		},
+		{
+			// No selector: matches nothing under policy/v1.
+			ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "default"},
+			Status:     policyv1.PodDisruptionBudgetStatus{CurrentHealthy: 1, DesiredHealthy: 1},
+		},
+	}
+	e := newPodEvaluator(pdbs, rss, nil)
+
+	pod := func(app string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            app + "-1",
+				Namespace:       "default",
+				Labels:          map[string]string{"app": app},
+				OwnerReferences: ownedBy("ReplicaSet", "apps/v1", app+"-abc"),
+			},
+			Spec: corev1.PodSpec{NodeName: "node-a"},
+		}
+	}
+
+	web := e.evaluate(pod("web"))
+	if web.penalty != 2.0 {
+		t.Errorf("web penalty=%v, want 2.0 (P2 halved for 3 replicas)", web.penalty)
+	}
+	if !web.evictable {
+		t.Error("web should be evictable")
+	}
+	if len(web.pdbs) != 1 || web.pdbs[0].key != "default/web" {
+		t.Errorf("web PDB coverage=%+v", web.pdbs)
+	}
+
+	db := e.evaluate(pod("db"))
+	if db.penalty != 8.0 {
+		t.Errorf("db penalty=%v, want 8.0 (P2 doubled at PDB floor)", db.penalty)
+	}
+	if db.evictable {
+		t.Error("db should not be evictable")
+	}
+}
+
+func TestPodEvaluator_MultiplePDBsMostRestrictiveWins(t *testing.T) {
+	pdbs := []*policyv1.PodDisruptionBudget{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "all", Namespace: "default"},
+			Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{}},
+			Status:     policyv1.PodDisruptionBudgetStatus{CurrentHealthy: 5, DesiredHealthy: 3, DisruptionsAllowed: 2},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+			},
+			Status: policyv1.PodDisruptionBudgetStatus{CurrentHealthy: 1, DesiredHealthy: 1},
+		},
+	}
+	e := newPodEvaluator(pdbs, nil, nil)
+	got := e.evaluate(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "default", Labels: map[string]string{"app": "api"}},
+		Spec:       corev1.PodSpec{NodeName: "node-a"},
+	})
+	if got.evictable {
+		t.Error("pod covered by a blocked PDB must not be evictable")
+	}
+	if len(got.pdbs) != 2 {
+		t.Fatalf("expected both PDBs to cover the pod, got %+v", got.pdbs)
+	}
+}
+
+func TestCollector_PDBSlackFollowsSelector(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		cacheTestNode("node-a", "us-east-1a", true),
+		cacheTestNode("node-b", "us-east-1a", true),
+		cacheTestPod("web-1", "default", "node-a", "1"),
+		cacheTestPod("web-2", "default", "node-b", "1"),
+		cacheTestPod("db-1", "default", "node-a", "1"),
+		&policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: "blocked", Namespace: "default"},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db-1"}},
+			},
+			Status: policyv1.PodDisruptionBudgetStatus{CurrentHealthy: 1, DesiredHealthy: 1},
+		},
+	)
+	m, err := NewCollector(client, slog.Default()).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	ps := m.PoolFeatures["m5.large:us-east-1a"].PoolSafety
+	// Only db-1 is covered by the blocked PDB; the web pods stay evictable.
+	if ps.EvictablePodFraction < 0.66 || ps.EvictablePodFraction > 0.67 {
+		t.Fatalf("evictable fraction=%v, want 2/3", ps.EvictablePodFraction)
+	}
+	if ps.MinPDBSlackIfOneNodeLost != -1 {
+		t.Fatalf("one-node slack=%v, want -1", ps.MinPDBSlackIfOneNodeLost)
+	}
+}
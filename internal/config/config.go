@@ -3,7 +3,9 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"strconv"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -11,15 +13,56 @@ import (
 
 // Config holds all SpotVortex configuration.
 type Config struct {
-	Controller  ControllerConfig  `yaml:"controller"`
-	Inference   InferenceConfig   `yaml:"inference"`
-	Prometheus  PrometheusConfig  `yaml:"prometheus"`
-	Karpenter   KarpenterConfig   `yaml:"karpenter"`
-	Autoscaling AutoscalingConfig `yaml:"autoscaling"`
-	AWS         AWSConfig         `yaml:"aws"`
-	GCP         GCPConfig         `yaml:"gcp"`
+	Controller     ControllerConfig     `yaml:"controller"`
+	Inference      InferenceConfig      `yaml:"inference"`
+	Prometheus     PrometheusConfig     `yaml:"prometheus"`
+	Karpenter      KarpenterConfig      `yaml:"karpenter"`
+	Autoscaling    AutoscalingConfig    `yaml:"autoscaling"`
+	AWS            AWSConfig            `yaml:"aws"`
+	GCP            GCPConfig            `yaml:"gcp"`
+	Azure          AzureConfig          `yaml:"azure"`
+	Recorder       RecorderConfig       `yaml:"recorder"`
+	Policy         PolicyConfig         `yaml:"policy"`
+	Interruption   InterruptionConfig   `yaml:"interruption"`
+	LeaderElection LeaderElectionConfig `yaml:"leaderElection"`
+	State          StateConfig          `yaml:"state"`
+	Server         ServerConfig         `yaml:"server"`
+	Informers      InformerConfig       `yaml:"informers"`
 }
 
+// NodePool layouts: how a workload pool maps to Karpenter NodePools.
+const (
+	// NodePoolLayoutTwin uses <pool><spotSuffix> and <pool><odSuffix> NodePools
+	// and flips their weights between SpotWeight and OnDemandWeight.
+	NodePoolLayoutTwin = "twin"
+	// NodePoolLayoutSingle uses one NodePool named after the workload pool whose
+	// karpenter.sh/capacity-type requirement allows both capacity types. Spot is
+	// disallowed while favoring On-Demand, and the NodePool weight is graduated
+	// between OnDemandWeight and SpotWeight by the spot-ratio gap.
+	NodePoolLayoutSingle = "single"
+)
+
+// Disruption modes: who replaces the Karpenter nodes SpotVortex migrates.
+const (
+	// DisruptionModeDrain cordons and evicts with SpotVortex's own drainer.
+	DisruptionModeDrain = "drain"
+	// DisruptionModeHandoff hands nodes to Karpenter: drifted NodeClaims are
+	// left to Karpenter's disruption controller and budgets, others are
+	// deleted for Karpenter's termination flow to drain and replace.
+	// SpotVortex tracks completion and marks high-risk nodes hosting critical
+	// pods karpenter.sh/do-not-disrupt.
+	DisruptionModeHandoff = "handoff"
+)
+
+// Mixed-instances ASG convergence strategies (autoscaling.mixedInstancesConvergence).
+const (
+	// MixedInstancesConvergeTerminate launches one instance under the new
+	// on-demand distribution, waits for it, and terminates the drained one.
+	MixedInstancesConvergeTerminate = "terminate"
+	// MixedInstancesConvergeInstanceRefresh starts an ASG instance refresh.
+	MixedInstancesConvergeInstanceRefresh = "instance-refresh"
+)
+
 // KarpenterConfig configures Karpenter integration per PRODUCTION_FLOW_EKS_KARPENTER.md.
 type KarpenterConfig struct {
 	// Enabled enables Karpenter NodePool weight steering.
@@ -47,6 +90,13 @@ type KarpenterConfig struct {
 	// Default: 10
 	OnDemandWeight int32 `yaml:"onDemandWeight"`
 
+	// NodePoolLayout is the default NodePool layout: "twin" or "single".
+	// Default: twin.
+	NodePoolLayout string `yaml:"nodePoolLayout"`
+
+	// NodePoolLayouts overrides NodePoolLayout per workload pool.
+	NodePoolLayouts map[string]string `yaml:"nodePoolLayouts"`
+
 	// ManagedWorkloadPools is an explicit allowlist of workload pools that SpotVortex is allowed to manage.
 	// If empty, all workload pools with spotvortex.io/pool label are managed (default behavior).
 	// If specified, only pools in this list will have their weights adjusted.
@@ -69,6 +119,31 @@ type KarpenterConfig struct {
 	// concurrency below those limits to avoid blocking Karpenter's consolidation/drift.
 	// Default: true when Karpenter is enabled.
 	RespectDisruptionBudgets bool `yaml:"respectDisruptionBudgets"`
+
+	// MaxDriftNodes caps how many NodeClaims of a single-layout NodePool
+	// Karpenter replaces at once after spot is removed from it, via a Drifted
+	// disruption budget that is removed when spot is allowed again. Default: 1.
+	MaxDriftNodes int `yaml:"maxDriftNodes"`
+
+	// WaitForNodeClaim holds drains until replacement capacity exists: after
+	// steering, SpotVortex waits for (or pre-provisions) a NodeClaim of the
+	// needed capacity type in the target NodePool to reach Initialized. On
+	// timeout the steering is rolled back and the pool's non-emergency drains
+	// are skipped for the tick.
+	WaitForNodeClaim bool `yaml:"waitForNodeClaim"`
+
+	// NodeClaimReadyTimeoutSeconds bounds the NodeClaim wait. Default: 300.
+	NodeClaimReadyTimeoutSeconds int `yaml:"nodeClaimReadyTimeoutSeconds"`
+
+	// NodeClaimPollIntervalSeconds is how often NodeClaims are polled. Default: 10.
+	NodeClaimPollIntervalSeconds int `yaml:"nodeClaimPollIntervalSeconds"`
+
+	// DisruptionMode is "drain" or "handoff". Default: drain.
+	DisruptionMode string `yaml:"disruptionMode"`
+
+	// HandoffTimeoutSeconds is how long a handed-off node may take to be
+	// replaced before SpotVortex reports it stalled. Default: 1800.
+	HandoffTimeoutSeconds int `yaml:"handoffTimeoutSeconds"`
 }
 
 // IsWorkloadPoolManaged checks if a workload pool is in the managed allowlist.
@@ -85,6 +160,25 @@ func (k *KarpenterConfig) IsWorkloadPoolManaged(workloadPool string) bool {
 	return false
 }
 
+// UsesSingleNodePool reports whether a workload pool uses the single NodePool
+// layout.
+func (k *KarpenterConfig) UsesSingleNodePool(workloadPool string) bool {
+	layout, ok := k.NodePoolLayouts[workloadPool]
+	if !ok {
+		layout = k.NodePoolLayout
+	}
+	return layout == NodePoolLayoutSingle
+}
+
+// NodePoolNames returns the NodePools serving a workload pool: the spot then
+// the on-demand NodePool, or the single NodePool.
+func (k *KarpenterConfig) NodePoolNames(workloadPool string) []string {
+	if k.UsesSingleNodePool(workloadPool) {
+		return []string{workloadPool}
+	}
+	return []string{workloadPool + k.SpotNodePoolSuffix, workloadPool + k.OnDemandNodePoolSuffix}
+}
+
 // WeightChangeCooldown returns the weight change cooldown as a duration.
 func (k *KarpenterConfig) WeightChangeCooldown() time.Duration {
 	if k.WeightChangeCooldownSeconds <= 0 {
@@ -93,6 +187,45 @@ func (k *KarpenterConfig) WeightChangeCooldown() time.Duration {
 	return time.Duration(k.WeightChangeCooldownSeconds) * time.Second
 }
 
+// NodeClaimReadyTimeout returns the NodeClaim wait timeout as a duration.
+func (k *KarpenterConfig) NodeClaimReadyTimeout() time.Duration {
+	if k.NodeClaimReadyTimeoutSeconds <= 0 {
+		return 5 * time.Minute
+	}
+	return time.Duration(k.NodeClaimReadyTimeoutSeconds) * time.Second
+}
+
+// NodeClaimPollInterval returns the NodeClaim poll interval as a duration.
+func (k *KarpenterConfig) NodeClaimPollInterval() time.Duration {
+	if k.NodeClaimPollIntervalSeconds <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(k.NodeClaimPollIntervalSeconds) * time.Second
+}
+
+// HandsOffDisruption reports whether Karpenter nodes are handed to Karpenter
+// instead of drained by SpotVortex.
+func (k *KarpenterConfig) HandsOffDisruption() bool {
+	return k.DisruptionMode == DisruptionModeHandoff
+}
+
+// HandoffTimeout returns the handoff completion timeout as a duration.
+func (k *KarpenterConfig) HandoffTimeout() time.Duration {
+	if k.HandoffTimeoutSeconds <= 0 {
+		return 30 * time.Minute
+	}
+	return time.Duration(k.HandoffTimeoutSeconds) * time.Second
+}
+
+func validateNodePoolLayout(layout string) error {
+	switch layout {
+	case NodePoolLayoutTwin, NodePoolLayoutSingle:
+		return nil
+	default:
+		return fmt.Errorf("unknown layout %q (want %q or %q)", layout, NodePoolLayoutTwin, NodePoolLayoutSingle)
+	}
+}
+
 // ControllerConfig configures the reconciliation controller.
 type ControllerConfig struct {
 	RiskThreshold            float64 `yaml:"riskThreshold"`
@@ -108,6 +241,67 @@ type InferenceConfig struct {
 	RLModelPath       string `yaml:"rlModelPath"`
 	ModelManifestPath string `yaml:"modelManifestPath"`
 	ExpectedCloud     string `yaml:"expectedCloud"`
+
+	// ShadowBundles are candidate model bundles loaded next to the active
+	// one. Shadow evaluators select them by name; they never actuate.
+	ShadowBundles []ShadowBundleConfig `yaml:"shadowBundles"`
+
+	// BundleRollout hot-swaps the active bundle from a watched directory.
+	BundleRollout BundleRolloutConfig `yaml:"bundleRollout"`
+}
+
+// BundleRolloutConfig configures staged rollout of new model bundles. Each
+// subdirectory of Dir is one bundle (tft.onnx, rl_policy.onnx and
+// MODEL_MANIFEST.json), for example an OCI artifact pulled into a shared
+// volume. A new bundle runs in shadow for ShadowTicks inference ticks, is
+// promoted, and is rolled back if it misbehaves within ProbationTicks.
+type BundleRolloutConfig struct {
+	// Dir is the watched bundle directory. Empty disables hot swapping.
+	Dir                 string `yaml:"dir"`
+	PollIntervalSeconds int    `yaml:"pollIntervalSeconds"`
+	ShadowTicks         int    `yaml:"shadowTicks"`
+	ProbationTicks      int    `yaml:"probationTicks"`
+	// MaxDisagreementRatio and MaxErrorRatio bound the share of decisions
+	// where the candidate disagreed with the active bundle, or failed.
+	MaxDisagreementRatio float64 `yaml:"maxDisagreementRatio"`
+	MaxErrorRatio        float64 `yaml:"maxErrorRatio"`
+}
+
+// ShadowBundleConfig locates one candidate model bundle.
+type ShadowBundleConfig struct {
+	Name              string `yaml:"name"`
+	TFTModelPath      string `yaml:"tftModelPath"`
+	RLModelPath       string `yaml:"rlModelPath"`
+	ModelManifestPath string `yaml:"modelManifestPath"`
+}
+
+// validate applies rollout defaults when a bundle directory is set.
+func (r *BundleRolloutConfig) validate() error {
+	if r.Dir == "" {
+		return nil
+	}
+	if r.PollIntervalSeconds <= 0 {
+		r.PollIntervalSeconds = 60
+	}
+	if r.ShadowTicks <= 0 {
+		r.ShadowTicks = 20
+	}
+	if r.ProbationTicks <= 0 {
+		r.ProbationTicks = r.ShadowTicks
+	}
+	if r.MaxDisagreementRatio == 0 {
+		r.MaxDisagreementRatio = 0.2
+	}
+	if r.MaxErrorRatio == 0 {
+		r.MaxErrorRatio = 0.05
+	}
+	if r.MaxDisagreementRatio < 0 || r.MaxDisagreementRatio > 1 {
+		return fmt.Errorf("inference.bundleRollout.maxDisagreementRatio must be between 0 and 1")
+	}
+	if r.MaxErrorRatio < 0 || r.MaxErrorRatio > 1 {
+		return fmt.Errorf("inference.bundleRollout.maxErrorRatio must be between 0 and 1")
+	}
+	return nil
 }
 
 // PrometheusConfig configures the Prometheus client.
@@ -123,13 +317,22 @@ type AWSConfig struct {
 	InstanceTypes []string `yaml:"instanceTypes"`
 	// Optional catalog hints for pricing workflows. Not used for model-scope gating.
 	AvailabilityZones []string `yaml:"availabilityZones"`
+	// ClusterName is the EKS cluster name. When set with autoscaling enabled,
+	// managed node groups are scaled through the EKS API instead of their ASGs.
+	ClusterName string `yaml:"clusterName"`
+}
+
+// EKSConfigured reports whether the EKS cluster name is set.
+func (a AWSConfig) EKSConfigured() bool {
+	return a.ClusterName != ""
 }
 
 // AutoscalingConfig configures ASG-based capacity management for Cluster Autoscaler
 // and EKS Managed Nodegroup integrations.
 //
 // Per integration_strategy.md Section 4: Both CA and MNG rely on Auto Scaling Groups,
-// so they share the same "Twin ASG" swap workflow (Scale-Wait-Drain).
+// so they share the same "Twin ASG" swap workflow (Scale-Wait-Drain). When
+// aws.clusterName is set, MNG pools are scaled through the EKS API instead.
 type AutoscalingConfig struct {
 	// Enabled enables ASG-based capacity management (for CA and MNG nodes).
 	// When true, SpotVortex will manage Twin ASG pairs for spot/OD swaps.
@@ -144,6 +347,16 @@ type AutoscalingConfig struct {
 
 	// PollIntervalSeconds is how often to poll for new node readiness. Default: 10.
 	PollIntervalSeconds int `yaml:"pollIntervalSeconds"`
+
+	// MixedInstancesConvergence is how a pool backed by a single ASG with a
+	// MixedInstancesPolicy (no twin pair) reaches a new on-demand distribution:
+	// "terminate" replaces the drained node through a scale-up, "instance-refresh"
+	// starts an instance refresh. Default: terminate.
+	MixedInstancesConvergence string `yaml:"mixedInstancesConvergence"`
+
+	// InstanceRefreshMinHealthyPercent is the share of a mixed ASG kept in
+	// service during an instance refresh. Default: 90.
+	InstanceRefreshMinHealthyPercent int `yaml:"instanceRefreshMinHealthyPercent"`
 }
 
 // ASGDiscoveryTags defines the tag keys used to discover twin ASG pairs.
@@ -172,6 +385,194 @@ func (a *AutoscalingConfig) PollInterval() time.Duration {
 	return time.Duration(a.PollIntervalSeconds) * time.Second
 }
 
+// RecorderConfig configures the per-tick reconcile input recorder.
+// Recorded traces are rotated gzip JSON-lines files bounded by a total size cap.
+type RecorderConfig struct {
+	// Enabled turns on trace recording. Default: false.
+	Enabled bool `yaml:"enabled"`
+
+	// Dir is where trace files are written. Default: "/var/lib/spotvortex/traces".
+	Dir string `yaml:"dir"`
+
+	// MaxFileSizeMB rotates the active trace file once it reaches this size. Default: 16.
+	MaxFileSizeMB int `yaml:"maxFileSizeMB"`
+
+	// MaxTotalSizeMB caps the total size of retained trace files; the oldest
+	// files are deleted first. Default: 256.
+	MaxTotalSizeMB int `yaml:"maxTotalSizeMB"`
+}
+
+// MaxFileBytes returns the rotation threshold in bytes.
+func (r *RecorderConfig) MaxFileBytes() int64 {
+	if r.MaxFileSizeMB <= 0 {
+		return 16 << 20
+	}
+	return int64(r.MaxFileSizeMB) << 20
+}
+
+// MaxTotalBytes returns the retention cap in bytes.
+func (r *RecorderConfig) MaxTotalBytes() int64 {
+	if r.MaxTotalSizeMB <= 0 {
+		return 256 << 20
+	}
+	return int64(r.MaxTotalSizeMB) << 20
+}
+
+// InterruptionConfig configures immediate handling of EC2 Spot interruption
+// warnings and rebalance recommendations, outside the reconcile tick.
+type InterruptionConfig struct {
+	// Enabled turns on the interruption handler. Default: false.
+	Enabled bool `yaml:"enabled"`
+
+	// SQSQueueURL is the queue EventBridge delivers EC2 interruption and
+	// rebalance events to. Empty disables the SQS source.
+	SQSQueueURL string `yaml:"sqsQueueUrl"`
+
+	// EventFile is a JSON-lines file of EventBridge events, tailed as a local
+	// stand-in for SQS. Empty disables the file source.
+	EventFile string `yaml:"eventFile"`
+
+	// HTTPEnabled accepts EventBridge events via POST /debug/interruptions on
+	// a loopback-only listener at HTTPAddress. Intended for tests and kind
+	// clusters.
+	HTTPEnabled bool `yaml:"httpEnabled"`
+
+	// HTTPAddress is the listener for HTTPEnabled. Its host must be a
+	// loopback address. Default: 127.0.0.1:8081.
+	HTTPAddress string `yaml:"httpAddress"`
+
+	// NodeMarkers watches nodes for node-termination-handler taints and
+	// SpotInterruption/RebalanceRecommendation conditions.
+	NodeMarkers bool `yaml:"nodeMarkers"`
+
+	// NodePollSeconds is how often nodes are checked for markers. Default: 5.
+	NodePollSeconds int `yaml:"nodePollSeconds"`
+
+	// RiskHalfLifeMinutes is how fast an interruption's boost to the pool's
+	// runtime score decays by half. Default: 30.
+	RiskHalfLifeMinutes int `yaml:"riskHalfLifeMinutes"`
+}
+
+// RiskHalfLife returns the runtime score decay half-life as a duration.
+func (i *InterruptionConfig) RiskHalfLife() time.Duration {
+	if i.RiskHalfLifeMinutes <= 0 {
+		return 30 * time.Minute
+	}
+	return time.Duration(i.RiskHalfLifeMinutes) * time.Minute
+}
+
+// HTTPListenAddress returns the interruption HTTP listener address.
+func (i *InterruptionConfig) HTTPListenAddress() string {
+	if i.HTTPAddress == "" {
+		return "127.0.0.1:8081"
+	}
+	return i.HTTPAddress
+}
+
+// NodePollInterval returns the node marker poll interval as a duration.
+func (i *InterruptionConfig) NodePollInterval() time.Duration {
+	if i.NodePollSeconds <= 0 {
+		return 5 * time.Second
+	}
+	return time.Duration(i.NodePollSeconds) * time.Second
+}
+
+// LeaderElectionConfig configures Lease-based leader election for running
+// several agent replicas. Only the leader actuates; followers run
+// observe-only ticks and mirror the leader's cooldowns and price history.
+type LeaderElectionConfig struct {
+	// Enabled turns on leader election. Default: false (single replica).
+	Enabled bool `yaml:"enabled"`
+
+	// LeaseName is the coordination.k8s.io Lease replicas contend for.
+	// Default: "spotvortex-agent".
+	LeaseName string `yaml:"leaseName"`
+
+	// Namespace holds the Lease. Default: the pod's namespace.
+	Namespace string `yaml:"namespace"`
+
+	// LeaseDurationSeconds is how long followers wait before taking over an
+	// unrenewed lease. Default: 15.
+	LeaseDurationSeconds int `yaml:"leaseDurationSeconds"`
+
+	// RenewDeadlineSeconds is how long the leader retries renewing before
+	// giving up leadership. Default: 10.
+	RenewDeadlineSeconds int `yaml:"renewDeadlineSeconds"`
+
+	// RetryPeriodSeconds is the interval between acquire/renew attempts. Default: 2.
+	RetryPeriodSeconds int `yaml:"retryPeriodSeconds"`
+}
+
+// StateConfig configures where controller state (target ratios, migration and
+// weight-change cooldowns, price history) is checkpointed across restarts.
+type StateConfig struct {
+	// Backend is "configmap" (default), "lease" (annotation on a dedicated
+	// Lease), "file" (local development) or "none".
+	Backend string `yaml:"backend"`
+
+	// Name of the ConfigMap or Lease. Default: "spotvortex-agent-state".
+	Name string `yaml:"name"`
+
+	// Namespace of the ConfigMap or Lease. Default: the pod's namespace.
+	Namespace string `yaml:"namespace"`
+
+	// Path is the checkpoint file for the file backend.
+	// Default: "/var/lib/spotvortex/state.json".
+	Path string `yaml:"path"`
+}
+
+// ServerConfig configures the HTTP server for /metrics, the health probes and
+// the /debug endpoints.
+type ServerConfig struct {
+	// BindAddress is the interface to listen on. Empty listens on all.
+	BindAddress string `yaml:"bindAddress"`
+
+	// Port to listen on. Default: 8080.
+	Port int `yaml:"port"`
+
+	// ReadyReconcileIntervals fails /readyz while a tick has run for more than
+	// this many reconcile intervals, or one interval plus the node and
+	// NodeClaim wait timeouts if longer. Default: 3.
+	ReadyReconcileIntervals int `yaml:"readyReconcileIntervals"`
+}
+
+// Address returns the listen address, e.g. ":8080" or "127.0.0.1:8080".
+func (c *ServerConfig) Address() string {
+	return net.JoinHostPort(c.BindAddress, strconv.Itoa(c.Port))
+}
+
+// InformerConfig configures the shared informer cache the collector,
+// guardrails and controller read from.
+type InformerConfig struct {
+	// ResyncSeconds re-delivers every cached object to handlers. Default: 600.
+	ResyncSeconds int `yaml:"resyncSeconds"`
+
+	// SyncTimeoutSeconds bounds the wait for the initial List at startup.
+	// On timeout the agent starts anyway and reads from the API server until
+	// the caches catch up. Default: 120.
+	SyncTimeoutSeconds int `yaml:"syncTimeoutSeconds"`
+}
+
+// ResyncPeriod returns the informer resync period as a duration.
+func (c *InformerConfig) ResyncPeriod() time.Duration {
+	return time.Duration(c.ResyncSeconds) * time.Second
+}
+
+// SyncTimeout returns the initial sync timeout as a duration.
+func (c *InformerConfig) SyncTimeout() time.Duration {
+	return time.Duration(c.SyncTimeoutSeconds) * time.Second
+}
+
+// PolicyConfig configures where the runtime config comes from.
+type PolicyConfig struct {
+	// CRDEnabled watches SpotVortexPolicy/SpotVortexPoolPolicy resources.
+	// When the CRDs are absent or no policy exists, config/runtime.json is used.
+	CRDEnabled bool `yaml:"crdEnabled"`
+
+	// Name is the cluster-scoped SpotVortexPolicy to apply. Default: "default".
+	Name string `yaml:"name"`
+}
+
 // GCPConfig configures GCP preemptible pricing.
 type GCPConfig struct {
 	ProjectID string `yaml:"projectId"`
@@ -180,6 +581,23 @@ type GCPConfig struct {
 	MachineTypes []string `yaml:"machineTypes"`
 }
 
+// AzureConfig configures Azure pricing and AKS node pool management.
+type AzureConfig struct {
+	// Region selects the Azure price provider for this ARM region (e.g.
+	// "eastus"). Empty auto-detects the cloud via instance metadata.
+	Region string `yaml:"region"`
+	// SubscriptionID, ResourceGroup and ClusterName locate the AKS cluster
+	// whose twin Spot/Regular node pools are scaled when autoscaling is enabled.
+	SubscriptionID string `yaml:"subscriptionId"`
+	ResourceGroup  string `yaml:"resourceGroup"`
+	ClusterName    string `yaml:"clusterName"`
+}
+
+// AKSConfigured reports whether the AKS cluster coordinates are set.
+func (a AzureConfig) AKSConfigured() bool {
+	return a.ClusterName != ""
+}
+
 // Load reads configuration from a YAML file.
 // Returns an error if file is missing or invalid.
 func Load(path string) (*Config, error) {
@@ -226,6 +644,19 @@ func (c *Config) Validate() error {
 	if c.Inference.ModelManifestPath == "" {
 		return fmt.Errorf("inference.modelManifestPath is required")
 	}
+	bundles := make(map[string]struct{}, len(c.Inference.ShadowBundles))
+	for i, b := range c.Inference.ShadowBundles {
+		if b.Name == "" || b.TFTModelPath == "" || b.RLModelPath == "" {
+			return fmt.Errorf("inference.shadowBundles[%d] requires name, tftModelPath and rlModelPath", i)
+		}
+		if _, dup := bundles[b.Name]; dup {
+			return fmt.Errorf("inference.shadowBundles: duplicate bundle %q", b.Name)
+		}
+		bundles[b.Name] = struct{}{}
+	}
+	if err := c.Inference.BundleRollout.validate(); err != nil {
+		return err
+	}
 
 	// Prometheus validation
 	if c.Prometheus.URL == "" {
@@ -238,6 +669,17 @@ func (c *Config) Validate() error {
 		c.AWS.Region = "us-east-1"
 	}
 
+	// Azure validation: the AKS cluster is addressed by all three fields.
+	aksFields := 0
+	for _, v := range []string{c.Azure.SubscriptionID, c.Azure.ResourceGroup, c.Azure.ClusterName} {
+		if v != "" {
+			aksFields++
+		}
+	}
+	if aksFields != 0 && aksFields != 3 {
+		return fmt.Errorf("azure.subscriptionId, azure.resourceGroup and azure.clusterName must be set together")
+	}
+
 	// Autoscaling validation - apply defaults for optional fields
 	if c.Autoscaling.Enabled {
 		if c.Autoscaling.DiscoveryTags.Pool == "" {
@@ -252,6 +694,20 @@ func (c *Config) Validate() error {
 		if c.Autoscaling.PollIntervalSeconds == 0 {
 			c.Autoscaling.PollIntervalSeconds = 10
 		}
+		if c.Autoscaling.MixedInstancesConvergence == "" {
+			c.Autoscaling.MixedInstancesConvergence = MixedInstancesConvergeTerminate
+		}
+		if c.Autoscaling.MixedInstancesConvergence != MixedInstancesConvergeTerminate &&
+			c.Autoscaling.MixedInstancesConvergence != MixedInstancesConvergeInstanceRefresh {
+			return fmt.Errorf("autoscaling.mixedInstancesConvergence: unknown strategy %q (want %q or %q)",
+				c.Autoscaling.MixedInstancesConvergence, MixedInstancesConvergeTerminate, MixedInstancesConvergeInstanceRefresh)
+		}
+		if c.Autoscaling.InstanceRefreshMinHealthyPercent == 0 {
+			c.Autoscaling.InstanceRefreshMinHealthyPercent = 90
+		}
+		if c.Autoscaling.InstanceRefreshMinHealthyPercent < 0 || c.Autoscaling.InstanceRefreshMinHealthyPercent > 100 {
+			return fmt.Errorf("autoscaling.instanceRefreshMinHealthyPercent must be between 0 and 100")
+		}
 	}
 
 	// Karpenter validation - apply defaults for optional fields
@@ -271,10 +727,143 @@ func (c *Config) Validate() error {
 		if c.Karpenter.WeightChangeCooldownSeconds == 0 {
 			c.Karpenter.WeightChangeCooldownSeconds = 60 // Default 60 seconds
 		}
+		if c.Karpenter.MaxDriftNodes == 0 {
+			c.Karpenter.MaxDriftNodes = 1
+		}
+		if c.Karpenter.NodeClaimReadyTimeoutSeconds == 0 {
+			c.Karpenter.NodeClaimReadyTimeoutSeconds = 300
+		}
+		if c.Karpenter.NodeClaimPollIntervalSeconds == 0 {
+			c.Karpenter.NodeClaimPollIntervalSeconds = 10
+		}
+		if c.Karpenter.NodePoolLayout == "" {
+			c.Karpenter.NodePoolLayout = NodePoolLayoutTwin
+		}
+		if err := validateNodePoolLayout(c.Karpenter.NodePoolLayout); err != nil {
+			return fmt.Errorf("karpenter.nodePoolLayout: %w", err)
+		}
+		for pool, layout := range c.Karpenter.NodePoolLayouts {
+			if err := validateNodePoolLayout(layout); err != nil {
+				return fmt.Errorf("karpenter.nodePoolLayouts[%s]: %w", pool, err)
+			}
+		}
+		if c.Karpenter.DisruptionMode == "" {
+			c.Karpenter.DisruptionMode = DisruptionModeDrain
+		}
+		if c.Karpenter.DisruptionMode != DisruptionModeDrain && c.Karpenter.DisruptionMode != DisruptionModeHandoff {
+			return fmt.Errorf("karpenter.disruptionMode: unknown mode %q (want %q or %q)",
+				c.Karpenter.DisruptionMode, DisruptionModeDrain, DisruptionModeHandoff)
+		}
+		if c.Karpenter.HandoffTimeoutSeconds == 0 {
+			c.Karpenter.HandoffTimeoutSeconds = 1800
+		}
 		// RespectDisruptionBudgets defaults to true when Karpenter is enabled
 		// (set via yaml tag default, but ensure it's true if not explicitly set to false)
 	}
 
+	// Recorder validation - apply defaults for optional fields
+	if c.Recorder.Enabled {
+		if c.Recorder.Dir == "" {
+			c.Recorder.Dir = "/var/lib/spotvortex/traces"
+		}
+		if c.Recorder.MaxFileSizeMB < 0 || c.Recorder.MaxTotalSizeMB < 0 {
+			return fmt.Errorf("recorder size limits must be >= 0")
+		}
+		if c.Recorder.MaxTotalBytes() < c.Recorder.MaxFileBytes() {
+			return fmt.Errorf("recorder.maxTotalSizeMB must be >= recorder.maxFileSizeMB")
+		}
+	}
+
+	if c.Policy.CRDEnabled && c.Policy.Name == "" {
+		c.Policy.Name = "default"
+	}
+
+	// Server validation - apply defaults for optional fields
+	if c.Server.Port == 0 {
+		c.Server.Port = 8080
+	}
+	if c.Server.Port < 0 || c.Server.Port > 65535 {
+		return fmt.Errorf("server.port must be between 1 and 65535")
+	}
+	if c.Server.ReadyReconcileIntervals == 0 {
+		c.Server.ReadyReconcileIntervals = 3
+	}
+	if c.Server.ReadyReconcileIntervals < 0 {
+		return fmt.Errorf("server.readyReconcileIntervals must be >= 0")
+	}
+
+	// Informer validation - apply defaults for optional fields
+	if c.Informers.ResyncSeconds == 0 {
+		c.Informers.ResyncSeconds = 600
+	}
+	if c.Informers.SyncTimeoutSeconds == 0 {
+		c.Informers.SyncTimeoutSeconds = 120
+	}
+	if c.Informers.ResyncSeconds < 0 || c.Informers.SyncTimeoutSeconds < 0 {
+		return fmt.Errorf("informers.resyncSeconds and informers.syncTimeoutSeconds must be >= 0")
+	}
+
+	// State store validation - apply defaults for optional fields
+	switch c.State.Backend {
+	case "":
+		c.State.Backend = "configmap"
+	case "configmap", "lease", "file", "none":
+	default:
+		return fmt.Errorf("state.backend must be one of configmap, lease, file, none (got %q)", c.State.Backend)
+	}
+	if c.State.Name == "" {
+		c.State.Name = "spotvortex-agent-state"
+	}
+	if c.State.Backend == "file" && c.State.Path == "" {
+		c.State.Path = "/var/lib/spotvortex/state.json"
+	}
+
+	// Leader election validation - apply defaults for optional fields
+	if c.LeaderElection.Enabled {
+		if c.LeaderElection.LeaseName == "" {
+			c.LeaderElection.LeaseName = "spotvortex-agent"
+		}
+		if c.LeaderElection.LeaseDurationSeconds == 0 {
+			c.LeaderElection.LeaseDurationSeconds = 15
+		}
+		if c.LeaderElection.RenewDeadlineSeconds == 0 {
+			c.LeaderElection.RenewDeadlineSeconds = 10
+		}
+		if c.LeaderElection.RetryPeriodSeconds == 0 {
+			c.LeaderElection.RetryPeriodSeconds = 2
+		}
+		if c.LeaderElection.RetryPeriodSeconds < 0 || c.LeaderElection.RenewDeadlineSeconds < 0 {
+			return fmt.Errorf("leaderElection durations must be > 0")
+		}
+		if c.LeaderElection.RenewDeadlineSeconds >= c.LeaderElection.LeaseDurationSeconds {
+			return fmt.Errorf("leaderElection.renewDeadlineSeconds must be less than leaseDurationSeconds")
+		}
+		if c.State.Backend == "file" {
+			return fmt.Errorf("state.backend=file cannot be shared between replicas; use configmap or lease with leaderElection")
+		}
+	}
+
+	if c.Interruption.Enabled {
+		if c.Interruption.NodePollSeconds < 0 || c.Interruption.RiskHalfLifeMinutes < 0 {
+			return fmt.Errorf("interruption poll and half-life settings must be >= 0")
+		}
+		if c.Interruption.SQSQueueURL == "" && c.Interruption.EventFile == "" &&
+			!c.Interruption.HTTPEnabled && !c.Interruption.NodeMarkers {
+			return fmt.Errorf("interruption.enabled requires at least one source (sqsQueueUrl, eventFile, httpEnabled, nodeMarkers)")
+		}
+		if c.Interruption.HTTPEnabled {
+			// The endpoint drains nodes without authentication, so it must
+			// not be reachable from outside the pod.
+			host, _, err := net.SplitHostPort(c.Interruption.HTTPListenAddress())
+			if err != nil {
+				return fmt.Errorf("invalid interruption.httpAddress: %w", err)
+			}
+			if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+				return fmt.Errorf("interruption.httpAddress %q must listen on a loopback address", c.Interruption.HTTPAddress)
+			}
+		}
+	}
+
 	return nil
 }
 
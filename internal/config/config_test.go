@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestValidate_AllowsEmptyAWSCatalogLists(t *testing.T) {
@@ -76,3 +77,412 @@ gcp:
 		t.Fatalf("expected default AWS region us-east-1, got %q", cfg.AWS.Region)
 	}
 }
+
+func TestValidate_RecorderDefaultsAndCaps(t *testing.T) {
+	cfg := &Config{
+		Controller: ControllerConfig{
+			RiskThreshold:            0.85,
+			MaxDrainRatio:            0.10,
+			ReconcileIntervalSeconds: 30,
+			ConfidenceThreshold:      0.50,
+			DrainGracePeriodSeconds:  60,
+		},
+		Inference: InferenceConfig{
+			TFTModelPath:      "models/tft.onnx",
+			RLModelPath:       "models/rl_policy.onnx",
+			ModelManifestPath: "models/MODEL_MANIFEST.json",
+		},
+		Prometheus: PrometheusConfig{
+			URL:            "http://prometheus:9090",
+			TimeoutSeconds: 10,
+		},
+		Recorder: RecorderConfig{Enabled: true},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if cfg.Recorder.Dir != "/var/lib/spotvortex/traces" {
+		t.Fatalf("recorder dir=%q, want default", cfg.Recorder.Dir)
+	}
+	if cfg.Recorder.MaxFileBytes() != 16<<20 || cfg.Recorder.MaxTotalBytes() != 256<<20 {
+		t.Fatalf("recorder caps=%d/%d, want 16MiB/256MiB", cfg.Recorder.MaxFileBytes(), cfg.Recorder.MaxTotalBytes())
+	}
+
+	cfg.Recorder.MaxFileSizeMB = 64
+	cfg.Recorder.MaxTotalSizeMB = 32
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected total cap below file cap to be rejected")
+	}
+}
+
+func TestValidate_LeaderElectionDefaults(t *testing.T) {
+	cfg := &Config{
+		Controller: ControllerConfig{
+			RiskThreshold:            0.85,
+			MaxDrainRatio:            0.10,
+			ReconcileIntervalSeconds: 30,
+			ConfidenceThreshold:      0.50,
+			DrainGracePeriodSeconds:  60,
+		},
+		Inference: InferenceConfig{
+			TFTModelPath:      "models/tft.onnx",
+			RLModelPath:       "models/rl_policy.onnx",
+			ModelManifestPath: "models/MODEL_MANIFEST.json",
+		},
+		Prometheus: PrometheusConfig{
+			URL:            "http://prometheus:9090",
+			TimeoutSeconds: 10,
+		},
+		LeaderElection: LeaderElectionConfig{Enabled: true},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	le := cfg.LeaderElection
+	if le.LeaseName != "spotvortex-agent" || le.LeaseDurationSeconds != 15 || le.RenewDeadlineSeconds != 10 || le.RetryPeriodSeconds != 2 {
+		t.Fatalf("unexpected leader election defaults: %+v", le)
+	}
+
+	cfg.LeaderElection.RenewDeadlineSeconds = 20
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected renew deadline >= lease duration to be rejected")
+	}
+}
+
+func TestValidate_StateBackend(t *testing.T) {
+	cfg := &Config{
+		Controller: ControllerConfig{
+			RiskThreshold:            0.85,
+			MaxDrainRatio:            0.10,
+			ReconcileIntervalSeconds: 30,
+			ConfidenceThreshold:      0.50,
+			DrainGracePeriodSeconds:  60,
+		},
+		Inference: InferenceConfig{
+			TFTModelPath:      "models/tft.onnx",
+			RLModelPath:       "models/rl_policy.onnx",
+			ModelManifestPath: "models/MODEL_MANIFEST.json",
+		},
+		Prometheus: PrometheusConfig{
+			URL:            "http://prometheus:9090",
+			TimeoutSeconds: 10,
+		},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if cfg.State.Backend != "configmap" || cfg.State.Name != "spotvortex-agent-state" {
+		t.Fatalf("unexpected state defaults: %+v", cfg.State)
+	}
+
+	cfg.State.Backend = "etcd"
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected unknown state backend to be rejected")
+	}
+
+	cfg.State.Backend = "file"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed for file backend: %v", err)
+	}
+	if cfg.State.Path != "/var/lib/spotvortex/state.json" {
+		t.Fatalf("unexpected state file default: %q", cfg.State.Path)
+	}
+
+	cfg.LeaderElection.Enabled = true
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected file state backend with leader election to be rejected")
+	}
+}
+
+func TestValidate_ServerDefaults(t *testing.T) {
+	cfg := &Config{
+		Controller: ControllerConfig{
+			RiskThreshold:            0.85,
+			MaxDrainRatio:            0.10,
+			ReconcileIntervalSeconds: 30,
+			ConfidenceThreshold:      0.50,
+			DrainGracePeriodSeconds:  60,
+		},
+		Inference: InferenceConfig{
+			TFTModelPath:      "models/tft.onnx",
+			RLModelPath:       "models/rl_policy.onnx",
+			ModelManifestPath: "models/MODEL_MANIFEST.json",
+		},
+		Prometheus: PrometheusConfig{
+			URL:            "http://prometheus:9090",
+			TimeoutSeconds: 10,
+		},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if cfg.Server.Address() != ":8080" || cfg.Server.ReadyReconcileIntervals != 3 {
+		t.Fatalf("unexpected server defaults: %+v", cfg.Server)
+	}
+
+	cfg.Server.BindAddress = "127.0.0.1"
+	cfg.Server.Port = 9443
+	if got := cfg.Server.Address(); got != "127.0.0.1:9443" {
+		t.Fatalf("Address()=%q", got)
+	}
+
+	cfg.Server.Port = 70000
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected out-of-range port to be rejected")
+	}
+}
+
+func TestValidate_InformerDefaults(t *testing.T) {
+	cfg := &Config{
+		Controller: ControllerConfig{
+			RiskThreshold:            0.85,
+			MaxDrainRatio:            0.10,
+			ReconcileIntervalSeconds: 30,
+			ConfidenceThreshold:      0.50,
+			DrainGracePeriodSeconds:  60,
+		},
+		Inference: InferenceConfig{
+			TFTModelPath:      "models/tft.onnx",
+			RLModelPath:       "models/rl_policy.onnx",
+			ModelManifestPath: "models/MODEL_MANIFEST.json",
+		},
+		Prometheus: PrometheusConfig{
+			URL:            "http://prometheus:9090",
+			TimeoutSeconds: 10,
+		},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if cfg.Informers.ResyncPeriod() != 10*time.Minute || cfg.Informers.SyncTimeout() != 2*time.Minute {
+		t.Fatalf("unexpected informer defaults: %+v", cfg.Informers)
+	}
+
+	cfg.Informers.SyncTimeoutSeconds = -1
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected negative sync timeout to be rejected")
+	}
+}
+
+func TestValidate_AzureClusterFieldsTogether(t *testing.T) {
+	cfg := &Config{
+		Controller: ControllerConfig{
+			RiskThreshold:            0.85,
+			MaxDrainRatio:            0.10,
+			ReconcileIntervalSeconds: 30,
+			ConfidenceThreshold:      0.50,
+			DrainGracePeriodSeconds:  60,
+		},
+		Inference: InferenceConfig{
+			TFTModelPath:      "models/tft.onnx",
+			RLModelPath:       "models/rl_policy.onnx",
+			ModelManifestPath: "models/MODEL_MANIFEST.json",
+		},
+		Prometheus: PrometheusConfig{
+			URL:            "http://prometheus:9090",
+			TimeoutSeconds: 10,
+		},
+		Azure: AzureConfig{Region: "eastus", ClusterName: "aks-prod"},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected partial AKS cluster coordinates to be rejected")
+	}
+
+	cfg.Azure.SubscriptionID = "00000000-0000-0000-0000-000000000000"
+	cfg.Azure.ResourceGroup = "rg-prod"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if !cfg.Azure.AKSConfigured() {
+		t.Fatal("expected AKS to be configured")
+	}
+}
+
+func TestValidate_ShadowBundles(t *testing.T) {
+	cfg := &Config{
+		Controller: ControllerConfig{
+			RiskThreshold:            0.85,
+			MaxDrainRatio:            0.10,
+			ReconcileIntervalSeconds: 30,
+			ConfidenceThreshold:      0.50,
+			DrainGracePeriodSeconds:  60,
+		},
+		Inference: InferenceConfig{
+			TFTModelPath:      "models/tft.onnx",
+			RLModelPath:       "models/rl_policy.onnx",
+			ModelManifestPath: "models/MODEL_MANIFEST.json",
+			ShadowBundles: []ShadowBundleConfig{
+				{Name: "next", TFTModelPath: "models/next/tft.onnx", RLModelPath: "models/next/rl_policy.onnx"},
+			},
+		},
+		Prometheus: PrometheusConfig{
+			URL:            "http://prometheus:9090",
+			TimeoutSeconds: 10,
+		},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	cfg.Inference.ShadowBundles = append(cfg.Inference.ShadowBundles, cfg.Inference.ShadowBundles[0])
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected duplicate shadow bundle to be rejected")
+	}
+	cfg.Inference.ShadowBundles = []ShadowBundleConfig{{Name: "next"}}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected shadow bundle without model paths to be rejected")
+	}
+}
+
+func TestValidate_BundleRolloutDefaults(t *testing.T) {
+	cfg := &Config{
+		Controller: ControllerConfig{
+			RiskThreshold:            0.85,
+			MaxDrainRatio:            0.10,
+			ReconcileIntervalSeconds: 30,
+			ConfidenceThreshold:      0.50,
+		},
+		Inference: InferenceConfig{
+			TFTModelPath:      "models/tft.onnx",
+			RLModelPath:       "models/rl_policy.onnx",
+			ModelManifestPath: "models/MODEL_MANIFEST.json",
+			BundleRollout:     BundleRolloutConfig{Dir: "/bundles", ShadowTicks: 5},
+		},
+		Prometheus: PrometheusConfig{URL: "http://prometheus:9090"},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	r := cfg.Inference.BundleRollout
+	if r.PollIntervalSeconds != 60 || r.ProbationTicks != 5 || r.MaxDisagreementRatio != 0.2 || r.MaxErrorRatio != 0.05 {
+		t.Fatalf("bundleRollout=%+v, want defaults with probation following shadowTicks", r)
+	}
+
+	cfg.Inference.BundleRollout.MaxErrorRatio = 1.5
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected maxErrorRatio above 1 to be rejected")
+	}
+}
+
+func TestValidate_InterruptionHTTPLoopbackOnly(t *testing.T) {
+	cfg := &Config{
+		Controller: ControllerConfig{
+			RiskThreshold:            0.85,
+			MaxDrainRatio:            0.10,
+			ReconcileIntervalSeconds: 30,
+			ConfidenceThreshold:      0.50,
+		},
+		Inference: InferenceConfig{
+			TFTModelPath:      "models/tft.onnx",
+			RLModelPath:       "models/rl_policy.onnx",
+			ModelManifestPath: "models/MODEL_MANIFEST.json",
+		},
+		Prometheus:   PrometheusConfig{URL: "http://prometheus:9090"},
+		Interruption: InterruptionConfig{Enabled: true, HTTPEnabled: true},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if got := cfg.Interruption.HTTPListenAddress(); got != "127.0.0.1:8081" {
+		t.Fatalf("HTTPListenAddress()=%q, want 127.0.0.1:8081", got)
+	}
+
+	for _, addr := range []string{"localhost:9000", "[::1]:8081"} {
+		cfg.Interruption.HTTPAddress = addr
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("Validate(%q) failed: %v", addr, err)
+		}
+	}
+	for _, addr := range []string{":8081", "0.0.0.0:8081", "10.0.0.5:8081"} {
+		cfg.Interruption.HTTPAddress = addr
+		if err := cfg.Validate(); err == nil {
+			t.Fatalf("expected non-loopback httpAddress %q to be rejected", addr)
+		}
+	}
+}
+
+func TestValidate_KarpenterNodePoolLayouts(t *testing.T) {
+	cfg := &Config{
+		Controller: ControllerConfig{
+			RiskThreshold:            0.85,
+			MaxDrainRatio:            0.10,
+			ReconcileIntervalSeconds: 30,
+			ConfidenceThreshold:      0.50,
+		},
+		Inference: InferenceConfig{
+			TFTModelPath:      "models/tft.onnx",
+			RLModelPath:       "models/rl_policy.onnx",
+			ModelManifestPath: "models/MODEL_MANIFEST.json",
+		},
+		Prometheus: PrometheusConfig{URL: "http://prometheus:9090"},
+		Karpenter: KarpenterConfig{
+			Enabled:         true,
+			NodePoolLayouts: map[string]string{"batch": NodePoolLayoutSingle},
+		},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	k := cfg.Karpenter
+	if k.NodePoolLayout != NodePoolLayoutTwin {
+		t.Fatalf("nodePoolLayout=%q, want twin by default", k.NodePoolLayout)
+	}
+	if k.WaitForNodeClaim || k.NodeClaimReadyTimeout() != 5*time.Minute || k.NodeClaimPollInterval() != 10*time.Second {
+		t.Fatalf("NodeClaim wait=%v %v %v, want off with 5m/10s defaults", k.WaitForNodeClaim, k.NodeClaimReadyTimeout(), k.NodeClaimPollInterval())
+	}
+	if got := k.NodePoolNames("web"); len(got) != 2 || got[0] != "web-spot" || got[1] != "web-od" {
+		t.Fatalf("NodePoolNames(web)=%v, want the twin NodePools", got)
+	}
+	if got := k.NodePoolNames("batch"); len(got) != 1 || got[0] != "batch" {
+		t.Fatalf("NodePoolNames(batch)=%v, want the single NodePool", got)
+	}
+
+	if k.DisruptionMode != DisruptionModeDrain || k.HandsOffDisruption() || k.HandoffTimeout() != 30*time.Minute {
+		t.Fatalf("disruption mode=%q timeout=%v, want drain with a 30m handoff timeout", k.DisruptionMode, k.HandoffTimeout())
+	}
+
+	cfg.Karpenter.DisruptionMode = "evict"
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected an unknown disruption mode to be rejected")
+	}
+	cfg.Karpenter.DisruptionMode = DisruptionModeHandoff
+
+	cfg.Karpenter.NodePoolLayouts["web"] = "mixed"
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected an unknown layout to be rejected")
+	}
+}
+
+func TestValidate_MixedInstancesConvergence(t *testing.T) {
+	cfg := &Config{
+		Controller: ControllerConfig{
+			RiskThreshold:            0.85,
+			MaxDrainRatio:            0.10,
+			ReconcileIntervalSeconds: 30,
+			ConfidenceThreshold:      0.50,
+		},
+		Inference: InferenceConfig{
+			TFTModelPath:      "models/tft.onnx",
+			RLModelPath:       "models/rl_policy.onnx",
+			ModelManifestPath: "models/MODEL_MANIFEST.json",
+		},
+		Prometheus:  PrometheusConfig{URL: "http://prometheus:9090"},
+		Autoscaling: AutoscalingConfig{Enabled: true},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if a := cfg.Autoscaling; a.MixedInstancesConvergence != MixedInstancesConvergeTerminate || a.InstanceRefreshMinHealthyPercent != 90 {
+		t.Fatalf("convergence=%q minHealthy=%d, want terminate/90 defaults", a.MixedInstancesConvergence, a.InstanceRefreshMinHealthyPercent)
+	}
+
+	cfg.Autoscaling.MixedInstancesConvergence = "replace"
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected an unknown convergence strategy to be rejected")
+	}
+	cfg.Autoscaling.MixedInstancesConvergence = MixedInstancesConvergeInstanceRefresh
+	cfg.Autoscaling.InstanceRefreshMinHealthyPercent = 120
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected minHealthyPercent above 100 to be rejected")
+	}
+}
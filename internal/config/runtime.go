@@ -6,6 +6,7 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"sync"
 
 	"gopkg.in/yaml.v3"
 )
@@ -19,6 +20,32 @@ const (
 	defaultTargetSpotRatioDriftAlpha = 0.10
 )
 
+var (
+	policyModesMu sync.RWMutex
+	policyModes   = map[string]struct{}{PolicyModeRL: {}, PolicyModeDeterministic: {}}
+)
+
+// RegisterPolicyMode makes name a valid policy_mode and shadow_policies
+// entry. The controller's policy registry calls it for every policy it
+// registers; names are case-insensitive.
+func RegisterPolicyMode(name string) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return
+	}
+	policyModesMu.Lock()
+	defer policyModesMu.Unlock()
+	policyModes[name] = struct{}{}
+}
+
+// IsPolicyMode reports whether name is a registered policy.
+func IsPolicyMode(name string) bool {
+	policyModesMu.RLock()
+	defer policyModesMu.RUnlock()
+	_, ok := policyModes[strings.ToLower(strings.TrimSpace(name))]
+	return ok
+}
+
 // PoolSafetyVector is the shared runtime contract for pool-level blast-radius
 // signals. The runtime computes and consumes this locally; it is not a model
 // input for TFT and it does not imply pod-level actuation.
@@ -56,17 +83,47 @@ type PoolSafetyVector struct {
 	RestartP95Seconds float64 `json:"restart_p95_seconds"`
 
 	// RecoveryBudgetViolationRisk estimates (0..1) how likely a spot loss is to
-	// violate recovery or availability budgets for the pool.
-	// Phase 1 status: derived_live heuristic.
-	// TODO: replace with direct service-budget telemetry when the runtime has it.
+	// violate recovery or availability budgets for the pool. With declared
+	// budgets it is the worst ratio of expected outage (measured restart time
+	// when a spot loss would leave fewer than the minimum available replicas)
+	// to the budget's max unavailable seconds, capped at 1.
+	// Phase 1 status: live for declared budgets, derived_live heuristic
+	// otherwise.
 	RecoveryBudgetViolationRisk float64 `json:"recovery_budget_violation_risk"`
 
+	// RecoveryBudgetCount is how many declared recovery budgets (the
+	// spotvortex.io/max-unavailable-seconds annotation or a RecoveryBudget
+	// resource) cover workloads with pods in the pool.
+	// Phase 1 status: live.
+	RecoveryBudgetCount float64 `json:"recovery_budget_count"`
+
+	// RecoveryBudgetMaxSpotRatio is the spot ratio cap implied by the most
+	// constrained declared budget: the largest spot share at which losing all
+	// of the pool's spot nodes still leaves each budgeted workload its
+	// minimum available replicas, for workloads whose measured restart time
+	// exceeds their budget. 1 when no budget constrains the pool.
+	// Phase 1 status: live.
+	RecoveryBudgetMaxSpotRatio float64 `json:"recovery_budget_max_spot_ratio"`
+
 	// SpareODHeadroomNodes estimates how many on-demand-equivalent nodes of
-	// immediate headroom the pool currently has.
-	// Phase 1 status: live approximation from current OD nodes and utilization.
-	// TODO: replace with scheduler or allocatable-aware headroom.
+	// immediate headroom the pool currently has: how many of its densest spot
+	// nodes could vanish with every pod bin-packed onto existing On-Demand
+	// capacity (requests vs allocatable, taints, node affinity, topology
+	// spread), up to two. Pools without spot nodes report free OD allocatable in node
+	// equivalents.
+	// Phase 1 status: live; falls back to OD nodes x (1 - utilization) when
+	// node allocatable is not reported.
 	SpareODHeadroomNodes float64 `json:"spare_od_headroom_nodes"`
 
+	// PendingPodsIfOneSpotNodeLost is how many pods would go Pending, for lack
+	// of fitting On-Demand capacity, if the pool's densest spot node vanished.
+	// Phase 1 status: live when node allocatable is reported, else 0.
+	PendingPodsIfOneSpotNodeLost float64 `json:"pending_pods_if_one_spot_node_lost"`
+
+	// PendingPodsIfTwoSpotNodesLost is the same for the two densest spot nodes.
+	// Phase 1 status: live when node allocatable is reported, else 0.
+	PendingPodsIfTwoSpotNodesLost float64 `json:"pending_pods_if_two_spot_nodes_lost"`
+
 	// ZoneDiversificationScore measures how well the workload is spread across
 	// zones: 0 means single-zone, 0.5 means two zones, 1 means three or more.
 	// Phase 1 status: live.
@@ -93,7 +150,11 @@ func DefaultPoolSafetyVector() PoolSafetyVector {
 		StatefulPodFraction:              0.0,
 		RestartP95Seconds:                300.0,
 		RecoveryBudgetViolationRisk:      0.0,
+		RecoveryBudgetCount:              0.0,
+		RecoveryBudgetMaxSpotRatio:       1.0,
 		SpareODHeadroomNodes:             0.0,
+		PendingPodsIfOneSpotNodeLost:     0.0,
+		PendingPodsIfTwoSpotNodesLost:    0.0,
 		ZoneDiversificationScore:         1.0,
 		EvictablePodFraction:             1.0,
 		SafeMaxSpotRatio:                 1.0,
@@ -109,15 +170,49 @@ func NormalizePoolSafetyVector(v PoolSafetyVector) PoolSafetyVector {
 		v.RestartP95Seconds = 0
 	}
 	v.RecoveryBudgetViolationRisk = clampFloat(v.RecoveryBudgetViolationRisk, 0, 1)
+	if v.RecoveryBudgetCount < 0 {
+		v.RecoveryBudgetCount = 0
+	}
+	v.RecoveryBudgetMaxSpotRatio = clampFloat(v.RecoveryBudgetMaxSpotRatio, 0, 1)
 	if v.SpareODHeadroomNodes < 0 {
 		v.SpareODHeadroomNodes = 0
 	}
+	if v.PendingPodsIfOneSpotNodeLost < 0 {
+		v.PendingPodsIfOneSpotNodeLost = 0
+	}
+	if v.PendingPodsIfTwoSpotNodesLost < v.PendingPodsIfOneSpotNodeLost {
+		v.PendingPodsIfTwoSpotNodesLost = v.PendingPodsIfOneSpotNodeLost
+	}
 	v.ZoneDiversificationScore = clampFloat(v.ZoneDiversificationScore, 0, 1)
 	v.EvictablePodFraction = clampFloat(v.EvictablePodFraction, 0, 1)
 	v.SafeMaxSpotRatio = clampFloat(v.SafeMaxSpotRatio, 0, 1)
 	return v
 }
 
+// RecoveryBudget is a workload's declared recovery objective.
+type RecoveryBudget struct {
+	// MaxUnavailableSeconds is how long the workload may run with fewer than
+	// MinAvailable replicas after a spot loss.
+	MaxUnavailableSeconds float64 `json:"maxUnavailableSeconds"`
+
+	// MinAvailable is how many replicas must stay up. Default: 1.
+	MinAvailable int32 `json:"minAvailable,omitempty"`
+}
+
+// Validate checks the budget and applies defaults.
+func (b *RecoveryBudget) Validate() error {
+	if b.MaxUnavailableSeconds <= 0 {
+		return fmt.Errorf("maxUnavailableSeconds must be > 0")
+	}
+	if b.MinAvailable < 0 {
+		return fmt.Errorf("minAvailable must be >= 0")
+	}
+	if b.MinAvailable == 0 {
+		b.MinAvailable = 1
+	}
+	return nil
+}
+
 // IsZero reports whether no pool-safety vector has been populated yet.
 func (v PoolSafetyVector) IsZero() bool {
 	return v == (PoolSafetyVector{})
@@ -145,8 +240,9 @@ type RuntimeConfig struct {
 	// Used for migration-cooldown and time-since-migration normalization.
 	StepMinutes int `json:"step_minutes"`
 
-	// PolicyMode controls action selection path.
-	// Supported values: "rl", "deterministic".
+	// PolicyMode names the policy that selects actions: "rl",
+	// "deterministic", or any policy registered with the controller.
+	// Unknown names fall back to "deterministic".
 	PolicyMode string `json:"policy_mode"`
 
 	// RLShadowEnabled controls whether RL shadow comparison telemetry is recorded
@@ -154,11 +250,127 @@ type RuntimeConfig struct {
 	// to enabled (to preserve current rollout behavior), and RL mode defaults off.
 	RLShadowEnabled *bool `json:"rl_shadow_enabled,omitempty"`
 
+	// ShadowPolicies names registered policies evaluated every tick next to
+	// the active one, without acting on their decisions. "rl" is added while
+	// RLShadowEnabled is in force.
+	ShadowPolicies []string `json:"shadow_policies,omitempty"`
+
+	// ShadowEvaluators are named shadows with their own policy, runtime
+	// config overlay or candidate model bundle. See ShadowEvaluator.
+	ShadowEvaluators []ShadowEvaluator `json:"shadow_evaluators,omitempty"`
+
+	// ShadowReportWindowMinutes is the rolling window of the shadow
+	// counterfactual report. Defaults to 24 hours.
+	ShadowReportWindowMinutes int `json:"shadow_report_window_minutes,omitempty"`
+
 	// DeterministicPolicy configures the TFT-risk + workload rule engine.
 	// The runtime-side pool safety vector contract consumed by this policy is
 	// documented by PoolSafetyVector above. Phase 1 does not add extra JSON
 	// knobs for those live fields; the vector is populated from cluster state.
 	DeterministicPolicy DeterministicPolicyConfig `json:"deterministic_policy"`
+
+	// PoolOverrides scope ratio bounds and cap rules to individual workload
+	// pools. The first matching override wins; unset fields keep the global
+	// values above.
+	PoolOverrides []PoolRuntimeOverride `json:"pool_overrides,omitempty"`
+}
+
+// PoolRuntimeOverride replaces the global spot ratio bounds and cap rules for
+// the pools it selects. A pool matches when its workload pool name is listed
+// in Pools or its node labels contain every NodeSelector entry.
+type PoolRuntimeOverride struct {
+	Name         string            `json:"name"`
+	Pools        []string          `json:"pools,omitempty"`
+	NodeSelector map[string]string `json:"node_selector,omitempty"`
+
+	MinSpotRatio    *float64 `json:"min_spot_ratio,omitempty"`
+	MaxSpotRatio    *float64 `json:"max_spot_ratio,omitempty"`
+	TargetSpotRatio *float64 `json:"target_spot_ratio,omitempty"`
+
+	PriorityCapRules      []SpotRatioCapRule `json:"priority_cap_rules,omitempty"`
+	OutagePenaltyCapRules []SpotRatioCapRule `json:"outage_penalty_cap_rules,omitempty"`
+	StartupTimeCapRules   []SpotRatioCapRule `json:"startup_time_cap_rules,omitempty"`
+	MigrationCostCapRules []SpotRatioCapRule `json:"migration_cost_cap_rules,omitempty"`
+	UtilizationCapRules   []SpotRatioCapRule `json:"utilization_cap_rules,omitempty"`
+}
+
+// PoolSelector identifies the pool a decision is being made for.
+type PoolSelector struct {
+	WorkloadPool string
+	NodeLabels   map[string]string
+}
+
+// Matches reports whether the override selects the given pool.
+func (o PoolRuntimeOverride) Matches(sel PoolSelector) bool {
+	if sel.WorkloadPool != "" {
+		for _, pool := range o.Pools {
+			if pool == sel.WorkloadPool {
+				return true
+			}
+		}
+	}
+	if len(o.NodeSelector) == 0 || len(sel.NodeLabels) == 0 {
+		return false
+	}
+	for k, v := range o.NodeSelector {
+		if got, ok := sel.NodeLabels[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolvePoolOverride returns the first override that selects the pool.
+func (c *RuntimeConfig) ResolvePoolOverride(sel PoolSelector) (PoolRuntimeOverride, bool) {
+	if c == nil {
+		return PoolRuntimeOverride{}, false
+	}
+	for _, o := range c.PoolOverrides {
+		if o.Matches(sel) {
+			return o, true
+		}
+	}
+	return PoolRuntimeOverride{}, false
+}
+
+// ForPool returns the effective config for one pool: the global config with
+// the matching pool override applied. The result carries no PoolOverrides.
+// Returns c unchanged when no override matches.
+func (c *RuntimeConfig) ForPool(sel PoolSelector) *RuntimeConfig {
+	o, ok := c.ResolvePoolOverride(sel)
+	if !ok {
+		return c
+	}
+	out := *c
+	out.PoolOverrides = nil
+	if o.MinSpotRatio != nil {
+		out.MinSpotRatio = *o.MinSpotRatio
+	}
+	if o.MaxSpotRatio != nil {
+		out.MaxSpotRatio = *o.MaxSpotRatio
+	}
+	if o.TargetSpotRatio != nil {
+		out.TargetSpotRatio = *o.TargetSpotRatio
+	}
+	clampSpotRatioBounds(&out)
+
+	dp := &out.DeterministicPolicy
+	if len(o.PriorityCapRules) > 0 {
+		dp.PriorityCapRules = cloneCapRules(o.PriorityCapRules)
+	}
+	if len(o.OutagePenaltyCapRules) > 0 {
+		dp.OutagePenaltyCapRules = cloneCapRules(o.OutagePenaltyCapRules)
+	}
+	if len(o.StartupTimeCapRules) > 0 {
+		dp.StartupTimeCapRules = cloneCapRules(o.StartupTimeCapRules)
+	}
+	if len(o.MigrationCostCapRules) > 0 {
+		dp.MigrationCostCapRules = cloneCapRules(o.MigrationCostCapRules)
+	}
+	if len(o.UtilizationCapRules) > 0 {
+		dp.UtilizationCapRules = cloneCapRules(o.UtilizationCapRules)
+	}
+	return &out
 }
 
 // LoadRuntimeConfig loads the runtime configuration from the specified path.
@@ -173,15 +385,21 @@ func LoadRuntimeConfig(path string) (*RuntimeConfig, error) {
 		return nil, fmt.Errorf("failed to parse runtime config: %w", err)
 	}
 
-	applyRuntimeDefaults(&cfg)
-	applyRuntimeClamps(&cfg)
-	if err := validateRuntimeConfig(&cfg); err != nil {
+	if err := NormalizeRuntimeConfig(&cfg); err != nil {
 		return nil, err
 	}
 
 	return &cfg, nil
 }
 
+// NormalizeRuntimeConfig applies the same defaults, clamps and validation as
+// LoadRuntimeConfig to a config decoded from another source (e.g. a CRD).
+func NormalizeRuntimeConfig(cfg *RuntimeConfig) error {
+	applyRuntimeDefaults(cfg)
+	applyRuntimeClamps(cfg)
+	return validateRuntimeConfig(cfg)
+}
+
 // DefaultRuntimeConfig returns a safe default runtime config.
 func DefaultRuntimeConfig() *RuntimeConfig {
 	cfg := RuntimeConfig{}
@@ -288,23 +506,7 @@ func applyRuntimeClamps(cfg *RuntimeConfig) {
 		cfg.MaxSpotRatio = 1.0
 	}
 
-	// Clamp ratio fields to valid range [0, 1]
-	cfg.MinSpotRatio = clampFloat(cfg.MinSpotRatio, 0, 1)
-	cfg.MaxSpotRatio = clampFloat(cfg.MaxSpotRatio, 0, 1)
-	cfg.TargetSpotRatio = clampFloat(cfg.TargetSpotRatio, 0, 1)
-
-	// Ensure min <= max
-	if cfg.MinSpotRatio > cfg.MaxSpotRatio {
-		cfg.MinSpotRatio = cfg.MaxSpotRatio
-	}
-
-	// Clamp target to be within min/max bounds
-	if cfg.TargetSpotRatio < cfg.MinSpotRatio {
-		cfg.TargetSpotRatio = cfg.MinSpotRatio
-	}
-	if cfg.TargetSpotRatio > cfg.MaxSpotRatio {
-		cfg.TargetSpotRatio = cfg.MaxSpotRatio
-	}
+	clampSpotRatioBounds(cfg)
 
 	// Keep control cadence in sane bounds.
 	if cfg.StepMinutes < 1 {
@@ -315,14 +517,24 @@ func applyRuntimeClamps(cfg *RuntimeConfig) {
 	}
 
 	// Normalize policy mode.
-	switch strings.ToLower(strings.TrimSpace(cfg.PolicyMode)) {
-	case PolicyModeDeterministic:
-		cfg.PolicyMode = PolicyModeDeterministic
-	case PolicyModeRL:
-		cfg.PolicyMode = PolicyModeRL
-	default:
+	cfg.PolicyMode = strings.ToLower(strings.TrimSpace(cfg.PolicyMode))
+	if !IsPolicyMode(cfg.PolicyMode) {
 		cfg.PolicyMode = PolicyModeDeterministic
 	}
+	if len(cfg.ShadowPolicies) > 0 {
+		shadows := make([]string, 0, len(cfg.ShadowPolicies))
+		seen := make(map[string]struct{}, len(cfg.ShadowPolicies))
+		for _, name := range cfg.ShadowPolicies {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if _, dup := seen[name]; dup || name == "" || name == cfg.PolicyMode {
+				continue
+			}
+			seen[name] = struct{}{}
+			shadows = append(shadows, name)
+		}
+		cfg.ShadowPolicies = shadows
+	}
+	normalizeShadowEvaluators(cfg)
 
 	// Clamp deterministic thresholds.
 	dp := &cfg.DeterministicPolicy
@@ -356,6 +568,10 @@ func applyRuntimeClamps(cfg *RuntimeConfig) {
 	dp.MigrationCostCapRules = dp.ResolvedMigrationCostCapRules()
 	dp.UtilizationCapRules = dp.ResolvedUtilizationCapRules()
 
+	for i := range cfg.PoolOverrides {
+		clampPoolOverride(&cfg.PoolOverrides[i])
+	}
+
 	dp.FeatureBuckets.PodStartupTimeSeconds = normalizeBoundaries(dp.FeatureBuckets.PodStartupTimeSeconds)
 	dp.FeatureBuckets.OutagePenaltyHours = normalizeBoundaries(dp.FeatureBuckets.OutagePenaltyHours)
 	dp.FeatureBuckets.PriorityScore = normalizeBoundaries(dp.FeatureBuckets.PriorityScore)
@@ -369,6 +585,51 @@ func applyRuntimeClamps(cfg *RuntimeConfig) {
 	}
 }
 
+// clampSpotRatioBounds clamps the ratio fields to [0, 1], keeps min <= max,
+// and pulls the target inside the bounds.
+func clampSpotRatioBounds(cfg *RuntimeConfig) {
+	cfg.MinSpotRatio = clampFloat(cfg.MinSpotRatio, 0, 1)
+	cfg.MaxSpotRatio = clampFloat(cfg.MaxSpotRatio, 0, 1)
+	cfg.TargetSpotRatio = clampFloat(cfg.TargetSpotRatio, 0, 1)
+
+	// Ensure min <= max
+	if cfg.MinSpotRatio > cfg.MaxSpotRatio {
+		cfg.MinSpotRatio = cfg.MaxSpotRatio
+	}
+
+	// Clamp target to be within min/max bounds
+	if cfg.TargetSpotRatio < cfg.MinSpotRatio {
+		cfg.TargetSpotRatio = cfg.MinSpotRatio
+	}
+	if cfg.TargetSpotRatio > cfg.MaxSpotRatio {
+		cfg.TargetSpotRatio = cfg.MaxSpotRatio
+	}
+}
+
+func clampPoolOverride(o *PoolRuntimeOverride) {
+	o.Name = strings.TrimSpace(o.Name)
+	for _, ratio := range []*float64{o.MinSpotRatio, o.MaxSpotRatio, o.TargetSpotRatio} {
+		if ratio != nil {
+			*ratio = clampFloat(*ratio, 0, 1)
+		}
+	}
+	if len(o.PriorityCapRules) > 0 {
+		o.PriorityCapRules = normalizeCapRules(o.PriorityCapRules, true)
+	}
+	if len(o.OutagePenaltyCapRules) > 0 {
+		o.OutagePenaltyCapRules = normalizeCapRules(o.OutagePenaltyCapRules, false)
+	}
+	if len(o.StartupTimeCapRules) > 0 {
+		o.StartupTimeCapRules = normalizeCapRules(o.StartupTimeCapRules, false)
+	}
+	if len(o.MigrationCostCapRules) > 0 {
+		o.MigrationCostCapRules = normalizeCapRules(o.MigrationCostCapRules, false)
+	}
+	if len(o.UtilizationCapRules) > 0 {
+		o.UtilizationCapRules = normalizeCapRules(o.UtilizationCapRules, true)
+	}
+}
+
 // clampFloat clamps a value to the given range [min, max].
 func clampFloat(v, min, max float64) float64 {
 	if v < min {
@@ -393,6 +654,24 @@ func (c *RuntimeConfig) UseDeterministicPolicy() bool {
 	return strings.EqualFold(c.PolicyMode, PolicyModeDeterministic)
 }
 
+// ShadowPolicyNames returns the policies to evaluate in shadow this tick:
+// ShadowPolicies, then "rl" when UseRLShadow is true.
+func (c *RuntimeConfig) ShadowPolicyNames() []string {
+	if c == nil {
+		return nil
+	}
+	names := append([]string(nil), c.ShadowPolicies...)
+	if c.UseRLShadow() {
+		for _, name := range names {
+			if name == PolicyModeRL {
+				return names
+			}
+		}
+		names = append(names, PolicyModeRL)
+	}
+	return names
+}
+
 // UseRLShadow reports whether RL shadow comparison should be recorded.
 // This only returns true when deterministic mode is active.
 func (c *RuntimeConfig) UseRLShadow() bool {
@@ -415,6 +694,27 @@ func validateRuntimeConfig(cfg *RuntimeConfig) error {
 	if strings.EqualFold(cfg.PolicyMode, PolicyModeRL) && cfg.RLShadowEnabled != nil && *cfg.RLShadowEnabled {
 		return fmt.Errorf("invalid runtime config: rl_shadow_enabled=true requires policy_mode=%q", PolicyModeDeterministic)
 	}
+	for _, name := range cfg.ShadowPolicies {
+		if !IsPolicyMode(name) {
+			return fmt.Errorf("invalid runtime config: shadow policy %q is not registered", name)
+		}
+	}
+	if err := validateShadowEvaluators(cfg); err != nil {
+		return err
+	}
+	seen := make(map[string]struct{}, len(cfg.PoolOverrides))
+	for i, o := range cfg.PoolOverrides {
+		if o.Name == "" {
+			return fmt.Errorf("invalid runtime config: pool_overrides[%d] requires a name", i)
+		}
+		if _, dup := seen[o.Name]; dup {
+			return fmt.Errorf("invalid runtime config: duplicate pool override %q", o.Name)
+		}
+		seen[o.Name] = struct{}{}
+		if len(o.Pools) == 0 && len(o.NodeSelector) == 0 {
+			return fmt.Errorf("invalid runtime config: pool override %q must set pools or node_selector", o.Name)
+		}
+	}
 	return nil
 }
 
@@ -402,6 +402,85 @@ func TestLoadRuntimeConfig_RLShadowToggleAndInvalidCombination(t *testing.T) {
 	})
 }
 
+func TestLoadRuntimeConfig_PoolOverrides(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "runtime.json")
+	content := `{
+		"min_spot_ratio": 0.1,
+		"max_spot_ratio": 0.8,
+		"target_spot_ratio": 0.5,
+		"pool_overrides": [
+			{
+				"name": "api",
+				"pools": ["api", "web"],
+				"max_spot_ratio": 0.4,
+				"priority_cap_rules": [
+					{ "threshold": 0.5, "max_spot_ratio": 0.3 },
+					{ "threshold": 0.9, "max_spot_ratio": 0.1 }
+				]
+			},
+			{
+				"name": "batch",
+				"node_selector": { "team": "batch" },
+				"min_spot_ratio": 0.5,
+				"max_spot_ratio": 1.5,
+				"target_spot_ratio": 1.0
+			}
+		]
+	}`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+	cfg, err := LoadRuntimeConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadRuntimeConfig failed: %v", err)
+	}
+
+	api := cfg.ForPool(PoolSelector{WorkloadPool: "web"})
+	if api.MinSpotRatio != 0.1 || api.MaxSpotRatio != 0.4 || api.TargetSpotRatio != 0.4 {
+		t.Fatalf("api bounds min=%v max=%v target=%v, want 0.1/0.4/0.4 (target clamped)", api.MinSpotRatio, api.MaxSpotRatio, api.TargetSpotRatio)
+	}
+	wantPriority := []SpotRatioCapRule{{Threshold: 0.9, MaxSpotRatio: 0.1}, {Threshold: 0.5, MaxSpotRatio: 0.3}}
+	if !reflect.DeepEqual(api.DeterministicPolicy.PriorityCapRules, wantPriority) {
+		t.Fatalf("api priority rules=%+v, want %+v", api.DeterministicPolicy.PriorityCapRules, wantPriority)
+	}
+	if !reflect.DeepEqual(api.DeterministicPolicy.OutagePenaltyCapRules, cfg.DeterministicPolicy.OutagePenaltyCapRules) {
+		t.Fatal("api outage rules should inherit the global rules")
+	}
+	if len(api.PoolOverrides) != 0 {
+		t.Fatal("resolved pool config should not carry pool overrides")
+	}
+
+	batch := cfg.ForPool(PoolSelector{WorkloadPool: "etl", NodeLabels: map[string]string{"team": "batch", "zone": "a"}})
+	if batch.MinSpotRatio != 0.5 || batch.MaxSpotRatio != 1.0 || batch.TargetSpotRatio != 1.0 {
+		t.Fatalf("batch bounds min=%v max=%v target=%v, want 0.5/1.0/1.0", batch.MinSpotRatio, batch.MaxSpotRatio, batch.TargetSpotRatio)
+	}
+
+	if other := cfg.ForPool(PoolSelector{WorkloadPool: "etl", NodeLabels: map[string]string{"team": "data"}}); other != cfg {
+		t.Fatal("unmatched pool should use the global config")
+	}
+	if cfg.MaxSpotRatio != 0.8 {
+		t.Fatalf("global max=%v mutated by ForPool, want 0.8", cfg.MaxSpotRatio)
+	}
+}
+
+func TestLoadRuntimeConfig_InvalidPoolOverrides(t *testing.T) {
+	tmpDir := t.TempDir()
+	for name, content := range map[string]string{
+		"missing name":     `{"pool_overrides": [{"pools": ["api"]}]}`,
+		"missing selector": `{"pool_overrides": [{"name": "api", "max_spot_ratio": 0.4}]}`,
+		"duplicate name":   `{"pool_overrides": [{"name": "api", "pools": ["a"]}, {"name": "api", "pools": ["b"]}]}`,
+	} {
+		configPath := filepath.Join(tmpDir, "runtime.json")
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write test config: %v", err)
+		}
+		if _, err := LoadRuntimeConfig(configPath); err == nil {
+			t.Fatalf("%s: expected invalid config error", name)
+		}
+	}
+}
+
 func TestLoadRuntimeConfig_InvalidJSON(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "invalid.json")
@@ -516,3 +595,60 @@ func TestPoolSafetyVector_DefaultAndNormalize(t *testing.T) {
 		t.Fatal("expected zero vector to report IsZero=true")
 	}
 }
+
+func TestRecoveryBudget_Validate(t *testing.T) {
+	b := RecoveryBudget{MaxUnavailableSeconds: 90}
+	if err := b.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if b.MinAvailable != 1 {
+		t.Fatalf("expected minAvailable default 1, got %d", b.MinAvailable)
+	}
+	for _, bad := range []RecoveryBudget{
+		{},
+		{MaxUnavailableSeconds: -1},
+		{MaxUnavailableSeconds: 30, MinAvailable: -1},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Fatalf("expected %+v to be rejected", bad)
+		}
+	}
+}
+
+func TestLoadRuntimeConfig_ShadowPolicies(t *testing.T) {
+	tmpDir := t.TempDir()
+	RegisterPolicyMode("Custom")
+
+	configPath := filepath.Join(tmpDir, "runtime-shadows.json")
+	content := `{
+		"policy_mode": "CUSTOM",
+		"shadow_policies": ["deterministic", "custom", " Deterministic ", ""]
+	}`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+	cfg, err := LoadRuntimeConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadRuntimeConfig failed: %v", err)
+	}
+	if cfg.PolicyMode != "custom" {
+		t.Fatalf("policy mode=%q, want registered custom policy", cfg.PolicyMode)
+	}
+	if got := cfg.ShadowPolicyNames(); len(got) != 1 || got[0] != PolicyModeDeterministic {
+		t.Fatalf("shadow policies=%v, want [deterministic] without the active policy or duplicates", got)
+	}
+
+	det := DefaultRuntimeConfig()
+	det.ShadowPolicies = []string{"custom"}
+	if got := det.ShadowPolicyNames(); len(got) != 2 || got[0] != "custom" || got[1] != PolicyModeRL {
+		t.Fatalf("deterministic shadow policies=%v, want [custom rl]", got)
+	}
+
+	unknownPath := filepath.Join(tmpDir, "runtime-unknown-shadow.json")
+	if err := os.WriteFile(unknownPath, []byte(`{"shadow_policies": ["nope"]}`), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+	if _, err := LoadRuntimeConfig(unknownPath); err == nil {
+		t.Fatal("expected unregistered shadow policy to be rejected")
+	}
+}
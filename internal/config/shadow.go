@@ -0,0 +1,170 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// defaultShadowReportWindowMinutes is the counterfactual report's rolling
+// window when shadow_report_window_minutes is unset.
+const defaultShadowReportWindowMinutes = 24 * 60
+
+// ShadowEvaluator is a named shadow: a registered policy evaluated every tick
+// against the same state as the active policy, on its own virtual spot-ratio
+// trajectory. It never actuates.
+type ShadowEvaluator struct {
+	Name string `json:"name"`
+	// Policy is the registered policy to run. Defaults to Name when that is
+	// a registered policy, otherwise to the active policy.
+	Policy string `json:"policy,omitempty"`
+	// Bundle names a candidate model bundle loaded by the agent
+	// (inference.shadowBundles). Empty uses the active bundle.
+	Bundle string `json:"bundle,omitempty"`
+	// RuntimeConfig is a partial runtime config overlaid on the active one
+	// for this shadow only. Nested objects merge key by key; lists and
+	// scalars replace the active value.
+	RuntimeConfig map[string]interface{} `json:"runtime_config,omitempty"`
+}
+
+// ResolvedShadowEvaluators returns every shadow to evaluate this tick:
+// ShadowEvaluators, then one evaluator per ShadowPolicyNames entry.
+func (c *RuntimeConfig) ResolvedShadowEvaluators() []ShadowEvaluator {
+	if c == nil {
+		return nil
+	}
+	out := make([]ShadowEvaluator, 0, len(c.ShadowEvaluators)+len(c.ShadowPolicies)+1)
+	seen := make(map[string]struct{}, cap(out))
+	for _, e := range c.ShadowEvaluators {
+		out = append(out, e)
+		seen[e.Name] = struct{}{}
+	}
+	for _, name := range c.ShadowPolicyNames() {
+		if _, dup := seen[name]; dup {
+			continue
+		}
+		out = append(out, ShadowEvaluator{Name: name, Policy: name})
+	}
+	return out
+}
+
+// ResolvedShadowReportWindowMinutes returns the counterfactual report window.
+func (c *RuntimeConfig) ResolvedShadowReportWindowMinutes() int {
+	if c == nil || c.ShadowReportWindowMinutes <= 0 {
+		return defaultShadowReportWindowMinutes
+	}
+	return c.ShadowReportWindowMinutes
+}
+
+// ForShadow returns the config the shadow evaluates under: c with the
+// shadow's RuntimeConfig overlay applied. Shadow lists are not inherited.
+// Returns c unchanged when the shadow has no overlay.
+func (c *RuntimeConfig) ForShadow(e ShadowEvaluator) (*RuntimeConfig, error) {
+	if c == nil || len(e.RuntimeConfig) == 0 {
+		return c, nil
+	}
+	base := *c
+	base.ShadowPolicies = nil
+	base.ShadowEvaluators = nil
+	base.RLShadowEnabled = nil
+	overrides := make(map[string]interface{}, len(e.RuntimeConfig))
+	for k, v := range e.RuntimeConfig {
+		switch k {
+		case "shadow_policies", "shadow_evaluators", "rl_shadow_enabled":
+			continue
+		}
+		overrides[k] = v
+	}
+	out, err := base.Overlay(overrides)
+	if err != nil {
+		return nil, fmt.Errorf("shadow %q: %w", e.Name, err)
+	}
+	return out, nil
+}
+
+// Overlay returns c with a partial runtime config merged on top, normalized
+// like a loaded config. Nested objects merge key by key; lists and scalars
+// replace the base value.
+func (c *RuntimeConfig) Overlay(overrides map[string]interface{}) (*RuntimeConfig, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("encode base runtime config: %w", err)
+	}
+	var merged map[string]interface{}
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, fmt.Errorf("decode base runtime config: %w", err)
+	}
+	mergeMaps(merged, overrides)
+
+	data, err = json.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("encode merged runtime config: %w", err)
+	}
+	var cfg RuntimeConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("decode merged runtime config: %w", err)
+	}
+	if err := NormalizeRuntimeConfig(&cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func mergeMaps(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeMaps(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// normalizeShadowEvaluators lowercases names and resolves default policies.
+func normalizeShadowEvaluators(cfg *RuntimeConfig) {
+	for i := range cfg.ShadowEvaluators {
+		e := &cfg.ShadowEvaluators[i]
+		e.Name = strings.ToLower(strings.TrimSpace(e.Name))
+		e.Policy = strings.ToLower(strings.TrimSpace(e.Policy))
+		if e.Policy == "" {
+			e.Policy = cfg.PolicyMode
+			if IsPolicyMode(e.Name) {
+				e.Policy = e.Name
+			}
+		}
+		e.Bundle = strings.TrimSpace(e.Bundle)
+	}
+	if cfg.ShadowReportWindowMinutes < 0 {
+		cfg.ShadowReportWindowMinutes = 0
+	}
+}
+
+func validateShadowEvaluators(cfg *RuntimeConfig) error {
+	seen := make(map[string]struct{}, len(cfg.ShadowEvaluators))
+	for i, e := range cfg.ShadowEvaluators {
+		if e.Name == "" {
+			return fmt.Errorf("invalid runtime config: shadow_evaluators[%d] requires a name", i)
+		}
+		if _, dup := seen[e.Name]; dup {
+			return fmt.Errorf("invalid runtime config: duplicate shadow evaluator %q", e.Name)
+		}
+		if e.Name == cfg.PolicyMode {
+			return fmt.Errorf("invalid runtime config: shadow evaluator %q shares the active policy's name", e.Name)
+		}
+		seen[e.Name] = struct{}{}
+		if !IsPolicyMode(e.Policy) {
+			return fmt.Errorf("invalid runtime config: shadow evaluator %q uses unregistered policy %q", e.Name, e.Policy)
+		}
+		if _, err := cfg.ForShadow(e); err != nil {
+			return fmt.Errorf("invalid runtime config: %w", err)
+		}
+	}
+	for _, name := range cfg.ShadowPolicies {
+		if _, dup := seen[name]; dup {
+			return fmt.Errorf("invalid runtime config: shadow policy %q collides with a shadow evaluator", name)
+		}
+	}
+	return nil
+}
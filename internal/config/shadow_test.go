@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadRuntimeConfig_ShadowEvaluators(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "runtime-evaluators.json")
+	content := `{
+		"policy_mode": "deterministic",
+		"max_spot_ratio": 0.9,
+		"shadow_evaluators": [
+			{"name": " Cautious ", "runtime_config": {"max_spot_ratio": 0.4, "deterministic_policy": {"high_risk_threshold": 0.5}}},
+			{"name": "candidate", "policy": "RL", "bundle": "next"}
+		],
+		"shadow_report_window_minutes": 120
+	}`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+	cfg, err := LoadRuntimeConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadRuntimeConfig failed: %v", err)
+	}
+	if cfg.ResolvedShadowReportWindowMinutes() != 120 {
+		t.Fatalf("window=%d, want 120", cfg.ResolvedShadowReportWindowMinutes())
+	}
+
+	shadows := cfg.ResolvedShadowEvaluators()
+	if len(shadows) != 3 || shadows[0].Name != "cautious" || shadows[0].Policy != PolicyModeDeterministic ||
+		shadows[1].Policy != PolicyModeRL || shadows[2].Name != PolicyModeRL {
+		t.Fatalf("shadows=%+v, want cautious, candidate, then rl", shadows)
+	}
+
+	alt, err := cfg.ForShadow(shadows[0])
+	if err != nil {
+		t.Fatalf("ForShadow: %v", err)
+	}
+	if alt.MaxSpotRatio != 0.4 || alt.DeterministicPolicy.HighRiskThreshold != 0.5 {
+		t.Fatalf("overlay max=%v high=%v, want 0.4/0.5", alt.MaxSpotRatio, alt.DeterministicPolicy.HighRiskThreshold)
+	}
+	if alt.DeterministicPolicy.MediumRiskThreshold != cfg.DeterministicPolicy.MediumRiskThreshold {
+		t.Fatal("overlay must keep unset nested fields from the active config")
+	}
+	if len(alt.ShadowEvaluators) != 0 || len(alt.ShadowPolicies) != 0 {
+		t.Fatal("shadow config must not inherit shadow lists")
+	}
+	if same, _ := cfg.ForShadow(shadows[1]); same != cfg {
+		t.Fatal("a shadow without an overlay evaluates under the active config")
+	}
+}
+
+func TestLoadRuntimeConfig_InvalidShadowEvaluators(t *testing.T) {
+	tmpDir := t.TempDir()
+	cases := map[string]string{
+		"missing name":        `{"shadow_evaluators": [{"policy": "rl"}]}`,
+		"duplicate name":      `{"shadow_evaluators": [{"name": "a", "policy": "rl"}, {"name": "a", "policy": "rl"}]}`,
+		"active name":         `{"policy_mode": "deterministic", "shadow_evaluators": [{"name": "deterministic"}]}`,
+		"unknown policy":      `{"shadow_evaluators": [{"name": "a", "policy": "nope"}]}`,
+		"invalid overlay":     `{"shadow_evaluators": [{"name": "a", "policy": "rl", "runtime_config": {"max_spot_ratio": "high"}}]}`,
+		"collides w/ shadows": `{"shadow_policies": ["rl"], "shadow_evaluators": [{"name": "rl"}]}`,
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(tmpDir, "runtime.json")
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("failed to write test config: %v", err)
+			}
+			if _, err := LoadRuntimeConfig(path); err == nil {
+				t.Fatal("expected invalid shadow evaluator to be rejected")
+			}
+		})
+	}
+}
@@ -0,0 +1,654 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/cloudapi"
+	"github.com/softcane/spot-vortex-agent/internal/config"
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+type nodeMetricsSourceFunc func(context.Context) ([]metrics.NodeMetrics, error)
+type assessmentObserverFunc func([]metrics.NodeMetrics, []NodeAssessment)
+
+// BacktestTrace is a recorded timeline replayed through Reconcile by RunBacktest.
+// Ticks that omit node metrics, cluster objects or prices carry the previous
+// tick's values forward, so traces only need to record what changed.
+type BacktestTrace struct {
+	// StepSeconds is the wall-clock spacing between ticks when timestamps are omitted.
+	// Defaults to the runtime config StepMinutes.
+	StepSeconds int       `json:"step_seconds,omitempty"`
+	Start       time.Time `json:"start,omitempty"`
+	// DefaultPrice is used for any instance type/zone without a per-tick price.
+	DefaultPrice cloudapi.FakePricePoint `json:"default_price"`
+	Ticks        []BacktestTick          `json:"ticks"`
+}
+
+// BacktestTick is one recorded reconcile input.
+type BacktestTick struct {
+	Timestamp   time.Time                      `json:"timestamp,omitempty"`
+	NodeMetrics []metrics.NodeMetrics          `json:"node_metrics,omitempty"`
+	Nodes       []corev1.Node                  `json:"nodes,omitempty"`
+	Pods        []corev1.Pod                   `json:"pods,omitempty"`
+	PDBs        []policyv1.PodDisruptionBudget `json:"pdbs,omitempty"`
+	// Prices are keyed by "<instanceType>:<zone>" (wildcards as in FakePriceScenario).
+	Prices map[string]cloudapi.FakePricePoint `json:"prices,omitempty"`
+	// Interruptions lists spot nodes reclaimed by the cloud after this tick's reconcile.
+	Interruptions []string `json:"interruptions,omitempty"`
+	// RuntimeConfig is the runtime config in force when the tick was
+	// recorded. It is replayed when BacktestConfig.RuntimeConfig is nil.
+	RuntimeConfig *config.RuntimeConfig `json:"runtime_config,omitempty"`
+}
+
+// HasRuntimeConfig reports whether any tick carries a recorded runtime config.
+func (t *BacktestTrace) HasRuntimeConfig() bool {
+	for _, tick := range t.Ticks {
+		if tick.RuntimeConfig != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadBacktestTrace reads a trace from disk: a JSON BacktestTrace, a trace
+// recorder file (*.jsonl.gz), or a trace recorder directory, whose files are
+// replayed oldest first.
+func LoadBacktestTrace(path string) (*BacktestTrace, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("read backtest trace %q: %w", path, err)
+	}
+	if info.IsDir() || strings.HasSuffix(path, traceFileSuffix) {
+		return loadRecordedTrace(path, info.IsDir())
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read backtest trace %q: %w", path, err)
+	}
+	var trace BacktestTrace
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&trace); err != nil {
+		return nil, fmt.Errorf("decode backtest trace %q: %w", path, err)
+	}
+	return &trace, nil
+}
+
+func loadRecordedTrace(path string, isDir bool) (*BacktestTrace, error) {
+	files := []string{path}
+	if isDir {
+		var err error
+		if files, err = ListTraceFiles(path); err != nil {
+			return nil, fmt.Errorf("list trace files in %q: %w", path, err)
+		}
+	}
+	var records []TickRecord
+	for _, file := range files {
+		recs, err := ReadTickRecords(file)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, recs...)
+	}
+	return BacktestTraceFromRecords(records), nil
+}
+
+// BacktestTraceFromRecords converts trace recorder output into a replayable
+// trace. The tick spacing is the mean gap between recorded timestamps.
+// Recorder traces carry no interruptions.
+func BacktestTraceFromRecords(records []TickRecord) *BacktestTrace {
+	trace := &BacktestTrace{Ticks: make([]BacktestTick, 0, len(records))}
+	if n := len(records); n > 1 {
+		span := records[n-1].Timestamp.Sub(records[0].Timestamp)
+		trace.StepSeconds = int((span / time.Duration(n-1)).Round(time.Second).Seconds())
+	}
+	for _, rec := range records {
+		tick := BacktestTick{
+			Timestamp:     rec.Timestamp,
+			NodeMetrics:   rec.NodeMetrics,
+			Nodes:         rec.Nodes,
+			Pods:          rec.Pods,
+			PDBs:          rec.PDBs,
+			RuntimeConfig: rec.RuntimeConfig,
+		}
+		if len(rec.SpotPrices) > 0 {
+			tick.Prices = make(map[string]cloudapi.FakePricePoint, len(rec.SpotPrices))
+		}
+		for _, price := range rec.SpotPrices {
+			current, onDemand, volatility := price.CurrentPrice, price.OnDemandPrice, price.Volatility
+			history := append([]float64(nil), price.PriceHistory...)
+			tick.Prices[price.InstanceType+":"+price.Zone] = cloudapi.FakePricePoint{
+				CurrentPrice:  &current,
+				OnDemandPrice: &onDemand,
+				PriceHistory:  &history,
+				Volatility:    &volatility,
+			}
+		}
+		trace.Ticks = append(trace.Ticks, tick)
+	}
+	return trace
+}
+
+// BacktestConfig configures an offline replay.
+type BacktestConfig struct {
+	Trace *BacktestTrace
+	// RuntimeConfig is the candidate runtime policy under evaluation.
+	// Nil replays each tick's recorded runtime config, falling back to
+	// config.DefaultRuntimeConfig().
+	RuntimeConfig       *config.RuntimeConfig
+	Inference           *inference.InferenceEngine
+	Karpenter           config.KarpenterConfig
+	Logger              *slog.Logger
+	RiskThreshold       float64
+	MaxDrainRatio       float64
+	ConfidenceThreshold float64
+
+	// predictDetailedOverride replaces model inference in tests.
+	predictDetailedOverride predictDetailedFunc
+}
+
+// BacktestDecision is one node assessment produced during replay.
+type BacktestDecision struct {
+	NodeID        string             `json:"node_id"`
+	Pool          string             `json:"pool"`
+	Action        string             `json:"action"`
+	ResponseMode  PolicyResponseMode `json:"response_mode,omitempty"`
+	Urgency       PolicyUrgency      `json:"urgency,omitempty"`
+	CapacityScore float32            `json:"capacity_score"`
+	RuntimeScore  float32            `json:"runtime_score"`
+	Confidence    float32            `json:"confidence"`
+}
+
+// BacktestTickResult is the per-tick decision log entry.
+type BacktestTickResult struct {
+	Index     int                `json:"index"`
+	Timestamp time.Time          `json:"timestamp"`
+	Decisions []BacktestDecision `json:"decisions"`
+	// Migrations are nodes the controller drained on this tick.
+	Migrations []string `json:"migrations,omitempty"`
+	// Outages are interruptions that hit a node the controller had not migrated.
+	Outages              []string           `json:"outages,omitempty"`
+	InterruptionsAvoided []string           `json:"interruptions_avoided,omitempty"`
+	TargetSpotRatio      map[string]float64 `json:"target_spot_ratio"`
+	SpotResidency        float64            `json:"spot_residency"`
+	CostUSD              float64            `json:"cost_usd"`
+	OnDemandCostUSD      float64            `json:"on_demand_cost_usd"`
+}
+
+// BacktestSummary aggregates a replay.
+type BacktestSummary struct {
+	Ticks         int     `json:"ticks"`
+	DurationHours float64 `json:"duration_hours"`
+	// SpotResidency is the node-weighted mean target spot ratio across ticks.
+	SpotResidency        float64        `json:"spot_residency"`
+	Migrations           int            `json:"migrations"`
+	Interruptions        int            `json:"interruptions"`
+	SimulatedOutages     int            `json:"simulated_outages"`
+	InterruptionsAvoided int            `json:"interruptions_avoided"`
+	CostUSD              float64        `json:"cost_usd"`
+	OnDemandCostUSD      float64        `json:"on_demand_cost_usd"`
+	SavingsUSD           float64        `json:"savings_usd"`
+	SavingsPercent       float64        `json:"savings_percent"`
+	ActionCounts         map[string]int `json:"action_counts"`
+}
+
+// BacktestReport is the full result of RunBacktest.
+type BacktestReport struct {
+	Ticks   []BacktestTickResult `json:"ticks"`
+	Summary BacktestSummary      `json:"summary"`
+}
+
+// replayCloudProvider runs the controller in active mode so the real drain
+// path executes against the fake cluster; no cloud calls are made.
+type replayCloudProvider struct{}
+
+func (replayCloudProvider) Drain(ctx context.Context, req cloudapi.DrainRequest) (*cloudapi.DrainResult, error) {
+	return &cloudapi.DrainResult{NodeID: req.NodeID, Success: true}, nil
+}
+
+func (replayCloudProvider) Provision(ctx context.Context, req cloudapi.ProvisionRequest) (*cloudapi.ProvisionResult, error) {
+	return &cloudapi.ProvisionResult{InstanceType: req.InstanceType, Zone: req.Zone, IsSpot: true}, nil
+}
+
+func (replayCloudProvider) IsDryRun() bool {
+	return false
+}
+
+// replayPriceProvider swaps in a single-step FakePriceProvider per tick so
+// repeated lookups within one reconcile see the same recorded price.
+type replayPriceProvider struct {
+	current *cloudapi.FakePriceProvider
+}
+
+func (p *replayPriceProvider) GetSpotPrice(ctx context.Context, instanceType, zone string) (cloudapi.SpotPriceData, error) {
+	if p.current == nil {
+		return cloudapi.SpotPriceData{}, fmt.Errorf("no recorded prices for %s:%s", instanceType, zone)
+	}
+	return p.current.GetSpotPrice(ctx, instanceType, zone)
+}
+
+func (p *replayPriceProvider) GetOnDemandPrice(ctx context.Context, instanceType, zone string) (float64, error) {
+	if p.current == nil {
+		return 0, fmt.Errorf("no recorded prices for %s:%s", instanceType, zone)
+	}
+	return p.current.GetOnDemandPrice(ctx, instanceType, zone)
+}
+
+var podsGVR = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+// RunBacktest replays a recorded trace through the real Reconcile path using
+// a fake Kubernetes clientset and recorded prices. Drained nodes are treated
+// as replaced: they stay cordoned and drop out of the replayed metrics for the
+// rest of the run, and later interruptions on them count as avoided.
+func RunBacktest(ctx context.Context, cfg BacktestConfig) (*BacktestReport, error) {
+	trace := cfg.Trace
+	if trace == nil || len(trace.Ticks) == 0 {
+		return nil, fmt.Errorf("backtest trace must contain at least one tick")
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	runtimeCfg := cfg.RuntimeConfig
+	replayRecorded := runtimeCfg == nil
+	if replayRecorded {
+		for _, tick := range trace.Ticks {
+			if tick.RuntimeConfig != nil {
+				runtimeCfg = tick.RuntimeConfig
+				break
+			}
+		}
+	}
+	if runtimeCfg == nil {
+		runtimeCfg = config.DefaultRuntimeConfig()
+	}
+
+	step := time.Duration(trace.StepSeconds) * time.Second
+	if trace.StepSeconds == 0 {
+		step = time.Duration(runtimeCfg.StepMinutes) * time.Minute
+	}
+	if step < 10*time.Second {
+		return nil, fmt.Errorf("backtest step must be >= 10s, got %s", step)
+	}
+	start := trace.Start
+	if start.IsZero() {
+		start = trace.Ticks[0].Timestamp
+	}
+	if start.IsZero() {
+		start = time.Unix(0, 0).UTC()
+	}
+
+	client := k8sfake.NewSimpleClientset()
+	// The fake clientset does not implement the eviction subresource; treat
+	// evictions as immediate pod deletion.
+	client.PrependReactor("create", "pods/eviction", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		create, ok := action.(k8stesting.CreateAction)
+		if !ok {
+			return false, nil, nil
+		}
+		name := ""
+		if obj, ok := create.GetObject().(metav1.Object); ok {
+			name = obj.GetName()
+		}
+		if err := client.Tracker().Delete(podsGVR, action.GetNamespace(), name); err != nil {
+			return true, nil, err
+		}
+		return true, nil, nil
+	})
+
+	prices := &replayPriceProvider{}
+	inf := cfg.Inference
+	if inf == nil && cfg.predictDetailedOverride != nil {
+		inf = &inference.InferenceEngine{}
+	}
+
+	ctrl, err := New(Config{
+		Cloud:                         replayCloudProvider{},
+		PriceProvider:                 prices,
+		K8sClient:                     client,
+		Inference:                     inf,
+		PrometheusClient:              &metrics.Client{},
+		Logger:                        logger,
+		RiskThreshold:                 cfg.RiskThreshold,
+		MaxDrainRatio:                 cfg.MaxDrainRatio,
+		ReconcileInterval:             step,
+		ConfidenceThreshold:           cfg.ConfidenceThreshold,
+		Karpenter:                     cfg.Karpenter,
+		ReliabilityTelemetryCollector: metrics.NoopReliabilityTelemetryCollector{},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("build replay controller: %w", err)
+	}
+	ctrl.predictDetailedOverride = cfg.predictDetailedOverride
+	ctrl.runtimeConfigLoader = func() *config.RuntimeConfig { return runtimeCfg }
+
+	report := &BacktestReport{
+		Ticks: make([]BacktestTickResult, 0, len(trace.Ticks)),
+		Summary: BacktestSummary{
+			ActionCounts: make(map[string]int),
+		},
+	}
+
+	var (
+		nodeMetrics   []metrics.NodeMetrics
+		nodes         []corev1.Node
+		pods          []corev1.Pod
+		pdbs          []policyv1.PodDisruptionBudget
+		tickPrices    = make(map[string]cloudapi.FakePricePoint)
+		migrated      = make(map[string]bool)
+		residencySum  float64
+		residencyNode int
+	)
+	stepHours := step.Hours()
+
+	for i, tick := range trace.Ticks {
+		ts := tick.Timestamp
+		if ts.IsZero() {
+			ts = start.Add(time.Duration(i) * step)
+		}
+		ctrl.now = func() time.Time { return ts }
+
+		if replayRecorded && tick.RuntimeConfig != nil {
+			runtimeCfg = tick.RuntimeConfig
+		}
+		if tick.NodeMetrics != nil {
+			nodeMetrics = tick.NodeMetrics
+		}
+		if tick.Nodes != nil {
+			nodes = tick.Nodes
+		}
+		if tick.Pods != nil {
+			pods = tick.Pods
+		}
+		if tick.PDBs != nil {
+			pdbs = tick.PDBs
+		}
+		for key, point := range tick.Prices {
+			tickPrices[key] = point
+		}
+
+		if err := syncReplayCluster(ctx, client, nodes, pods, pdbs, migrated); err != nil {
+			return nil, fmt.Errorf("tick %d: sync cluster: %w", i, err)
+		}
+		provider, err := replayPricesForTick(trace.DefaultPrice, tickPrices)
+		if err != nil {
+			return nil, fmt.Errorf("tick %d: %w", i, err)
+		}
+		prices.current = provider
+
+		active := make([]metrics.NodeMetrics, 0, len(nodeMetrics))
+		for _, m := range nodeMetrics {
+			if !migrated[strings.TrimSpace(m.NodeID)] {
+				active = append(active, m)
+			}
+		}
+		ctrl.nodeMetricsSource = func(context.Context) ([]metrics.NodeMetrics, error) {
+			return active, nil
+		}
+
+		var assessments []NodeAssessment
+		ctrl.assessmentObserver = func(_ []metrics.NodeMetrics, out []NodeAssessment) {
+			assessments = out
+		}
+
+		if err := ctrl.Reconcile(ctx); err != nil {
+			return nil, fmt.Errorf("tick %d: reconcile: %w", i, err)
+		}
+
+		result := BacktestTickResult{
+			Index:           i,
+			Timestamp:       ts,
+			Decisions:       make([]BacktestDecision, 0, len(assessments)),
+			TargetSpotRatio: make(map[string]float64),
+		}
+
+		poolByNode, err := ctrl.replayPoolsByNode(ctx, nodeMetrics)
+		if err != nil {
+			return nil, fmt.Errorf("tick %d: resolve pools: %w", i, err)
+		}
+		for _, a := range assessments {
+			action := inference.ActionToString(a.Action)
+			report.Summary.ActionCounts[action]++
+			result.Decisions = append(result.Decisions, BacktestDecision{
+				NodeID:        a.NodeID,
+				Pool:          poolByNode[a.NodeID].poolID,
+				Action:        action,
+				ResponseMode:  a.ResponseMode,
+				Urgency:       a.Urgency,
+				CapacityScore: a.CapacityScore,
+				RuntimeScore:  a.RuntimeScore,
+				Confidence:    a.Confidence,
+			})
+		}
+
+		drained, err := newlyCordonedNodes(ctx, client, nodes, migrated)
+		if err != nil {
+			return nil, fmt.Errorf("tick %d: list nodes: %w", i, err)
+		}
+		for _, name := range drained {
+			migrated[name] = true
+		}
+		result.Migrations = drained
+
+		for _, name := range tick.Interruptions {
+			if migrated[name] {
+				result.InterruptionsAvoided = append(result.InterruptionsAvoided, name)
+			} else {
+				result.Outages = append(result.Outages, name)
+			}
+		}
+
+		ctrl.historyLock.Lock()
+		for poolID, ratio := range ctrl.targetSpotRatio {
+			result.TargetSpotRatio[poolID] = ratio
+		}
+		ctrl.historyLock.Unlock()
+
+		tickNodes := 0
+		for _, m := range nodeMetrics {
+			pool, ok := poolByNode[strings.TrimSpace(m.NodeID)]
+			if !ok {
+				continue
+			}
+			ratio := result.TargetSpotRatio[pool.poolID]
+			spot, onDemand := m.SpotPrice, m.OnDemandPrice
+			if spot <= 0 || onDemand <= 0 {
+				data, err := provider.GetSpotPrice(ctx, pool.instanceType, pool.zone)
+				if err == nil {
+					if spot <= 0 {
+						spot = data.CurrentPrice
+					}
+					if onDemand <= 0 {
+						onDemand = data.OnDemandPrice
+					}
+				}
+			}
+			tickNodes++
+			result.SpotResidency += ratio
+			if spot <= 0 || onDemand <= 0 {
+				continue
+			}
+			result.CostUSD += (ratio*spot + (1-ratio)*onDemand) * stepHours
+			result.OnDemandCostUSD += onDemand * stepHours
+		}
+		residencySum += result.SpotResidency
+		residencyNode += tickNodes
+		if tickNodes > 0 {
+			result.SpotResidency /= float64(tickNodes)
+		}
+
+		report.Summary.Migrations += len(result.Migrations)
+		report.Summary.Interruptions += len(tick.Interruptions)
+		report.Summary.SimulatedOutages += len(result.Outages)
+		report.Summary.InterruptionsAvoided += len(result.InterruptionsAvoided)
+		report.Summary.CostUSD += result.CostUSD
+		report.Summary.OnDemandCostUSD += result.OnDemandCostUSD
+		report.Ticks = append(report.Ticks, result)
+	}
+
+	report.Summary.Ticks = len(report.Ticks)
+	report.Summary.DurationHours = float64(len(report.Ticks)) * stepHours
+	if residencyNode > 0 {
+		report.Summary.SpotResidency = residencySum / float64(residencyNode)
+	}
+	report.Summary.SavingsUSD = report.Summary.OnDemandCostUSD - report.Summary.CostUSD
+	if report.Summary.OnDemandCostUSD > 0 {
+		report.Summary.SavingsPercent = report.Summary.SavingsUSD / report.Summary.OnDemandCostUSD * 100
+	}
+	return report, nil
+}
+
+type replayPool struct {
+	poolID       string
+	instanceType string
+	zone         string
+}
+
+// replayPoolsByNode resolves pool identity the same way runInference does.
+func (c *Controller) replayPoolsByNode(ctx context.Context, nodeMetrics []metrics.NodeMetrics) (map[string]replayPool, error) {
+	info, err := c.nodeInfoMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pools := make(map[string]replayPool, len(nodeMetrics))
+	for _, m := range nodeMetrics {
+		nodeID := strings.TrimSpace(m.NodeID)
+		instanceType, zone, workloadPool := m.InstanceType, m.Zone, ""
+		meta, ok := info[nodeID]
+		if !ok {
+			meta, ok = info[strings.Split(nodeID, ":")[0]]
+		}
+		if ok {
+			nodeID = meta.name
+			if zone == "" {
+				zone = meta.zone
+			}
+			if instanceType == "" {
+				instanceType = meta.instanceType
+			}
+			workloadPool = meta.workloadPool
+		}
+		poolID := c.getPoolIDWithExtendedFormat(instanceType, zone, workloadPool)
+		if instanceType == "" || zone == "" {
+			poolID = "unknown:unknown"
+		}
+		pools[nodeID] = replayPool{poolID: poolID, instanceType: instanceType, zone: zone}
+	}
+	return pools, nil
+}
+
+// syncReplayCluster resets the fake clientset to the recorded objects.
+func syncReplayCluster(
+	ctx context.Context,
+	client *k8sfake.Clientset,
+	nodes []corev1.Node,
+	pods []corev1.Pod,
+	pdbs []policyv1.PodDisruptionBudget,
+	migrated map[string]bool,
+) error {
+	existingNodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, n := range existingNodes.Items {
+		if err := client.CoreV1().Nodes().Delete(ctx, n.Name, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+	}
+	existingPods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, p := range existingPods.Items {
+		if err := client.CoreV1().Pods(p.Namespace).Delete(ctx, p.Name, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+	}
+	existingPDBs, err := client.PolicyV1().PodDisruptionBudgets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, p := range existingPDBs.Items {
+		if err := client.PolicyV1().PodDisruptionBudgets(p.Namespace).Delete(ctx, p.Name, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+	}
+
+	for i := range nodes {
+		node := nodes[i].DeepCopy()
+		if migrated[node.Name] {
+			node.Spec.Unschedulable = true
+		}
+		if _, err := client.CoreV1().Nodes().Create(ctx, node, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("node %s: %w", node.Name, err)
+		}
+	}
+	for i := range pods {
+		pod := pods[i].DeepCopy()
+		if migrated[pod.Spec.NodeName] {
+			continue
+		}
+		if _, err := client.CoreV1().Pods(pod.Namespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+	}
+	for i := range pdbs {
+		pdb := pdbs[i].DeepCopy()
+		if _, err := client.PolicyV1().PodDisruptionBudgets(pdb.Namespace).Create(ctx, pdb, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("pdb %s/%s: %w", pdb.Namespace, pdb.Name, err)
+		}
+	}
+	return nil
+}
+
+// newlyCordonedNodes returns nodes cordoned by the drainer during this tick.
+func newlyCordonedNodes(ctx context.Context, client *k8sfake.Clientset, recorded []corev1.Node, migrated map[string]bool) ([]string, error) {
+	recordedCordon := make(map[string]bool, len(recorded))
+	for _, n := range recorded {
+		recordedCordon[n.Name] = n.Spec.Unschedulable
+	}
+	list, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var drained []string
+	for _, n := range list.Items {
+		if n.Spec.Unschedulable && !recordedCordon[n.Name] && !migrated[n.Name] {
+			drained = append(drained, n.Name)
+		}
+	}
+	sort.Strings(drained)
+	return drained, nil
+}
+
+func replayPricesForTick(defaultPrice cloudapi.FakePricePoint, points map[string]cloudapi.FakePricePoint) (*cloudapi.FakePriceProvider, error) {
+	scenario := cloudapi.FakePriceScenario{
+		Default: defaultPrice,
+		Series:  make(map[string][]cloudapi.FakePricePoint, len(points)),
+	}
+	for key, point := range points {
+		scenario.Series[key] = []cloudapi.FakePricePoint{point}
+	}
+	provider, err := cloudapi.NewFakePriceProvider(scenario)
+	if err != nil {
+		return nil, fmt.Errorf("recorded prices: %w", err)
+	}
+	return provider, nil
+}
@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/cloudapi"
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+)
+
+const backtestTraceFixture = `{
+  "step_seconds": 600,
+  "start": "2026-01-05T00:00:00Z",
+  "default_price": {"current_price": 0.2, "on_demand_price": 1.0, "price_history": [0.2, 0.21, 0.19]},
+  "ticks": [
+    {
+      "node_metrics": [
+        {"node_id": "node-risky", "cpu_usage_percent": 40, "memory_usage_percent": 50},
+        {"node_id": "node-calm", "cpu_usage_percent": 40, "memory_usage_percent": 50}
+      ],
+      "nodes": [
+        {"metadata": {"name": "node-risky", "labels": {
+          "karpenter.sh/capacity-type": "spot",
+          "topology.kubernetes.io/zone": "us-east-1a",
+          "node.kubernetes.io/instance-type": "m5.large",
+          "spotvortex.io/managed": "true"}}},
+        {"metadata": {"name": "node-calm", "labels": {
+          "karpenter.sh/capacity-type": "spot",
+          "topology.kubernetes.io/zone": "us-east-1b",
+          "node.kubernetes.io/instance-type": "c5.large"}}}
+      ]
+    },
+    {
+      "interruptions": ["node-risky", "node-calm"]
+    }
+  ]
+}`
+
+func TestRunBacktest_ReplaysTraceThroughReconcile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.json")
+	if err := os.WriteFile(path, []byte(backtestTraceFixture), 0o600); err != nil {
+		t.Fatalf("write trace: %v", err)
+	}
+	trace, err := LoadBacktestTrace(path)
+	if err != nil {
+		t.Fatalf("LoadBacktestTrace failed: %v", err)
+	}
+
+	report, err := RunBacktest(context.Background(), BacktestConfig{
+		Trace:               trace,
+		RuntimeConfig:       deterministicRuntimeConfigShadowTest(),
+		Logger:              slog.Default(),
+		RiskThreshold:       0.95,
+		MaxDrainRatio:       1.0,
+		ConfidenceThreshold: 0.5,
+		predictDetailedOverride: func(ctx context.Context, nodeID string, state inference.NodeState, riskMultiplier float64) (inference.Action, float32, float32, float32, error) {
+			if nodeID == "node-risky" {
+				return inference.ActionHold, 0.70, 0.10, 0.90, nil
+			}
+			return inference.ActionHold, 0.05, 0.05, 0.90, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("RunBacktest failed: %v", err)
+	}
+
+	if len(report.Ticks) != 2 {
+		t.Fatalf("ticks=%d, want 2", len(report.Ticks))
+	}
+	first := report.Ticks[0]
+	if len(first.Decisions) != 2 {
+		t.Fatalf("tick 0 decisions=%d, want 2", len(first.Decisions))
+	}
+	if len(first.Migrations) != 1 || first.Migrations[0] != "node-risky" {
+		t.Fatalf("tick 0 migrations=%v, want [node-risky]", first.Migrations)
+	}
+	if got := first.TargetSpotRatio["m5.large:us-east-1a"]; got > 0.71 || got < 0.69 {
+		t.Fatalf("target spot ratio for risky pool=%v, want ~0.70", got)
+	}
+
+	second := report.Ticks[1]
+	for _, d := range second.Decisions {
+		if d.NodeID == "node-risky" {
+			t.Fatal("migrated node should drop out of replayed metrics")
+		}
+	}
+	if second.Timestamp.Sub(first.Timestamp).Minutes() != 10 {
+		t.Fatalf("tick spacing=%v, want 10m", second.Timestamp.Sub(first.Timestamp))
+	}
+
+	s := report.Summary
+	if s.Migrations != 1 || s.Interruptions != 2 || s.InterruptionsAvoided != 1 || s.SimulatedOutages != 1 {
+		t.Fatalf("summary=%+v, want 1 migration, 2 interruptions, 1 avoided, 1 outage", s)
+	}
+	if s.SavingsUSD <= 0 || s.SavingsPercent <= 0 || s.SavingsPercent >= 100 {
+		t.Fatalf("savings=%v (%v%%), want positive partial savings", s.SavingsUSD, s.SavingsPercent)
+	}
+	if s.SpotResidency <= 0 || s.SpotResidency > 1 {
+		t.Fatalf("spot residency=%v, want (0,1]", s.SpotResidency)
+	}
+}
+
+func TestRunBacktest_RejectsEmptyTrace(t *testing.T) {
+	if _, err := RunBacktest(context.Background(), BacktestConfig{Trace: &BacktestTrace{}}); err == nil {
+		t.Fatal("expected empty trace to be rejected")
+	}
+}
+
+func TestRunBacktest_ReplaysRecorderDirectory(t *testing.T) {
+	var trace BacktestTrace
+	if err := json.Unmarshal([]byte(backtestTraceFixture), &trace); err != nil {
+		t.Fatalf("decode fixture: %v", err)
+	}
+	recorded := deterministicRuntimeConfigShadowTest()
+	recorded.StepMinutes = 10
+	recorded.RiskMultiplier = 1.25
+
+	dir := t.TempDir()
+	rec, err := NewTraceRecorder(TraceRecorderConfig{Dir: dir, MaxFileBytes: 1 << 20, MaxTotalBytes: 4 << 20})
+	if err != nil {
+		t.Fatalf("NewTraceRecorder failed: %v", err)
+	}
+	start := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		if err := rec.Record(TickRecord{
+			Timestamp:     start.Add(time.Duration(i) * 10 * time.Minute),
+			NodeMetrics:   trace.Ticks[0].NodeMetrics,
+			Nodes:         trace.Ticks[0].Nodes,
+			SpotPrices:    []cloudapi.SpotPriceData{{CurrentPrice: 0.2, OnDemandPrice: 1.0, PriceHistory: []float64{0.2, 0.21}, InstanceType: "m5.large", Zone: "us-east-1a"}},
+			RuntimeConfig: recorded,
+		}); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	loaded, err := LoadBacktestTrace(dir)
+	if err != nil {
+		t.Fatalf("LoadBacktestTrace failed: %v", err)
+	}
+	if len(loaded.Ticks) != 2 || loaded.StepSeconds != 600 || !loaded.HasRuntimeConfig() {
+		t.Fatalf("trace ticks=%d step=%ds, want 2 ticks 600s apart with recorded config", len(loaded.Ticks), loaded.StepSeconds)
+	}
+	point, ok := loaded.Ticks[0].Prices["m5.large:us-east-1a"]
+	if !ok || point.CurrentPrice == nil || *point.CurrentPrice != 0.2 {
+		t.Fatalf("prices=%+v, want recorded m5.large:us-east-1a price", loaded.Ticks[0].Prices)
+	}
+
+	// Without a candidate config the recorded one is replayed.
+	var multipliers []float64
+	report, err := RunBacktest(context.Background(), BacktestConfig{
+		Trace:               loaded,
+		Logger:              slog.Default(),
+		RiskThreshold:       0.95,
+		MaxDrainRatio:       1.0,
+		ConfidenceThreshold: 0.5,
+		predictDetailedOverride: func(ctx context.Context, nodeID string, state inference.NodeState, riskMultiplier float64) (inference.Action, float32, float32, float32, error) {
+			multipliers = append(multipliers, riskMultiplier)
+			if nodeID == "node-risky" {
+				return inference.ActionHold, 0.70, 0.10, 0.90, nil
+			}
+			return inference.ActionHold, 0.05, 0.05, 0.90, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("RunBacktest failed: %v", err)
+	}
+	if len(report.Ticks) != 2 || report.Summary.Migrations != 1 {
+		t.Fatalf("report ticks=%d migrations=%d, want 2 ticks and node-risky migrated", len(report.Ticks), report.Summary.Migrations)
+	}
+	if report.Summary.DurationHours < 0.33 || report.Summary.DurationHours > 0.34 {
+		t.Fatalf("duration=%vh, want two 10m ticks", report.Summary.DurationHours)
+	}
+	if len(multipliers) == 0 {
+		t.Fatal("expected model inference during replay")
+	}
+	for _, m := range multipliers {
+		if m != 1.25 {
+			t.Fatalf("risk multipliers=%v, want the recorded 1.25", multipliers)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+)
+
+// BundleRollout stages a model bundle next to the active one and decides
+// when to promote or roll it back. inference.BundleManager satisfies it.
+type BundleRollout interface {
+	// Staged returns the bundle to compare against the active one, if any.
+	Staged() (string, inference.Predictor, bool)
+	// RecordShadow records whether the staged bundle's decision agreed with
+	// the active decision, or why it could not be made.
+	RecordShadow(version string, agree bool, err error)
+	// RecordActive records one inference call of the active bundle.
+	RecordActive(err error)
+	// EndTick closes an inference tick.
+	EndTick()
+}
+
+// evaluateStagedBundle scores the scope with the staged bundle, decides with
+// the active policy on the same state, and records agreement with the active
+// decision. Any prediction error counts against the staged bundle, including
+// RL fallbacks.
+func (c *Controller) evaluateStagedBundle(ctx context.Context, policy Policy, in PolicyInput, active PolicyDecision) {
+	if c.bundles == nil {
+		return
+	}
+	version, bundle, ok := c.bundles.Staged()
+	if !ok {
+		return
+	}
+	scope := in.NodeID
+	if scope == "" {
+		scope = in.PoolID
+	}
+	// The staged engine keeps its own price history, so the active scope is
+	// reused as is.
+	stagedIn := in
+	if err := c.rescore(ctx, bundle.PredictDetailed, scope, &stagedIn); err != nil {
+		c.bundles.RecordShadow(version, false, err)
+		return
+	}
+	decision, err := policy.Decide(ctx, stagedIn)
+	if err != nil {
+		c.bundles.RecordShadow(version, false, err)
+		return
+	}
+	agree := decision.Action == active.Action
+	if !agree && c.logger != nil {
+		c.logger.Debug("staged bundle disagreed",
+			"bundle_version", version,
+			"pool", in.PoolID,
+			"node_id", in.NodeID,
+			"active_action", inference.ActionToString(active.Action),
+			"staged_action", inference.ActionToString(decision.Action),
+		)
+	}
+	c.bundles.RecordShadow(version, agree, nil)
+}
+
+func (c *Controller) recordActiveInference(err error) {
+	if c.bundles != nil {
+		c.bundles.RecordActive(err)
+	}
+}
+
+func (c *Controller) endBundleTick() {
+	if c.bundles != nil {
+		c.bundles.EndTick()
+	}
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+	svmetrics "github.com/softcane/spot-vortex-agent/internal/metrics"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// fakeRollout stages a bundle that sees critical risk on node-1 only.
+type fakeRollout struct {
+	scopes   []string
+	agreed   map[string]bool
+	active   int
+	endTicks int
+}
+
+func (f *fakeRollout) Staged() (string, inference.Predictor, bool) { return "v2", f, true }
+
+func (f *fakeRollout) PredictDetailed(_ context.Context, nodeID string, _ inference.NodeState, _ float64) (inference.Action, float32, float32, float32, error) {
+	f.scopes = append(f.scopes, nodeID)
+	if nodeID == "node-1" {
+		return inference.ActionEmergencyExit, 0.99, 0.90, 0.9, nil
+	}
+	return inference.ActionIncrease30, 0.70, 0.10, 0.40, nil
+}
+
+func (f *fakeRollout) RecordShadow(version string, agree bool, err error) {
+	if version == "v2" && err == nil {
+		f.agreed[f.scopes[len(f.scopes)-1]] = agree
+	}
+}
+
+func (f *fakeRollout) RecordActive(error) { f.active++ }
+
+func (f *fakeRollout) EndTick() { f.endTicks++ }
+
+func TestRunInference_StagedBundleComparedOnActiveState(t *testing.T) {
+	k8sClient := k8sfake.NewSimpleClientset()
+	createNode(k8sClient, "node-1", "spot", "us-east-1a", "m5.large")
+	createNode(k8sClient, "node-2", "spot", "us-east-1b", "m5.large")
+	rollout := &fakeRollout{agreed: make(map[string]bool)}
+	ctrl, err := New(Config{
+		Cloud:               &MockCloudProvider{DryRun: true},
+		PriceProvider:       fixedPriceProvider(),
+		K8sClient:           k8sClient,
+		Inference:           &inference.InferenceEngine{},
+		PrometheusClient:    &svmetrics.Client{},
+		Logger:              slog.Default(),
+		RiskThreshold:       0.95,
+		MaxDrainRatio:       0.2,
+		ReconcileInterval:   10 * time.Second,
+		ConfidenceThreshold: 0.5,
+		Bundles:             rollout,
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctrl.runtimeConfigLoader = deterministicRuntimeConfigShadowTest
+	ctrl.predictDetailedOverride = func(ctx context.Context, nodeID string, state inference.NodeState, riskMultiplier float64) (inference.Action, float32, float32, float32, error) {
+		return inference.ActionIncrease30, 0.70, 0.10, 0.40, nil
+	}
+
+	assessments, err := ctrl.runInference(context.Background(), []svmetrics.NodeMetrics{
+		{NodeID: "node-1", InstanceType: "m5.large", Zone: "us-east-1a", IsSpot: true, CPUUsagePercent: 35},
+		{NodeID: "node-2", InstanceType: "m5.large", Zone: "us-east-1b", IsSpot: true, CPUUsagePercent: 35},
+	})
+	if err != nil {
+		t.Fatalf("runInference failed: %v", err)
+	}
+	for _, a := range assessments {
+		if a.Action != inference.ActionDecrease30 {
+			t.Fatalf("%s action=%s, want the active bundle's DECREASE_30", a.NodeID, inference.ActionToString(a.Action))
+		}
+	}
+
+	sort.Strings(rollout.scopes)
+	if len(rollout.scopes) != 2 || rollout.scopes[0] != "node-1" || rollout.scopes[1] != "node-2" {
+		t.Fatalf("staged scopes=%v, want the active scopes unprefixed", rollout.scopes)
+	}
+	if agree, ok := rollout.agreed["node-1"]; !ok || agree {
+		t.Fatalf("node-1 agreement=%v (recorded %v), want a disagreement", agree, ok)
+	}
+	if agree := rollout.agreed["node-2"]; !agree {
+		t.Fatal("node-2 must agree when both bundles see the same risk")
+	}
+	if rollout.active != 2 || rollout.endTicks != 1 {
+		t.Fatalf("active records=%d end ticks=%d, want 2 and 1", rollout.active, rollout.endTicks)
+	}
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/softcane/spot-vortex-agent/internal/kubecache"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Read helpers that serve from the informer cache once it has synced and
+// fall back to the API server otherwise (no cache, still warming, tests).
+// Returned objects may be shared with the cache and must not be mutated.
+
+func (c *Controller) getNode(ctx context.Context, name string) (*corev1.Node, error) {
+	if c.cache.HasSynced() {
+		return c.cache.Node(name)
+	}
+	return c.k8s.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *Controller) listNodes(ctx context.Context) ([]*corev1.Node, error) {
+	return listNodes(ctx, c.k8s, c.cache)
+}
+
+func (c *Controller) podsOnNode(ctx context.Context, nodeName string) ([]*corev1.Pod, error) {
+	return podsOnNode(ctx, c.k8s, c.cache, nodeName)
+}
+
+func listNodes(ctx context.Context, k8s kubernetes.Interface, cache *kubecache.Cache) ([]*corev1.Node, error) {
+	if cache.HasSynced() {
+		return cache.Nodes()
+	}
+	list, err := k8s.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*corev1.Node, 0, len(list.Items))
+	for i := range list.Items {
+		nodes = append(nodes, &list.Items[i])
+	}
+	return nodes, nil
+}
+
+func listPods(ctx context.Context, k8s kubernetes.Interface, cache *kubecache.Cache) ([]*corev1.Pod, error) {
+	if cache.HasSynced() {
+		return cache.Pods()
+	}
+	list, err := k8s.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pods := make([]*corev1.Pod, 0, len(list.Items))
+	for i := range list.Items {
+		pods = append(pods, &list.Items[i])
+	}
+	return pods, nil
+}
+
+func podsOnNode(ctx context.Context, k8s kubernetes.Interface, cache *kubecache.Cache, nodeName string) ([]*corev1.Pod, error) {
+	if cache.HasSynced() {
+		return cache.PodsOnNode(nodeName)
+	}
+	list, err := k8s.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	pods := make([]*corev1.Pod, 0, len(list.Items))
+	for i := range list.Items {
+		pods = append(pods, &list.Items[i])
+	}
+	return pods, nil
+}
+
+func pdbsInNamespace(ctx context.Context, k8s kubernetes.Interface, cache *kubecache.Cache, namespace string) ([]*policyv1.PodDisruptionBudget, error) {
+	if cache.HasSynced() {
+		return cache.PDBs(namespace)
+	}
+	list, err := k8s.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pdbs := make([]*policyv1.PodDisruptionBudget, 0, len(list.Items))
+	for i := range list.Items {
+		pdbs = append(pdbs, &list.Items[i])
+	}
+	return pdbs, nil
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/softcane/spot-vortex-agent/internal/capacity"
@@ -17,7 +18,9 @@ import (
 	"github.com/softcane/spot-vortex-agent/internal/collector"
 	"github.com/softcane/spot-vortex-agent/internal/config"
 	"github.com/softcane/spot-vortex-agent/internal/inference"
+	"github.com/softcane/spot-vortex-agent/internal/interruption"
 	"github.com/softcane/spot-vortex-agent/internal/karpenter"
+	"github.com/softcane/spot-vortex-agent/internal/kubecache"
 	"github.com/softcane/spot-vortex-agent/internal/metrics"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,20 +38,44 @@ type Controller struct {
 	prom   *metrics.Client
 	drain  *Drainer
 	coll   *collector.Collector
+	cache  *kubecache.Cache
 	logger *slog.Logger
 	metric *metricSynth
 
 	reliabilityTelemetry metrics.ReliabilityTelemetryCollector
+	runtimeSource        RuntimeConfigSource
 
-	// Test hooks (nil in production)
+	// recorder persists per-tick reconcile inputs (nil = disabled).
+	recorder       *TraceRecorder
+	recordedPrices *recordingPriceProvider
+
+	// explanations holds the latest deterministic decision explanation per pool.
+	explanations *DecisionExplanationStore
+
+	// shadows tracks shadow evaluators' virtual trajectories; shadowBundles
+	// are the candidate model bundles they may name.
+	shadows       *shadowLedger
+	shadowBundles map[string]ShadowPredictor
+
+	// bundles stages hot-swapped model bundles against the active one.
+	bundles BundleRollout
+
+	// Test and replay hooks (nil in production)
 	predictDetailedOverride      predictDetailedFunc
 	supportsInstanceTypeOverride supportsInstanceTypeFunc
 	runtimeConfigLoader          runtimeConfigLoaderFunc
+	nodeMetricsSource            nodeMetricsSourceFunc
+	assessmentObserver           assessmentObserverFunc
+	// now returns the logical time used for migration/cooldown bookkeeping.
+	// Backtests replace it with the recorded tick timestamp.
+	now func() time.Time
 
 	// Karpenter integration (per PRODUCTION_FLOW_EKS_KARPENTER.md)
 	nodePoolMgr   *karpenter.NodePoolManager
 	karpenterCfg  config.KarpenterConfig
 	dynamicClient dynamic.Interface
+	// labeler sets karpenter.sh/do-not-disrupt in handoff mode (nil otherwise).
+	labeler *karpenter.Labeler
 
 	// Capacity management: unified routing across Karpenter, CA, and MNG.
 	// Per integration_strategy.md: routes per-node based on provisioner labels.
@@ -72,8 +99,53 @@ type Controller struct {
 	currentSpotRatio map[string]float64
 	// poolNodeCounts tracks node counts per pool for drain calculation
 	poolNodeCounts map[string]*poolCount
+	// workloadPoolCounts splits poolNodeCounts by workload pool, then pool ID.
+	// Per-node pool IDs (instanceType:zone) carry no workload pool and can
+	// span several.
+	workloadPoolCounts map[string]map[string]*poolCount
 	// lastWeightChange tracks when weights were last changed per workload pool (for cooldown)
 	lastWeightChange map[string]time.Time
+	// karpenterSnapshots holds this tick's pre-steering NodePool state per
+	// workload pool, for rollback when no replacement NodeClaim initializes.
+	karpenterSnapshots map[string][]karpenter.SteeringSnapshot
+	// handoffs tracks nodes handed to Karpenter for replacement, by node name
+	// (see karpenter_handoff.go).
+	handoffs map[string]karpenterHandoff
+	// lastDecisionEvent tracks the last action/reason published per NodePool
+	lastDecisionEvent map[string]string
+	// lastClusterUtilization is the most recent tick's cluster utilization,
+	// used by guardrails for out-of-tick interruption handling.
+	lastClusterUtilization float64
+	// tickRuntimeConfig is the runtime config the most recent tick's
+	// inference ran with, for the trace recorder.
+	tickRuntimeConfig *config.RuntimeConfig
+
+	// Interruption handling (see interruption.go)
+	interruptionSources  []interruption.Source
+	interruptionHalfLife time.Duration
+	interruptionSignals  map[string]interruptionSignal
+	handledInterruptions map[string]time.Time
+
+	// Leader election (see leadership.go). following is false unless the
+	// controller was built with LeaderElection and has not been handed
+	// leadership; warm is set after the first inference pass.
+	following atomic.Bool
+	warm      atomic.Bool
+
+	// stateStore checkpoints cooldowns, targets and price history (see
+	// state.go). Nil disables persistence.
+	stateStore StateStore
+
+	// Health (see health.go). lastReconcile is the UnixNano end of the most
+	// recent tick and tickStarted the start of the running one (0 between
+	// ticks); lastAssessments is guarded by historyLock.
+	readinessMu     sync.Mutex
+	readinessChecks []namedReadinessCheck
+	readyTickLimit  time.Duration
+	lastReconcile   atomic.Int64
+	tickStarted     atomic.Int64
+	lastAssessments         []NodeAssessment
+	lastAssessedAt          time.Time
 }
 
 // poolCount tracks node counts per pool for drain calculation.
@@ -102,9 +174,50 @@ type Config struct {
 	Autoscaling config.AutoscalingConfig
 	// ASGClient for ASG operations (nil = disabled, use FakeASGClient for testing)
 	ASGClient capacity.ASGClient
+	// VMSSClient scales twin AKS node pools (nil = disabled, use FakeVMSSClient for testing)
+	VMSSClient capacity.VMSSClient
+	// EKSClient scales twin EKS managed node groups (nil = MNG pools use ASGClient,
+	// use FakeEKSClient for testing)
+	EKSClient capacity.EKSClient
+	// KubeCache serves nodes, pods and PDBs from shared informers once synced.
+	// Nil keeps every read on the API server.
+	KubeCache *kubecache.Cache
 	// ReliabilityTelemetryCollector records real disruption/recovery signals.
 	// Nil defaults to a noop collector (metrics stay zero).
 	ReliabilityTelemetryCollector metrics.ReliabilityTelemetryCollector
+	// Recorder persists each tick's inputs and assessments for later replay.
+	// Nil disables recording.
+	Recorder *TraceRecorder
+	// RuntimeSource serves runtime config from SpotVortexPolicy resources.
+	// Nil (or no applied policy) reads config/runtime.json every tick.
+	RuntimeSource RuntimeConfigSource
+	// RecoveryBudgets serves RecoveryBudget resources to the collector. Nil
+	// limits declared budgets to the max-unavailable-seconds annotation.
+	RecoveryBudgets collector.RecoveryBudgetSource
+	// ShadowBundles are candidate model bundles, by name, that shadow
+	// evaluators may score with. They never drive actuation.
+	ShadowBundles map[string]ShadowPredictor
+	// Bundles stages new model bundles in shadow before they are swapped
+	// into Inference. Nil disables staged rollout.
+	Bundles BundleRollout
+	// InterruptionSources deliver Spot interruption warnings and rebalance
+	// recommendations that are handled immediately, outside the tick.
+	InterruptionSources []interruption.Source
+	// InterruptionRiskHalfLife is how fast an interruption's boost to the
+	// pool's runtime score decays. Default: 30 minutes.
+	InterruptionRiskHalfLife time.Duration
+	// LeaderElection starts the controller as a follower: it runs
+	// observe-only ticks to keep caches warm and does not actuate until
+	// StartLeading is called.
+	LeaderElection bool
+	// StateStore checkpoints target ratios, migration and weight-change
+	// cooldowns, and price history after each leader tick. The checkpoint is
+	// restored in New and read by followers. Nil disables persistence.
+	StateStore StateStore
+	// ReadyReconcileIntervals fails readiness once a tick has run for more
+	// than this many reconcile intervals, or for one interval plus the swap
+	// and NodeClaim wait timeouts if longer. Zero disables the check.
+	ReadyReconcileIntervals int
 }
 
 // New creates a new Controller instance.
@@ -184,11 +297,18 @@ func New(cfg Config) (*Controller, error) {
 			"od_suffix", cfg.Karpenter.OnDemandNodePoolSuffix,
 			"spot_weight", cfg.Karpenter.SpotWeight,
 			"od_weight", cfg.Karpenter.OnDemandWeight,
+			"nodepool_layout", cfg.Karpenter.NodePoolLayout,
+			"disruption_mode", cfg.Karpenter.DisruptionMode,
 		)
 	}
+	var labeler *karpenter.Labeler
+	if nodePoolMgr != nil && cfg.Karpenter.HandsOffDisruption() && cfg.K8sClient != nil {
+		labeler = karpenter.NewLabeler(cfg.K8sClient, logger, cfg.Cloud != nil && cfg.Cloud.IsDryRun())
+	}
 
 	// Build unified capacity router with all enabled managers.
 	var capacityManagers []capacity.CapacityManager
+	var twinLookup capacity.TwinLookup
 
 	if nodePoolMgr != nil {
 		kMgr := capacity.NewKarpenterManager(capacity.KarpenterManagerConfig{
@@ -199,6 +319,8 @@ func New(cfg Config) (*Controller, error) {
 			SpotWeight:             cfg.Karpenter.SpotWeight,
 			OnDemandWeight:         cfg.Karpenter.OnDemandWeight,
 			CooldownSeconds:        cfg.Karpenter.WeightChangeCooldownSeconds,
+			SingleNodePool:         cfg.Karpenter.UsesSingleNodePool,
+			MaxDriftNodes:          cfg.Karpenter.MaxDriftNodes,
 		})
 		capacityManagers = append(capacityManagers, kMgr)
 	}
@@ -215,41 +337,121 @@ func New(cfg Config) (*Controller, error) {
 		})
 		capacityManagers = append(capacityManagers, asgMgr)
 
-		// Also register for MNG (same ASG workflow, different ManagerType)
-		mngMgr := capacity.NewASGManager(capacity.ASGManagerConfig{
-			ASGClient:        cfg.ASGClient,
+		// Also register for MNG (same ASG workflow, different ManagerType),
+		// unless MNG pools are scaled through the EKS API below.
+		if cfg.EKSClient == nil {
+			mngMgr := capacity.NewASGManager(capacity.ASGManagerConfig{
+				ASGClient:        cfg.ASGClient,
+				K8sClient:        cfg.K8sClient,
+				Logger:           logger,
+				ManagerType:      capacity.ManagerManagedNodegroup,
+				NodeReadyTimeout: cfg.Autoscaling.NodeReadyTimeout(),
+				PollInterval:     cfg.Autoscaling.PollInterval(),
+			})
+			capacityManagers = append(capacityManagers, mngMgr)
+		}
+
+		// Pools with a single mixed-instances ASG instead of a twin pair.
+		mixedMgr := capacity.NewMixedASGManager(capacity.MixedASGManagerConfig{
+			ASGClient:         cfg.ASGClient,
+			K8sClient:         cfg.K8sClient,
+			Logger:            logger,
+			Convergence:       cfg.Autoscaling.MixedInstancesConvergence,
+			MinHealthyPercent: int32(cfg.Autoscaling.InstanceRefreshMinHealthyPercent),
+			NodeReadyTimeout:  cfg.Autoscaling.NodeReadyTimeout(),
+			PollInterval:      cfg.Autoscaling.PollInterval(),
+		})
+		capacityManagers = append(capacityManagers, mixedMgr)
+		twinLookup = capacity.NewTwinASGLookup(cfg.ASGClient, 0, logger)
+
+		logger.Info("ASG integration enabled",
+			"pool_tag", cfg.Autoscaling.DiscoveryTags.Pool,
+			"capacity_tag", cfg.Autoscaling.DiscoveryTags.CapacityType,
+			"mixed_instances_convergence", cfg.Autoscaling.MixedInstancesConvergence,
+		)
+	}
+
+	if cfg.Autoscaling.Enabled && cfg.EKSClient != nil {
+		eksMgr := capacity.NewEKSNodegroupManager(capacity.EKSNodegroupManagerConfig{
+			EKSClient:        cfg.EKSClient,
 			K8sClient:        cfg.K8sClient,
 			Logger:           logger,
-			ManagerType:      capacity.ManagerManagedNodegroup,
 			NodeReadyTimeout: cfg.Autoscaling.NodeReadyTimeout(),
 			PollInterval:     cfg.Autoscaling.PollInterval(),
 		})
-		capacityManagers = append(capacityManagers, mngMgr)
+		capacityManagers = append(capacityManagers, eksMgr)
 
-		logger.Info("ASG integration enabled",
+		// Twin node groups keep their pools off the mixed-instances manager.
+		if twinLookup != nil {
+			twinLookup = capacity.AnyTwin(twinLookup, capacity.NewTwinNodegroupLookup(cfg.EKSClient, 0, logger))
+		}
+
+		logger.Info("EKS managed node group integration enabled",
+			"pool_label", cfg.Autoscaling.DiscoveryTags.Pool,
+		)
+	}
+
+	if cfg.Autoscaling.Enabled && cfg.VMSSClient != nil {
+		vmssMgr := capacity.NewVMSSManager(capacity.VMSSManagerConfig{
+			VMSSClient:       cfg.VMSSClient,
+			K8sClient:        cfg.K8sClient,
+			Logger:           logger,
+			NodeReadyTimeout: cfg.Autoscaling.NodeReadyTimeout(),
+			PollInterval:     cfg.Autoscaling.PollInterval(),
+		})
+		capacityManagers = append(capacityManagers, vmssMgr)
+
+		logger.Info("AKS node pool integration enabled",
 			"pool_tag", cfg.Autoscaling.DiscoveryTags.Pool,
-			"capacity_tag", cfg.Autoscaling.DiscoveryTags.CapacityType,
 		)
 	}
 
 	capacityRouter := capacity.NewRouter(logger, capacityManagers...)
+	if twinLookup != nil {
+		capacityRouter.SetTwinLookup(twinLookup)
+	}
 	logger.Info("capacity router initialized",
 		"registered_managers", capacityRouter.RegisteredTypes(),
 	)
 
-	return &Controller{
+	priceProvider := cfg.PriceProvider
+	var recordedPrices *recordingPriceProvider
+	if cfg.Recorder != nil {
+		recordedPrices = newRecordingPriceProvider(cfg.PriceProvider)
+		priceProvider = recordedPrices
+	}
+
+	coll := collector.NewCollector(cfg.K8sClient, logger)
+	if err := coll.SetCache(cfg.KubeCache); err != nil {
+		return nil, fmt.Errorf("failed to subscribe collector to informer cache: %w", err)
+	}
+	if cfg.RecoveryBudgets != nil {
+		coll.SetRecoveryBudgetSource(cfg.RecoveryBudgets)
+	}
+	// Telemetry collectors that learn replacement times feed them back as
+	// the pools' restart times.
+	if restarts, ok := cfg.ReliabilityTelemetryCollector.(collector.RestartTimeSource); ok {
+		coll.SetRestartTimeSource(restarts)
+	}
+
+	c := &Controller{
 		cloud:                cfg.Cloud,
-		priceP:               cfg.PriceProvider,
+		priceP:               priceProvider,
 		k8s:                  cfg.K8sClient,
 		dynamicClient:        cfg.DynamicClient,
 		inf:                  cfg.Inference,
 		prom:                 cfg.PrometheusClient,
 		drain:                drainer,
-		coll:                 collector.NewCollector(cfg.K8sClient, logger), // Wiring Collector
+		coll:                 coll, // Wiring Collector
+		cache:                cfg.KubeCache,
 		logger:               logger,
 		metric:               metricSynth,
 		reliabilityTelemetry: reliabilityTelemetryCollector,
+		runtimeSource:        cfg.RuntimeSource,
+		recorder:             cfg.Recorder,
+		recordedPrices:       recordedPrices,
 		nodePoolMgr:          nodePoolMgr,
+		labeler:              labeler,
 		karpenterCfg:         cfg.Karpenter,
 		capacityRouter:       capacityRouter,
 		riskThreshold:        cfg.RiskThreshold,
@@ -257,14 +459,44 @@ func New(cfg Config) (*Controller, error) {
 		reconcileInterval:    cfg.ReconcileInterval,
 		confidenceThreshold:  cfg.ConfidenceThreshold,
 		useSyntheticMetrics:  useSyntheticMetrics,
+		now:                  time.Now,
 		stopCh:               make(chan struct{}),
 		priceHistory:         make(map[string][]float64),
 		lastMigration:        make(map[string]time.Time),
 		targetSpotRatio:      make(map[string]float64),
 		currentSpotRatio:     make(map[string]float64),
 		poolNodeCounts:       make(map[string]*poolCount),
+		workloadPoolCounts:   make(map[string]map[string]*poolCount),
 		lastWeightChange:     make(map[string]time.Time),
-	}, nil
+		handoffs:             make(map[string]karpenterHandoff),
+		lastDecisionEvent:    make(map[string]string),
+		explanations:         NewDecisionExplanationStore(),
+		shadows:              newShadowLedger(),
+		shadowBundles:        cfg.ShadowBundles,
+		bundles:              cfg.Bundles,
+		interruptionSources:  cfg.InterruptionSources,
+		interruptionHalfLife: cfg.InterruptionRiskHalfLife,
+		interruptionSignals:  make(map[string]interruptionSignal),
+		handledInterruptions: make(map[string]time.Time),
+		stateStore:           cfg.StateStore,
+
+		readyTickLimit: readyTickLimit(cfg),
+	}
+	c.following.Store(cfg.LeaderElection)
+
+	if c.stateStore != nil {
+		restoreCtx, cancel := context.WithTimeout(context.Background(), stateRestoreTimeout)
+		if c.restoreState(restoreCtx) {
+			logger.Info("restored controller state",
+				"pools_with_targets", len(c.targetSpotRatio),
+				"migration_cooldowns", len(c.lastMigration),
+				"weight_cooldowns", len(c.lastWeightChange),
+				"price_histories", len(c.priceHistory),
+			)
+		}
+		cancel()
+	}
+	return c, nil
 }
 
 // Start begins the controller's main loop.
@@ -286,6 +518,12 @@ func (c *Controller) Start(ctx context.Context) error {
 		"synthetic_metrics", c.useSyntheticMetrics,
 	)
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if c.IsLeading() {
+		c.startInterruptionHandling(ctx)
+	}
+
 	ticker := time.NewTicker(c.reconcileInterval)
 	defer ticker.Stop()
 
@@ -329,10 +567,23 @@ func (c *Controller) Reconcile(ctx context.Context) error {
 	defer c.mu.RUnlock()
 
 	start := time.Now()
-	defer metrics.ReconcileLoopDuration.Observe(time.Since(start).Seconds())
+	c.tickStarted.Store(start.UnixNano())
+	defer func() {
+		metrics.ReconcileLoopDuration.Observe(time.Since(start).Seconds())
+		c.lastReconcile.Store(time.Now().UnixNano())
+		c.tickStarted.Store(0)
+	}()
 
 	isDryRun := c.cloud != nil && c.cloud.IsDryRun()
-	c.logger.Debug("starting reconciliation cycle", "dry_run", isDryRun)
+	c.logger.Debug("starting reconciliation cycle", "dry_run", isDryRun, "leading", c.IsLeading())
+
+	// The leader checkpoints its state once the tick is done; followers adopt
+	// the leader's cooldowns and targets before inference.
+	if c.IsLeading() {
+		defer c.saveState(ctx)
+	} else {
+		c.restoreState(ctx)
+	}
 
 	// Step 1: Get current node metrics from Prometheus
 	nodeMetrics, err := c.fetchNodeMetrics(ctx)
@@ -343,11 +594,13 @@ func (c *Controller) Reconcile(ctx context.Context) error {
 
 	if len(nodeMetrics) == 0 {
 		c.logger.Debug("no nodes to assess")
+		c.warm.Store(true)
 		return nil
 	}
 
 	// Step 1.5: Refresh local workload metrics (Pod latency, PDBs, etc.)
-	if _, err := c.coll.Collect(ctx); err != nil {
+	localMetrics, err := c.coll.Collect(ctx)
+	if err != nil {
 		c.logger.Warn("failed to collect workload metrics", "error", err)
 		return nil // Skip cycle instead of inferring with stale/default workload features.
 	}
@@ -368,6 +621,12 @@ func (c *Controller) Reconcile(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("inference failure: %w", err)
 	}
+	c.warm.Store(true)
+	c.recordAssessments(assessments)
+	if c.assessmentObserver != nil {
+		c.assessmentObserver(nodeMetrics, assessments)
+	}
+	c.recordTick(ctx, nodeMetrics, localMetrics, assessments)
 
 	// Step 2.5: In dry-run mode, generate and log savings report
 	// This shows customers the potential value before enabling active management
@@ -375,6 +634,17 @@ func (c *Controller) Reconcile(ctx context.Context) error {
 		c.generateAndLogSavingsReport(ctx, nodeMetrics, assessments)
 	}
 
+	// Followers stop here: caches are warm, actuation belongs to the leader.
+	if !c.IsLeading() {
+		c.logger.Debug("follower tick complete, skipping actuation", "total_nodes", len(assessments))
+		return nil
+	}
+
+	// Step 2.6: Follow up on nodes handed to Karpenter, and keep high-risk
+	// nodes hosting critical pods out of Karpenter's own disruption.
+	c.trackKarpenterHandoffs(ctx)
+	c.syncKarpenterProtection(ctx, assessments)
+
 	// Step 3: Identify actionable nodes
 	actionableNodes := c.filterActionableNodes(assessments)
 	actionableNodes = c.filterExecutableNodes(ctx, actionableNodes)
@@ -402,10 +672,12 @@ func (c *Controller) Reconcile(ctx context.Context) error {
 
 	// Step 5: Prepare replacement capacity BEFORE draining.
 	// Routes to the correct CapacityManager per node:
-	// - Karpenter nodes: batch steer NodePool weights (fast, non-blocking)
+	// - Karpenter nodes: batch steer NodePool weights (fast, non-blocking),
+	//   then optionally wait for an Initialized replacement NodeClaim
 	// - CA/MNG nodes: scale up twin ASG, wait for Ready (blocking)
 	c.batchSteerKarpenterWeights(ctx, nodesToDrain)
-	c.prepareCapacitySwaps(ctx, nodesToDrain)
+	nodesToDrain = c.awaitKarpenterReplacements(ctx, nodesToDrain)
+	nodesToDrain = c.prepareCapacitySwaps(ctx, nodesToDrain)
 
 	// Step 6: Execute actions (drain nodes)
 	// In dry-run mode, drainer logs but doesn't actually evict pods
@@ -425,6 +697,9 @@ func (c *Controller) Reconcile(ctx context.Context) error {
 // fetchNodeMetrics gets current metrics from Prometheus.
 // REQUIRED: Prometheus client must be configured.
 func (c *Controller) fetchNodeMetrics(ctx context.Context) ([]metrics.NodeMetrics, error) {
+	if c.nodeMetricsSource != nil {
+		return c.nodeMetricsSource(ctx)
+	}
 	if c.useSyntheticMetrics {
 		return c.syntheticNodeMetrics(ctx)
 	}
@@ -461,6 +736,7 @@ func (c *Controller) unsupportedFamilyAssessment(nodeID, instanceType, reason st
 	metrics.DecisionSource.WithLabelValues(
 		"unsupported_family",
 		inference.ActionToString(inference.ActionEmergencyExit),
+		metrics.ActiveBundleVersion(),
 	).Inc()
 
 	return NodeAssessment{
@@ -482,10 +758,22 @@ func (c *Controller) loadRuntimeConfig() *config.RuntimeConfig {
 }
 
 func stepsSinceMigration(last time.Time, stepMinutes int) int {
+	return stepsSinceMigrationAt(time.Now(), last, stepMinutes)
+}
+
+// clock returns the controller's logical time (wall clock unless replaying).
+func (c *Controller) clock() time.Time {
+	if c != nil && c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+func stepsSinceMigrationAt(now, last time.Time, stepMinutes int) int {
 	if stepMinutes <= 0 {
 		stepMinutes = 10
 	}
-	steps := int(time.Since(last).Minutes() / float64(stepMinutes))
+	steps := int(now.Sub(last).Minutes() / float64(stepMinutes))
 	if steps < 0 {
 		return 0
 	}
@@ -502,16 +790,19 @@ func (c *Controller) runInference(ctx context.Context, nodeMetrics []metrics.Nod
 	}
 
 	runtimeCfg := c.runtimeConfigForTick()
+	c.setTickRuntimeConfig(runtimeCfg)
 	riskMult := runtimeCfg.RiskMultiplier
 	stepMinutes := runtimeCfg.StepMinutes
-	useDeterministic := runtimeCfg.UseDeterministicPolicy()
-	useRLShadow := runtimeCfg.UseRLShadow()
+	c.beginShadowTick(runtimeCfg)
+	defer c.shadows.finish(c.clock())
+	defer c.endBundleTick()
 
 	assessments := make([]NodeAssessment, 0, len(nodeMetrics))
 	shadowProjectedByPool := make(map[string]float64, len(nodeMetrics))
 
 	// Get cluster-wide stats for feature building
 	clusterUtil := c.calculateClusterUtilization(nodeMetrics)
+	c.setLastClusterUtilization(clusterUtil)
 	nodeInfo, err := c.nodeInfoMap(ctx)
 	if err != nil {
 		c.logger.Warn("failed to load node labels", "error", err)
@@ -519,8 +810,10 @@ func (c *Controller) runInference(ctx context.Context, nodeMetrics []metrics.Nod
 
 	// Track workload pool per node for extended pool ID support
 	nodeWorkloadPool := make(map[string]string)
+	nodeLabels := make(map[string]map[string]string)
 
 	poolCounts := make(map[string]*poolCount)
+	workloadPoolCounts := make(map[string]map[string]*poolCount)
 	resolved := make([]metrics.NodeMetrics, 0, len(nodeMetrics))
 	for _, raw := range nodeMetrics {
 		m := raw
@@ -541,6 +834,7 @@ func (c *Controller) runInference(ctx context.Context, nodeMetrics []metrics.Nod
 			m.IsSpot = info.isSpot
 			// Store workload pool for later use
 			nodeWorkloadPool[m.NodeID] = info.workloadPool
+			nodeLabels[m.NodeID] = info.labels
 		}
 
 		// Identify Node Pool - use extended format if configured
@@ -557,6 +851,7 @@ func (c *Controller) runInference(ctx context.Context, nodeMetrics []metrics.Nod
 		if m.IsSpot {
 			counts.spot++
 		}
+		addWorkloadPoolCount(workloadPoolCounts, nodeWorkloadPool[m.NodeID], poolID, m.IsSpot)
 		resolved = append(resolved, m)
 	}
 
@@ -571,6 +866,7 @@ func (c *Controller) runInference(ctx context.Context, nodeMetrics []metrics.Nod
 	c.historyLock.Lock()
 	// Update controller state with current pool counts and ratios
 	c.poolNodeCounts = poolCounts
+	c.workloadPoolCounts = workloadPoolCounts
 	c.currentSpotRatio = currentSpotRatio
 	for poolID, ratio := range currentSpotRatio {
 		if _, ok := c.targetSpotRatio[poolID]; !ok {
@@ -672,7 +968,7 @@ func (c *Controller) runInference(ctx context.Context, nodeMetrics []metrics.Nod
 
 		c.historyLock.Lock()
 		if last, ok := c.lastMigration[poolID]; ok {
-			tsm = stepsSinceMigration(last, stepMinutes)
+			tsm = stepsSinceMigrationAt(c.clock(), last, stepMinutes)
 		}
 		c.historyLock.Unlock()
 
@@ -688,7 +984,7 @@ func (c *Controller) runInference(ctx context.Context, nodeMetrics []metrics.Nod
 			MemoryUsage:        m.MemoryUsagePercent / 100.0,
 			ClusterUtilization: clusterUtil,
 			IsSpot:             m.IsSpot,
-			Timestamp:          time.Now(),
+			Timestamp:          c.clock(),
 			// REAL TELEMETRY (Phase 4)
 			PodStartupTime:     poolFeats.PodStartupTime,
 			OutagePenaltyHours: poolFeats.OutagePenaltyHours,
@@ -701,85 +997,54 @@ func (c *Controller) runInference(ctx context.Context, nodeMetrics []metrics.Nod
 		}
 
 		action, capacityScore, runtimeScore, confidence, err := c.predictDetailed(ctx, m.NodeID, state, riskMult)
+		c.recordActiveInference(err)
 		rlAvailable := err == nil
 
 		if err != nil {
-			if useDeterministic {
-				if rlFallback, ok := inference.AsRLFallbackError(err); ok {
-					c.logger.Warn("RL inference failed in deterministic mode; proceeding with deterministic fallback",
-						"node_id", m.NodeID,
-						"capacity_score", rlFallback.CapacityScore,
-						"runtime_score", rlFallback.RuntimeScore,
-						"error", err,
-					)
-					capacityScore = rlFallback.CapacityScore
-					runtimeScore = rlFallback.RuntimeScore
-				} else {
-					c.logger.Error("inference failed for node", "node_id", m.NodeID, "error", err)
-					continue // Skip node, but don't fail entire loop
-				}
-			} else {
+			rlFallback, ok := inference.AsRLFallbackError(err)
+			if !ok {
 				c.logger.Error("inference failed for node", "node_id", m.NodeID, "error", err)
 				continue // Skip node, but don't fail entire loop
 			}
-		}
-
-		rlAction := action
-		rlConfidence := confidence
-		decisionSource := "rl"
-		hasShadow := false
-		shadowAction := inference.ActionHold
-		responseMode := PolicyResponseMode("")
-		urgency := PolicyUrgency("")
-		if useDeterministic {
-			deterministicAction, deterministic := evaluateDeterministicPolicy(state, float64(capacityScore), float64(runtimeScore), runtimeCfg)
-			action = deterministicAction
-			confidence = 1.0
-			decisionSource = "deterministic"
-			responseMode = deterministic.ResponseMode
-			urgency = deterministic.Urgency
-			if useRLShadow && rlAvailable {
-				hasShadow = true
-				shadowAction = rlAction
-			}
-
-			metrics.DeterministicDecisionReason.WithLabelValues(deterministic.Reason).Inc()
-			metrics.WorkloadCap.WithLabelValues(poolID).Set(deterministic.EffectiveCap)
-			c.logger.Debug("deterministic pool decision",
-				"pool", poolID,
+			c.logger.Warn("RL inference failed; proceeding with TFT scores only",
 				"node_id", m.NodeID,
-				"response_mode", deterministic.ResponseMode,
-				"urgency", deterministic.Urgency,
-				"reason", deterministic.Reason,
-				"safe_max_spot_ratio", deterministic.PoolSafety.SafeMaxSpotRatio,
-				"recovery_budget_violation_risk", deterministic.PoolSafety.RecoveryBudgetViolationRisk,
+				"policy", runtimeCfg.PolicyMode,
+				"capacity_score", rlFallback.CapacityScore,
+				"runtime_score", rlFallback.RuntimeScore,
+				"error", err,
 			)
-			if deterministic.IsOOD {
-				metrics.WorkloadOOD.WithLabelValues(poolID).Set(1.0)
-				for _, reason := range deterministic.OODReasons {
-					metrics.WorkloadOODReason.WithLabelValues(reason).Inc()
-				}
-			} else {
-				metrics.WorkloadOOD.WithLabelValues(poolID).Set(0.0)
-			}
-			if useRLShadow && rlAvailable {
-				if _, ok := shadowProjectedByPool[poolID]; !ok {
-					shadowProjectedByPool[poolID] = 0
-				}
-				shadowProjectedByPool[poolID] += c.recordShadowDecisionComparison(
-					ctx,
-					poolID,
-					m.NodeID,
-					state,
-					action,
-					rlAction,
-					capacityScore,
-					rlConfidence,
-					1.0,
-				)
-			}
+			capacityScore = rlFallback.CapacityScore
+			runtimeScore = rlFallback.RuntimeScore
+		}
+
+		runtimeScore = c.applyInterruptionRisk(poolID, runtimeScore)
+
+		poolCfg := c.runtimeConfigForPool(runtimeCfg, nodeWorkloadPool[m.NodeID], nodeLabels[m.NodeID])
+		recordPoolSpotRatioBounds(poolID, poolCfg)
+
+		outcome, err := c.evaluatePolicies(ctx, runtimeCfg, PolicyInput{
+			PoolID:        poolID,
+			NodeID:        m.NodeID,
+			WorkloadPool:  nodeWorkloadPool[m.NodeID],
+			State:         state,
+			PoolNodes:     poolCounts[poolID].total,
+			CapacityScore: float64(capacityScore),
+			RuntimeScore:  float64(runtimeScore),
+			RLAction:      action,
+			RLConfidence:  float64(confidence),
+			RLAvailable:   rlAvailable,
+			Config:        poolCfg,
+		}, m.NodeID, 1.0)
+		if err != nil {
+			c.logger.Error("policy evaluation failed for node", "node_id", m.NodeID, "error", err)
+			continue
 		}
-		metrics.DecisionSource.WithLabelValues(decisionSource, inference.ActionToString(action)).Inc()
+		action = outcome.Decision.Action
+		confidence = float32(outcome.Decision.Confidence)
+		if outcome.HasShadowDelta {
+			shadowProjectedByPool[poolID] += outcome.ShadowDeltaUSD
+		}
+		metrics.DecisionSource.WithLabelValues(outcome.Source, inference.ActionToString(action), metrics.ActiveBundleVersion()).Inc()
 
 		assessments = append(assessments, NodeAssessment{
 			NodeID:             m.NodeID,
@@ -788,10 +1053,10 @@ func (c *Controller) runInference(ctx context.Context, nodeMetrics []metrics.Nod
 			RuntimeScore:       runtimeScore,
 			Confidence:         confidence,
 			ClusterUtilization: float32(clusterUtil),
-			ShadowAction:       shadowAction,
-			HasShadow:          hasShadow,
-			ResponseMode:       responseMode,
-			Urgency:            urgency,
+			ShadowAction:       outcome.ShadowAction,
+			HasShadow:          outcome.HasShadow,
+			ResponseMode:       outcome.Decision.ResponseMode,
+			Urgency:            outcome.Decision.Urgency,
 		})
 
 		metrics.CapacityScore.WithLabelValues(m.NodeID, m.Zone).Set(float64(capacityScore))
@@ -804,10 +1069,8 @@ func (c *Controller) runInference(ctx context.Context, nodeMetrics []metrics.Nod
 		}
 	}
 
-	if useDeterministic && useRLShadow {
-		for poolID, delta := range shadowProjectedByPool {
-			metrics.ShadowProjectedSavingsDeltaUSD.WithLabelValues(poolID).Set(delta)
-		}
+	for poolID, delta := range shadowProjectedByPool {
+		metrics.ShadowProjectedSavingsDeltaUSD.WithLabelValues(poolID).Set(delta)
 	}
 
 	return assessments, nil
@@ -861,23 +1124,36 @@ type poolAggregation struct {
 	odNodes      int
 	workloadPool string
 	zone         string
+	labels       map[string]string // labels of the first node, for pool override selectors
+}
+
+// pendingPool is a pool whose state is built and awaits the tick's batched
+// inference.
+type pendingPool struct {
+	key          string
+	agg          *poolAggregation
+	instanceType string
 }
 
 // runPoolLevelInference implements Section 6 Option 2 of PRODUCTION_FLOW_EKS_KARPENTER.md.
 // It aggregates market telemetry to pool-level and runs inference once per pool using
 // the dominant instance type (by count). This provides "one coherent action per pool per tick".
+// All pools of a tick are scored with one batched TFT and RL run.
 func (c *Controller) runPoolLevelInference(ctx context.Context, nodeMetrics []metrics.NodeMetrics) ([]NodeAssessment, error) {
 	runtimeCfg := c.runtimeConfigForTick()
+	c.setTickRuntimeConfig(runtimeCfg)
 	riskMult := runtimeCfg.RiskMultiplier
 	stepMinutes := runtimeCfg.StepMinutes
-	useDeterministic := runtimeCfg.UseDeterministicPolicy()
-	useRLShadow := runtimeCfg.UseRLShadow()
+	c.beginShadowTick(runtimeCfg)
+	defer c.shadows.finish(c.clock())
+	defer c.endBundleTick()
 
 	assessments := make([]NodeAssessment, 0, len(nodeMetrics))
 	shadowProjectedByPool := make(map[string]float64, len(nodeMetrics))
 
 	// Get cluster-wide stats for feature building
 	clusterUtil := c.calculateClusterUtilization(nodeMetrics)
+	c.setLastClusterUtilization(clusterUtil)
 	nodeInfo, err := c.nodeInfoMap(ctx)
 	if err != nil {
 		c.logger.Warn("failed to load node labels", "error", err)
@@ -909,8 +1185,10 @@ func (c *Controller) runPoolLevelInference(ctx context.Context, nodeMetrics []me
 		// For pool-level inference, we group by workload pool + zone (not instance type)
 		// This allows "one action per pool" regardless of instance type mix
 		workloadPool := ""
+		var labels map[string]string
 		if info, ok := nodeInfo[m.NodeID]; ok {
 			workloadPool = info.workloadPool
+			labels = info.labels
 		}
 
 		// Pool key for aggregation: workloadPool:zone (or just zone if no workload pool)
@@ -929,6 +1207,7 @@ func (c *Controller) runPoolLevelInference(ctx context.Context, nodeMetrics []me
 				typeCounts:   make(map[string]int),
 				workloadPool: workloadPool,
 				zone:         m.Zone,
+				labels:       labels,
 			}
 			poolAggregations[poolKey] = agg
 		}
@@ -973,11 +1252,19 @@ func (c *Controller) runPoolLevelInference(ctx context.Context, nodeMetrics []me
 
 	// Step 2: Update pool counts and ratios
 	poolCounts := make(map[string]*poolCount)
+	workloadPoolCounts := make(map[string]map[string]*poolCount)
 	for poolKey, agg := range poolAggregations {
-		poolCounts[poolKey] = &poolCount{
+		counts := &poolCount{
 			total: len(agg.nodes),
 			spot:  agg.spotNodes,
 		}
+		poolCounts[poolKey] = counts
+		if agg.workloadPool != "" {
+			if workloadPoolCounts[agg.workloadPool] == nil {
+				workloadPoolCounts[agg.workloadPool] = make(map[string]*poolCount)
+			}
+			workloadPoolCounts[agg.workloadPool][poolKey] = counts
+		}
 	}
 
 	currentSpotRatio := make(map[string]float64, len(poolCounts))
@@ -990,6 +1277,7 @@ func (c *Controller) runPoolLevelInference(ctx context.Context, nodeMetrics []me
 	targetSpotRatio := make(map[string]float64, len(poolCounts))
 	c.historyLock.Lock()
 	c.poolNodeCounts = poolCounts
+	c.workloadPoolCounts = workloadPoolCounts
 	c.currentSpotRatio = currentSpotRatio
 	for poolID, ratio := range currentSpotRatio {
 		if _, ok := c.targetSpotRatio[poolID]; !ok {
@@ -1001,9 +1289,11 @@ func (c *Controller) runPoolLevelInference(ctx context.Context, nodeMetrics []me
 	}
 	c.historyLock.Unlock()
 
-	// Step 3: Run inference once per pool using dominant instance type
+	// Step 3: Build one state per pool using the dominant instance type
 	poolActions := make(map[string]NodeAssessment)
 	priceCache := make(map[string]cloudapi.SpotPriceData)
+	pending := make([]pendingPool, 0, len(poolAggregations))
+	batch := make([]inference.BatchItem, 0, len(poolAggregations))
 
 	for poolKey, agg := range poolAggregations {
 		if len(agg.nodes) == 0 {
@@ -1092,7 +1382,7 @@ func (c *Controller) runPoolLevelInference(ctx context.Context, nodeMetrics []me
 		tsm := 100
 		c.historyLock.Lock()
 		if last, ok := c.lastMigration[poolID]; ok {
-			tsm = stepsSinceMigration(last, stepMinutes)
+			tsm = stepsSinceMigrationAt(c.clock(), last, stepMinutes)
 		}
 		c.historyLock.Unlock()
 
@@ -1105,7 +1395,7 @@ func (c *Controller) runPoolLevelInference(ctx context.Context, nodeMetrics []me
 			MemoryUsage:        agg.avgMemUsage / 100.0,
 			ClusterUtilization: clusterUtil,
 			IsSpot:             agg.spotNodes > agg.odNodes, // Majority determines
-			Timestamp:          time.Now(),
+			Timestamp:          c.clock(),
 			PodStartupTime:     poolFeats.PodStartupTime,
 			OutagePenaltyHours: poolFeats.OutagePenaltyHours,
 			MigrationCost:      migCost,
@@ -1116,89 +1406,69 @@ func (c *Controller) runPoolLevelInference(ctx context.Context, nodeMetrics []me
 			PoolSafety:         poolFeats.PoolSafety,
 		}
 
-		// Run inference once for this pool
-		action, capacityScore, runtimeScore, confidence, err := c.predictDetailed(ctx, poolKey, state, riskMult)
+		pending = append(pending, pendingPool{key: poolKey, agg: agg, instanceType: instanceType})
+		batch = append(batch, inference.BatchItem{NodeID: poolKey, State: state})
+	}
+
+	// Step 4: Run inference once for all pools, then decide per pool
+	results := c.predictBatch(ctx, batch, riskMult)
+	for i, pool := range pending {
+		poolKey, agg, instanceType := pool.key, pool.agg, pool.instanceType
+		zone := agg.zone
+		state := batch[i].State
+		spotPrice, odPrice := state.SpotPrice, state.OnDemandPrice
+		result := results[i]
+		action, capacityScore, runtimeScore, confidence, err := result.Action, result.CapacityScore, result.RuntimeScore, result.Confidence, result.Err
+		c.recordActiveInference(err)
 		rlAvailable := err == nil
 		if err != nil {
-			if useDeterministic {
-				if rlFallback, ok := inference.AsRLFallbackError(err); ok {
-					c.logger.Warn("RL inference failed in deterministic mode; proceeding with deterministic fallback",
-						"pool", poolKey,
-						"capacity_score", rlFallback.CapacityScore,
-						"runtime_score", rlFallback.RuntimeScore,
-						"error", err,
-					)
-					capacityScore = rlFallback.CapacityScore
-					runtimeScore = rlFallback.RuntimeScore
-				} else {
-					c.logger.Error("pool-level inference failed", "pool", poolKey, "error", err)
-					continue
-				}
-			} else {
+			rlFallback, ok := inference.AsRLFallbackError(err)
+			if !ok {
 				c.logger.Error("pool-level inference failed", "pool", poolKey, "error", err)
 				continue
 			}
+			c.logger.Warn("RL inference failed; proceeding with TFT scores only",
+				"pool", poolKey,
+				"policy", runtimeCfg.PolicyMode,
+				"capacity_score", rlFallback.CapacityScore,
+				"runtime_score", rlFallback.RuntimeScore,
+				"error", err,
+			)
+			capacityScore = rlFallback.CapacityScore
+			runtimeScore = rlFallback.RuntimeScore
 		}
 
-		rlAction := action
-		rlConfidence := confidence
-		decisionSource := "rl"
-		hasShadow := false
-		shadowAction := inference.ActionHold
-		responseMode := PolicyResponseMode("")
-		urgency := PolicyUrgency("")
-		if useDeterministic {
-			deterministicAction, deterministic := evaluateDeterministicPolicy(state, float64(capacityScore), float64(runtimeScore), runtimeCfg)
-			action = deterministicAction
-			confidence = 1.0
-			decisionSource = "deterministic"
-			responseMode = deterministic.ResponseMode
-			urgency = deterministic.Urgency
-			if useRLShadow && rlAvailable {
-				hasShadow = true
-				shadowAction = rlAction
-			}
+		runtimeScore = c.applyInterruptionRisk(poolKey, runtimeScore)
 
-			metrics.DeterministicDecisionReason.WithLabelValues(deterministic.Reason).Inc()
-			metrics.WorkloadCap.WithLabelValues(poolKey).Set(deterministic.EffectiveCap)
-			c.logger.Debug("deterministic pool decision",
-				"pool", poolKey,
-				"response_mode", deterministic.ResponseMode,
-				"urgency", deterministic.Urgency,
-				"reason", deterministic.Reason,
-				"safe_max_spot_ratio", deterministic.PoolSafety.SafeMaxSpotRatio,
-				"recovery_budget_violation_risk", deterministic.PoolSafety.RecoveryBudgetViolationRisk,
-			)
-			if deterministic.IsOOD {
-				metrics.WorkloadOOD.WithLabelValues(poolKey).Set(1.0)
-				for _, reason := range deterministic.OODReasons {
-					metrics.WorkloadOODReason.WithLabelValues(reason).Inc()
-				}
-			} else {
-				metrics.WorkloadOOD.WithLabelValues(poolKey).Set(0.0)
-			}
-			if useRLShadow && rlAvailable {
-				if _, ok := shadowProjectedByPool[poolKey]; !ok {
-					shadowProjectedByPool[poolKey] = 0
-				}
-				representativeNodeID := ""
-				if len(agg.nodes) > 0 {
-					representativeNodeID = agg.nodes[0].NodeID
-				}
-				shadowProjectedByPool[poolKey] += c.recordShadowDecisionComparison(
-					ctx,
-					poolKey,
-					representativeNodeID,
-					state,
-					action,
-					rlAction,
-					capacityScore,
-					rlConfidence,
-					float64(len(agg.nodes)),
-				)
-			}
+		poolCfg := c.runtimeConfigForPool(runtimeCfg, agg.workloadPool, agg.labels)
+		recordPoolSpotRatioBounds(poolKey, poolCfg)
+
+		representativeNodeID := ""
+		if len(agg.nodes) > 0 {
+			representativeNodeID = agg.nodes[0].NodeID
+		}
+		outcome, err := c.evaluatePolicies(ctx, runtimeCfg, PolicyInput{
+			PoolID:        poolKey,
+			WorkloadPool:  agg.workloadPool,
+			State:         state,
+			PoolNodes:     len(agg.nodes),
+			CapacityScore: float64(capacityScore),
+			RuntimeScore:  float64(runtimeScore),
+			RLAction:      action,
+			RLConfidence:  float64(confidence),
+			RLAvailable:   rlAvailable,
+			Config:        poolCfg,
+		}, representativeNodeID, float64(len(agg.nodes)))
+		if err != nil {
+			c.logger.Error("pool-level policy evaluation failed", "pool", poolKey, "error", err)
+			continue
+		}
+		action = outcome.Decision.Action
+		confidence = float32(outcome.Decision.Confidence)
+		if outcome.HasShadowDelta {
+			shadowProjectedByPool[poolKey] += outcome.ShadowDeltaUSD
 		}
-		metrics.DecisionSource.WithLabelValues(decisionSource, inference.ActionToString(action)).Inc()
+		metrics.DecisionSource.WithLabelValues(outcome.Source, inference.ActionToString(action), metrics.ActiveBundleVersion()).Inc()
 
 		c.logger.Info("pool-level inference complete",
 			"pool", poolKey,
@@ -1217,10 +1487,10 @@ func (c *Controller) runPoolLevelInference(ctx context.Context, nodeMetrics []me
 			RuntimeScore:       runtimeScore,
 			Confidence:         confidence,
 			ClusterUtilization: float32(clusterUtil),
-			ShadowAction:       shadowAction,
-			HasShadow:          hasShadow,
-			ResponseMode:       responseMode,
-			Urgency:            urgency,
+			ShadowAction:       outcome.ShadowAction,
+			HasShadow:          outcome.HasShadow,
+			ResponseMode:       outcome.Decision.ResponseMode,
+			Urgency:            outcome.Decision.Urgency,
 		}
 
 		// Emit metrics for the pool
@@ -1234,13 +1504,11 @@ func (c *Controller) runPoolLevelInference(ctx context.Context, nodeMetrics []me
 		}
 	}
 
-	if useDeterministic && useRLShadow {
-		for poolID, delta := range shadowProjectedByPool {
-			metrics.ShadowProjectedSavingsDeltaUSD.WithLabelValues(poolID).Set(delta)
-		}
+	for poolID, delta := range shadowProjectedByPool {
+		metrics.ShadowProjectedSavingsDeltaUSD.WithLabelValues(poolID).Set(delta)
 	}
 
-	// Step 4: Apply pool-level action to all nodes in each pool
+	// Step 5: Apply pool-level action to all nodes in each pool
 	for _, m := range nodeMetrics {
 		nodeID := strings.TrimSpace(m.NodeID)
 		instanceType := m.InstanceType
@@ -1402,6 +1670,17 @@ func (c *Controller) applyTargetSpotRatioWithConfig(poolID string, action infere
 		return
 	}
 
+	c.historyLock.Lock()
+	defer c.historyLock.Unlock()
+
+	if updated, ok := nextTargetSpotRatio(c.targetSpotRatio[poolID], action, runtimeCfg, riskLow); ok {
+		c.targetSpotRatio[poolID] = updated
+	}
+}
+
+// nextTargetSpotRatio applies one action to a target spot ratio. It returns
+// false for actions that do not move the target.
+func nextTargetSpotRatio(current float64, action inference.Action, runtimeCfg *config.RuntimeConfig, riskLow bool) (float64, bool) {
 	delta := 0.0
 	setValue := -1.0
 	switch action {
@@ -1419,13 +1698,9 @@ func (c *Controller) applyTargetSpotRatioWithConfig(poolID string, action infere
 	case inference.ActionEmergencyExit:
 		setValue = 0.0
 	default:
-		return
+		return current, false
 	}
 
-	c.historyLock.Lock()
-	defer c.historyLock.Unlock()
-
-	current := c.targetSpotRatio[poolID]
 	var updated float64
 
 	if setValue >= 0 {
@@ -1454,7 +1729,7 @@ func (c *Controller) applyTargetSpotRatioWithConfig(poolID string, action infere
 		}
 	}
 
-	c.targetSpotRatio[poolID] = updated
+	return updated, true
 }
 
 // lerp performs linear interpolation between a and b.
@@ -1549,16 +1824,23 @@ func (c *Controller) steerKarpenterWeights(ctx context.Context, workloadPool str
 	c.historyLock.Unlock()
 
 	cooldown := c.karpenterCfg.WeightChangeCooldown()
-	if hasLastChange && time.Since(lastChange) < cooldown {
+	now := c.clock()
+	if hasLastChange && now.Sub(lastChange) < cooldown {
 		c.logger.Info("skipping weight steering due to cooldown",
 			"workload_pool", workloadPool,
 			"last_change", lastChange,
 			"cooldown", cooldown,
-			"remaining", cooldown-time.Since(lastChange),
+			"remaining", cooldown-now.Sub(lastChange),
 		)
 		return nil
 	}
 
+	c.snapshotKarpenterSteering(ctx, workloadPool)
+	if c.karpenterCfg.UsesSingleNodePool(workloadPool) {
+		c.steerSingleNodePool(ctx, workloadPool, favorSpot, now)
+		return nil
+	}
+
 	spotPoolName := workloadPool + c.karpenterCfg.SpotNodePoolSuffix
 	odPoolName := workloadPool + c.karpenterCfg.OnDemandNodePoolSuffix
 
@@ -1603,13 +1885,83 @@ func (c *Controller) steerKarpenterWeights(ctx context.Context, workloadPool str
 	// Record weight change time if at least one succeeded
 	if spotErr == nil || odErr == nil {
 		c.historyLock.Lock()
-		c.lastWeightChange[workloadPool] = time.Now()
+		c.lastWeightChange[workloadPool] = now
 		c.historyLock.Unlock()
 	}
 
 	return nil
 }
 
+// steerSingleNodePool steers a workload pool served by one NodePool with both
+// capacity types; see karpenter.NodePoolManager.SteerMixedNodePool.
+func (c *Controller) steerSingleNodePool(ctx context.Context, workloadPool string, favorSpot bool, now time.Time) {
+	current, target, ok := c.workloadPoolSpotRatios(workloadPool)
+	err := c.nodePoolMgr.SteerMixedNodePool(ctx, workloadPool, karpenter.MixedSteering{
+		FavorSpot:        favorSpot,
+		SpotWeight:       c.karpenterCfg.SpotWeight,
+		OnDemandWeight:   c.karpenterCfg.OnDemandWeight,
+		RatioKnown:       ok,
+		CurrentSpotRatio: current,
+		TargetSpotRatio:  target,
+		MaxDriftNodes:    c.karpenterCfg.MaxDriftNodes,
+	})
+	if err != nil {
+		c.logger.Warn("failed to steer single NodePool",
+			"nodepool", workloadPool,
+			"error", err,
+		)
+		return
+	}
+
+	c.historyLock.Lock()
+	c.lastWeightChange[workloadPool] = now
+	c.historyLock.Unlock()
+}
+
+// workloadPoolSpotRatios returns the node-weighted current and target spot
+// ratios across a workload pool's pools, or ok=false when none are tracked.
+func (c *Controller) workloadPoolSpotRatios(workloadPool string) (current, target float64, ok bool) {
+	c.historyLock.Lock()
+	defer c.historyLock.Unlock()
+
+	var nodes, spot int
+	var weightedTarget float64
+	for poolID, counts := range c.workloadPoolCounts[workloadPool] {
+		if counts == nil || counts.total == 0 {
+			continue
+		}
+		nodes += counts.total
+		spot += counts.spot
+		weightedTarget += c.targetSpotRatio[poolID] * float64(counts.total)
+	}
+	if nodes == 0 {
+		return 0, 0, false
+	}
+	return float64(spot) / float64(nodes), weightedTarget / float64(nodes), true
+}
+
+// addWorkloadPoolCount records one node of poolID under its workload pool.
+// Nodes without a workload pool are not recorded.
+func addWorkloadPoolCount(counts map[string]map[string]*poolCount, workloadPool, poolID string, isSpot bool) {
+	if workloadPool == "" {
+		return
+	}
+	byPool := counts[workloadPool]
+	if byPool == nil {
+		byPool = make(map[string]*poolCount)
+		counts[workloadPool] = byPool
+	}
+	pc := byPool[poolID]
+	if pc == nil {
+		pc = &poolCount{}
+		byPool[poolID] = pc
+	}
+	pc.total++
+	if isSpot {
+		pc.spot++
+	}
+}
+
 // batchSteerKarpenterWeights aggregates weight steering decisions per workload pool
 // and applies weights once per pool before any drains occur.
 // This ensures:
@@ -1620,6 +1972,9 @@ func (c *Controller) batchSteerKarpenterWeights(ctx context.Context, nodes []Nod
 	if c.nodePoolMgr == nil || !c.karpenterCfg.Enabled {
 		return
 	}
+	c.historyLock.Lock()
+	c.karpenterSnapshots = nil
+	c.historyLock.Unlock()
 
 	// Aggregate decisions per workload pool
 	// Map: workloadPool -> {favorSpotCount, favorODCount}
@@ -1635,7 +1990,7 @@ func (c *Controller) batchSteerKarpenterWeights(ctx context.Context, nodes []Nod
 		if c.k8s == nil {
 			continue
 		}
-		nodeObj, err := c.k8s.CoreV1().Nodes().Get(ctx, node.NodeID, metav1.GetOptions{})
+		nodeObj, err := c.getNode(ctx, node.NodeID)
 		if err != nil {
 			continue
 		}
@@ -1686,11 +2041,15 @@ func (c *Controller) batchSteerKarpenterWeights(ctx context.Context, nodes []Nod
 }
 
 // prepareCapacitySwaps routes capacity preparation to the correct CapacityManager per node.
-// For ASG-managed nodes (CA/MNG), this executes the Twin ASG Scale-Wait workflow.
+// For ASG-managed nodes (CA/MNG), this executes the Twin ASG Scale-Wait workflow,
+// or shifts the on-demand distribution of a pool's single mixed-instances ASG.
 // For Karpenter nodes, weight steering is already handled by batchSteerKarpenterWeights.
-func (c *Controller) prepareCapacitySwaps(ctx context.Context, nodes []NodeAssessment) {
+//
+// It returns the nodes still to drain: nodes of pools whose manager replaces
+// them itself (SwapResult.SkipDrain) are dropped, except EMERGENCY_EXIT nodes.
+func (c *Controller) prepareCapacitySwaps(ctx context.Context, nodes []NodeAssessment) []NodeAssessment {
 	if c.capacityRouter == nil || c.k8s == nil {
-		return
+		return nodes
 	}
 
 	// Group nodes by pool and manager type to batch operations
@@ -1700,9 +2059,10 @@ func (c *Controller) prepareCapacitySwaps(ctx context.Context, nodes []NodeAsses
 		mgrType   capacity.ManagerType
 	}
 	poolSwaps := make(map[string]*swapRequest) // pool name -> swap request
+	nodePools := make(map[string]string)       // node ID -> pool name
 
 	for _, node := range nodes {
-		nodeObj, err := c.k8s.CoreV1().Nodes().Get(ctx, node.NodeID, metav1.GetOptions{})
+		nodeObj, err := c.getNode(ctx, node.NodeID)
 		if err != nil {
 			continue
 		}
@@ -1733,13 +2093,16 @@ func (c *Controller) prepareCapacitySwaps(ctx context.Context, nodes []NodeAsses
 			continue
 		}
 
+		nodePools[node.NodeID] = workloadPool
 		if _, exists := poolSwaps[workloadPool]; !exists {
+			pool := capacity.PoolInfo{
+				Name:         workloadPool,
+				Zone:         labels["topology.kubernetes.io/zone"],
+				InstanceType: labels["node.kubernetes.io/instance-type"],
+			}
+			pool.CurrentSpotRatio, pool.TargetSpotRatio, pool.SpotRatioKnown = c.workloadPoolSpotRatios(workloadPool)
 			poolSwaps[workloadPool] = &swapRequest{
-				pool: capacity.PoolInfo{
-					Name:         workloadPool,
-					Zone:         labels["topology.kubernetes.io/zone"],
-					InstanceType: labels["node.kubernetes.io/instance-type"],
-				},
+				pool:      pool,
 				direction: direction,
 				mgrType:   mgrType,
 			}
@@ -1747,6 +2110,7 @@ func (c *Controller) prepareCapacitySwaps(ctx context.Context, nodes []NodeAsses
 	}
 
 	// Execute swaps per pool
+	skipDrain := make(map[string]bool)
 	for poolName, req := range poolSwaps {
 		mgr := c.capacityRouter.ManagerForType(req.mgrType)
 		if mgr == nil {
@@ -1779,7 +2143,27 @@ func (c *Controller) prepareCapacitySwaps(ctx context.Context, nodes []NodeAsses
 				"duration", result.Duration,
 			)
 		}
+		if result.SkipDrain {
+			skipDrain[poolName] = true
+		}
 	}
+	if len(skipDrain) == 0 {
+		return nodes
+	}
+
+	kept := make([]NodeAssessment, 0, len(nodes))
+	for _, node := range nodes {
+		if pool, ok := nodePools[node.NodeID]; ok && skipDrain[pool] && node.Action != inference.ActionEmergencyExit {
+			c.logger.Info("skipping drain; capacity manager replaces the node",
+				"node_id", node.NodeID,
+				"pool", pool,
+				"action", inference.ActionToString(node.Action),
+			)
+			continue
+		}
+		kept = append(kept, node)
+	}
+	return kept
 }
 
 // getWorkloadPoolFromPoolID extracts the workload pool name from a pool ID.
@@ -1968,7 +2352,8 @@ func (c *Controller) applyDrainLimit(ctx context.Context, nodes []NodeAssessment
 }
 
 // getKarpenterDisruptionLimit calculates the effective drain limit based on
-// Karpenter NodePool disruption budgets.
+// Karpenter NodePool disruption budgets, less the NodePool's handoffs still
+// in flight.
 // Returns the minimum limit across all relevant NodePools, or -1 if no limit.
 func (c *Controller) getKarpenterDisruptionLimit(ctx context.Context, nodes []NodeAssessment, totalNodes int) int {
 	if c.nodePoolMgr == nil || c.k8s == nil {
@@ -1978,7 +2363,7 @@ func (c *Controller) getKarpenterDisruptionLimit(ctx context.Context, nodes []No
 	// Group nodes by workload pool to check their respective NodePool budgets
 	workloadPools := make(map[string]int) // workloadPool -> node count
 	for _, node := range nodes {
-		nodeObj, err := c.k8s.CoreV1().Nodes().Get(ctx, node.NodeID, metav1.GetOptions{})
+		nodeObj, err := c.getNode(ctx, node.NodeID)
 		if err != nil {
 			continue
 		}
@@ -1996,32 +2381,20 @@ func (c *Controller) getKarpenterDisruptionLimit(ctx context.Context, nodes []No
 		return -1
 	}
 
-	// Check disruption budgets for both spot and on-demand NodePools
+	// Check disruption budgets for every NodePool serving the workload pool
 	minLimit := -1
 	for workloadPool := range workloadPools {
-		// Check spot NodePool budget
-		spotPoolName := workloadPool + c.karpenterCfg.SpotNodePoolSuffix
-		spotLimit, err := c.nodePoolMgr.GetEffectiveDisruptionLimit(ctx, spotPoolName, totalNodes)
-		if err == nil && spotLimit >= 0 {
-			if minLimit < 0 || spotLimit < minLimit {
-				minLimit = spotLimit
-				c.logger.Debug("found disruption budget limit",
-					"nodepool", spotPoolName,
-					"limit", spotLimit,
-				)
-			}
-		}
-
-		// Check on-demand NodePool budget
-		odPoolName := workloadPool + c.karpenterCfg.OnDemandNodePoolSuffix
-		odLimit, err := c.nodePoolMgr.GetEffectiveDisruptionLimit(ctx, odPoolName, totalNodes)
-		if err == nil && odLimit >= 0 {
-			if minLimit < 0 || odLimit < minLimit {
-				minLimit = odLimit
-				c.logger.Debug("found disruption budget limit",
-					"nodepool", odPoolName,
-					"limit", odLimit,
-				)
+		for _, nodePool := range c.karpenterCfg.NodePoolNames(workloadPool) {
+			limit, err := c.nodePoolMgr.GetEffectiveDisruptionLimit(ctx, nodePool, totalNodes)
+			if err == nil && limit >= 0 {
+				limit = max(limit-c.pendingHandoffs(nodePool), 0)
+				if minLimit < 0 || limit < minLimit {
+					minLimit = limit
+					c.logger.Debug("found disruption budget limit",
+						"nodepool", nodePool,
+						"limit", limit,
+					)
+				}
 			}
 		}
 	}
@@ -2029,18 +2402,28 @@ func (c *Controller) getKarpenterDisruptionLimit(ctx context.Context, nodes []No
 	return minLimit
 }
 
+// monitoringDrainBlocked reports whether a node hosting the monitoring
+// namespace must be skipped, unless SPOTVORTEX_ALLOW_MONITORING_DRAIN is set.
+func (c *Controller) monitoringDrainBlocked(ctx context.Context, nodeID string) bool {
+	allowMonitoringDrain := strings.EqualFold(os.Getenv("SPOTVORTEX_ALLOW_MONITORING_DRAIN"), "true") ||
+		os.Getenv("SPOTVORTEX_ALLOW_MONITORING_DRAIN") == "1"
+	if !allowMonitoringDrain && c.nodeHasNamespace(ctx, nodeID, "monitoring") {
+		c.logger.Info("skipping drain for monitoring node", "node_id", nodeID)
+		return true
+	}
+	return false
+}
+
 func (c *Controller) nodeHasNamespace(ctx context.Context, nodeName, namespace string) bool {
 	if c.k8s == nil {
 		return false
 	}
-	pods, err := c.k8s.CoreV1().Pods("").List(ctx, metav1.ListOptions{
-		FieldSelector: "spec.nodeName=" + nodeName,
-	})
+	pods, err := c.podsOnNode(ctx, nodeName)
 	if err != nil {
 		c.logger.Warn("failed to list pods for node", "node_id", nodeName, "error", err)
 		return false
 	}
-	for _, pod := range pods.Items {
+	for _, pod := range pods {
 		if pod.Spec.NodeName != nodeName {
 			continue
 		}
@@ -2072,7 +2455,7 @@ func (c *Controller) applyActiveGuardrails(ctx context.Context, nodeObj *corev1.
 		return assessment.Action, false, nil
 	}
 
-	checker := NewGuardrailChecker(c.k8s, c.logger, c.maxDrainRatio)
+	checker := NewGuardrailChecker(c.k8s, c.logger, c.maxDrainRatio).WithCache(c.cache)
 	result, err := checker.Check(ctx, nodeObj, action, NodeState{
 		NodeName:           nodeObj.Name,
 		InstanceType:       nodeObj.Labels["node.kubernetes.io/instance-type"],
@@ -2122,7 +2505,7 @@ func (c *Controller) applyGuardrailsBeforePrep(ctx context.Context, nodes []Node
 
 	filtered := make([]NodeAssessment, 0, len(nodes))
 	for _, n := range nodes {
-		nodeObj, err := c.k8s.CoreV1().Nodes().Get(ctx, n.NodeID, metav1.GetOptions{})
+		nodeObj, err := c.getNode(ctx, n.NodeID)
 		if err != nil {
 			// Safe fallback: do not prepare capacity or actuate when guardrail pre-check
 			// cannot evaluate the node.
@@ -2233,8 +2616,8 @@ func (c *Controller) executeAction(ctx context.Context, node NodeAssessment) err
 	zone := labels["topology.kubernetes.io/zone"]
 	poolID := c.getPoolIDWithExtendedFormat(instanceType, zone, workloadPool)
 
-	// Load runtime config for ratio bounds
-	runtimeCfg := c.runtimeConfigForTick()
+	// Load runtime config for ratio bounds, resolved for this node's pool
+	runtimeCfg := c.runtimeConfigForPool(c.runtimeConfigForTick(), workloadPool, labels)
 	riskLow := node.CapacityScore < float32(c.riskThreshold)*0.5 // Consider low risk if below 50% of threshold
 
 	// Update target spot ratio with runtime config bounds
@@ -2246,6 +2629,14 @@ func (c *Controller) executeAction(ctx context.Context, node NodeAssessment) err
 	// 2. Only one weight update per pool per reconcile cycle (efficiency)
 	// 3. Cooldown is respected across the batch
 
+	// In handoff mode Karpenter drains and replaces its own nodes.
+	if c.handsOffToKarpenter(nodeObj) {
+		if c.monitoringDrainBlocked(ctx, node.NodeID) {
+			return nil
+		}
+		return c.handOffToKarpenter(ctx, nodeObj, actionToExecute)
+	}
+
 	if c.drain == nil {
 		c.logger.Info("no drainer configured, skipping actual drain",
 			"node_id", node.NodeID,
@@ -2253,10 +2644,7 @@ func (c *Controller) executeAction(ctx context.Context, node NodeAssessment) err
 		return nil
 	}
 
-	allowMonitoringDrain := strings.EqualFold(os.Getenv("SPOTVORTEX_ALLOW_MONITORING_DRAIN"), "true") ||
-		os.Getenv("SPOTVORTEX_ALLOW_MONITORING_DRAIN") == "1"
-	if !allowMonitoringDrain && c.nodeHasNamespace(ctx, node.NodeID, "monitoring") {
-		c.logger.Info("skipping drain for monitoring node", "node_id", node.NodeID)
+	if c.monitoringDrainBlocked(ctx, node.NodeID) {
 		return nil
 	}
 
@@ -2296,14 +2684,14 @@ func (c *Controller) executeAction(ctx context.Context, node NodeAssessment) err
 		// We need the pool ID.
 		// ...
 		// We can get node details from Client.
-		if nodeObj, err := c.k8s.CoreV1().Nodes().Get(ctx, node.NodeID, metav1.GetOptions{}); err == nil {
+		if nodeObj, err := c.getNode(ctx, node.NodeID); err == nil {
 			poolID := collector.GetNodePoolID(nodeObj)
 			c.historyLock.Lock()
-			c.lastMigration[poolID] = time.Now()
+			c.lastMigration[poolID] = c.clock()
 			c.historyLock.Unlock()
 		}
 
-		metrics.ActionTaken.WithLabelValues(inference.ActionToString(actionToExecute)).Inc()
+		metrics.ActionTaken.WithLabelValues(inference.ActionToString(actionToExecute), metrics.ActiveBundleVersion()).Inc()
 		metrics.OutagesAvoided.Inc()
 	}
 
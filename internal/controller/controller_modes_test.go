@@ -8,8 +8,11 @@ import (
 	"time"
 
 	"github.com/softcane/spot-vortex-agent/internal/cloudapi"
+	"github.com/softcane/spot-vortex-agent/internal/config"
 	"github.com/softcane/spot-vortex-agent/internal/inference"
 	"github.com/softcane/spot-vortex-agent/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 )
 
@@ -131,3 +134,73 @@ func TestNew_ConfiguresDrainerDryRunFromCloud(t *testing.T) {
 		})
 	}
 }
+
+type stubRuntimeSource struct {
+	cluster *config.RuntimeConfig
+	pools   map[string]*config.RuntimeConfig
+}
+
+func (s *stubRuntimeSource) RuntimeConfig() (*config.RuntimeConfig, bool) {
+	return s.cluster, s.cluster != nil
+}
+
+func (s *stubRuntimeSource) PoolRuntimeConfig(base *config.RuntimeConfig, workloadPool string) *config.RuntimeConfig {
+	if cfg, ok := s.pools[workloadPool]; ok {
+		return cfg
+	}
+	return base
+}
+
+func TestRunInference_UsesRuntimeSourceClusterAndPoolPolicies(t *testing.T) {
+	k8sClient := k8sfake.NewSimpleClientset()
+	for name, pool := range map[string]string{"node-api": "api", "node-batch": "batch"} {
+		_, err := k8sClient.CoreV1().Nodes().Create(context.Background(), &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					"karpenter.sh/capacity-type":       "spot",
+					"topology.kubernetes.io/zone":      "us-east-1a",
+					"node.kubernetes.io/instance-type": "m5.large",
+					"spotvortex.io/pool":               pool,
+				},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			t.Fatalf("create node: %v", err)
+		}
+	}
+
+	// The batch pool tolerates more risk than the cluster policy.
+	tolerant := deterministicRuntimeConfigShadowTest()
+	tolerant.DeterministicPolicy.HighRiskThreshold = 0.95
+	tolerant.DeterministicPolicy.MediumRiskThreshold = 0.90
+	cfg := baseControllerConfig(&noopCloudProvider{dryRun: true})
+	cfg.K8sClient = k8sClient
+	cfg.Karpenter.UseExtendedPoolID = true
+	cfg.RuntimeSource = &stubRuntimeSource{
+		cluster: deterministicRuntimeConfigShadowTest(),
+		pools:   map[string]*config.RuntimeConfig{"batch": tolerant},
+	}
+	ctrl, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctrl.predictDetailedOverride = func(ctx context.Context, nodeID string, state inference.NodeState, riskMultiplier float64) (inference.Action, float32, float32, float32, error) {
+		return inference.ActionHold, 0.70, 0.10, 0.90, nil
+	}
+
+	if _, err := ctrl.runInference(context.Background(), []metrics.NodeMetrics{
+		{NodeID: "node-api", CPUUsagePercent: 35, MemoryUsagePercent: 50},
+		{NodeID: "node-batch", CPUUsagePercent: 35, MemoryUsagePercent: 50},
+	}); err != nil {
+		t.Fatalf("runInference failed: %v", err)
+	}
+	api, ok := ctrl.DecisionExplanations().Get("api:m5.large:us-east-1a")
+	if !ok || api.Risk.Band != RiskBandHigh || api.Risk.HighThreshold == 0.95 {
+		t.Fatalf("api explanation=%+v, want high band under cluster policy", api)
+	}
+	batch, ok := ctrl.DecisionExplanations().Get("batch:m5.large:us-east-1a")
+	if !ok || batch.Risk.Band != RiskBandLow || batch.Risk.HighThreshold != 0.95 {
+		t.Fatalf("batch explanation=%+v, want low band under pool policy", batch)
+	}
+}
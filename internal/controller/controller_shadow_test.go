@@ -47,7 +47,7 @@ func TestRunInference_DeterministicMode_RecordsRLShadowAndKeepsDeterministicActi
 
 	beforeDeterministic := decisionSourceTotal("deterministic")
 	beforeRLDecisionSource := decisionSourceTotal("rl")
-	beforeReason := counterVecValue(t, svmetrics.DeterministicDecisionReason, "high_risk")
+	beforeReason := counterVecValue(t, svmetrics.DeterministicDecisionReason, "high_risk", svmetrics.ActiveBundleVersion())
 	beforeShadowRecommended := counterVecValue(t, svmetrics.ShadowActionRecommended, "rl", "INCREASE_30")
 	beforeShadowAgreement := counterVecValue(t, svmetrics.ShadowActionAgreement, "different")
 	beforeShadowDelta := counterVecValue(t, svmetrics.ShadowActionDelta, "DECREASE_30", "INCREASE_30")
@@ -92,7 +92,7 @@ func TestRunInference_DeterministicMode_RecordsRLShadowAndKeepsDeterministicActi
 	if delta := decisionSourceTotal("rl") - beforeRLDecisionSource; delta != 0 {
 		t.Fatalf("rl decision_source_total delta=%v, want 0 in deterministic-active mode", delta)
 	}
-	if delta := counterVecValue(t, svmetrics.DeterministicDecisionReason, "high_risk") - beforeReason; delta != 1 {
+	if delta := counterVecValue(t, svmetrics.DeterministicDecisionReason, "high_risk", svmetrics.ActiveBundleVersion()) - beforeReason; delta != 1 {
 		t.Fatalf("deterministic reason metric delta=%v, want 1", delta)
 	}
 	if delta := counterVecValue(t, svmetrics.ShadowActionRecommended, "rl", "INCREASE_30") - beforeShadowRecommended; delta != 1 {
@@ -146,14 +146,14 @@ func TestReconcile_DeterministicMode_ActuatesDeterministicActionNotRLShadow(t *t
 	}
 
 	poolID := "m5.large:us-east-1a"
-	beforeActionTaken := counterVecValue(t, svmetrics.ActionTaken, "DECREASE_30")
+	beforeActionTaken := counterVecValue(t, svmetrics.ActionTaken, "DECREASE_30", svmetrics.ActiveBundleVersion())
 	beforeShadowRecommended := counterVecValue(t, svmetrics.ShadowActionRecommended, "rl", "INCREASE_30")
 
 	if err := ctrl.Reconcile(context.Background()); err != nil {
 		t.Fatalf("Reconcile failed: %v", err)
 	}
 
-	if delta := counterVecValue(t, svmetrics.ActionTaken, "DECREASE_30") - beforeActionTaken; delta != 1 {
+	if delta := counterVecValue(t, svmetrics.ActionTaken, "DECREASE_30", svmetrics.ActiveBundleVersion()) - beforeActionTaken; delta != 1 {
 		t.Fatalf("action_taken_total{action=DECREASE_30} delta=%v, want 1", delta)
 	}
 	if delta := counterVecValue(t, svmetrics.ShadowActionRecommended, "rl", "INCREASE_30") - beforeShadowRecommended; delta != 1 {
@@ -200,14 +200,14 @@ func TestReconcile_DeterministicMode_RLInferenceFailureDoesNotBreakDeterministic
 		return inference.ActionIncrease10, 0.70, 0.10, 0.90, nil
 	}
 
-	beforeActionTaken := counterVecValue(t, svmetrics.ActionTaken, "DECREASE_30")
+	beforeActionTaken := counterVecValue(t, svmetrics.ActionTaken, "DECREASE_30", svmetrics.ActiveBundleVersion())
 	beforeDeterministic := decisionSourceTotal("deterministic")
 
 	if err := ctrl.Reconcile(context.Background()); err != nil {
 		t.Fatalf("Reconcile should continue when one node inference fails, got error: %v", err)
 	}
 
-	if delta := counterVecValue(t, svmetrics.ActionTaken, "DECREASE_30") - beforeActionTaken; delta < 1 {
+	if delta := counterVecValue(t, svmetrics.ActionTaken, "DECREASE_30", svmetrics.ActiveBundleVersion()) - beforeActionTaken; delta < 1 {
 		t.Fatalf("expected deterministic action to still be actuated for healthy node, action_taken delta=%v", delta)
 	}
 	if delta := decisionSourceTotal("deterministic") - beforeDeterministic; delta < 1 {
@@ -247,7 +247,7 @@ func TestReconcile_DeterministicMode_SameNodeRLFallbackStillActuatesDeterministi
 		}
 	}
 
-	beforeActionTaken := counterVecValue(t, svmetrics.ActionTaken, "DECREASE_30")
+	beforeActionTaken := counterVecValue(t, svmetrics.ActionTaken, "DECREASE_30", svmetrics.ActiveBundleVersion())
 	beforeDeterministic := decisionSourceTotal("deterministic")
 	beforeShadowRecommended := counterVecValue(t, svmetrics.ShadowActionRecommended, "rl", "HOLD")
 
@@ -255,7 +255,7 @@ func TestReconcile_DeterministicMode_SameNodeRLFallbackStillActuatesDeterministi
 		t.Fatalf("Reconcile failed: %v", err)
 	}
 
-	if delta := counterVecValue(t, svmetrics.ActionTaken, "DECREASE_30") - beforeActionTaken; delta != 1 {
+	if delta := counterVecValue(t, svmetrics.ActionTaken, "DECREASE_30", svmetrics.ActiveBundleVersion()) - beforeActionTaken; delta != 1 {
 		t.Fatalf("expected deterministic fallback action to be actuated, action_taken delta=%v want 1", delta)
 	}
 	if delta := decisionSourceTotal("deterministic") - beforeDeterministic; delta != 1 {
@@ -26,12 +26,13 @@ import (
 
 type cleanupTrackingManager struct {
 	mgrType      capacity.ManagerType
+	skipDrain    bool
 	cleanupCalls int
 }
 
 func (m *cleanupTrackingManager) Type() capacity.ManagerType { return m.mgrType }
 func (m *cleanupTrackingManager) PrepareSwap(ctx context.Context, pool capacity.PoolInfo, dir capacity.SwapDirection) (*capacity.SwapResult, error) {
-	return &capacity.SwapResult{Ready: true}, nil
+	return &capacity.SwapResult{Ready: true, SkipDrain: m.skipDrain}, nil
 }
 func (m *cleanupTrackingManager) PostDrainCleanup(ctx context.Context, nodeName string, pool capacity.PoolInfo) error {
 	m.cleanupCalls++
@@ -555,6 +556,43 @@ func TestController_ExecuteAction_PostDrainCleanupRunsForASGNodes(t *testing.T)
 	}
 }
 
+func TestController_PrepareCapacitySwaps_DropsDrainsTheManagerReplaces(t *testing.T) {
+	k8sClient := k8sfake.NewSimpleClientset()
+	logger := slog.Default()
+
+	for i := 0; i < 2; i++ {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("ca-node-%d", i),
+				Labels: map[string]string{
+					"spotvortex.io/managed":       "true",
+					"spotvortex.io/manager":       "cluster-autoscaler",
+					"spotvortex.io/pool":          "ca-pool",
+					"spotvortex.io/capacity-type": "spot",
+				},
+			},
+		}
+		if _, err := k8sClient.CoreV1().Nodes().Create(context.Background(), node, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("create node %s: %v", node.Name, err)
+		}
+	}
+
+	manager := &cleanupTrackingManager{mgrType: capacity.ManagerClusterAutoscaler, skipDrain: true}
+	ctrl := &Controller{
+		k8s:            k8sClient,
+		logger:         logger,
+		capacityRouter: capacity.NewRouter(logger, manager),
+	}
+
+	kept := ctrl.prepareCapacitySwaps(context.Background(), []NodeAssessment{
+		{NodeID: "ca-node-0", Action: inference.ActionDecrease10},
+		{NodeID: "ca-node-1", Action: inference.ActionEmergencyExit},
+	})
+	if len(kept) != 1 || kept[0].NodeID != "ca-node-1" {
+		t.Fatalf("kept=%v, want only the EMERGENCY_EXIT node", kept)
+	}
+}
+
 func TestController_ExecuteAction_PostDrainCleanupSkippedInDryRun(t *testing.T) {
 	k8sClient := k8sfake.NewSimpleClientset()
 	logger := slog.Default()
@@ -847,6 +885,93 @@ func TestBatchSteerKarpenterWeights(t *testing.T) {
 	}
 }
 
+func TestBatchSteerKarpenterWeights_SingleNodePool(t *testing.T) {
+	k8sClient := k8sfake.NewSimpleClientset()
+	dynClient := fake.NewSimpleDynamicClient(runtime.NewScheme(), makeTestNodePool("batch", 50))
+	logger := slog.Default()
+	mgr := karpenter.NewNodePoolManager(dynClient, logger)
+	ctrl := &Controller{
+		k8s:           k8sClient,
+		dynamicClient: dynClient,
+		logger:        logger,
+		karpenterCfg: config.KarpenterConfig{
+			Enabled:                     true,
+			SpotNodePoolSuffix:          "-spot",
+			OnDemandNodePoolSuffix:      "-od",
+			SpotWeight:                  80,
+			OnDemandWeight:              20,
+			WeightChangeCooldownSeconds: 1,
+			NodePoolLayout:              config.NodePoolLayoutTwin,
+			NodePoolLayouts:             map[string]string{"batch": config.NodePoolLayoutSingle},
+		},
+		nodePoolMgr:      mgr,
+		lastWeightChange: make(map[string]time.Time),
+		// Per-node pool IDs: both workload pools share m5.large:us-east-1a.
+		poolNodeCounts: map[string]*poolCount{
+			"m5.large:us-east-1a": {total: 8, spot: 3},
+		},
+		workloadPoolCounts: map[string]map[string]*poolCount{
+			"batch": {"m5.large:us-east-1a": {total: 4, spot: 3}},
+			"web":   {"m5.large:us-east-1a": {total: 4, spot: 0}},
+		},
+		targetSpotRatio: map[string]float64{"m5.large:us-east-1a": 0.5},
+	}
+	createNode(k8sClient, "n1", "spot", "us-east-1a", "m5.large")
+	node, err := k8sClient.CoreV1().Nodes().Get(context.Background(), "n1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get test node: %v", err)
+	}
+	node.Labels[collector.WorkloadPoolLabel] = "batch"
+	if _, err := k8sClient.CoreV1().Nodes().Update(context.Background(), node, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to label test node: %v", err)
+	}
+
+	// 75% spot against a 50% target: spot disallowed and the weight a quarter
+	// of the way from 20 to 80.
+	ctrl.batchSteerKarpenterWeights(context.Background(), []NodeAssessment{
+		{NodeID: "n1", Action: inference.ActionDecrease30},
+	})
+	types, err := mgr.GetCapacityTypes(context.Background(), "batch")
+	if err != nil || len(types) != 1 || types[0] != karpenter.CapacityTypeOnDemand {
+		t.Fatalf("capacity types=%v, %v; want [on-demand]", types, err)
+	}
+	if weight, _ := mgr.GetWeight(context.Background(), "batch"); weight != 35 {
+		t.Fatalf("weight=%d, want 35", weight)
+	}
+
+	ctrl.historyLock.Lock()
+	ctrl.lastWeightChange["batch"] = time.Now().Add(-2 * time.Minute)
+	ctrl.targetSpotRatio["m5.large:us-east-1a"] = 1
+	ctrl.historyLock.Unlock()
+	ctrl.batchSteerKarpenterWeights(context.Background(), []NodeAssessment{
+		{NodeID: "n1", Action: inference.ActionIncrease30},
+	})
+	if types, _ := mgr.GetCapacityTypes(context.Background(), "batch"); len(types) != 2 {
+		t.Fatalf("capacity types=%v, want spot re-allowed", types)
+	}
+	if weight, _ := mgr.GetWeight(context.Background(), "batch"); weight != 65 {
+		t.Fatalf("weight=%d, want 65", weight)
+	}
+}
+
+func TestAddWorkloadPoolCount_SplitsSharedPoolIDs(t *testing.T) {
+	counts := make(map[string]map[string]*poolCount)
+	addWorkloadPoolCount(counts, "batch", "m5.large:us-east-1a", true)
+	addWorkloadPoolCount(counts, "batch", "m5.large:us-east-1a", false)
+	addWorkloadPoolCount(counts, "web", "m5.large:us-east-1a", true)
+	addWorkloadPoolCount(counts, "", "m5.large:us-east-1a", true)
+
+	if got := counts["batch"]["m5.large:us-east-1a"]; got == nil || got.total != 2 || got.spot != 1 {
+		t.Fatalf("batch counts=%+v, want 2 nodes with 1 spot", got)
+	}
+	if got := counts["web"]["m5.large:us-east-1a"]; got == nil || got.total != 1 || got.spot != 1 {
+		t.Fatalf("web counts=%+v, want 1 spot node", got)
+	}
+	if len(counts) != 2 {
+		t.Fatalf("workload pools=%d, want nodes without a workload pool left out", len(counts))
+	}
+}
+
 func makeTestNodePool(name string, weight int64) *unstructured.Unstructured {
 	return &unstructured.Unstructured{
 		Object: map[string]interface{}{
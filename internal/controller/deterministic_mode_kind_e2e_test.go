@@ -244,7 +244,7 @@ func decisionSourceTotal(source string) float64 {
 	}
 	total := 0.0
 	for _, action := range actions {
-		counter, err := svmetrics.DecisionSource.GetMetricWithLabelValues(source, inference.ActionToString(action))
+		counter, err := svmetrics.DecisionSource.GetMetricWithLabelValues(source, inference.ActionToString(action), svmetrics.ActiveBundleVersion())
 		if err != nil {
 			continue
 		}
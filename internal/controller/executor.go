@@ -166,7 +166,7 @@ func (e *Executor) executeHold(ctx context.Context, node *corev1.Node, state Nod
 	}
 
 	// Update metrics
-	metrics.ActionTaken.WithLabelValues("stay").Inc()
+	metrics.ActionTaken.WithLabelValues("stay", metrics.ActiveBundleVersion()).Inc()
 	metrics.CapacityScore.WithLabelValues(node.Name, state.Zone).Set(state.CapacityScore)
 
 	return nil
@@ -205,7 +205,7 @@ func (e *Executor) executeMigrateSlow(ctx context.Context, node *corev1.Node, st
 		}
 	}()
 
-	metrics.ActionTaken.WithLabelValues("migrate_slow").Inc()
+	metrics.ActionTaken.WithLabelValues("migrate_slow", metrics.ActiveBundleVersion()).Inc()
 	return nil
 }
 
@@ -235,7 +235,7 @@ func (e *Executor) executeMigrateNow(ctx context.Context, node *corev1.Node, sta
 		}
 	}()
 
-	metrics.ActionTaken.WithLabelValues("migrate_now").Inc()
+	metrics.ActionTaken.WithLabelValues("migrate_now", metrics.ActiveBundleVersion()).Inc()
 	return nil
 }
 
@@ -266,7 +266,7 @@ func (e *Executor) executeFallbackOD(ctx context.Context, node *corev1.Node, sta
 		}
 	}()
 
-	metrics.ActionTaken.WithLabelValues("fallback_od").Inc()
+	metrics.ActionTaken.WithLabelValues("fallback_od", metrics.ActiveBundleVersion()).Inc()
 	return nil
 }
 
@@ -292,7 +292,7 @@ func (e *Executor) executeRecover(ctx context.Context, node *corev1.Node, state
 		e.logger.Warn("failed to add prefer-spot taint", "error", err)
 	}
 
-	metrics.ActionTaken.WithLabelValues("recover").Inc()
+	metrics.ActionTaken.WithLabelValues("recover", metrics.ActiveBundleVersion()).Inc()
 	return nil
 }
 
@@ -0,0 +1,297 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/config"
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// nodePoolEventNamespace is where Events for cluster-scoped NodePools land.
+	nodePoolEventNamespace = "default"
+	decisionEventComponent = "spotvortex-agent"
+)
+
+// DecisionExplanation is the structured answer to "why did SpotVortex pick
+// this action for this pool on this tick?".
+type DecisionExplanation struct {
+	Pool         string                  `json:"pool"`
+	WorkloadPool string                  `json:"workload_pool,omitempty"`
+	NodePool     string                  `json:"node_pool,omitempty"`
+	NodeID       string                  `json:"node_id,omitempty"`
+	Timestamp    time.Time               `json:"timestamp"`
+	Action       string                  `json:"action"`
+	Reason       string                  `json:"reason"`
+	ResponseMode PolicyResponseMode      `json:"response_mode"`
+	Urgency      PolicyUrgency           `json:"urgency"`
+	Risk         RiskExplanation         `json:"risk"`
+	Cap          CapExplanation          `json:"cap"`
+	PoolSafety   config.PoolSafetyVector `json:"pool_safety"`
+	OOD          OODExplanation          `json:"ood"`
+	Economics    EconomicGate            `json:"economics"`
+}
+
+// RiskExplanation shows which risk band fired and the thresholds in force.
+type RiskExplanation struct {
+	Band                      string  `json:"band"`
+	CapacityScore             float64 `json:"capacity_score"`
+	RuntimeScore              float64 `json:"runtime_score"`
+	CompositeRisk             float64 `json:"composite_risk"`
+	EmergencyThreshold        float64 `json:"emergency_threshold"`
+	RuntimeEmergencyThreshold float64 `json:"runtime_emergency_threshold"`
+	HighThreshold             float64 `json:"high_threshold"`
+	MediumThreshold           float64 `json:"medium_threshold"`
+}
+
+// CapExplanation shows how the effective spot cap was derived and which
+// constraint was binding.
+type CapExplanation struct {
+	CurrentSpotRatio float64             `json:"current_spot_ratio"`
+	FeatureCap       float64             `json:"feature_cap"`
+	SafeMaxSpotRatio float64             `json:"safe_max_spot_ratio"`
+	WorkloadCap      float64             `json:"workload_cap"`
+	EffectiveCap     float64             `json:"effective_cap"`
+	MinSpotRatio     float64             `json:"min_spot_ratio"`
+	MaxSpotRatio     float64             `json:"max_spot_ratio"`
+	Binding          string              `json:"binding"`
+	Rules            []CapRuleEvaluation `json:"rules"`
+}
+
+// OODExplanation shows whether the pool's features fell outside the
+// training buckets.
+type OODExplanation struct {
+	Detected bool     `json:"detected"`
+	Mode     string   `json:"mode"`
+	Features []string `json:"features,omitempty"`
+}
+
+func newDecisionExplanation(
+	poolID string,
+	nodeID string,
+	state inference.NodeState,
+	action inference.Action,
+	capacityScore float64,
+	runtimeScore float64,
+	decision deterministicDecision,
+	runtimeCfg *config.RuntimeConfig,
+) DecisionExplanation {
+	dp := runtimeCfg.DeterministicPolicy
+	return DecisionExplanation{
+		Pool:         poolID,
+		NodeID:       nodeID,
+		Timestamp:    state.Timestamp,
+		Action:       inference.ActionToString(action),
+		Reason:       decision.Reason,
+		ResponseMode: decision.ResponseMode,
+		Urgency:      decision.Urgency,
+		Risk: RiskExplanation{
+			Band:                      decision.RiskBand,
+			CapacityScore:             capacityScore,
+			RuntimeScore:              runtimeScore,
+			CompositeRisk:             decision.CompositeRisk,
+			EmergencyThreshold:        dp.EmergencyRiskThreshold,
+			RuntimeEmergencyThreshold: dp.RuntimeEmergencyThreshold,
+			HighThreshold:             dp.HighRiskThreshold,
+			MediumThreshold:           dp.MediumRiskThreshold,
+		},
+		Cap: CapExplanation{
+			CurrentSpotRatio: state.CurrentSpotRatio,
+			FeatureCap:       decision.FeatureCap,
+			SafeMaxSpotRatio: decision.PoolSafety.SafeMaxSpotRatio,
+			WorkloadCap:      decision.WorkloadCap,
+			EffectiveCap:     decision.EffectiveCap,
+			MinSpotRatio:     runtimeCfg.MinSpotRatio,
+			MaxSpotRatio:     runtimeCfg.MaxSpotRatio,
+			Binding:          decision.BindingCap,
+			Rules:            decision.CapRules,
+		},
+		PoolSafety: decision.PoolSafety,
+		OOD: OODExplanation{
+			Detected: decision.IsOOD,
+			Mode:     dp.OODMode,
+			Features: decision.OODReasons,
+		},
+		Economics: decision.Economics,
+	}
+}
+
+// Summary renders the explanation as a single line for Events and logs.
+func (e DecisionExplanation) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s) for pool %s: risk band %s, composite %.2f (capacity %.2f, runtime %.2f)",
+		e.Action, e.Reason, e.Pool, e.Risk.Band, e.Risk.CompositeRisk, e.Risk.CapacityScore, e.Risk.RuntimeScore)
+	fmt.Fprintf(&b, "; spot %.2f vs cap %.2f bound by %s", e.Cap.CurrentSpotRatio, e.Cap.EffectiveCap, e.Cap.Binding)
+	if e.OOD.Detected {
+		fmt.Fprintf(&b, "; OOD on %s", strings.Join(e.OOD.Features, ","))
+	}
+	if e.Economics.Evaluated {
+		fmt.Fprintf(&b, "; economics savings %.2f payback %.1fh passed=%t",
+			e.Economics.SavingsRatio, e.Economics.PaybackHours, e.Economics.Passed)
+	}
+	return b.String()
+}
+
+// DecisionExplanationStore keeps the latest explanation per pool and serves
+// them as JSON. Query with ?pool=<id> for a single pool.
+type DecisionExplanationStore struct {
+	mu     sync.RWMutex
+	byPool map[string]DecisionExplanation
+}
+
+// NewDecisionExplanationStore creates an empty store.
+func NewDecisionExplanationStore() *DecisionExplanationStore {
+	return &DecisionExplanationStore{byPool: make(map[string]DecisionExplanation)}
+}
+
+// Put records the latest explanation for its pool.
+func (s *DecisionExplanationStore) Put(e DecisionExplanation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byPool[e.Pool] = e
+}
+
+// Get returns the latest explanation for a pool.
+func (s *DecisionExplanationStore) Get(pool string) (DecisionExplanation, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.byPool[pool]
+	return e, ok
+}
+
+// List returns the latest explanation for every pool, sorted by pool ID.
+func (s *DecisionExplanationStore) List() []DecisionExplanation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]DecisionExplanation, 0, len(s.byPool))
+	for _, e := range s.byPool {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Pool < out[j].Pool })
+	return out
+}
+
+// ServeHTTP implements http.Handler.
+func (s *DecisionExplanationStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var body any
+	if pool := r.URL.Query().Get("pool"); pool != "" {
+		e, ok := s.Get(pool)
+		if !ok {
+			http.Error(w, fmt.Sprintf("no decision recorded for pool %q", pool), http.StatusNotFound)
+			return
+		}
+		body = e
+	} else {
+		body = s.List()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(body)
+}
+
+// DecisionExplanations returns the store backing the decision explanation
+// endpoint.
+func (c *Controller) DecisionExplanations() *DecisionExplanationStore {
+	return c.explanations
+}
+
+// explainDecision stores the explanation and, for decisions that move a pool
+// toward On-Demand, publishes it as an Event on the pool's spot (or single)
+// NodePool.
+func (c *Controller) explainDecision(ctx context.Context, workloadPool string, exp DecisionExplanation) {
+	if c.karpenterCfg.Enabled && workloadPool != "" {
+		exp.WorkloadPool = workloadPool
+		exp.NodePool = c.karpenterCfg.NodePoolNames(workloadPool)[0]
+	}
+	if c.explanations != nil {
+		c.explanations.Put(exp)
+	}
+
+	if exp.NodePool == "" || !explanationWarrantsEvent(exp) || !c.IsLeading() {
+		return
+	}
+
+	// Only publish when the pool's decision changes so steady-state
+	// FREEZE ticks do not flood the event stream.
+	key := exp.Action + "/" + exp.Reason
+	c.historyLock.Lock()
+	if c.lastDecisionEvent[exp.NodePool] == key {
+		c.historyLock.Unlock()
+		return
+	}
+	if c.lastDecisionEvent == nil {
+		c.lastDecisionEvent = make(map[string]string)
+	}
+	c.lastDecisionEvent[exp.NodePool] = key
+	c.historyLock.Unlock()
+
+	if err := c.publishDecisionEvent(ctx, exp); err != nil {
+		c.logger.Warn("failed to publish decision event",
+			"node_pool", exp.NodePool,
+			"action", exp.Action,
+			"error", err,
+		)
+	}
+}
+
+func explanationWarrantsEvent(exp DecisionExplanation) bool {
+	if exp.ResponseMode == ResponseModeFreezeSpot {
+		return true
+	}
+	switch exp.Action {
+	case inference.ActionToString(inference.ActionDecrease30), inference.ActionToString(inference.ActionEmergencyExit):
+		return true
+	}
+	return false
+}
+
+func (c *Controller) publishDecisionEvent(ctx context.Context, exp DecisionExplanation) error {
+	if c.k8s == nil {
+		return nil
+	}
+	reason, eventType := "SpotVortexReduceSpot", corev1.EventTypeWarning
+	switch {
+	case exp.ResponseMode == ResponseModeFreezeSpot:
+		reason, eventType = "SpotVortexFreezeSpot", corev1.EventTypeNormal
+	case exp.ResponseMode == ResponseModeEmergencyExit:
+		reason = "SpotVortexEmergencyExit"
+	}
+
+	now := metav1.NewTime(c.clock())
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", exp.NodePool, now.UnixNano()),
+			Namespace: nodePoolEventNamespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "karpenter.sh/v1",
+			Kind:       "NodePool",
+			Name:       exp.NodePool,
+		},
+		Reason:         reason,
+		Message:        exp.Summary(),
+		Type:           eventType,
+		Source:         corev1.EventSource{Component: decisionEventComponent},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	if _, err := c.k8s.CoreV1().Events(nodePoolEventNamespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("create event for NodePool %s: %w", exp.NodePool, err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/config"
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+	svmetrics "github.com/softcane/spot-vortex-agent/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRunInference_ExplainsDecisionAndPublishesNodePoolEvent(t *testing.T) {
+	k8sClient := k8sfake.NewSimpleClientset()
+	_, err := k8sClient.CoreV1().Nodes().Create(context.Background(), &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-1",
+			Labels: map[string]string{
+				"karpenter.sh/capacity-type":       "spot",
+				"topology.kubernetes.io/zone":      "us-east-1a",
+				"node.kubernetes.io/instance-type": "m5.large",
+				"spotvortex.io/managed":            "true",
+				"spotvortex.io/pool":               "web",
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("create node: %v", err)
+	}
+
+	ctrl, err := New(Config{
+		Cloud:               &MockCloudProvider{DryRun: true},
+		PriceProvider:       fixedPriceProvider(),
+		K8sClient:           k8sClient,
+		Inference:           &inference.InferenceEngine{},
+		PrometheusClient:    &svmetrics.Client{},
+		Logger:              slog.Default(),
+		RiskThreshold:       0.95,
+		MaxDrainRatio:       0.2,
+		ReconcileInterval:   10 * time.Second,
+		ConfidenceThreshold: 0.5,
+		Karpenter: config.KarpenterConfig{
+			Enabled:                true,
+			SpotNodePoolSuffix:     "-spot",
+			OnDemandNodePoolSuffix: "-od",
+		},
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctrl.runtimeConfigLoader = deterministicRuntimeConfigShadowTest
+	ctrl.predictDetailedOverride = func(ctx context.Context, nodeID string, state inference.NodeState, riskMultiplier float64) (inference.Action, float32, float32, float32, error) {
+		return inference.ActionHold, 0.70, 0.10, 0.90, nil
+	}
+
+	metricsIn := []svmetrics.NodeMetrics{{
+		NodeID:             "node-1",
+		CPUUsagePercent:    35,
+		MemoryUsagePercent: 50,
+	}}
+	// Two identical ticks: the explanation refreshes, the Event is not repeated.
+	for i := 0; i < 2; i++ {
+		if _, err := ctrl.runInference(context.Background(), metricsIn); err != nil {
+			t.Fatalf("runInference failed: %v", err)
+		}
+	}
+
+	store := ctrl.DecisionExplanations()
+	explanations := store.List()
+	if len(explanations) != 1 {
+		t.Fatalf("explanations=%d, want 1", len(explanations))
+	}
+	exp := explanations[0]
+	if exp.Action != "DECREASE_30" || exp.Reason != "high_risk" || exp.Risk.Band != RiskBandHigh {
+		t.Fatalf("explanation=%+v, want DECREASE_30/high_risk/high band", exp)
+	}
+	if exp.NodePool != "web-spot" || exp.WorkloadPool != "web" {
+		t.Fatalf("node pool=%q workload pool=%q, want web-spot/web", exp.NodePool, exp.WorkloadPool)
+	}
+	if len(exp.Cap.Rules) != 5 || exp.Cap.Binding == "" {
+		t.Fatalf("cap explanation=%+v, want all five rule sets and a binding source", exp.Cap)
+	}
+
+	rec := httptest.NewRecorder()
+	store.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/decisions?pool="+exp.Pool, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET status=%d, want 200", rec.Code)
+	}
+	var served DecisionExplanation
+	if err := json.Unmarshal(rec.Body.Bytes(), &served); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if served.Pool != exp.Pool || served.Risk.CompositeRisk != exp.Risk.CompositeRisk {
+		t.Fatalf("served=%+v, want %+v", served, exp)
+	}
+
+	rec = httptest.NewRecorder()
+	store.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/decisions?pool=missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("GET unknown pool status=%d, want 404", rec.Code)
+	}
+
+	events, err := k8sClient.CoreV1().Events(nodePoolEventNamespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	if len(events.Items) != 1 {
+		t.Fatalf("events=%d, want 1 (deduplicated)", len(events.Items))
+	}
+	ev := events.Items[0]
+	if ev.InvolvedObject.Kind != "NodePool" || ev.InvolvedObject.Name != "web-spot" {
+		t.Fatalf("event involved object=%+v, want NodePool web-spot", ev.InvolvedObject)
+	}
+	if ev.Reason != "SpotVortexReduceSpot" || ev.Type != corev1.EventTypeWarning || ev.Message == "" {
+		t.Fatalf("event=%s/%s %q, want Warning SpotVortexReduceSpot with message", ev.Type, ev.Reason, ev.Message)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"log/slog"
 
 	"github.com/softcane/spot-vortex-agent/internal/capacity"
+	"github.com/softcane/spot-vortex-agent/internal/kubecache"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,6 +36,7 @@ type GuardrailResult struct {
 // GuardrailChecker implements production guardrails per phase.md.
 type GuardrailChecker struct {
 	k8s                      kubernetes.Interface
+	cache                    *kubecache.Cache // Optional: read from informers when synced
 	logger                   *slog.Logger
 	clusterFractionLimit     float64 // Default: 0.20 (20%)
 	confidenceThreshold      float64 // Default: 0.50
@@ -56,6 +58,13 @@ func NewGuardrailChecker(k8s kubernetes.Interface, logger *slog.Logger, clusterF
 	}
 }
 
+// WithCache makes the checker read nodes, pods and PDBs from the informer
+// cache once it has synced.
+func (g *GuardrailChecker) WithCache(cache *kubecache.Cache) *GuardrailChecker {
+	g.cache = cache
+	return g
+}
+
 // Check applies all guardrails to an action.
 // Returns modified action if guardrails require downgrade.
 func (g *GuardrailChecker) Check(ctx context.Context, node *corev1.Node, action Action, state NodeState) (*GuardrailResult, error) {
@@ -111,14 +120,14 @@ func (g *GuardrailChecker) Check(ctx context.Context, node *corev1.Node, action
 // checkClusterFraction implements GUARDRAIL 1: Human Override Check.
 // Blocks actions affecting >20% of cluster.
 func (g *GuardrailChecker) checkClusterFraction(ctx context.Context, node *corev1.Node) (*GuardrailResult, error) {
-	nodes, err := g.k8s.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	nodes, err := listNodes(ctx, g.k8s, g.cache)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list nodes: %w", err)
 	}
 
 	clusterSize := 0
-	for i := range nodes.Items {
-		if capacity.IsSpotNode(&nodes.Items[i]) {
+	for _, n := range nodes {
+		if capacity.IsSpotNode(n) {
 			clusterSize++
 		}
 	}
@@ -180,16 +189,14 @@ func (g *GuardrailChecker) checkPDB(ctx context.Context, node *corev1.Node, acti
 	}
 
 	// Get pods on this node
-	pods, err := g.k8s.CoreV1().Pods("").List(ctx, metav1.ListOptions{
-		FieldSelector: "spec.nodeName=" + node.Name,
-	})
+	pods, err := podsOnNode(ctx, g.k8s, g.cache, node.Name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pods: %w", err)
 	}
 
 	// Check each pod's PDB
-	for _, pod := range pods.Items {
-		pdb, err := g.getPDBForPod(ctx, &pod)
+	for _, pod := range pods {
+		pdb, err := g.getPDBForPod(ctx, pod)
 		if err != nil {
 			continue // No PDB for this pod
 		}
@@ -230,14 +237,12 @@ func (g *GuardrailChecker) checkCriticalWorkloads(ctx context.Context, node *cor
 	}
 
 	// Get pods on this node
-	pods, err := g.k8s.CoreV1().Pods("").List(ctx, metav1.ListOptions{
-		FieldSelector: "spec.nodeName=" + node.Name,
-	})
+	pods, err := podsOnNode(ctx, g.k8s, g.cache, node.Name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pods: %w", err)
 	}
 
-	for _, pod := range pods.Items {
+	for _, pod := range pods {
 		// Check critical annotation
 		if pod.Annotations[AnnotationCritical] == "true" {
 			g.logger.Warn("downgrading action for critical pod",
@@ -338,19 +343,19 @@ func (g *GuardrailChecker) checkHighUtilization(state NodeState, action Action)
 
 // getPDBForPod finds the PDB that matches a pod.
 func (g *GuardrailChecker) getPDBForPod(ctx context.Context, pod *corev1.Pod) (*policyv1.PodDisruptionBudget, error) {
-	pdbs, err := g.k8s.PolicyV1().PodDisruptionBudgets(pod.Namespace).List(ctx, metav1.ListOptions{})
+	pdbs, err := pdbsInNamespace(ctx, g.k8s, g.cache, pod.Namespace)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, pdb := range pdbs.Items {
+	for _, pdb := range pdbs {
 		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
 		if err != nil {
 			continue
 		}
 
 		if selector.Matches(labels.Set(pod.Labels)) {
-			return &pdb, nil
+			return pdb, nil
 		}
 	}
 
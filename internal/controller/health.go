@@ -0,0 +1,287 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+)
+
+// readinessCheckTimeout bounds each dependency check on /readyz so a hung
+// dependency reports as not ready instead of timing out the probe.
+const readinessCheckTimeout = 2 * time.Second
+
+// ReadinessCheck reports whether a dependency is usable; nil means ready.
+type ReadinessCheck func(ctx context.Context) error
+
+type namedReadinessCheck struct {
+	name  string
+	check ReadinessCheck
+}
+
+// AddReadinessCheck registers a dependency check (model contract, Prometheus,
+// price provider, ...) evaluated by ReadinessHandler.
+func (c *Controller) AddReadinessCheck(name string, check ReadinessCheck) {
+	c.readinessMu.Lock()
+	defer c.readinessMu.Unlock()
+	c.readinessChecks = append(c.readinessChecks, namedReadinessCheck{name: name, check: check})
+}
+
+// LastReconcile returns when the most recent tick finished, or the zero time
+// before the first one.
+func (c *Controller) LastReconcile() time.Time {
+	ns := c.lastReconcile.Load()
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
+
+// readyTickLimit is how long a tick may run before readiness fails: the
+// configured number of reconcile intervals, but never less than one interval
+// plus the longest waits a tick makes for replacement capacity (a Ready ASG
+// node, then an Initialized NodeClaim). Zero disables the check.
+func readyTickLimit(cfg Config) time.Duration {
+	if cfg.ReadyReconcileIntervals <= 0 || cfg.ReconcileInterval <= 0 {
+		return 0
+	}
+	limit := time.Duration(cfg.ReadyReconcileIntervals) * cfg.ReconcileInterval
+	swapBound := cfg.ReconcileInterval
+	if cfg.Autoscaling.Enabled {
+		swapBound += cfg.Autoscaling.NodeReadyTimeout()
+	}
+	if cfg.Karpenter.Enabled && cfg.Karpenter.WaitForNodeClaim {
+		swapBound += cfg.Karpenter.NodeClaimReadyTimeout()
+	}
+	if swapBound > limit {
+		return swapBound
+	}
+	return limit
+}
+
+// tickOverrun reports an error while a tick, including the first, has been
+// running for longer than readyTickLimit, i.e. the loop is wedged. Time
+// between ticks never counts.
+func (c *Controller) tickOverrun(now time.Time) error {
+	if c.readyTickLimit <= 0 {
+		return nil
+	}
+	started := c.tickStarted.Load()
+	if started == 0 {
+		return nil
+	}
+	if age := now.Sub(time.Unix(0, started)); age > c.readyTickLimit {
+		return fmt.Errorf("tick running for %s (limit %s)", age.Round(time.Second), c.readyTickLimit)
+	}
+	return nil
+}
+
+// LivenessHandler serves 200 while the process is up. Dependency and
+// reconcile-loop health belong to ReadinessHandler.
+func (c *Controller) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, "ok")
+	})
+}
+
+// ReadinessHandler serves 200 once the controller is warm, its reconcile
+// loop is making progress and every registered dependency check passes,
+// naming its role. Otherwise it serves 503 listing each failure.
+func (c *Controller) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role := "follower"
+		if c.IsLeading() {
+			role = "leader"
+		}
+
+		var failures []string
+		if !c.Warm() {
+			failures = append(failures, fmt.Sprintf("%s caches warming", role))
+		}
+		if err := c.tickOverrun(time.Now()); err != nil {
+			failures = append(failures, fmt.Sprintf("%s reconcile: %v", role, err))
+		}
+
+		c.readinessMu.Lock()
+		checks := append([]namedReadinessCheck(nil), c.readinessChecks...)
+		c.readinessMu.Unlock()
+		for _, nc := range checks {
+			ctx, cancel := context.WithTimeout(r.Context(), readinessCheckTimeout)
+			err := nc.check(ctx)
+			cancel()
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s %s: %v", role, nc.name, err))
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if len(failures) > 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			for _, f := range failures {
+				fmt.Fprintf(w, "not ready: %s\n", f)
+			}
+			return
+		}
+		fmt.Fprintf(w, "ok: %s\n", role)
+	})
+}
+
+// DebugState is the /debug/state snapshot of the controller's view of each
+// pool.
+type DebugState struct {
+	Leading         bool                  `json:"leading"`
+	Warm            bool                  `json:"warm"`
+	LastReconcile   *time.Time            `json:"last_reconcile,omitempty"`
+	Pools           []PoolDebugState      `json:"pools"`
+	WeightCooldowns []WeightCooldownState `json:"weight_cooldowns,omitempty"`
+	AssessedAt      *time.Time            `json:"assessed_at,omitempty"`
+	LastAssessments []AssessmentState     `json:"last_assessments"`
+}
+
+// PoolDebugState is one pool's ratios, node counts and migration cooldown.
+type PoolDebugState struct {
+	Pool             string     `json:"pool"`
+	TargetSpotRatio  *float64   `json:"target_spot_ratio,omitempty"`
+	CurrentSpotRatio *float64   `json:"current_spot_ratio,omitempty"`
+	Nodes            int        `json:"nodes"`
+	SpotNodes        int        `json:"spot_nodes"`
+	LastMigration    *time.Time `json:"last_migration,omitempty"`
+	// SinceMigrationSeconds is how long ago the pool last migrated.
+	SinceMigrationSeconds float64 `json:"since_migration_seconds,omitempty"`
+}
+
+// WeightCooldownState is a workload pool's NodePool weight-change cooldown.
+type WeightCooldownState struct {
+	WorkloadPool     string    `json:"workload_pool"`
+	LastChange       time.Time `json:"last_change"`
+	RemainingSeconds float64   `json:"remaining_seconds"`
+}
+
+// AssessmentState is one node's (or pool's) result from the latest tick.
+type AssessmentState struct {
+	NodeID        string             `json:"node_id"`
+	Action        string             `json:"action"`
+	CapacityScore float32            `json:"capacity_score"`
+	RuntimeScore  float32            `json:"runtime_score"`
+	Confidence    float32            `json:"confidence"`
+	ResponseMode  PolicyResponseMode `json:"response_mode,omitempty"`
+	Urgency       PolicyUrgency      `json:"urgency,omitempty"`
+	ShadowAction  string             `json:"shadow_action,omitempty"`
+}
+
+// recordAssessments keeps the latest tick's assessments for /debug/state.
+func (c *Controller) recordAssessments(assessments []NodeAssessment) {
+	c.historyLock.Lock()
+	defer c.historyLock.Unlock()
+	c.lastAssessments = append([]NodeAssessment(nil), assessments...)
+	c.lastAssessedAt = c.clock()
+}
+
+// DebugState snapshots per-pool ratios, cooldowns and the last assessments.
+func (c *Controller) DebugState() DebugState {
+	now := c.clock()
+	out := DebugState{
+		Leading:         c.IsLeading(),
+		Warm:            c.Warm(),
+		Pools:           []PoolDebugState{},
+		LastAssessments: []AssessmentState{},
+	}
+	if last := c.LastReconcile(); !last.IsZero() {
+		out.LastReconcile = &last
+	}
+
+	c.historyLock.Lock()
+	defer c.historyLock.Unlock()
+
+	pools := make(map[string]*PoolDebugState)
+	pool := func(id string) *PoolDebugState {
+		p, ok := pools[id]
+		if !ok {
+			p = &PoolDebugState{Pool: id}
+			pools[id] = p
+		}
+		return p
+	}
+	for id, ratio := range c.targetSpotRatio {
+		r := ratio
+		pool(id).TargetSpotRatio = &r
+	}
+	for id, ratio := range c.currentSpotRatio {
+		r := ratio
+		pool(id).CurrentSpotRatio = &r
+	}
+	for id, counts := range c.poolNodeCounts {
+		if counts == nil {
+			continue
+		}
+		p := pool(id)
+		p.Nodes = counts.total
+		p.SpotNodes = counts.spot
+	}
+	for id, last := range c.lastMigration {
+		t := last
+		p := pool(id)
+		p.LastMigration = &t
+		p.SinceMigrationSeconds = now.Sub(last).Seconds()
+	}
+	for _, p := range pools {
+		out.Pools = append(out.Pools, *p)
+	}
+	sort.Slice(out.Pools, func(i, j int) bool { return out.Pools[i].Pool < out.Pools[j].Pool })
+
+	cooldown := c.karpenterCfg.WeightChangeCooldown()
+	for wp, last := range c.lastWeightChange {
+		remaining := cooldown - now.Sub(last)
+		if remaining < 0 {
+			remaining = 0
+		}
+		out.WeightCooldowns = append(out.WeightCooldowns, WeightCooldownState{
+			WorkloadPool:     wp,
+			LastChange:       last,
+			RemainingSeconds: remaining.Seconds(),
+		})
+	}
+	sort.Slice(out.WeightCooldowns, func(i, j int) bool {
+		return out.WeightCooldowns[i].WorkloadPool < out.WeightCooldowns[j].WorkloadPool
+	})
+
+	if !c.lastAssessedAt.IsZero() {
+		at := c.lastAssessedAt
+		out.AssessedAt = &at
+	}
+	for _, a := range c.lastAssessments {
+		as := AssessmentState{
+			NodeID:        a.NodeID,
+			Action:        inference.ActionToString(a.Action),
+			CapacityScore: a.CapacityScore,
+			RuntimeScore:  a.RuntimeScore,
+			Confidence:    a.Confidence,
+			ResponseMode:  a.ResponseMode,
+			Urgency:       a.Urgency,
+		}
+		if a.HasShadow {
+			as.ShadowAction = inference.ActionToString(a.ShadowAction)
+		}
+		out.LastAssessments = append(out.LastAssessments, as)
+	}
+	return out
+}
+
+// StateHandler serves DebugState as JSON.
+func (c *Controller) StateHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(c.DebugState())
+	})
+}
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/config"
+)
+
+func TestReadinessHandler_StaleReconcileAndChecks(t *testing.T) {
+	ctrl := &Controller{reconcileInterval: 10 * time.Second, readyTickLimit: 30 * time.Second}
+	ctrl.warm.Store(true)
+	ctrl.lastReconcile.Store(time.Now().UnixNano())
+
+	serve := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		ctrl.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return rec
+	}
+
+	if rec := serve(); rec.Code != http.StatusOK || rec.Body.String() != "ok: leader\n" {
+		t.Fatalf("fresh leader: status=%d body=%q", rec.Code, rec.Body.String())
+	}
+
+	// Idle time between ticks never counts; only a tick running too long.
+	ctrl.lastReconcile.Store(time.Now().Add(-45 * time.Second).UnixNano())
+	if rec := serve(); rec.Code != http.StatusOK {
+		t.Fatalf("idle between ticks: status=%d body=%q", rec.Code, rec.Body.String())
+	}
+	ctrl.tickStarted.Store(time.Now().Add(-45 * time.Second).UnixNano())
+	rec := serve()
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "reconcile") {
+		t.Fatalf("wedged tick: status=%d body=%q", rec.Code, rec.Body.String())
+	}
+
+	ctrl.tickStarted.Store(0)
+	ctrl.lastReconcile.Store(time.Now().UnixNano())
+	ctrl.AddReadinessCheck("prometheus", func(context.Context) error { return errors.New("connection refused") })
+	rec = serve()
+	if rec.Code != http.StatusServiceUnavailable || rec.Body.String() != "not ready: leader prometheus: connection refused\n" {
+		t.Fatalf("failing check: status=%d body=%q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestTickOverrun_FirstTick(t *testing.T) {
+	ctrl := &Controller{readyTickLimit: 30 * time.Second}
+	ctrl.tickStarted.Store(time.Now().Add(-time.Minute).UnixNano())
+	if err := ctrl.tickOverrun(time.Now()); err == nil {
+		t.Fatal("expected a hung first tick to be reported before any tick finished")
+	}
+}
+
+func TestReadyTickLimit_CoversSwapWaits(t *testing.T) {
+	cfg := Config{ReconcileInterval: 30 * time.Second, ReadyReconcileIntervals: 3}
+	if got := readyTickLimit(cfg); got != 90*time.Second {
+		t.Fatalf("limit=%s, want 3 intervals", got)
+	}
+
+	cfg.Autoscaling = config.AutoscalingConfig{Enabled: true, NodeReadyTimeoutSeconds: 300}
+	cfg.Karpenter = config.KarpenterConfig{Enabled: true, WaitForNodeClaim: true, NodeClaimReadyTimeoutSeconds: 120}
+	if got := readyTickLimit(cfg); got != 30*time.Second+300*time.Second+120*time.Second {
+		t.Fatalf("limit=%s, want the interval plus both wait timeouts", got)
+	}
+
+	cfg.ReadyReconcileIntervals = 0
+	if got := readyTickLimit(cfg); got != 0 {
+		t.Fatalf("limit=%s, want the check disabled", got)
+	}
+}
+
+func TestLivenessHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	(&Controller{}).LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status=%d, want 200", rec.Code)
+	}
+}
+
+func TestStateHandler_ReportsPoolsAndAssessments(t *testing.T) {
+	ctrl := newLeadershipTestController(t, nil, false)
+	if err := ctrl.Reconcile(context.Background()); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	ctrl.StateHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/state", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", rec.Code, rec.Body.String())
+	}
+	var st DebugState
+	if err := json.Unmarshal(rec.Body.Bytes(), &st); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !st.Leading || !st.Warm || st.LastReconcile == nil {
+		t.Fatalf("unexpected controller status: %+v", st)
+	}
+
+	var found bool
+	for _, p := range st.Pools {
+		if p.Pool != "m5.large:us-east-1a" {
+			continue
+		}
+		found = true
+		if p.CurrentSpotRatio == nil || *p.CurrentSpotRatio != 1.0 || p.Nodes != 1 || p.SpotNodes != 1 {
+			t.Fatalf("unexpected pool state: %+v", p)
+		}
+		if p.TargetSpotRatio == nil {
+			t.Fatalf("pool missing target ratio: %+v", p)
+		}
+	}
+	if !found {
+		t.Fatalf("pool missing from state: %+v", st.Pools)
+	}
+	if len(st.LastAssessments) != 1 || st.LastAssessments[0].Action == "" {
+		t.Fatalf("unexpected assessments: %+v", st.LastAssessments)
+	}
+}
@@ -0,0 +1,422 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/capacity"
+	"github.com/softcane/spot-vortex-agent/internal/collector"
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+	"github.com/softcane/spot-vortex-agent/internal/interruption"
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// defaultInterruptionRiskHalfLife is how long it takes an interruption's
+	// boost to the pool runtime score to decay by half.
+	defaultInterruptionRiskHalfLife = 30 * time.Minute
+
+	// interruptionDedupeWindow suppresses repeat handling of the same node and
+	// event kind when several sources report the same interruption.
+	interruptionDedupeWindow = 10 * time.Minute
+
+	// Runtime score contributed by each signal before decay. A Spot warning
+	// means the pool's capacity is being reclaimed right now; a rebalance
+	// recommendation is an early, weaker signal.
+	interruptionRiskScore = 1.0
+	rebalanceRiskScore    = 0.6
+)
+
+// Interruption handling outcomes (metrics.InterruptionHandled "outcome" label).
+const (
+	interruptionOutcomeDrained = "drained"
+	interruptionOutcomeBlocked = "blocked"
+	interruptionOutcomeSkipped = "skipped"
+	interruptionOutcomeFailed  = "failed"
+)
+
+// interruptionSignal is a runtime risk signal recorded for a pool.
+type interruptionSignal struct {
+	score float64
+	at    time.Time
+}
+
+// startInterruptionHandling runs the configured interruption sources and
+// handles each event as soon as it arrives, independent of the reconcile
+// ticker. Everything stops when ctx is cancelled.
+func (c *Controller) startInterruptionHandling(ctx context.Context) {
+	if len(c.interruptionSources) == 0 {
+		return
+	}
+
+	events := make(chan interruption.Event, 64)
+	for _, src := range c.interruptionSources {
+		go func(src interruption.Source) {
+			c.logger.Info("interruption source started", "source", src.Name())
+			if err := src.Run(ctx, events); err != nil && ctx.Err() == nil {
+				c.logger.Error("interruption source stopped", "source", src.Name(), "error", err)
+			}
+		}(src)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev := <-events:
+				go c.handleInterruption(ctx, ev)
+			}
+		}
+	}()
+}
+
+// handleInterruption drains the affected node and prepares replacement
+// capacity for its pool. Guardrails still apply: a blocked guardrail skips
+// the drain, but the pool's runtime score is raised either way. Returns the
+// outcome recorded in metrics.
+func (c *Controller) handleInterruption(ctx context.Context, ev interruption.Event) string {
+	switch ev.Kind {
+	case interruption.KindSpotInterruption:
+		metrics.RecordReliabilityTelemetry(metrics.ReliabilityTelemetrySnapshot{AWSInterruptionNotices: 1})
+	case interruption.KindRebalanceRecommendation:
+		metrics.RecordReliabilityTelemetry(metrics.ReliabilityTelemetrySnapshot{AWSRebalanceRecommendations: 1})
+	}
+
+	outcome := c.processInterruption(ctx, ev)
+	metrics.InterruptionHandled.WithLabelValues(string(ev.Kind), outcome).Inc()
+	return outcome
+}
+
+func (c *Controller) processInterruption(ctx context.Context, ev interruption.Event) string {
+	if c.k8s == nil {
+		return interruptionOutcomeSkipped
+	}
+
+	nodeObj, err := c.resolveInterruptedNode(ctx, ev)
+	if err != nil {
+		c.logger.Warn("failed to resolve node for interruption event",
+			"kind", ev.Kind,
+			"instance_id", ev.InstanceID,
+			"node_name", ev.NodeName,
+			"error", err,
+		)
+		return interruptionOutcomeFailed
+	}
+	if nodeObj == nil {
+		c.logger.Info("interruption event for unknown node",
+			"kind", ev.Kind,
+			"instance_id", ev.InstanceID,
+			"node_name", ev.NodeName,
+		)
+		return interruptionOutcomeSkipped
+	}
+
+	labels := nodeObj.Labels
+	workloadPool := labels[collector.WorkloadPoolLabel]
+	instanceType := labels["node.kubernetes.io/instance-type"]
+	zone := labels["topology.kubernetes.io/zone"]
+	c.recordInterruptionSignal(ev, instanceType, zone, workloadPool)
+
+	logger := c.logger.With(
+		"node_id", nodeObj.Name,
+		"kind", ev.Kind,
+		"source", ev.Source,
+		"instance_id", ev.InstanceID,
+	)
+
+	if reason := interruptionSkipReason(nodeObj); reason != "" {
+		logger.Info("skipping interruption handling", "reason", reason)
+		return interruptionOutcomeSkipped
+	}
+
+	action := inference.ActionEmergencyExit
+	if ev.Kind == interruption.KindRebalanceRecommendation {
+		action = inference.ActionDecrease30
+	}
+	assessment := NodeAssessment{
+		NodeID:             nodeObj.Name,
+		Action:             action,
+		CapacityScore:      1,
+		Confidence:         1,
+		ClusterUtilization: float32(c.lastUtilization()),
+	}
+	if _, blocked, err := c.applyActiveGuardrails(ctx, nodeObj, assessment); err != nil {
+		logger.Warn("skipping interruption drain: guardrail check failed", "error", err)
+		return interruptionOutcomeFailed
+	} else if blocked {
+		return interruptionOutcomeBlocked
+	}
+
+	// Claim only once the drain is allowed, so a blocked first report does
+	// not suppress a later one that the guardrails would let through.
+	claim := interruptionClaimKey(nodeObj.Name, ev.Kind)
+	if !c.claimInterruption(claim) {
+		logger.Info("interruption already being handled for node")
+		return interruptionOutcomeSkipped
+	}
+
+	pool := capacity.PoolInfo{Name: workloadPool, Zone: zone, InstanceType: instanceType}
+	logger.Info("handling interruption",
+		"deadline", ev.Deadline,
+		"workload_pool", workloadPool,
+	)
+
+	handleCtx := ctx
+	if !ev.Deadline.IsZero() {
+		var cancel context.CancelFunc
+		handleCtx, cancel = context.WithDeadline(ctx, ev.Deadline)
+		defer cancel()
+	}
+
+	// A Spot warning leaves two minutes: prepare replacement capacity and
+	// drain at the same time. A rebalance recommendation has no deadline, so
+	// bring replacement capacity up first, matching the tick's prep-then-drain
+	// order.
+	var wg sync.WaitGroup
+	if ev.Kind == interruption.KindSpotInterruption {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.prepareInterruptionCapacity(handleCtx, logger, nodeObj, pool)
+		}()
+	} else {
+		c.prepareInterruptionCapacity(handleCtx, logger, nodeObj, pool)
+	}
+	outcome := c.drainInterruptedNode(handleCtx, logger, nodeObj, pool, action)
+	wg.Wait()
+	if outcome == interruptionOutcomeFailed {
+		// Let a repeat report from another source retry the drain.
+		c.releaseInterruption(claim)
+	}
+	return outcome
+}
+
+func (c *Controller) prepareInterruptionCapacity(ctx context.Context, logger *slog.Logger, nodeObj *corev1.Node, pool capacity.PoolInfo) {
+	if c.capacityRouter == nil || pool.Name == "" {
+		return
+	}
+	result, err := c.capacityRouter.PrepareSwapForNode(ctx, nodeObj, pool, capacity.SwapToOnDemand)
+	if err != nil {
+		logger.Warn("interruption capacity prep failed", "pool", pool.Name, "error", err)
+		return
+	}
+	if result != nil && result.Ready {
+		logger.Info("interruption replacement capacity ready",
+			"pool", pool.Name,
+			"replacement_node", result.ReplacementNodeName,
+			"duration", result.Duration,
+		)
+	}
+}
+
+func (c *Controller) drainInterruptedNode(ctx context.Context, logger *slog.Logger, nodeObj *corev1.Node, pool capacity.PoolInfo, action inference.Action) string {
+	if c.drain == nil {
+		logger.Info("no drainer configured, skipping interruption drain")
+		return interruptionOutcomeSkipped
+	}
+
+	result, err := c.drain.Drain(ctx, nodeObj.Name)
+	if err != nil {
+		logger.Warn("interruption drain failed", "error", err)
+		return interruptionOutcomeFailed
+	}
+	if !result.Success {
+		logger.Warn("interruption drain incomplete",
+			"pods_evicted", result.PodsEvicted,
+			"pods_failed", result.PodsFailed,
+		)
+		return interruptionOutcomeFailed
+	}
+
+	logger.Info("interrupted node drained",
+		"pods_evicted", result.PodsEvicted,
+		"duration", result.Duration,
+		"dry_run", result.DryRun,
+	)
+	if c.capacityRouter != nil && !result.DryRun && pool.Name != "" {
+		if err := c.capacityRouter.PostDrainCleanupForNode(ctx, nodeObj, pool); err != nil {
+			logger.Warn("post-drain cleanup failed after interruption", "error", err)
+		}
+	}
+
+	c.historyLock.Lock()
+	c.lastMigration[collector.GetNodePoolID(nodeObj)] = c.clock()
+	c.historyLock.Unlock()
+	metrics.ActionTaken.WithLabelValues(inference.ActionToString(action), metrics.ActiveBundleVersion()).Inc()
+	return interruptionOutcomeDrained
+}
+
+// resolveInterruptedNode finds the node named by the event, or the node whose
+// providerID ends in the event's instance ID. Returns nil when no node matches.
+func (c *Controller) resolveInterruptedNode(ctx context.Context, ev interruption.Event) (*corev1.Node, error) {
+	if ev.NodeName != "" {
+		node, err := c.k8s.CoreV1().Nodes().Get(ctx, ev.NodeName, metav1.GetOptions{})
+		if err == nil {
+			return node, nil
+		}
+		if ev.InstanceID == "" {
+			return nil, err
+		}
+	}
+	if ev.InstanceID == "" {
+		return nil, nil
+	}
+
+	nodes, err := c.k8s.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range nodes.Items {
+		if strings.HasSuffix(nodes.Items[i].Spec.ProviderID, "/"+ev.InstanceID) {
+			return &nodes.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// interruptionSkipReason mirrors executeAction's node filters. Interruptions
+// only drain managed Spot workers.
+func interruptionSkipReason(node *corev1.Node) string {
+	labels := node.Labels
+	if labels == nil || labels["spotvortex.io/managed"] != "true" {
+		return "unmanaged node"
+	}
+	if _, ok := labels["node-role.kubernetes.io/control-plane"]; ok {
+		return "control-plane node"
+	}
+	if _, ok := labels["node-role.kubernetes.io/master"]; ok {
+		return "control-plane node"
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == "spotvortex.io/fake" {
+			return "fake node"
+		}
+	}
+	if capacity.CapacityTypeFromLabels(labels) != "spot" {
+		return "not a spot node"
+	}
+	return ""
+}
+
+// interruptionClaimKey dedupes per node and event kind, so a Spot warning
+// that follows a rebalance recommendation for the same node still drains.
+func interruptionClaimKey(node string, kind interruption.Kind) string {
+	return node + "/" + string(kind)
+}
+
+// claimInterruption reports whether the caller should handle key. Repeat
+// claims within interruptionDedupeWindow are refused.
+func (c *Controller) claimInterruption(key string) bool {
+	now := c.clock()
+	c.historyLock.Lock()
+	defer c.historyLock.Unlock()
+	if c.handledInterruptions == nil {
+		c.handledInterruptions = make(map[string]time.Time)
+	}
+	for name, at := range c.handledInterruptions {
+		if now.Sub(at) > interruptionDedupeWindow {
+			delete(c.handledInterruptions, name)
+		}
+	}
+	if _, ok := c.handledInterruptions[key]; ok {
+		return false
+	}
+	c.handledInterruptions[key] = now
+	return true
+}
+
+// releaseInterruption drops a claim whose handling did not complete.
+func (c *Controller) releaseInterruption(key string) {
+	c.historyLock.Lock()
+	delete(c.handledInterruptions, key)
+	c.historyLock.Unlock()
+}
+
+// recordInterruptionSignal stores a runtime risk signal under both the
+// node-level pool ID and the pool-level aggregation key, so whichever
+// inference path runs next picks it up.
+func (c *Controller) recordInterruptionSignal(ev interruption.Event, instanceType, zone, workloadPool string) {
+	score := interruptionRiskScore
+	if ev.Kind == interruption.KindRebalanceRecommendation {
+		score = rebalanceRiskScore
+	}
+
+	poolKey := zone
+	if workloadPool != "" {
+		poolKey = workloadPool + ":" + zone
+	}
+	if zone == "" {
+		poolKey = "unknown"
+	}
+	keys := []string{c.getPoolIDWithExtendedFormat(instanceType, zone, workloadPool), poolKey}
+
+	now := c.clock()
+	c.historyLock.Lock()
+	defer c.historyLock.Unlock()
+	if c.interruptionSignals == nil {
+		c.interruptionSignals = make(map[string]interruptionSignal)
+	}
+	for _, key := range keys {
+		// Keep the stronger of the existing (decayed) and new signal.
+		if existing, ok := c.interruptionSignals[key]; ok && c.decayInterruption(existing, now) > score {
+			continue
+		}
+		c.interruptionSignals[key] = interruptionSignal{score: score, at: now}
+	}
+}
+
+// applyInterruptionRisk raises runtimeScore to the pool's decayed
+// interruption signal, if any.
+func (c *Controller) applyInterruptionRisk(poolID string, runtimeScore float32) float32 {
+	c.historyLock.Lock()
+	signal, ok := c.interruptionSignals[poolID]
+	c.historyLock.Unlock()
+	if !ok {
+		return runtimeScore
+	}
+
+	boosted := c.decayInterruption(signal, c.clock())
+	if boosted < 0.01 {
+		c.historyLock.Lock()
+		if current, ok := c.interruptionSignals[poolID]; ok && current == signal {
+			delete(c.interruptionSignals, poolID)
+		}
+		c.historyLock.Unlock()
+		return runtimeScore
+	}
+	if float32(boosted) > runtimeScore {
+		return float32(boosted)
+	}
+	return runtimeScore
+}
+
+func (c *Controller) decayInterruption(signal interruptionSignal, now time.Time) float64 {
+	halfLife := c.interruptionHalfLife
+	if halfLife <= 0 {
+		halfLife = defaultInterruptionRiskHalfLife
+	}
+	elapsed := now.Sub(signal.at)
+	if elapsed <= 0 {
+		return signal.score
+	}
+	return signal.score * math.Pow(0.5, float64(elapsed)/float64(halfLife))
+}
+
+func (c *Controller) setLastClusterUtilization(util float64) {
+	c.historyLock.Lock()
+	c.lastClusterUtilization = util
+	c.historyLock.Unlock()
+}
+
+func (c *Controller) lastUtilization() float64 {
+	c.historyLock.Lock()
+	defer c.historyLock.Unlock()
+	return c.lastClusterUtilization
+}
@@ -0,0 +1,178 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/softcane/spot-vortex-agent/internal/interruption"
+	svmetrics "github.com/softcane/spot-vortex-agent/internal/metrics"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func newInterruptionTestController(t *testing.T, maxDrainRatio float64) (*Controller, *k8sfake.Clientset) {
+	t.Helper()
+	client := k8sfake.NewSimpleClientset()
+	createNode(client, "spot-1", "spot", "us-east-1a", "m5.large")
+	createNode(client, "od-1", "on-demand", "us-east-1a", "m5.large")
+	node, _ := client.CoreV1().Nodes().Get(context.Background(), "spot-1", metav1.GetOptions{})
+	node.Spec.ProviderID = "aws:///us-east-1a/i-0spot"
+	_, _ = client.CoreV1().Nodes().Update(context.Background(), node, metav1.UpdateOptions{})
+
+	cfg := baseControllerConfig(&noopCloudProvider{dryRun: false})
+	cfg.K8sClient = client
+	cfg.MaxDrainRatio = maxDrainRatio
+	ctrl, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	return ctrl, client
+}
+
+func TestHandleInterruption_DrainsNodeByInstanceID(t *testing.T) {
+	ctrl, client := newInterruptionTestController(t, 1.0)
+	before := testutil.ToFloat64(svmetrics.AWSInterruptionNoticeTotal)
+
+	now := time.Now()
+	outcome := ctrl.handleInterruption(context.Background(), interruption.Event{
+		Kind:       interruption.KindSpotInterruption,
+		InstanceID: "i-0spot",
+		Time:       now,
+		Deadline:   now.Add(interruption.SpotInterruptionNotice),
+		Source:     "test",
+	})
+	if outcome != interruptionOutcomeDrained {
+		t.Fatalf("outcome=%q, want %q", outcome, interruptionOutcomeDrained)
+	}
+	node, _ := client.CoreV1().Nodes().Get(context.Background(), "spot-1", metav1.GetOptions{})
+	if !node.Spec.Unschedulable {
+		t.Fatal("interrupted node was not cordoned")
+	}
+	if delta := testutil.ToFloat64(svmetrics.AWSInterruptionNoticeTotal) - before; delta != 1 {
+		t.Fatalf("interruption notice delta=%v, want 1", delta)
+	}
+
+	// A second source reporting the same interruption is deduplicated.
+	outcome = ctrl.handleInterruption(context.Background(), interruption.Event{
+		Kind:     interruption.KindSpotInterruption,
+		NodeName: "spot-1",
+		Source:   "node",
+	})
+	if outcome != interruptionOutcomeSkipped {
+		t.Fatalf("repeat outcome=%q, want %q", outcome, interruptionOutcomeSkipped)
+	}
+}
+
+func TestHandleInterruption_HonorsGuardrails(t *testing.T) {
+	// One spot node with a 20% drain ratio trips the cluster_fraction guardrail.
+	ctrl, client := newInterruptionTestController(t, 0.2)
+
+	outcome := ctrl.handleInterruption(context.Background(), interruption.Event{
+		Kind:     interruption.KindRebalanceRecommendation,
+		NodeName: "spot-1",
+	})
+	if outcome != interruptionOutcomeBlocked {
+		t.Fatalf("outcome=%q, want %q", outcome, interruptionOutcomeBlocked)
+	}
+	node, _ := client.CoreV1().Nodes().Get(context.Background(), "spot-1", metav1.GetOptions{})
+	if node.Spec.Unschedulable {
+		t.Fatal("guardrail-blocked node was cordoned")
+	}
+	// The pool's runtime score is raised even when the drain is blocked.
+	if got := ctrl.applyInterruptionRisk("m5.large:us-east-1a", 0.1); got < 0.59 {
+		t.Fatalf("runtime score=%v, want rebalance boost", got)
+	}
+}
+
+func TestHandleInterruption_BlockedEventDoesNotClaimNode(t *testing.T) {
+	ctrl, client := newInterruptionTestController(t, 0.2)
+
+	ev := interruption.Event{Kind: interruption.KindRebalanceRecommendation, NodeName: "spot-1"}
+	if outcome := ctrl.handleInterruption(context.Background(), ev); outcome != interruptionOutcomeBlocked {
+		t.Fatalf("outcome=%q, want %q", outcome, interruptionOutcomeBlocked)
+	}
+
+	// Once the guardrail clears, a repeat report is handled rather than
+	// deduplicated against the blocked one.
+	ctrl.maxDrainRatio = 1.0
+	if outcome := ctrl.handleInterruption(context.Background(), ev); outcome != interruptionOutcomeDrained {
+		t.Fatalf("retry outcome=%q, want %q", outcome, interruptionOutcomeDrained)
+	}
+	node, _ := client.CoreV1().Nodes().Get(context.Background(), "spot-1", metav1.GetOptions{})
+	if !node.Spec.Unschedulable {
+		t.Fatal("node was not cordoned on retry")
+	}
+}
+
+func TestClaimInterruption_PerNodeAndKind(t *testing.T) {
+	ctrl := &Controller{}
+	rebalance := interruptionClaimKey("spot-1", interruption.KindRebalanceRecommendation)
+	spot := interruptionClaimKey("spot-1", interruption.KindSpotInterruption)
+
+	if !ctrl.claimInterruption(rebalance) {
+		t.Fatal("first rebalance claim refused")
+	}
+	if ctrl.claimInterruption(rebalance) {
+		t.Fatal("repeat rebalance claim accepted")
+	}
+	if !ctrl.claimInterruption(spot) {
+		t.Fatal("spot warning after a rebalance recommendation was deduplicated")
+	}
+
+	ctrl.releaseInterruption(spot)
+	if !ctrl.claimInterruption(spot) {
+		t.Fatal("released claim was not available again")
+	}
+}
+
+func TestHandleInterruption_SkipsOnDemandAndUnknownNodes(t *testing.T) {
+	ctrl, _ := newInterruptionTestController(t, 1.0)
+
+	if outcome := ctrl.handleInterruption(context.Background(), interruption.Event{
+		Kind:     interruption.KindSpotInterruption,
+		NodeName: "od-1",
+	}); outcome != interruptionOutcomeSkipped {
+		t.Fatalf("on-demand outcome=%q, want skipped", outcome)
+	}
+	if outcome := ctrl.handleInterruption(context.Background(), interruption.Event{
+		Kind:       interruption.KindSpotInterruption,
+		InstanceID: "i-missing",
+	}); outcome != interruptionOutcomeSkipped {
+		t.Fatalf("unknown instance outcome=%q, want skipped", outcome)
+	}
+}
+
+func TestApplyInterruptionRisk_DecaysWithHalfLife(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctrl := &Controller{
+		now:                  func() time.Time { return now },
+		interruptionHalfLife: 10 * time.Minute,
+	}
+	ctrl.recordInterruptionSignal(interruption.Event{Kind: interruption.KindSpotInterruption}, "m5.large", "us-east-1a", "api")
+
+	if got := ctrl.applyInterruptionRisk("api:us-east-1a", 0.2); got != 1 {
+		t.Fatalf("fresh pool-level score=%v, want 1", got)
+	}
+	if got := ctrl.applyInterruptionRisk("other:us-east-1a", 0.2); got != 0.2 {
+		t.Fatalf("unaffected pool score=%v, want 0.2", got)
+	}
+
+	now = now.Add(10 * time.Minute)
+	if got := ctrl.applyInterruptionRisk("m5.large:us-east-1a", 0.2); got < 0.49 || got > 0.51 {
+		t.Fatalf("score after one half-life=%v, want ~0.5", got)
+	}
+	// Model score above the decayed signal wins.
+	if got := ctrl.applyInterruptionRisk("m5.large:us-east-1a", 0.9); got != 0.9 {
+		t.Fatalf("score=%v, want model score 0.9", got)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if got := ctrl.applyInterruptionRisk("api:us-east-1a", 0.2); got != 0.2 {
+		t.Fatalf("score after decay=%v, want 0.2", got)
+	}
+	if _, ok := ctrl.interruptionSignals["api:us-east-1a"]; ok {
+		t.Fatal("fully decayed signal was not pruned")
+	}
+}
@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/collector"
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+	"github.com/softcane/spot-vortex-agent/internal/karpenter"
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Handoff methods: how a node was handed to Karpenter.
+const (
+	// handoffMethodDrift leaves a NodeClaim Karpenter already marked Drifted
+	// (for example after a single NodePool stopped allowing spot) to its
+	// disruption controller, within the NodePool's disruption budgets.
+	handoffMethodDrift = "drift"
+	// handoffMethodDelete deletes the NodeClaim; Karpenter's termination flow
+	// cordons and drains the node, honoring PDBs, and the NodePool replaces it.
+	handoffMethodDelete = "delete"
+)
+
+// karpenterHandoff is a node SpotVortex handed to Karpenter for replacement.
+type karpenterHandoff struct {
+	nodePool  string
+	nodeClaim string
+	method    string
+	since     time.Time
+}
+
+// handsOffToKarpenter reports whether a node is migrated by Karpenter rather
+// than drained by SpotVortex.
+func (c *Controller) handsOffToKarpenter(nodeObj *corev1.Node) bool {
+	return c.nodePoolMgr != nil && c.karpenterCfg.Enabled && c.karpenterCfg.HandsOffDisruption() &&
+		nodeObj.Labels[karpenter.NodePoolLabel] != ""
+}
+
+// handOffToKarpenter migrates a Karpenter node through Karpenter: a drifted
+// NodeClaim is left to drift replacement, any other is deleted. Deletes skip
+// Karpenter's disruption controller, so the node is tracked until it is gone
+// and counted against its NodePool's budget meanwhile (see
+// trackKarpenterHandoffs and getKarpenterDisruptionLimit).
+func (c *Controller) handOffToKarpenter(ctx context.Context, nodeObj *corev1.Node, action inference.Action) error {
+	c.historyLock.Lock()
+	pending, ok := c.handoffs[nodeObj.Name]
+	c.historyLock.Unlock()
+	if ok {
+		c.logger.Debug("node already handed to Karpenter",
+			"node_id", nodeObj.Name,
+			"nodeclaim", pending.nodeClaim,
+			"method", pending.method,
+		)
+		return nil
+	}
+
+	nodePool := nodeObj.Labels[karpenter.NodePoolLabel]
+	if c.cloud != nil && c.cloud.IsDryRun() {
+		c.logger.Info("DRY-RUN: would hand node to Karpenter for replacement",
+			"node_id", nodeObj.Name,
+			"nodepool", nodePool,
+			"action", inference.ActionToString(action),
+		)
+		return nil
+	}
+
+	claim, err := c.nodePoolMgr.NodeClaimForNode(ctx, nodePool, nodeObj.Name)
+	if err != nil {
+		return fmt.Errorf("karpenter handoff of node %s: %w", nodeObj.Name, err)
+	}
+
+	// SpotVortex is moving the node now; its own protection must not block
+	// Karpenter's replacement.
+	if c.labeler != nil {
+		if err := c.labeler.UnprotectNode(ctx, nodeObj.Name); err != nil {
+			c.logger.Warn("failed to remove do-not-disrupt before handoff", "node_id", nodeObj.Name, "error", err)
+		}
+	}
+
+	method := handoffMethodDrift
+	if !claim.Drifted {
+		method = handoffMethodDelete
+		if err := c.nodePoolMgr.DeleteNodeClaim(ctx, claim.Name); err != nil {
+			return fmt.Errorf("karpenter handoff of node %s: %w", nodeObj.Name, err)
+		}
+	}
+
+	now := c.clock()
+	c.historyLock.Lock()
+	if c.handoffs == nil {
+		c.handoffs = make(map[string]karpenterHandoff)
+	}
+	c.handoffs[nodeObj.Name] = karpenterHandoff{nodePool: nodePool, nodeClaim: claim.Name, method: method, since: now}
+	c.lastMigration[collector.GetNodePoolID(nodeObj)] = now
+	c.historyLock.Unlock()
+
+	c.logger.Info("node handed to Karpenter for replacement",
+		"node_id", nodeObj.Name,
+		"nodepool", nodePool,
+		"nodeclaim", claim.Name,
+		"method", method,
+		"action", inference.ActionToString(action),
+	)
+	metrics.KarpenterHandoffs.WithLabelValues(method, "started").Inc()
+	metrics.ActionTaken.WithLabelValues(inference.ActionToString(action), metrics.ActiveBundleVersion()).Inc()
+	return nil
+}
+
+// pendingHandoffs returns how many handed-off nodes of nodePool Karpenter
+// is still replacing. They count against the NodePool's disruption budget
+// until they are gone.
+func (c *Controller) pendingHandoffs(nodePool string) int {
+	c.historyLock.Lock()
+	defer c.historyLock.Unlock()
+	n := 0
+	for _, h := range c.handoffs {
+		if h.nodePool == nodePool {
+			n++
+		}
+	}
+	return n
+}
+
+// trackKarpenterHandoffs completes handoffs whose node is gone and reports
+// those Karpenter has not replaced within the handoff timeout.
+func (c *Controller) trackKarpenterHandoffs(ctx context.Context) {
+	if c.k8s == nil {
+		return
+	}
+	c.historyLock.Lock()
+	pending := make(map[string]karpenterHandoff, len(c.handoffs))
+	for name, h := range c.handoffs {
+		pending[name] = h
+	}
+	c.historyLock.Unlock()
+
+	now := c.clock()
+	for nodeName, h := range pending {
+		var outcome string
+		_, err := c.k8s.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			outcome = "completed"
+			c.logger.Info("Karpenter replaced handed-off node",
+				"node_id", nodeName,
+				"nodeclaim", h.nodeClaim,
+				"method", h.method,
+				"duration", now.Sub(h.since),
+			)
+			metrics.OutagesAvoided.Inc()
+		case err != nil:
+			c.logger.Warn("failed to check handed-off node", "node_id", nodeName, "error", err)
+			continue
+		case now.Sub(h.since) > c.karpenterCfg.HandoffTimeout():
+			outcome = "timeout"
+			c.logger.Warn("Karpenter has not replaced handed-off node; no longer tracking it",
+				"node_id", nodeName,
+				"nodeclaim", h.nodeClaim,
+				"method", h.method,
+				"since", h.since,
+			)
+		default:
+			continue
+		}
+
+		metrics.KarpenterHandoffs.WithLabelValues(h.method, outcome).Inc()
+		c.historyLock.Lock()
+		delete(c.handoffs, nodeName)
+		c.historyLock.Unlock()
+	}
+}
+
+// syncKarpenterProtection sets karpenter.sh/do-not-disrupt on high-risk
+// Karpenter nodes hosting critical pods, so Karpenter's drift and
+// consolidation leave them for SpotVortex to hand off deliberately, and
+// removes it once a node is no longer high risk.
+func (c *Controller) syncKarpenterProtection(ctx context.Context, assessments []NodeAssessment) {
+	if c.labeler == nil || !c.karpenterCfg.Enabled || !c.karpenterCfg.HandsOffDisruption() || c.k8s == nil {
+		return
+	}
+	for _, a := range assessments {
+		nodeObj, err := c.getNode(ctx, a.NodeID)
+		if err != nil || nodeObj.Labels[karpenter.NodePoolLabel] == "" {
+			continue
+		}
+		highRisk := float64(a.CapacityScore) >= c.riskThreshold
+		_, protected := nodeObj.Annotations[karpenter.ProtectedAnnotation]
+
+		switch {
+		case highRisk && !protected:
+			pod := c.criticalPodOnNode(ctx, a.NodeID)
+			if pod == "" {
+				continue
+			}
+			if err := c.labeler.ProtectNode(ctx, a.NodeID, "critical pod "+pod); err != nil {
+				c.logger.Warn("failed to protect critical node from Karpenter disruption", "node_id", a.NodeID, "error", err)
+			}
+		case !highRisk && protected:
+			if err := c.labeler.UnprotectNode(ctx, a.NodeID); err != nil {
+				c.logger.Warn("failed to remove do-not-disrupt", "node_id", a.NodeID, "error", err)
+			}
+		}
+	}
+}
+
+// criticalPodOnNode returns the namespaced name of a pod on the node
+// annotated spotvortex.io/critical=true, or "".
+func (c *Controller) criticalPodOnNode(ctx context.Context, nodeName string) string {
+	pods, err := podsOnNode(ctx, c.k8s, c.cache, nodeName)
+	if err != nil {
+		c.logger.Warn("failed to list pods for critical workload check", "node_id", nodeName, "error", err)
+		return ""
+	}
+	for _, pod := range pods {
+		if pod.Spec.NodeName == nodeName && pod.Annotations[AnnotationCritical] == "true" {
+			return pod.Namespace + "/" + pod.Name
+		}
+	}
+	return ""
+}
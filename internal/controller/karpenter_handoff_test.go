@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/config"
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+	"github.com/softcane/spot-vortex-agent/internal/karpenter"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func makeTestNodeClaim(name, nodeName string, drifted bool) *unstructured.Unstructured {
+	conditions := []interface{}{map[string]interface{}{"type": "Initialized", "status": "True"}}
+	if drifted {
+		conditions = append(conditions, map[string]interface{}{"type": "Drifted", "status": "True"})
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "karpenter.sh/v1",
+		"kind":       "NodeClaim",
+		"metadata": map[string]interface{}{
+			"name":   name,
+			"labels": map[string]interface{}{karpenter.NodePoolLabel: "general-spot"},
+		},
+		"status": map[string]interface{}{"nodeName": nodeName, "conditions": conditions},
+	}}
+}
+
+func newHandoffTestController(t *testing.T) (*Controller, *k8sfake.Clientset, *karpenter.NodePoolManager) {
+	t.Helper()
+	k8sClient := k8sfake.NewSimpleClientset()
+	for i := 0; i < 5; i++ {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("k-node-%d", i),
+			Labels: map[string]string{
+				"spotvortex.io/managed":            "true",
+				"spotvortex.io/pool":               "general",
+				"karpenter.sh/capacity-type":       "spot",
+				karpenter.NodePoolLabel:            "general-spot",
+				"topology.kubernetes.io/zone":      "us-east-1a",
+				"node.kubernetes.io/instance-type": "m5.large",
+			},
+		}}
+		if _, err := k8sClient.CoreV1().Nodes().Create(context.Background(), node, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("create node %s: %v", node.Name, err)
+		}
+	}
+	dynClient := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Group: "karpenter.sh", Version: "v1", Resource: "nodepools"}:  "NodePoolList",
+		{Group: "karpenter.sh", Version: "v1", Resource: "nodeclaims"}: "NodeClaimList",
+	},
+		makeTestNodeClaim("general-spot-aaaaa", "k-node-0", true),
+		makeTestNodeClaim("general-spot-bbbbb", "k-node-1", false),
+	)
+
+	logger := slog.Default()
+	mgr := karpenter.NewNodePoolManager(dynClient, logger)
+	ctrl := &Controller{
+		k8s:           k8sClient,
+		dynamicClient: dynClient,
+		logger:        logger,
+		cloud:         &MockCloudProvider{DryRun: false},
+		karpenterCfg: config.KarpenterConfig{
+			Enabled:                true,
+			SpotNodePoolSuffix:     "-spot",
+			OnDemandNodePoolSuffix: "-od",
+			DisruptionMode:         config.DisruptionModeHandoff,
+			HandoffTimeoutSeconds:  60,
+		},
+		nodePoolMgr:      mgr,
+		labeler:          karpenter.NewLabeler(k8sClient, logger, false),
+		riskThreshold:    0.7,
+		lastMigration:    make(map[string]time.Time),
+		targetSpotRatio:  map[string]float64{"m5.large:us-east-1a": 1.0},
+		currentSpotRatio: map[string]float64{"m5.large:us-east-1a": 1.0},
+		handoffs:         make(map[string]karpenterHandoff),
+	}
+	return ctrl, k8sClient, mgr
+}
+
+func TestExecuteAction_HandsKarpenterNodesToKarpenter(t *testing.T) {
+	ctrl, k8sClient, mgr := newHandoffTestController(t)
+	ctx := context.Background()
+
+	for _, nodeID := range []string{"k-node-0", "k-node-1"} {
+		if err := ctrl.executeAction(ctx, NodeAssessment{NodeID: nodeID, Action: inference.ActionDecrease10, Confidence: 1.0}); err != nil {
+			t.Fatalf("executeAction(%s): %v", nodeID, err)
+		}
+	}
+
+	// The drifted NodeClaim is left to Karpenter's drift replacement; the
+	// other is deleted for Karpenter to drain and replace. Neither node is
+	// cordoned by SpotVortex.
+	claims, err := mgr.ListNodeClaims(ctx, "general-spot")
+	if err != nil || len(claims) != 1 || claims[0].Name != "general-spot-aaaaa" {
+		t.Fatalf("claims=%v, %v; want only the drifted NodeClaim left", claims, err)
+	}
+	if got := ctrl.handoffs["k-node-0"].method; got != handoffMethodDrift {
+		t.Fatalf("k-node-0 method=%q, want drift", got)
+	}
+	if got := ctrl.handoffs["k-node-1"].method; got != handoffMethodDelete {
+		t.Fatalf("k-node-1 method=%q, want delete", got)
+	}
+	for _, nodeID := range []string{"k-node-0", "k-node-1"} {
+		node, _ := k8sClient.CoreV1().Nodes().Get(ctx, nodeID, metav1.GetOptions{})
+		if node.Spec.Unschedulable {
+			t.Fatalf("%s cordoned; Karpenter should drain it", nodeID)
+		}
+	}
+
+	// Karpenter removes k-node-1; k-node-0 stalls past the handoff timeout.
+	if err := k8sClient.CoreV1().Nodes().Delete(ctx, "k-node-1", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("delete node: %v", err)
+	}
+	ctrl.trackKarpenterHandoffs(ctx)
+	if _, ok := ctrl.handoffs["k-node-1"]; ok {
+		t.Fatal("expected the replaced node's handoff to complete")
+	}
+	if _, ok := ctrl.handoffs["k-node-0"]; !ok {
+		t.Fatal("expected the drifting node to stay tracked")
+	}
+	ctrl.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	ctrl.trackKarpenterHandoffs(ctx)
+	if len(ctrl.handoffs) != 0 {
+		t.Fatalf("handoffs=%v, want the stalled handoff dropped", ctrl.handoffs)
+	}
+}
+
+func TestGetKarpenterDisruptionLimit_CountsPendingHandoffs(t *testing.T) {
+	ctrl, _, _ := newHandoffTestController(t)
+	ctx := context.Background()
+	nodePool := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "karpenter.sh/v1",
+		"kind":       "NodePool",
+		"metadata":   map[string]interface{}{"name": "general-spot"},
+		"spec": map[string]interface{}{
+			"disruption": map[string]interface{}{
+				"budgets": []interface{}{map[string]interface{}{"nodes": "2"}},
+			},
+		},
+	}}
+	gvr := schema.GroupVersionResource{Group: "karpenter.sh", Version: "v1", Resource: "nodepools"}
+	if _, err := ctrl.dynamicClient.Resource(gvr).Create(ctx, nodePool, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create NodePool: %v", err)
+	}
+	atRisk := []NodeAssessment{{NodeID: "k-node-2"}, {NodeID: "k-node-3"}}
+
+	if got := ctrl.getKarpenterDisruptionLimit(ctx, atRisk, 5); got != 2 {
+		t.Fatalf("limit=%d, want the NodePool budget of 2", got)
+	}
+
+	// A deleted NodeClaim bypasses Karpenter's budget accounting; it still
+	// counts until its node is gone.
+	if err := ctrl.executeAction(ctx, NodeAssessment{NodeID: "k-node-1", Action: inference.ActionDecrease10, Confidence: 1.0}); err != nil {
+		t.Fatalf("executeAction: %v", err)
+	}
+	if got := ctrl.getKarpenterDisruptionLimit(ctx, atRisk, 5); got != 1 {
+		t.Fatalf("limit=%d, want 1 with one handoff in flight", got)
+	}
+	ctrl.handoffs["k-node-4"] = karpenterHandoff{nodePool: "general-spot", method: handoffMethodDelete}
+	ctrl.handoffs["k-node-0"] = karpenterHandoff{nodePool: "general-spot", method: handoffMethodDrift}
+	if got := ctrl.getKarpenterDisruptionLimit(ctx, atRisk, 5); got != 0 {
+		t.Fatalf("limit=%d, want 0 once in-flight handoffs exhaust the budget", got)
+	}
+}
+
+func TestSyncKarpenterProtection_CriticalHighRiskNodes(t *testing.T) {
+	ctrl, k8sClient, _ := newHandoffTestController(t)
+	ctx := context.Background()
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "payments-0",
+			Namespace:   "default",
+			Annotations: map[string]string{AnnotationCritical: "true"},
+		},
+		Spec: corev1.PodSpec{NodeName: "k-node-2"},
+	}
+	if _, err := k8sClient.CoreV1().Pods("default").Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create pod: %v", err)
+	}
+
+	ctrl.syncKarpenterProtection(ctx, []NodeAssessment{
+		{NodeID: "k-node-2", CapacityScore: 0.9},
+		{NodeID: "k-node-3", CapacityScore: 0.9}, // high risk, nothing critical
+	})
+	node, _ := k8sClient.CoreV1().Nodes().Get(ctx, "k-node-2", metav1.GetOptions{})
+	if node.Annotations[karpenter.DoNotDisruptAnnotation] != "true" {
+		t.Fatalf("annotations=%v, want do-not-disrupt on the critical high-risk node", node.Annotations)
+	}
+	node, _ = k8sClient.CoreV1().Nodes().Get(ctx, "k-node-3", metav1.GetOptions{})
+	if _, ok := node.Annotations[karpenter.DoNotDisruptAnnotation]; ok {
+		t.Fatal("a node without critical pods must stay disruptable")
+	}
+
+	ctrl.syncKarpenterProtection(ctx, []NodeAssessment{{NodeID: "k-node-2", CapacityScore: 0.2}})
+	node, _ = k8sClient.CoreV1().Nodes().Get(ctx, "k-node-2", metav1.GetOptions{})
+	if _, ok := node.Annotations[karpenter.DoNotDisruptAnnotation]; ok {
+		t.Fatal("expected do-not-disrupt removed once the node is no longer high risk")
+	}
+}
@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/collector"
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+	"github.com/softcane/spot-vortex-agent/internal/karpenter"
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
+)
+
+// karpenterReplacement is the capacity a workload pool's drained Karpenter
+// nodes move to.
+type karpenterReplacement struct {
+	workloadPool string
+	capacityType string
+}
+
+// awaitKarpenterReplacements holds drains of Karpenter nodes until their
+// workload pool has an Initialized NodeClaim of the capacity type they move
+// to, pre-provisioning one when Karpenter is not already launching it. All
+// pools wait concurrently under one NodeClaimReadyTimeout. This is the
+// Karpenter counterpart of the ASG path's wait for a Ready node.
+//
+// When a pool's wait times out, its steering from this tick is rolled back
+// and its nodes are not drained, except EMERGENCY_EXIT nodes, which are
+// drained regardless. Disabled unless karpenter.waitForNodeClaim is set, and
+// skipped in dry-run since pre-provisioning launches instances.
+func (c *Controller) awaitKarpenterReplacements(ctx context.Context, nodes []NodeAssessment) []NodeAssessment {
+	if c.nodePoolMgr == nil || !c.karpenterCfg.Enabled || !c.karpenterCfg.WaitForNodeClaim || c.k8s == nil {
+		return nodes
+	}
+	if c.cloud != nil && c.cloud.IsDryRun() {
+		return nodes
+	}
+
+	needs := make(map[string]karpenterReplacement) // node ID -> replacement
+	var pending []karpenterReplacement
+	seen := make(map[karpenterReplacement]bool)
+	for _, node := range nodes {
+		capacityType := replacementCapacityType(node)
+		if capacityType == "" {
+			continue
+		}
+		nodeObj, err := c.getNode(ctx, node.NodeID)
+		if err != nil || nodeObj.Labels[karpenter.NodePoolLabel] == "" {
+			continue
+		}
+		workloadPool := nodeObj.Labels[collector.WorkloadPoolLabel]
+		if workloadPool == "" || !c.karpenterCfg.IsWorkloadPoolManaged(workloadPool) {
+			continue
+		}
+		r := karpenterReplacement{workloadPool: workloadPool, capacityType: capacityType}
+		needs[node.NodeID] = r
+		if !seen[r] {
+			seen[r] = true
+			pending = append(pending, r)
+		}
+	}
+
+	waits := make([]karpenter.ReplacementWait, len(pending))
+	for i, r := range pending {
+		waits[i] = karpenter.ReplacementWait{NodePool: c.replacementNodePool(r), CapacityType: r.capacityType}
+	}
+	results := c.nodePoolMgr.WaitForNodeClaims(ctx, waits,
+		c.karpenterCfg.NodeClaimReadyTimeout(), c.karpenterCfg.NodeClaimPollInterval())
+
+	failed := make(map[karpenterReplacement]bool)
+	for i, result := range results {
+		if result.Err != nil {
+			metrics.KarpenterNodeClaimWaits.WithLabelValues("timeout").Inc()
+			failed[pending[i]] = true
+			c.rollbackKarpenterSteering(pending[i].workloadPool)
+			continue
+		}
+		metrics.KarpenterNodeClaimWaits.WithLabelValues("ready").Inc()
+	}
+	if len(failed) == 0 {
+		return nodes
+	}
+
+	kept := make([]NodeAssessment, 0, len(nodes))
+	for _, node := range nodes {
+		if r, ok := needs[node.NodeID]; ok && failed[r] && node.Action != inference.ActionEmergencyExit {
+			c.logger.Info("skipping drain without replacement capacity",
+				"node_id", node.NodeID,
+				"workload_pool", r.workloadPool,
+				"action", inference.ActionToString(node.Action),
+			)
+			continue
+		}
+		kept = append(kept, node)
+	}
+	return kept
+}
+
+// replacementCapacityType returns the capacity type that replaces a drained
+// node, or "" for assessments that do not move capacity.
+func replacementCapacityType(node NodeAssessment) string {
+	if node.ResponseMode == ResponseModeFreezeSpot {
+		return ""
+	}
+	switch node.Action {
+	case inference.ActionDecrease10, inference.ActionDecrease30, inference.ActionEmergencyExit:
+		return karpenter.CapacityTypeOnDemand
+	case inference.ActionIncrease10, inference.ActionIncrease30:
+		return karpenter.CapacityTypeSpot
+	}
+	return ""
+}
+
+// replacementNodePool returns the NodePool that launches r: the pool itself
+// in the single layout, else its spot or on-demand twin.
+func (c *Controller) replacementNodePool(r karpenterReplacement) string {
+	if c.karpenterCfg.UsesSingleNodePool(r.workloadPool) {
+		return r.workloadPool
+	}
+	if r.capacityType == karpenter.CapacityTypeSpot {
+		return r.workloadPool + c.karpenterCfg.SpotNodePoolSuffix
+	}
+	return r.workloadPool + c.karpenterCfg.OnDemandNodePoolSuffix
+}
+
+// snapshotKarpenterSteering records a workload pool's NodePool weights and
+// capacity types before this tick steers them, when replacement waits are
+// enabled.
+func (c *Controller) snapshotKarpenterSteering(ctx context.Context, workloadPool string) {
+	if !c.karpenterCfg.WaitForNodeClaim {
+		return
+	}
+	var snapshots []karpenter.SteeringSnapshot
+	for _, name := range c.karpenterCfg.NodePoolNames(workloadPool) {
+		snapshot, err := c.nodePoolMgr.SnapshotSteering(ctx, name)
+		if err != nil {
+			c.logger.Warn("failed to snapshot NodePool before steering", "nodepool", name, "error", err)
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	c.historyLock.Lock()
+	defer c.historyLock.Unlock()
+	if c.karpenterSnapshots == nil {
+		c.karpenterSnapshots = make(map[string][]karpenter.SteeringSnapshot)
+	}
+	c.karpenterSnapshots[workloadPool] = snapshots
+}
+
+// rollbackKarpenterSteering restores a workload pool's NodePools to their
+// state before this tick's steering and clears its weight cooldown, so the
+// next tick may steer again.
+func (c *Controller) rollbackKarpenterSteering(workloadPool string) {
+	c.historyLock.Lock()
+	snapshots, ok := c.karpenterSnapshots[workloadPool]
+	delete(c.karpenterSnapshots, workloadPool)
+	if ok {
+		delete(c.lastWeightChange, workloadPool)
+	}
+	c.historyLock.Unlock()
+
+	// A timed-out wait can leave little of the tick's deadline, so restore
+	// under a fresh one.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for _, snapshot := range snapshots {
+		if err := c.nodePoolMgr.RestoreSteering(ctx, snapshot); err != nil {
+			c.logger.Error("failed to roll back NodePool steering",
+				"nodepool", snapshot.NodePool,
+				"error", err,
+			)
+			continue
+		}
+		c.logger.Info("rolled back NodePool steering",
+			"workload_pool", workloadPool,
+			"nodepool", snapshot.NodePool,
+			"weight", snapshot.Weight,
+			"capacity_types", snapshot.CapacityTypes,
+		)
+	}
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/softcane/spot-vortex-agent/internal/collector"
+	"github.com/softcane/spot-vortex-agent/internal/config"
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+	"github.com/softcane/spot-vortex-agent/internal/karpenter"
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAwaitKarpenterReplacements_TimeoutRollsBackAndHoldsDrains(t *testing.T) {
+	k8sClient := k8sfake.NewSimpleClientset()
+	dynClient := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Group: "karpenter.sh", Version: "v1", Resource: "nodepools"}:  "NodePoolList",
+		{Group: "karpenter.sh", Version: "v1", Resource: "nodeclaims"}: "NodeClaimList",
+	}, makeTestNodePool("general-spot", 80), makeTestNodePool("general-od", 20))
+	logger := slog.Default()
+	mgr := karpenter.NewNodePoolManager(dynClient, logger)
+	ctrl := &Controller{
+		k8s:           k8sClient,
+		dynamicClient: dynClient,
+		logger:        logger,
+		karpenterCfg: config.KarpenterConfig{
+			Enabled:                      true,
+			SpotNodePoolSuffix:           "-spot",
+			OnDemandNodePoolSuffix:       "-od",
+			SpotWeight:                   80,
+			OnDemandWeight:               20,
+			WeightChangeCooldownSeconds:  60,
+			WaitForNodeClaim:             true,
+			NodeClaimReadyTimeoutSeconds: 1,
+			NodeClaimPollIntervalSeconds: 1,
+		},
+		nodePoolMgr:      mgr,
+		lastWeightChange: make(map[string]time.Time),
+	}
+	for _, name := range []string{"n1", "n2"} {
+		createNode(k8sClient, name, "spot", "us-east-1a", "m5.large")
+		node, err := k8sClient.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("failed to get test node: %v", err)
+		}
+		node.Labels[collector.WorkloadPoolLabel] = "general"
+		node.Labels[karpenter.NodePoolLabel] = "general-spot"
+		if _, err := k8sClient.CoreV1().Nodes().Update(context.Background(), node, metav1.UpdateOptions{}); err != nil {
+			t.Fatalf("failed to label test node: %v", err)
+		}
+	}
+	before := testutil.ToFloat64(metrics.KarpenterNodeClaimWaits.WithLabelValues("timeout"))
+
+	nodes := []NodeAssessment{
+		{NodeID: "n1", Action: inference.ActionDecrease30},
+		{NodeID: "n2", Action: inference.ActionEmergencyExit},
+	}
+	ctrl.batchSteerKarpenterWeights(context.Background(), nodes)
+	if weight, _ := mgr.GetWeight(context.Background(), "general-od"); weight != 80 {
+		t.Fatalf("od weight=%d after steering, want 80", weight)
+	}
+
+	// No NodeClaim ever initializes: the steering is undone, the decrease is
+	// held, and the emergency exit still drains.
+	kept := ctrl.awaitKarpenterReplacements(context.Background(), nodes)
+	if len(kept) != 1 || kept[0].NodeID != "n2" {
+		t.Fatalf("kept=%v, want only the EMERGENCY_EXIT node", kept)
+	}
+	if weight, _ := mgr.GetWeight(context.Background(), "general-spot"); weight != 80 {
+		t.Fatalf("spot weight=%d, want 80 restored", weight)
+	}
+	if weight, _ := mgr.GetWeight(context.Background(), "general-od"); weight != 20 {
+		t.Fatalf("od weight=%d, want 20 restored", weight)
+	}
+	if _, cooling := ctrl.lastWeightChange["general"]; cooling {
+		t.Fatal("rollback must clear the weight cooldown")
+	}
+	if claims, _ := mgr.ListNodeClaims(context.Background(), "general-od"); len(claims) != 0 {
+		t.Fatalf("claims=%v, want the pre-provisioned NodeClaim deleted", claims)
+	}
+	if got := testutil.ToFloat64(metrics.KarpenterNodeClaimWaits.WithLabelValues("timeout")) - before; got != 1 {
+		t.Fatalf("timeout waits delta=%v, want 1", got)
+	}
+}
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"context"
+)
+
+// IsLeading reports whether this controller actuates. Controllers built
+// without leader election always lead.
+func (c *Controller) IsLeading() bool {
+	return !c.following.Load()
+}
+
+// StartLeading switches a follower to leader. It first catches up on the
+// previous leader's last checkpoint, then enables actuation and interruption
+// handling for as long as ctx lives.
+func (c *Controller) StartLeading(ctx context.Context) {
+	c.restoreState(ctx)
+	c.following.Store(false)
+	c.logger.Info("controller leading: actuation enabled")
+	c.startInterruptionHandling(ctx)
+}
+
+// StopLeading returns the controller to observe-only follower ticks.
+// Interruption handling stops with the context passed to StartLeading.
+func (c *Controller) StopLeading() {
+	c.following.Store(true)
+	c.logger.Info("controller following: actuation disabled")
+}
+
+// Warm reports whether the controller has completed at least one inference
+// pass, i.e. its caches reflect the cluster.
+func (c *Controller) Warm() bool {
+	return c.warm.Load()
+}
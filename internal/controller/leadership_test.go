@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+	svmetrics "github.com/softcane/spot-vortex-agent/internal/metrics"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+type memoryStateStore struct {
+	mu   sync.Mutex
+	data []byte
+}
+
+func (m *memoryStateStore) Save(_ context.Context, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *memoryStateStore) Load(context.Context) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.data, nil
+}
+
+func newLeadershipTestController(t *testing.T, store StateStore, leaderElection bool) *Controller {
+	t.Helper()
+	t.Setenv("SPOTVORTEX_METRICS_MODE", "synthetic")
+
+	k8sClient := k8sfake.NewSimpleClientset()
+	createNode(k8sClient, "node-1", "spot", "us-east-1a", "m5.large")
+
+	ctrl, err := New(Config{
+		Cloud:                   &MockCloudProvider{DryRun: true},
+		PriceProvider:           fixedPriceProvider(),
+		K8sClient:               k8sClient,
+		Inference:               &inference.InferenceEngine{},
+		PrometheusClient:        &svmetrics.Client{},
+		Logger:                  slog.Default(),
+		RiskThreshold:           0.95,
+		MaxDrainRatio:           1.0,
+		ReconcileInterval:       10 * time.Second,
+		ConfidenceThreshold:     0.5,
+		DrainGracePeriodSeconds: 1,
+		LeaderElection:          leaderElection,
+		StateStore:              store,
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctrl.runtimeConfigLoader = deterministicRuntimeConfigShadowTest
+	ctrl.predictDetailedOverride = func(ctx context.Context, nodeID string, state inference.NodeState, riskMultiplier float64) (inference.Action, float32, float32, float32, error) {
+		return inference.ActionIncrease30, 0.70, 0.10, 0.90, nil
+	}
+	return ctrl
+}
+
+func TestReconcile_FollowerKeepsCachesWarmWithoutActuating(t *testing.T) {
+	poolID := "m5.large:us-east-1a"
+	migratedAt := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	leaderState, _ := json.Marshal(persistedState{
+		Version:         stateSchemaVersion,
+		TargetSpotRatio: map[string]float64{poolID: 0.4},
+		LastMigration:   map[string]time.Time{poolID: migratedAt},
+		PriceHistory:    map[string][]float64{poolID: {0.2, 0.21, 0.22}},
+	})
+	mirror := &memoryStateStore{data: leaderState}
+
+	ctrl := newLeadershipTestController(t, mirror, true)
+	if ctrl.IsLeading() {
+		t.Fatal("controller with leader election should start as follower")
+	}
+	beforeActions := counterVecValue(t, svmetrics.ActionTaken, "DECREASE_30", svmetrics.ActiveBundleVersion())
+
+	if err := ctrl.Reconcile(context.Background()); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if delta := counterVecValue(t, svmetrics.ActionTaken, "DECREASE_30", svmetrics.ActiveBundleVersion()) - beforeActions; delta != 0 {
+		t.Fatalf("follower actuated: action_taken delta=%v", delta)
+	}
+	if !ctrl.Warm() {
+		t.Fatal("follower not warm after a tick")
+	}
+	if got := ctrl.targetSpotRatio[poolID]; got != 0.4 {
+		t.Fatalf("target spot ratio=%v, want leader's 0.4", got)
+	}
+	if got := ctrl.lastMigration[poolID]; !got.Equal(migratedAt) {
+		t.Fatalf("last migration=%v, want leader's %v", got, migratedAt)
+	}
+	if got := ctrl.priceHistory[poolID]; len(got) != 3 {
+		t.Fatalf("price history=%v, want leader's window", got)
+	}
+	if string(mirror.data) != string(leaderState) {
+		t.Fatal("follower overwrote the leader's published state")
+	}
+}
+
+func TestStartLeading_ActuatesAndPublishesWarmState(t *testing.T) {
+	mirror := &memoryStateStore{}
+	ctrl := newLeadershipTestController(t, mirror, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctrl.StartLeading(ctx)
+	if !ctrl.IsLeading() {
+		t.Fatal("StartLeading did not enable actuation")
+	}
+
+	beforeActions := counterVecValue(t, svmetrics.ActionTaken, "DECREASE_30", svmetrics.ActiveBundleVersion())
+	if err := ctrl.Reconcile(context.Background()); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if delta := counterVecValue(t, svmetrics.ActionTaken, "DECREASE_30", svmetrics.ActiveBundleVersion()) - beforeActions; delta != 1 {
+		t.Fatalf("leader action_taken delta=%v, want 1", delta)
+	}
+
+	var published persistedState
+	if err := json.Unmarshal(mirror.data, &published); err != nil {
+		t.Fatalf("leader did not publish warm state: %v", err)
+	}
+	if _, ok := published.LastMigration["m5.large:us-east-1a"]; !ok {
+		t.Fatalf("published state missing migration timestamp: %+v", published)
+	}
+
+	ctrl.StopLeading()
+	if ctrl.IsLeading() {
+		t.Fatal("StopLeading did not disable actuation")
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	ctrl := &Controller{}
+	ctrl.following.Store(true)
+
+	rec := httptest.NewRecorder()
+	ctrl.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("cold status=%d, want 503", rec.Code)
+	}
+
+	ctrl.warm.Store(true)
+	rec = httptest.NewRecorder()
+	ctrl.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok: follower\n" {
+		t.Fatalf("warm follower: status=%d body=%q", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,258 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/softcane/spot-vortex-agent/internal/config"
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
+)
+
+// PolicyInput is what a Policy sees for one decision scope (a node, or a
+// whole pool under pool-level inference) on one tick.
+type PolicyInput struct {
+	PoolID       string
+	NodeID       string // empty for pool-level decisions
+	WorkloadPool string
+	State        inference.NodeState
+	// PoolNodes is the number of nodes in the pool.
+	PoolNodes int
+
+	// CapacityScore and RuntimeScore are the TFT risk scores, with recent
+	// interruption warnings already applied to RuntimeScore.
+	CapacityScore float64
+	RuntimeScore  float64
+
+	// RLAction and RLConfidence are the RL model's recommendation.
+	// RLAvailable is false when RL inference failed this tick.
+	RLAction     inference.Action
+	RLConfidence float64
+	RLAvailable  bool
+
+	// Config is the runtime config resolved for this pool.
+	Config *config.RuntimeConfig
+}
+
+// PolicyDecision is a Policy's output for one scope.
+type PolicyDecision struct {
+	Action inference.Action
+	// Cap is the highest spot ratio the policy considers safe for the pool.
+	Cap        float64
+	Reason     string
+	Confidence float64
+
+	// ResponseMode and Urgency are optional runtime intent for explanations.
+	ResponseMode PolicyResponseMode
+	Urgency      PolicyUrgency
+
+	// deterministic carries the deterministic policy's full reasoning for
+	// explanations and metrics; nil for other policies.
+	deterministic *deterministicDecision
+}
+
+// Policy is a decision engine the controller can run as the active policy or
+// in shadow. Implementations must be safe for concurrent use and must not
+// mutate the input.
+type Policy interface {
+	Name() string
+	Decide(ctx context.Context, in PolicyInput) (PolicyDecision, error)
+}
+
+var (
+	policyRegistryMu sync.RWMutex
+	policyRegistry   = make(map[string]Policy)
+)
+
+func init() {
+	for _, p := range []Policy{deterministicPolicy{}, rlPolicy{}} {
+		if err := RegisterPolicy(p); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterPolicy makes p selectable by name through policy_mode and
+// shadow_policies in the runtime config. Names are case-insensitive and
+// must be unique. Register from an init function so the name is known
+// before the runtime config is loaded.
+func RegisterPolicy(p Policy) error {
+	if p == nil {
+		return fmt.Errorf("policy is nil")
+	}
+	name := strings.ToLower(strings.TrimSpace(p.Name()))
+	if name == "" {
+		return fmt.Errorf("policy name is required")
+	}
+	policyRegistryMu.Lock()
+	defer policyRegistryMu.Unlock()
+	if _, dup := policyRegistry[name]; dup {
+		return fmt.Errorf("policy %q is already registered", name)
+	}
+	policyRegistry[name] = p
+	config.RegisterPolicyMode(name)
+	return nil
+}
+
+// LookupPolicy returns the registered policy with the given name.
+func LookupPolicy(name string) (Policy, bool) {
+	policyRegistryMu.RLock()
+	defer policyRegistryMu.RUnlock()
+	p, ok := policyRegistry[strings.ToLower(strings.TrimSpace(name))]
+	return p, ok
+}
+
+// RegisteredPolicies returns the registered policy names, sorted.
+func RegisteredPolicies() []string {
+	policyRegistryMu.RLock()
+	defer policyRegistryMu.RUnlock()
+	names := make([]string, 0, len(policyRegistry))
+	for name := range policyRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// deterministicPolicy is the TFT-risk plus workload rule engine.
+type deterministicPolicy struct{}
+
+func (deterministicPolicy) Name() string { return config.PolicyModeDeterministic }
+
+func (deterministicPolicy) Decide(_ context.Context, in PolicyInput) (PolicyDecision, error) {
+	action, decision := evaluateDeterministicPolicy(in.State, in.CapacityScore, in.RuntimeScore, in.Config)
+	return PolicyDecision{
+		Action:        action,
+		Cap:           decision.EffectiveCap,
+		Reason:        decision.Reason,
+		Confidence:    1.0,
+		ResponseMode:  decision.ResponseMode,
+		Urgency:       decision.Urgency,
+		deterministic: &decision,
+	}, nil
+}
+
+// rlPolicy follows the RL model's recommendation.
+type rlPolicy struct{}
+
+func (rlPolicy) Name() string { return config.PolicyModeRL }
+
+func (rlPolicy) Decide(_ context.Context, in PolicyInput) (PolicyDecision, error) {
+	if !in.RLAvailable {
+		return PolicyDecision{}, fmt.Errorf("rl inference unavailable")
+	}
+	maxRatio := 1.0
+	if in.Config != nil {
+		maxRatio = in.Config.MaxSpotRatio
+	}
+	return PolicyDecision{
+		Action:     in.RLAction,
+		Cap:        maxRatio,
+		Reason:     "rl_policy",
+		Confidence: in.RLConfidence,
+	}, nil
+}
+
+// policyOutcome is the result of running the active and shadow policies for
+// one scope.
+type policyOutcome struct {
+	Source   string
+	Decision PolicyDecision
+
+	// ShadowAction is the first shadow's action, for the assessment.
+	ShadowAction inference.Action
+	HasShadow    bool
+	// ShadowDeltaUSD sums the projected hourly savings delta of each shadow
+	// against the active decision.
+	ShadowDeltaUSD float64
+	HasShadowDelta bool
+}
+
+// evaluatePolicies runs the tick's active policy and its shadow evaluators
+// for one scope. scopeNodeID is used for shadow guardrail checks and multiplier
+// scales the projected savings delta to the scope's node count.
+func (c *Controller) evaluatePolicies(ctx context.Context, tickCfg *config.RuntimeConfig, in PolicyInput, scopeNodeID string, multiplier float64) (policyOutcome, error) {
+	activeName := tickCfg.PolicyMode
+	active, ok := LookupPolicy(activeName)
+	if !ok {
+		return policyOutcome{}, fmt.Errorf("policy %q is not registered", activeName)
+	}
+	decision, err := active.Decide(ctx, in)
+	if err != nil {
+		return policyOutcome{}, fmt.Errorf("policy %q: %w", activeName, err)
+	}
+	out := policyOutcome{Source: active.Name(), Decision: decision}
+	c.evaluateStagedBundle(ctx, active, in, decision)
+	if decision.deterministic != nil {
+		c.observeDeterministicDecision(ctx, in, decision)
+	} else if c.logger != nil {
+		c.logger.Debug("policy decision",
+			"policy", out.Source,
+			"pool", in.PoolID,
+			"node_id", in.NodeID,
+			"action", inference.ActionToString(decision.Action),
+			"cap", decision.Cap,
+			"reason", decision.Reason,
+		)
+	}
+
+	for _, e := range tickCfg.ResolvedShadowEvaluators() {
+		shadowDecision, err := c.evaluateShadow(ctx, e, in, decision)
+		if err != nil {
+			if c.logger != nil {
+				c.logger.Debug("shadow evaluation skipped", "shadow", e.Name, "pool", in.PoolID, "error", err)
+			}
+			continue
+		}
+		if !out.HasShadow {
+			out.ShadowAction = shadowDecision.Action
+			out.HasShadow = true
+		}
+		out.ShadowDeltaUSD += c.recordShadowDecisionComparison(
+			ctx,
+			e.Name,
+			in.PoolID,
+			scopeNodeID,
+			in.State,
+			decision.Action,
+			shadowDecision.Action,
+			float32(in.CapacityScore),
+			float32(shadowDecision.Confidence),
+			multiplier,
+		)
+		out.HasShadowDelta = true
+	}
+	return out, nil
+}
+
+// observeDeterministicDecision publishes the deterministic policy's
+// explanation, metrics and debug log.
+func (c *Controller) observeDeterministicDecision(ctx context.Context, in PolicyInput, decision PolicyDecision) {
+	deterministic := *decision.deterministic
+	c.explainDecision(ctx, in.WorkloadPool, newDecisionExplanation(
+		in.PoolID, in.NodeID, in.State, decision.Action, in.CapacityScore, in.RuntimeScore, deterministic, in.Config,
+	))
+
+	metrics.DeterministicDecisionReason.WithLabelValues(deterministic.Reason, metrics.ActiveBundleVersion()).Inc()
+	metrics.WorkloadCap.WithLabelValues(in.PoolID).Set(deterministic.EffectiveCap)
+	c.logger.Debug("deterministic pool decision",
+		"pool", in.PoolID,
+		"node_id", in.NodeID,
+		"response_mode", deterministic.ResponseMode,
+		"urgency", deterministic.Urgency,
+		"reason", deterministic.Reason,
+		"safe_max_spot_ratio", deterministic.PoolSafety.SafeMaxSpotRatio,
+		"recovery_budget_violation_risk", deterministic.PoolSafety.RecoveryBudgetViolationRisk,
+	)
+	if deterministic.IsOOD {
+		metrics.WorkloadOOD.WithLabelValues(in.PoolID).Set(1.0)
+		for _, reason := range deterministic.OODReasons {
+			metrics.WorkloadOODReason.WithLabelValues(reason).Inc()
+		}
+	} else {
+		metrics.WorkloadOOD.WithLabelValues(in.PoolID).Set(0.0)
+	}
+}
@@ -45,6 +45,56 @@ type deterministicDecision struct {
 	PoolSafety    config.PoolSafetyVector
 	IsOOD         bool
 	OODReasons    []string
+
+	// Explanation detail for on-call tooling (see explain.go).
+	RiskBand   string
+	CapRules   []CapRuleEvaluation
+	BindingCap string
+	Economics  EconomicGate
+}
+
+// Risk bands reported in decision explanations.
+const (
+	RiskBandEmergency = "emergency"
+	RiskBandHigh      = "high"
+	RiskBandMedium    = "medium"
+	RiskBandLow       = "low"
+)
+
+// Cap sources reported as the binding constraint on the effective spot cap.
+const (
+	CapSourcePriority      = "priority"
+	CapSourceOutagePenalty = "outage_penalty"
+	CapSourceStartupTime   = "startup_time"
+	CapSourceMigrationCost = "migration_cost"
+	CapSourceUtilization   = "utilization"
+	CapSourcePoolSafety    = "pool_safety"
+	// CapSourceRecoveryBudget is pool safety bound by a declared recovery budget.
+	CapSourceRecoveryBudget = "recovery_budget"
+	CapSourceMinSpotRatio   = "min_spot_ratio"
+	CapSourceMaxSpotRatio   = "max_spot_ratio"
+	CapSourceNone           = "none"
+)
+
+// CapRuleEvaluation records how one SpotRatioCapRule set evaluated against
+// the pool's feature value.
+type CapRuleEvaluation struct {
+	Source  string                   `json:"source"`
+	Value   float64                  `json:"value"`
+	Matched *config.SpotRatioCapRule `json:"matched_rule,omitempty"`
+	Binding bool                     `json:"binding"`
+}
+
+// EconomicGate records the inputs and outcome of the spot-increase economics
+// check. Evaluated is false when an earlier rule decided before the gate ran.
+type EconomicGate struct {
+	Evaluated       bool    `json:"evaluated"`
+	Passed          bool    `json:"passed"`
+	SavingsRatio    float64 `json:"savings_ratio"`
+	PaybackHours    float64 `json:"payback_hours"`
+	MaxRisk         float64 `json:"max_risk"`
+	MinSavingsRatio float64 `json:"min_savings_ratio"`
+	MaxPaybackHours float64 `json:"max_payback_hours"`
 }
 
 // PolicyEvaluator encapsulates deterministic policy evaluation logic.
@@ -104,10 +154,30 @@ func (p *PolicyEvaluator) Evaluate(
 	dp := p.cfg.DeterministicPolicy
 
 	compositeRisk := math.Max(capacityScore, runtimeScore)
-	workloadCap, featureCap, poolSafety := p.resolveWorkloadSurface(state)
+	featureCap, capRules := p.explainFeatureSpotCap(state)
+	poolSafety := resolvePoolSafetyVector(state.PoolSafety)
+	workloadCap := clamp01(math.Min(featureCap, poolSafety.SafeMaxSpotRatio))
 	effectiveCap := clampRange(workloadCap, p.cfg.MinSpotRatio, p.cfg.MaxSpotRatio)
 
 	isOOD, oodReasons := detectOOD(state, dp.FeatureBuckets)
+	oodConservative := isOOD && strings.EqualFold(dp.OODMode, "conservative")
+	gate := EconomicGate{
+		MaxRisk:         dp.MediumRiskThreshold,
+		MinSavingsRatio: dp.MinSavingsRatioForIncrease,
+		MaxPaybackHours: dp.MaxPaybackHoursForIncrease,
+	}
+	if oodConservative {
+		gate.MaxRisk = dp.OODMaxRiskForIncrease
+		gate.MinSavingsRatio = dp.OODMinSavingsRatioForIncrease
+		gate.MaxPaybackHours = dp.OODMaxPaybackHoursForIncrease
+	}
+	gate.SavingsRatio, gate.PaybackHours, gate.Passed = evaluateEconomicGate(state, compositeRisk, gate.MaxRisk, gate.MinSavingsRatio, gate.MaxPaybackHours)
+
+	bindingCap := bindingCapSource(capRules, featureCap, poolSafety, workloadCap, p.cfg.MinSpotRatio, p.cfg.MaxSpotRatio)
+	for i := range capRules {
+		capRules[i].Binding = capRules[i].Source == bindingCap
+	}
+
 	decision := deterministicDecision{
 		CompositeRisk: compositeRisk,
 		FeatureCap:    featureCap,
@@ -116,11 +186,16 @@ func (p *PolicyEvaluator) Evaluate(
 		PoolSafety:    poolSafety,
 		IsOOD:         isOOD,
 		OODReasons:    oodReasons,
+		RiskBand:      RiskBandLow,
+		CapRules:      capRules,
+		BindingCap:    bindingCap,
+		Economics:     gate,
 	}
 
 	// 1. Emergency: composite risk or runtime score exceeds emergency thresholds
 	if compositeRisk >= dp.EmergencyRiskThreshold || runtimeScore >= dp.RuntimeEmergencyThreshold {
 		decision.Reason = "emergency_risk"
+		decision.RiskBand = RiskBandEmergency
 		decision.ResponseMode = ResponseModeEmergencyExit
 		decision.Urgency = PolicyUrgencyCritical
 		return inference.ActionEmergencyExit, decision
@@ -129,6 +204,7 @@ func (p *PolicyEvaluator) Evaluate(
 	// 2. High risk
 	if compositeRisk >= dp.HighRiskThreshold {
 		decision.Reason = "high_risk"
+		decision.RiskBand = RiskBandHigh
 		decision.ResponseMode = ResponseModeReduceSpotFast
 		decision.Urgency = PolicyUrgencyHigh
 		return inference.ActionDecrease30, decision
@@ -137,6 +213,7 @@ func (p *PolicyEvaluator) Evaluate(
 	// 3. Medium risk
 	if compositeRisk >= dp.MediumRiskThreshold {
 		decision.Reason = "medium_risk"
+		decision.RiskBand = RiskBandMedium
 		decision.ResponseMode = ResponseModeReduceSpotGradual
 		decision.Urgency = PolicyUrgencyMedium
 		return inference.ActionDecrease10, decision
@@ -171,8 +248,9 @@ func (p *PolicyEvaluator) Evaluate(
 	}
 
 	// 6. Out-of-distribution: be conservative
-	if isOOD && strings.EqualFold(dp.OODMode, "conservative") {
-		if canIncreaseSpot(state, compositeRisk, dp.OODMaxRiskForIncrease, dp.OODMinSavingsRatioForIncrease, dp.OODMaxPaybackHoursForIncrease) {
+	decision.Economics.Evaluated = true
+	if oodConservative {
+		if gate.Passed {
 			decision.Reason = "ood_conservative_increase10"
 			decision.ResponseMode = ResponseModeAllowGrowth
 			decision.Urgency = PolicyUrgencyLow
@@ -185,7 +263,7 @@ func (p *PolicyEvaluator) Evaluate(
 	}
 
 	// 7. In-distribution: economic analysis for increase
-	if canIncreaseSpot(state, compositeRisk, dp.MediumRiskThreshold, dp.MinSavingsRatioForIncrease, dp.MaxPaybackHoursForIncrease) {
+	if gate.Passed {
 		decision.ResponseMode = ResponseModeAllowGrowth
 		decision.Urgency = PolicyUrgencyLow
 		if effectiveCap-state.CurrentSpotRatio >= 0.25 {
@@ -220,16 +298,60 @@ func (p *PolicyEvaluator) computeWorkloadSpotCap(state inference.NodeState) floa
 // computeFeatureSpotCap derives the feature-rule cap from the configured
 // severity and economics surfaces before pool-safety tightening is applied.
 func (p *PolicyEvaluator) computeFeatureSpotCap(state inference.NodeState) float64 {
-	cap := 1.0
+	cap, _ := p.explainFeatureSpotCap(state)
+	return cap
+}
+
+// explainFeatureSpotCap computes the feature-rule cap and reports which rule
+// from each set matched.
+func (p *PolicyEvaluator) explainFeatureSpotCap(state inference.NodeState) (float64, []CapRuleEvaluation) {
+	sets := []struct {
+		source string
+		value  float64
+		rules  []config.SpotRatioCapRule
+	}{
+		{CapSourcePriority, state.PriorityScore, p.PriorityCapRules},
+		{CapSourceOutagePenalty, state.OutagePenaltyHours, p.OutageCapRules},
+		{CapSourceStartupTime, state.PodStartupTime, p.StartupCapRules},
+		{CapSourceMigrationCost, state.MigrationCost, p.MigrationCapRules},
+		{CapSourceUtilization, state.ClusterUtilization, p.UtilizationCapRules},
+	}
 
 	// Feature-based caps (each independently constrains)
-	cap = applyCapRules(state.PriorityScore, cap, p.PriorityCapRules)
-	cap = applyCapRules(state.OutagePenaltyHours, cap, p.OutageCapRules)
-	cap = applyCapRules(state.PodStartupTime, cap, p.StartupCapRules)
-	cap = applyCapRules(state.MigrationCost, cap, p.MigrationCapRules)
-	cap = applyCapRules(state.ClusterUtilization, cap, p.UtilizationCapRules)
+	cap := 1.0
+	evals := make([]CapRuleEvaluation, 0, len(sets))
+	for _, set := range sets {
+		eval := CapRuleEvaluation{Source: set.source, Value: set.value}
+		if rule, ok := matchCapRule(set.value, set.rules); ok {
+			eval.Matched = &rule
+			cap = math.Min(cap, rule.MaxSpotRatio)
+		}
+		evals = append(evals, eval)
+	}
+	return clamp01(cap), evals
+}
 
-	return clamp01(cap)
+// bindingCapSource names the constraint that set the effective spot cap.
+// Ties go to the earliest rule set in evaluation order.
+func bindingCapSource(rules []CapRuleEvaluation, featureCap float64, poolSafety config.PoolSafetyVector, workloadCap, minRatio, maxRatio float64) string {
+	if workloadCap < minRatio {
+		return CapSourceMinSpotRatio
+	}
+	if workloadCap > maxRatio {
+		return CapSourceMaxSpotRatio
+	}
+	if poolSafety.SafeMaxSpotRatio < featureCap {
+		if poolSafety.RecoveryBudgetCount > 0 && poolSafety.RecoveryBudgetMaxSpotRatio <= poolSafety.SafeMaxSpotRatio+1e-9 {
+			return CapSourceRecoveryBudget
+		}
+		return CapSourcePoolSafety
+	}
+	for _, rule := range rules {
+		if rule.Matched != nil && clamp01(rule.Matched.MaxSpotRatio) <= featureCap+1e-9 {
+			return rule.Source
+		}
+	}
+	return CapSourceNone
 }
 
 func resolvePoolSafetyVector(v config.PoolSafetyVector) config.PoolSafetyVector {
@@ -259,28 +381,50 @@ func canIncreaseSpot(
 	minSavingsRatio float64,
 	maxPaybackHours float64,
 ) bool {
+	_, _, ok := evaluateEconomicGate(state, compositeRisk, maxRisk, minSavingsRatio, maxPaybackHours)
+	return ok
+}
+
+// evaluateEconomicGate returns the savings ratio and payback hours alongside
+// the canIncreaseSpot outcome.
+func evaluateEconomicGate(
+	state inference.NodeState,
+	compositeRisk float64,
+	maxRisk float64,
+	minSavingsRatio float64,
+	maxPaybackHours float64,
+) (float64, float64, bool) {
 	if state.OnDemandPrice <= 0 {
-		return false
+		return 0, 0, false
 	}
 	delta := math.Max(0.0, state.OnDemandPrice-state.SpotPrice)
 	if delta <= 0 {
-		return false
+		return 0, 0, false
 	}
 	savingsRatio := delta / math.Max(state.OnDemandPrice, 1e-6)
 	paybackHours := state.MigrationCost / math.Max(delta, 1e-6)
 
-	return compositeRisk <= maxRisk &&
+	ok := compositeRisk <= maxRisk &&
 		savingsRatio >= minSavingsRatio &&
 		paybackHours <= maxPaybackHours
+	return savingsRatio, paybackHours, ok
 }
 
 func applyCapRules(value float64, currentCap float64, rules []config.SpotRatioCapRule) float64 {
+	if rule, ok := matchCapRule(value, rules); ok {
+		return math.Min(currentCap, rule.MaxSpotRatio)
+	}
+	return currentCap
+}
+
+// matchCapRule returns the first rule whose threshold the value meets.
+func matchCapRule(value float64, rules []config.SpotRatioCapRule) (config.SpotRatioCapRule, bool) {
 	for _, rule := range rules {
 		if value >= rule.Threshold {
-			return math.Min(currentCap, rule.MaxSpotRatio)
+			return rule, true
 		}
 	}
-	return currentCap
+	return config.SpotRatioCapRule{}, false
 }
 
 func detectOOD(state inference.NodeState, buckets config.FeatureBuckets) (bool, []string) {
@@ -251,3 +251,79 @@ func TestEvaluateDeterministicPolicy_UsesPoolSafetyVectorSurface(t *testing.T) {
 		t.Fatalf("expected safe max spot ratio 0.10, got %.2f", decision.PoolSafety.SafeMaxSpotRatio)
 	}
 }
+
+func TestEvaluateDeterministicPolicy_ExplainsBindingCapRule(t *testing.T) {
+	state := baseDeterministicState()
+
+	cfg := deterministicRuntimeConfig()
+	cfg.DeterministicPolicy.PriorityCapRules = []config.SpotRatioCapRule{
+		{Threshold: 0.10, MaxSpotRatio: 0.60},
+	}
+	cfg.DeterministicPolicy.StartupTimeCapRules = []config.SpotRatioCapRule{
+		{Threshold: 20, MaxSpotRatio: 0.40},
+	}
+
+	_, decision := evaluateDeterministicPolicy(state, 0.10, 0.10, cfg)
+	if decision.RiskBand != RiskBandLow {
+		t.Fatalf("risk band=%q, want %q", decision.RiskBand, RiskBandLow)
+	}
+	if decision.BindingCap != CapSourceStartupTime {
+		t.Fatalf("binding cap=%q, want %q", decision.BindingCap, CapSourceStartupTime)
+	}
+	for _, rule := range decision.CapRules {
+		switch rule.Source {
+		case CapSourceStartupTime:
+			if !rule.Binding || rule.Matched == nil || rule.Matched.MaxSpotRatio != 0.40 {
+				t.Fatalf("startup rule=%+v, want binding match at 0.40", rule)
+			}
+		case CapSourcePriority:
+			if rule.Binding || rule.Matched == nil {
+				t.Fatalf("priority rule=%+v, want non-binding match", rule)
+			}
+		}
+	}
+	if !decision.Economics.Evaluated || !decision.Economics.Passed {
+		t.Fatalf("economics=%+v, want evaluated and passed", decision.Economics)
+	}
+	if decision.Economics.SavingsRatio != 0.5 {
+		t.Fatalf("savings ratio=%v, want 0.5", decision.Economics.SavingsRatio)
+	}
+}
+
+func TestEvaluateDeterministicPolicy_ExplainsRiskBandAndPoolSafetyCap(t *testing.T) {
+	state := baseDeterministicState()
+	state.PoolSafety = config.NormalizePoolSafetyVector(config.PoolSafetyVector{
+		SafeMaxSpotRatio:         0.30,
+		MinPDBSlackIfOneNodeLost: 1,
+	})
+
+	_, decision := evaluateDeterministicPolicy(state, 0.65, 0.20, deterministicRuntimeConfig())
+	if decision.RiskBand != RiskBandHigh {
+		t.Fatalf("risk band=%q, want %q", decision.RiskBand, RiskBandHigh)
+	}
+	if decision.BindingCap != CapSourcePoolSafety {
+		t.Fatalf("binding cap=%q, want %q", decision.BindingCap, CapSourcePoolSafety)
+	}
+	if decision.Economics.Evaluated {
+		t.Fatal("economic gate should not be marked evaluated when a risk band decides first")
+	}
+}
+
+func TestEvaluateDeterministicPolicy_RecoveryBudgetBindsCap(t *testing.T) {
+	state := baseDeterministicState()
+	state.PoolSafety = config.NormalizePoolSafetyVector(config.PoolSafetyVector{
+		SafeMaxSpotRatio:           0.40,
+		MinPDBSlackIfOneNodeLost:   1,
+		RecoveryBudgetCount:        2,
+		RecoveryBudgetMaxSpotRatio: 0.40,
+	})
+	state.CurrentSpotRatio = 0.50
+
+	action, decision := evaluateDeterministicPolicy(state, 0.10, 0.10, deterministicRuntimeConfig())
+	if decision.BindingCap != CapSourceRecoveryBudget {
+		t.Fatalf("binding cap=%q, want %q", decision.BindingCap, CapSourceRecoveryBudget)
+	}
+	if action != inference.ActionDecrease10 || decision.Reason != "pool_safety_reduce_gradual" {
+		t.Fatalf("action=%v reason=%q, want gradual reduction to the budget cap", action, decision.Reason)
+	}
+}
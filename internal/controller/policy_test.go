@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/config"
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+	svmetrics "github.com/softcane/spot-vortex-agent/internal/metrics"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// holdPolicy always holds and reports the pool's max spot ratio as its cap.
+type holdPolicy struct{}
+
+func (holdPolicy) Name() string { return "test-hold" }
+
+func (holdPolicy) Decide(_ context.Context, in PolicyInput) (PolicyDecision, error) {
+	return PolicyDecision{Action: inference.ActionHold, Cap: in.Config.MaxSpotRatio, Reason: "always_hold", Confidence: 0.9}, nil
+}
+
+var registerHoldPolicy sync.Once
+
+func registerTestHoldPolicy(t *testing.T) {
+	t.Helper()
+	registerHoldPolicy.Do(func() {
+		if err := RegisterPolicy(holdPolicy{}); err != nil {
+			t.Fatalf("RegisterPolicy: %v", err)
+		}
+	})
+}
+
+func TestRegisterPolicy(t *testing.T) {
+	registerTestHoldPolicy(t)
+
+	if err := RegisterPolicy(holdPolicy{}); err == nil {
+		t.Fatal("expected duplicate registration to fail")
+	}
+	if err := RegisterPolicy(nil); err == nil {
+		t.Fatal("expected nil policy to be rejected")
+	}
+	if p, ok := LookupPolicy(" Test-Hold "); !ok || p.Name() != "test-hold" {
+		t.Fatalf("LookupPolicy = %v, %v; want test-hold", p, ok)
+	}
+	names := RegisteredPolicies()
+	for _, want := range []string{config.PolicyModeDeterministic, config.PolicyModeRL, "test-hold"} {
+		if !slices.Contains(names, want) {
+			t.Fatalf("RegisteredPolicies() = %v, missing %q", names, want)
+		}
+	}
+	if !config.IsPolicyMode("test-hold") {
+		t.Fatal("registered policy must be a valid policy_mode")
+	}
+}
+
+func TestRunInference_CustomActivePolicyWithDeterministicShadow(t *testing.T) {
+	registerTestHoldPolicy(t)
+
+	k8sClient := k8sfake.NewSimpleClientset()
+	createNode(k8sClient, "node-1", "spot", "us-east-1a", "m5.large")
+	ctrl, err := New(Config{
+		Cloud:               &MockCloudProvider{DryRun: true},
+		PriceProvider:       fixedPriceProvider(),
+		K8sClient:           k8sClient,
+		Inference:           &inference.InferenceEngine{},
+		PrometheusClient:    &svmetrics.Client{},
+		Logger:              slog.Default(),
+		RiskThreshold:       0.95,
+		MaxDrainRatio:       0.2,
+		ReconcileInterval:   10 * time.Second,
+		ConfidenceThreshold: 0.5,
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctrl.runtimeConfigLoader = func() *config.RuntimeConfig {
+		cfg := config.DefaultRuntimeConfig()
+		cfg.PolicyMode = "test-hold"
+		cfg.ShadowPolicies = []string{config.PolicyModeDeterministic}
+		cfg.StepMinutes = 30
+		return cfg
+	}
+	ctrl.predictDetailedOverride = func(ctx context.Context, nodeID string, state inference.NodeState, riskMultiplier float64) (inference.Action, float32, float32, float32, error) {
+		// High capacity risk: the deterministic shadow would move toward On-Demand.
+		return inference.ActionIncrease30, 0.70, 0.10, 0.40, nil
+	}
+
+	beforeCustom := decisionSourceTotal("test-hold")
+	beforeShadow := counterVecValue(t, svmetrics.ShadowActionRecommended, config.PolicyModeDeterministic, "DECREASE_30")
+	beforeRLShadow := counterVecValue(t, svmetrics.ShadowActionRecommended, config.PolicyModeRL, "INCREASE_30")
+
+	assessments, err := ctrl.runInference(context.Background(), []svmetrics.NodeMetrics{{
+		NodeID:             "node-1",
+		InstanceType:       "m5.large",
+		Zone:               "us-east-1a",
+		IsSpot:             true,
+		CPUUsagePercent:    35,
+		MemoryUsagePercent: 50,
+	}})
+	if err != nil {
+		t.Fatalf("runInference failed: %v", err)
+	}
+	if len(assessments) != 1 {
+		t.Fatalf("expected 1 assessment, got %d", len(assessments))
+	}
+	got := assessments[0]
+	if got.Action != inference.ActionHold || got.Confidence != 0.9 {
+		t.Fatalf("active action=%s confidence=%v, want HOLD from the custom policy", inference.ActionToString(got.Action), got.Confidence)
+	}
+	if !got.HasShadow || got.ShadowAction != inference.ActionDecrease30 {
+		t.Fatalf("shadow=%v action=%s, want deterministic DECREASE_30", got.HasShadow, inference.ActionToString(got.ShadowAction))
+	}
+	if delta := decisionSourceTotal("test-hold") - beforeCustom; delta != 1 {
+		t.Fatalf("custom decision_source_total delta=%v, want 1", delta)
+	}
+	if delta := counterVecValue(t, svmetrics.ShadowActionRecommended, config.PolicyModeDeterministic, "DECREASE_30") - beforeShadow; delta != 1 {
+		t.Fatalf("deterministic shadow recommended delta=%v, want 1", delta)
+	}
+	if delta := counterVecValue(t, svmetrics.ShadowActionRecommended, config.PolicyModeRL, "INCREASE_30") - beforeRLShadow; delta != 0 {
+		t.Fatalf("rl shadow recommended delta=%v, want 0 when rl is not a shadow", delta)
+	}
+}
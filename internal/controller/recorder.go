@@ -0,0 +1,391 @@
+package controller
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/cloudapi"
+	"github.com/softcane/spot-vortex-agent/internal/collector"
+	"github.com/softcane/spot-vortex-agent/internal/config"
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+)
+
+const (
+	traceFilePrefix = "spotvortex-trace-"
+	traceFileSuffix = ".jsonl.gz"
+)
+
+// TickRecord is the exact set of inputs and outputs of one reconcile tick.
+// Nodes, Pods and PDBs are the cluster objects the tick saw, so the record
+// can be replayed by RunBacktest (see BacktestTraceFromRecords).
+type TickRecord struct {
+	Timestamp     time.Time                      `json:"timestamp"`
+	NodeMetrics   []metrics.NodeMetrics          `json:"node_metrics"`
+	LocalMetrics  *collector.LocalMetrics        `json:"local_metrics,omitempty"`
+	SpotPrices    []cloudapi.SpotPriceData       `json:"spot_prices"`
+	Nodes         []corev1.Node                  `json:"nodes"`
+	Pods          []corev1.Pod                   `json:"pods"`
+	PDBs          []policyv1.PodDisruptionBudget `json:"pdbs"`
+	RuntimeConfig *config.RuntimeConfig          `json:"runtime_config"`
+	Assessments   []NodeAssessment               `json:"assessments"`
+}
+
+// TraceRecorderConfig configures a TraceRecorder.
+type TraceRecorderConfig struct {
+	Dir           string
+	MaxFileBytes  int64
+	MaxTotalBytes int64
+	Logger        *slog.Logger
+}
+
+// TraceRecorder appends TickRecords to gzip JSON-lines files, rotating when
+// the active file reaches MaxFileBytes and pruning the oldest files once the
+// directory exceeds MaxTotalBytes. Each record is flushed so a crashed agent
+// still leaves every completed tick readable.
+type TraceRecorder struct {
+	mu     sync.Mutex
+	cfg    TraceRecorderConfig
+	logger *slog.Logger
+
+	file    *os.File
+	gz      *gzip.Writer
+	written int64
+	seq     int
+}
+
+// NewTraceRecorder creates the trace directory and returns a recorder.
+func NewTraceRecorder(cfg TraceRecorderConfig) (*TraceRecorder, error) {
+	if strings.TrimSpace(cfg.Dir) == "" {
+		return nil, fmt.Errorf("trace recorder dir is required")
+	}
+	if cfg.MaxFileBytes <= 0 {
+		return nil, fmt.Errorf("trace recorder max file size must be > 0")
+	}
+	if cfg.MaxTotalBytes < cfg.MaxFileBytes {
+		return nil, fmt.Errorf("trace recorder total cap must be >= max file size")
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create trace dir %s: %w", cfg.Dir, err)
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &TraceRecorder{cfg: cfg, logger: logger}, nil
+}
+
+// Record appends one tick to the active trace file.
+func (r *TraceRecorder) Record(rec TickRecord) error {
+	if r == nil {
+		return nil
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode tick record: %w", err)
+	}
+	line = append(line, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file != nil && r.written >= r.cfg.MaxFileBytes {
+		if err := r.closeLocked(); err != nil {
+			return err
+		}
+	}
+	if r.file == nil {
+		if err := r.openLocked(rec.Timestamp); err != nil {
+			return err
+		}
+	}
+
+	if _, err := r.gz.Write(line); err != nil {
+		return fmt.Errorf("write tick record: %w", err)
+	}
+	if err := r.gz.Flush(); err != nil {
+		return fmt.Errorf("flush tick record: %w", err)
+	}
+	info, err := r.file.Stat()
+	if err != nil {
+		return fmt.Errorf("stat trace file: %w", err)
+	}
+	r.written = info.Size()
+	return nil
+}
+
+// Close finalizes the active trace file.
+func (r *TraceRecorder) Close() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closeLocked()
+}
+
+func (r *TraceRecorder) openLocked(ts time.Time) error {
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	r.seq++
+	name := fmt.Sprintf("%s%s-%04d%s", traceFilePrefix, ts.UTC().Format("20060102T150405Z"), r.seq, traceFileSuffix)
+	f, err := os.OpenFile(filepath.Join(r.cfg.Dir, name), os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("open trace file: %w", err)
+	}
+	r.file = f
+	r.gz = gzip.NewWriter(f)
+	r.written = 0
+	r.pruneLocked()
+	return nil
+}
+
+func (r *TraceRecorder) closeLocked() error {
+	if r.file == nil {
+		return nil
+	}
+	gzErr := r.gz.Close()
+	fileErr := r.file.Close()
+	r.file = nil
+	r.gz = nil
+	if err := errors.Join(gzErr, fileErr); err != nil {
+		return fmt.Errorf("close trace file: %w", err)
+	}
+	return nil
+}
+
+// pruneLocked deletes the oldest closed trace files until the directory fits
+// within MaxTotalBytes. The active file is never removed.
+func (r *TraceRecorder) pruneLocked() {
+	files, err := ListTraceFiles(r.cfg.Dir)
+	if err != nil {
+		r.logger.Warn("failed to list trace files for pruning", "dir", r.cfg.Dir, "error", err)
+		return
+	}
+	active := ""
+	if r.file != nil {
+		active = r.file.Name()
+	}
+
+	sizes := make([]int64, len(files))
+	var total int64
+	for i, path := range files {
+		if info, err := os.Stat(path); err == nil {
+			sizes[i] = info.Size()
+			total += sizes[i]
+		}
+	}
+	// Reserve room for the active file to grow to its cap.
+	budget := r.cfg.MaxTotalBytes - r.cfg.MaxFileBytes
+	for i, path := range files {
+		if total <= budget {
+			return
+		}
+		if path == active {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			r.logger.Warn("failed to prune trace file", "path", path, "error", err)
+			continue
+		}
+		total -= sizes[i]
+	}
+}
+
+// ListTraceFiles returns trace files in dir, oldest first.
+func ListTraceFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, traceFilePrefix) || !strings.HasSuffix(name, traceFileSuffix) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ReadTickRecords decodes every complete record in a trace file. A file that
+// was not closed cleanly (agent crash) is read up to its last flushed record.
+func ReadTickRecords(path string) ([]TickRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open trace file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("open gzip stream %s: %w", path, err)
+	}
+	defer gz.Close()
+
+	var records []TickRecord
+	reader := bufio.NewReader(gz)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			var rec TickRecord
+			if decodeErr := json.Unmarshal(line, &rec); decodeErr != nil {
+				return records, fmt.Errorf("decode tick record %d in %s: %w", len(records), path, decodeErr)
+			}
+			records = append(records, rec)
+		}
+		if err == nil {
+			continue
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return records, nil
+		}
+		return records, fmt.Errorf("read trace file %s: %w", path, err)
+	}
+}
+
+// recordingPriceProvider captures every spot price response served during a
+// tick so the recorder can persist the exact market data inference saw.
+type recordingPriceProvider struct {
+	cloudapi.PriceProvider
+
+	mu       sync.Mutex
+	observed map[string]cloudapi.SpotPriceData
+}
+
+func newRecordingPriceProvider(inner cloudapi.PriceProvider) *recordingPriceProvider {
+	return &recordingPriceProvider{
+		PriceProvider: inner,
+		observed:      make(map[string]cloudapi.SpotPriceData),
+	}
+}
+
+func (p *recordingPriceProvider) GetSpotPrice(ctx context.Context, instanceType, zone string) (cloudapi.SpotPriceData, error) {
+	data, err := p.PriceProvider.GetSpotPrice(ctx, instanceType, zone)
+	if err != nil {
+		return data, err
+	}
+	recorded := data
+	recorded.PriceHistory = append([]float64(nil), data.PriceHistory...)
+	if recorded.InstanceType == "" {
+		recorded.InstanceType = instanceType
+	}
+	if recorded.Zone == "" {
+		recorded.Zone = zone
+	}
+	p.mu.Lock()
+	p.observed[instanceType+":"+zone] = recorded
+	p.mu.Unlock()
+	return data, nil
+}
+
+// drain returns the prices observed since the last call, sorted by key.
+func (p *recordingPriceProvider) drain() []cloudapi.SpotPriceData {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	keys := make([]string, 0, len(p.observed))
+	for key := range p.observed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	out := make([]cloudapi.SpotPriceData, 0, len(keys))
+	for _, key := range keys {
+		out = append(out, p.observed[key])
+	}
+	p.observed = make(map[string]cloudapi.SpotPriceData)
+	return out
+}
+
+// recordTick persists the tick's inputs and assessments. Recording failures
+// are logged and never fail the reconcile loop.
+func (c *Controller) recordTick(ctx context.Context, nodeMetrics []metrics.NodeMetrics, local *collector.LocalMetrics, assessments []NodeAssessment) {
+	if c.recorder == nil {
+		return
+	}
+	var prices []cloudapi.SpotPriceData
+	if c.recordedPrices != nil {
+		prices = c.recordedPrices.drain()
+	}
+	rec := TickRecord{
+		Timestamp:     c.clock(),
+		NodeMetrics:   nodeMetrics,
+		LocalMetrics:  local,
+		SpotPrices:    prices,
+		RuntimeConfig: c.lastTickRuntimeConfig(),
+		Assessments:   assessments,
+	}
+	if err := c.snapshotCluster(ctx, &rec); err != nil {
+		c.logger.Warn("failed to snapshot cluster objects for tick record", "error", err)
+	}
+	if err := c.recorder.Record(rec); err != nil {
+		c.logger.Warn("failed to record reconcile tick", "error", err)
+	}
+}
+
+// snapshotCluster copies the nodes, pods and PDBs the tick ran against into
+// rec. Managed fields are dropped; nothing reads them.
+func (c *Controller) snapshotCluster(ctx context.Context, rec *TickRecord) error {
+	nodes, err := c.listNodes(ctx)
+	if err != nil {
+		return fmt.Errorf("list nodes: %w", err)
+	}
+	rec.Nodes = make([]corev1.Node, 0, len(nodes))
+	for _, n := range nodes {
+		node := *n
+		node.ManagedFields = nil
+		rec.Nodes = append(rec.Nodes, node)
+	}
+
+	pods, err := listPods(ctx, c.k8s, c.cache)
+	if err != nil {
+		return fmt.Errorf("list pods: %w", err)
+	}
+	rec.Pods = make([]corev1.Pod, 0, len(pods))
+	for _, p := range pods {
+		pod := *p
+		pod.ManagedFields = nil
+		rec.Pods = append(rec.Pods, pod)
+	}
+
+	pdbs, err := pdbsInNamespace(ctx, c.k8s, c.cache, "")
+	if err != nil {
+		return fmt.Errorf("list pdbs: %w", err)
+	}
+	rec.PDBs = make([]policyv1.PodDisruptionBudget, 0, len(pdbs))
+	for _, p := range pdbs {
+		pdb := *p
+		pdb.ManagedFields = nil
+		rec.PDBs = append(rec.PDBs, pdb)
+	}
+	return nil
+}
+
+func (c *Controller) setTickRuntimeConfig(cfg *config.RuntimeConfig) {
+	c.historyLock.Lock()
+	c.tickRuntimeConfig = cfg
+	c.historyLock.Unlock()
+}
+
+// lastTickRuntimeConfig returns the runtime config the latest tick's
+// inference ran with.
+func (c *Controller) lastTickRuntimeConfig() *config.RuntimeConfig {
+	c.historyLock.Lock()
+	defer c.historyLock.Unlock()
+	return c.tickRuntimeConfig
+}
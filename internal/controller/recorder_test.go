@@ -0,0 +1,200 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/config"
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+	svmetrics "github.com/softcane/spot-vortex-agent/internal/metrics"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTraceRecorder_RotatesAndPrunesWithinCap(t *testing.T) {
+	dir := t.TempDir()
+	rec, err := NewTraceRecorder(TraceRecorderConfig{
+		Dir:           dir,
+		MaxFileBytes:  256,
+		MaxTotalBytes: 1024,
+	})
+	if err != nil {
+		t.Fatalf("NewTraceRecorder failed: %v", err)
+	}
+
+	start := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 200; i++ {
+		tick := TickRecord{
+			Timestamp: start.Add(time.Duration(i) * 10 * time.Minute),
+			NodeMetrics: []svmetrics.NodeMetrics{
+				{NodeID: fmt.Sprintf("node-%d", i), CPUUsagePercent: float64(i), MemoryUsagePercent: float64(200 - i)},
+			},
+		}
+		if err := rec.Record(tick); err != nil {
+			t.Fatalf("Record(%d) failed: %v", i, err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	files, err := ListTraceFiles(dir)
+	if err != nil {
+		t.Fatalf("ListTraceFiles failed: %v", err)
+	}
+	if len(files) < 2 {
+		t.Fatalf("expected rotation to produce multiple files, got %d", len(files))
+	}
+	var total int64
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("stat %s: %v", path, err)
+		}
+		total += info.Size()
+	}
+	if total > 1024 {
+		t.Fatalf("trace dir size=%d, want <= 1024", total)
+	}
+
+	// The newest file must still hold the most recent tick.
+	records, err := ReadTickRecords(files[len(files)-1])
+	if err != nil {
+		t.Fatalf("ReadTickRecords failed: %v", err)
+	}
+	if len(records) == 0 {
+		t.Fatal("expected records in newest trace file")
+	}
+	last := records[len(records)-1]
+	if !last.Timestamp.Equal(start.Add(199 * 10 * time.Minute)) {
+		t.Fatalf("last record timestamp=%v, want final tick", last.Timestamp)
+	}
+}
+
+func TestTraceRecorder_ReadsUnclosedFile(t *testing.T) {
+	dir := t.TempDir()
+	rec, err := NewTraceRecorder(TraceRecorderConfig{Dir: dir, MaxFileBytes: 1 << 20, MaxTotalBytes: 1 << 20})
+	if err != nil {
+		t.Fatalf("NewTraceRecorder failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := rec.Record(TickRecord{Timestamp: time.Unix(int64(i), 0).UTC()}); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	// Simulate a crash: read without closing the gzip stream.
+	files, err := ListTraceFiles(dir)
+	if err != nil || len(files) != 1 {
+		t.Fatalf("ListTraceFiles=%v, %v; want one file", files, err)
+	}
+	records, err := ReadTickRecords(files[0])
+	if err != nil {
+		t.Fatalf("ReadTickRecords failed: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("records=%d, want 3 flushed ticks", len(records))
+	}
+}
+
+func TestNewTraceRecorder_RejectsInvalidConfig(t *testing.T) {
+	if _, err := NewTraceRecorder(TraceRecorderConfig{MaxFileBytes: 1, MaxTotalBytes: 1}); err == nil {
+		t.Fatal("expected missing dir to be rejected")
+	}
+	if _, err := NewTraceRecorder(TraceRecorderConfig{Dir: t.TempDir(), MaxFileBytes: 10, MaxTotalBytes: 5}); err == nil {
+		t.Fatal("expected total cap below file cap to be rejected")
+	}
+}
+
+func TestReconcile_RecordsTickInputsAndAssessments(t *testing.T) {
+	dir := t.TempDir()
+	rec, err := NewTraceRecorder(TraceRecorderConfig{Dir: dir, MaxFileBytes: 1 << 20, MaxTotalBytes: 4 << 20})
+	if err != nil {
+		t.Fatalf("NewTraceRecorder failed: %v", err)
+	}
+
+	k8sClient := k8sfake.NewSimpleClientset()
+	createNode(k8sClient, "node-1", "spot", "us-east-1a", "m5.large")
+
+	ctrl, err := New(Config{
+		Cloud:               &MockCloudProvider{DryRun: true},
+		PriceProvider:       fixedPriceProvider(),
+		K8sClient:           k8sClient,
+		Inference:           &inference.InferenceEngine{},
+		PrometheusClient:    &svmetrics.Client{},
+		Logger:              slog.Default(),
+		RiskThreshold:       0.95,
+		MaxDrainRatio:       0.2,
+		ReconcileInterval:   10 * time.Second,
+		ConfidenceThreshold: 0.5,
+		Recorder:            rec,
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	// Later lookups in the same tick see a changed config; the record must
+	// keep the one inference ran with.
+	loads := 0
+	ctrl.runtimeConfigLoader = func() *config.RuntimeConfig {
+		loads++
+		cfg := deterministicRuntimeConfigShadowTest()
+		if loads > 1 {
+			cfg.StepMinutes = 99
+		}
+		return cfg
+	}
+	ctrl.nodeMetricsSource = func(ctx context.Context) ([]svmetrics.NodeMetrics, error) {
+		return []svmetrics.NodeMetrics{{
+			NodeID:             "node-1",
+			InstanceType:       "m5.large",
+			Zone:               "us-east-1a",
+			IsSpot:             true,
+			CPUUsagePercent:    35,
+			MemoryUsagePercent: 50,
+		}}, nil
+	}
+	ctrl.predictDetailedOverride = func(ctx context.Context, nodeID string, state inference.NodeState, riskMultiplier float64) (inference.Action, float32, float32, float32, error) {
+		return inference.ActionHold, 0.70, 0.10, 0.90, nil
+	}
+
+	if err := ctrl.Reconcile(context.Background()); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	files, err := ListTraceFiles(dir)
+	if err != nil || len(files) != 1 {
+		t.Fatalf("ListTraceFiles=%v, %v; want one file", files, err)
+	}
+	records, err := ReadTickRecords(files[0])
+	if err != nil {
+		t.Fatalf("ReadTickRecords failed: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("records=%d, want 1", len(records))
+	}
+	got := records[0]
+	if len(got.NodeMetrics) != 1 || got.NodeMetrics[0].NodeID != "node-1" {
+		t.Fatalf("node metrics=%+v, want node-1", got.NodeMetrics)
+	}
+	if got.LocalMetrics == nil {
+		t.Fatal("expected local workload metrics to be recorded")
+	}
+	if len(got.SpotPrices) != 1 || got.SpotPrices[0].InstanceType != "m5.large" || got.SpotPrices[0].Zone != "us-east-1a" {
+		t.Fatalf("spot prices=%+v, want one m5.large/us-east-1a entry", got.SpotPrices)
+	}
+	if got.RuntimeConfig == nil || got.RuntimeConfig.PolicyMode != "deterministic" || got.RuntimeConfig.StepMinutes != 30 {
+		t.Fatalf("runtime config=%+v, want the deterministic config inference ran with", got.RuntimeConfig)
+	}
+	if len(got.Nodes) != 1 || got.Nodes[0].Name != "node-1" || got.Pods == nil || got.PDBs == nil {
+		t.Fatalf("cluster snapshot nodes=%d pods=%v pdbs=%v, want node-1 and empty pod/PDB lists", len(got.Nodes), got.Pods, got.PDBs)
+	}
+	if len(got.Assessments) != 1 || got.Assessments[0].Action != inference.ActionDecrease30 {
+		t.Fatalf("assessments=%+v, want one DECREASE_30", got.Assessments)
+	}
+}
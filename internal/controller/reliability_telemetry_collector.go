@@ -6,9 +6,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/softcane/spot-vortex-agent/internal/kubecache"
 	svmetrics "github.com/softcane/spot-vortex-agent/internal/metrics"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -16,14 +16,18 @@ import (
 // signals from the Kubernetes API using periodic polling.
 //
 // Signals currently implemented:
-// - node Ready -> NotReady transitions
-// - observed node deletions between polling cycles
-// - pod container restart count deltas
-// - pod pending -> running duration samples (recorded as both pending/recovery)
+//   - node Ready -> NotReady transitions
+//   - observed node deletions between polling cycles
+//   - pod container restart count deltas
+//   - pod pending -> running duration samples (recorded as both pending/recovery)
+//   - replica replacement durations: eviction or termination of a workload's
+//     pod to a replacement becoming Ready, kept per workload (see RestartP95)
 //
-// AWS interruption/rebalance signals are intentionally not faked here and stay zero.
+// AWS interruption/rebalance signals are counted by the interruption handler
+// (see interruption.go), not by this collector.
 type KubernetesReliabilityTelemetryCollector struct {
 	k8s    kubernetes.Interface
+	cache  *kubecache.Cache // Optional: read from informers when synced
 	logger *slog.Logger
 
 	mu sync.Mutex
@@ -34,6 +38,7 @@ type KubernetesReliabilityTelemetryCollector struct {
 	lastSeenNodes          map[string]struct{}
 	lastContainerRestarts  map[string]int32
 	lastPodLifecycleStatus map[string]podLifecycleStatus
+	restarts               *restartTracker
 }
 
 type podLifecycleStatus struct {
@@ -56,9 +61,34 @@ func NewKubernetesReliabilityTelemetryCollector(k8s kubernetes.Interface, logger
 		lastSeenNodes:          map[string]struct{}{},
 		lastContainerRestarts:  map[string]int32{},
 		lastPodLifecycleStatus: map[string]podLifecycleStatus{},
+		restarts:               newRestartTracker(),
 	}
 }
 
+// WithCache makes the collector read nodes and pods from the informer cache
+// once it has synced.
+func (c *KubernetesReliabilityTelemetryCollector) WithCache(cache *kubecache.Cache) *KubernetesReliabilityTelemetryCollector {
+	if c != nil {
+		c.cache = cache
+	}
+	return c
+}
+
+// RestartP95 returns the learned P95 replacement time for a workload, once
+// enough replacements have been observed. It implements
+// collector.RestartTimeSource.
+func (c *KubernetesReliabilityTelemetryCollector) RestartP95(namespace, kind, name string) (float64, bool) {
+	if c == nil {
+		return 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.restarts == nil {
+		return 0, false
+	}
+	return c.restarts.restartP95(workloadKey{namespace: namespace, kind: kind, name: name})
+}
+
 func (c *KubernetesReliabilityTelemetryCollector) CollectReliabilityTelemetry(ctx context.Context) (svmetrics.ReliabilityTelemetrySnapshot, error) {
 	if c == nil || c.k8s == nil {
 		return svmetrics.ReliabilityTelemetrySnapshot{}, nil
@@ -79,15 +109,14 @@ func (c *KubernetesReliabilityTelemetryCollector) CollectReliabilityTelemetry(ct
 }
 
 func (c *KubernetesReliabilityTelemetryCollector) collectNodeSignals(ctx context.Context, snapshot *svmetrics.ReliabilityTelemetrySnapshot) {
-	nodes, err := c.k8s.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	nodes, err := listNodes(ctx, c.k8s, c.cache)
 	if err != nil {
 		c.logger.Warn("reliability telemetry: failed to list nodes", "error", err)
 		return
 	}
 
-	currentSeen := make(map[string]struct{}, len(nodes.Items))
-	for i := range nodes.Items {
-		node := &nodes.Items[i]
+	currentSeen := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
 		nodeKey := nodeIdentity(node)
 		currentSeen[nodeKey] = struct{}{}
 
@@ -110,17 +139,20 @@ func (c *KubernetesReliabilityTelemetryCollector) collectNodeSignals(ctx context
 }
 
 func (c *KubernetesReliabilityTelemetryCollector) collectPodSignals(ctx context.Context, now time.Time, snapshot *svmetrics.ReliabilityTelemetrySnapshot) {
-	pods, err := c.k8s.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	pods, err := listPods(ctx, c.k8s, c.cache)
 	if err != nil {
 		c.logger.Warn("reliability telemetry: failed to list pods", "error", err)
 		return
 	}
 
-	currentPods := make(map[string]struct{}, len(pods.Items))
+	if c.restarts != nil {
+		snapshot.ReplacementReadyDurationsSeconds = c.restarts.observe(ctx, c.k8s, c.cache, pods, now)
+	}
+
+	currentPods := make(map[string]struct{}, len(pods))
 	currentContainers := map[string]struct{}{}
 
-	for i := range pods.Items {
-		pod := &pods.Items[i]
+	for _, pod := range pods {
 		podKey := podIdentity(pod)
 		currentPods[podKey] = struct{}{}
 
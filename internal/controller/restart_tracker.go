@@ -0,0 +1,298 @@
+package controller
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/collector"
+	"github.com/softcane/spot-vortex-agent/internal/kubecache"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// restartSampleWindow is how many recent replacements each workload's
+	// restart distribution keeps.
+	restartSampleWindow = 50
+	// minRestartSamples is how many replacements a workload needs before its
+	// learned P95 replaces the startup-latency proxy.
+	minRestartSamples = 5
+	// maxReplacementWait drops departures that were never replaced, such as
+	// scale-downs.
+	maxReplacementWait = 30 * time.Minute
+	// replacementCreationSlack tolerates clock skew between a departure and
+	// its replacement's creation timestamp.
+	replacementCreationSlack = 5 * time.Second
+)
+
+// workloadKey identifies a pod's top-level workload, as the collector
+// resolves it.
+type workloadKey struct {
+	namespace, kind, name string
+}
+
+// trackedPod is what the restart tracker remembers about a pod between polls.
+type trackedPod struct {
+	workload workloadKey
+	created  time.Time
+	lastSeen time.Time
+	ready    bool
+	departed bool
+}
+
+// restartTracker learns, per workload, how long a replica takes to come back:
+// from the moment one is evicted or terminated to the moment a replacement
+// created after it becomes Ready. Departures are matched to replacements
+// oldest first.
+type restartTracker struct {
+	pods       map[string]trackedPod
+	departures map[workloadKey][]time.Time
+	samples    map[workloadKey][]float64 // most recent last
+	rsOwners   map[string]*metav1.OwnerReference
+}
+
+func newRestartTracker() *restartTracker {
+	return &restartTracker{
+		pods:       make(map[string]trackedPod),
+		departures: make(map[workloadKey][]time.Time),
+		samples:    make(map[workloadKey][]float64),
+		rsOwners:   make(map[string]*metav1.OwnerReference),
+	}
+}
+
+// observe folds one poll of pods into the tracker and returns the replacement
+// durations completed since the previous poll.
+func (t *restartTracker) observe(ctx context.Context, k8s kubernetes.Interface, cache *kubecache.Cache, pods []*corev1.Pod, now time.Time) []float64 {
+	owners := t.replicaSetOwnerLookup(ctx, k8s, cache)
+
+	// Departures first, so a replacement that is already Ready in this poll
+	// finds the replica it replaces.
+	seen := make(map[string]struct{}, len(pods))
+	type readyPod struct {
+		workload workloadKey
+		created  time.Time
+		readyAt  time.Time
+	}
+	var becameReady []readyPod
+	for _, pod := range pods {
+		key := podIdentity(pod)
+		seen[key] = struct{}{}
+		prev, known := t.pods[key]
+		next := prev
+		if !known {
+			kind, name := collector.WorkloadOf(pod, owners)
+			next = trackedPod{
+				workload: workloadKey{namespace: pod.Namespace, kind: kind, name: name},
+				created:  pod.CreationTimestamp.Time,
+			}
+		}
+		next.lastSeen = now
+
+		if podDeparting(pod) {
+			if !next.departed {
+				t.depart(next.workload, departureTime(pod, now))
+				next.departed = true
+			}
+			next.ready = false
+			t.pods[key] = next
+			continue
+		}
+
+		ready, readyAt := podReadySince(pod, now)
+		if ready && !prev.ready {
+			becameReady = append(becameReady, readyPod{workload: next.workload, created: next.created, readyAt: readyAt})
+		}
+		next.ready = ready
+		t.pods[key] = next
+	}
+
+	// Pods that vanished without being seen terminating left some time after
+	// they were last seen.
+	for key, pod := range t.pods {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		if !pod.departed {
+			t.depart(pod.workload, pod.lastSeen)
+		}
+		delete(t.pods, key)
+	}
+
+	sort.Slice(becameReady, func(i, j int) bool { return becameReady[i].readyAt.Before(becameReady[j].readyAt) })
+	var completed []float64
+	for _, p := range becameReady {
+		if d, ok := t.matchDeparture(p.workload, p.created, p.readyAt); ok {
+			seconds := p.readyAt.Sub(d).Seconds()
+			t.record(p.workload, seconds)
+			completed = append(completed, seconds)
+		}
+	}
+
+	t.expire(now)
+	t.pruneReplicaSetOwners(pods)
+	return completed
+}
+
+// restartP95 returns the workload's learned P95 once it has enough samples.
+func (t *restartTracker) restartP95(w workloadKey) (float64, bool) {
+	samples := t.samples[w]
+	if len(samples) < minRestartSamples {
+		return 0, false
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	return sorted[int(math.Ceil(0.95*float64(len(sorted))))-1], true
+}
+
+func (t *restartTracker) depart(w workloadKey, at time.Time) {
+	// Bare pods and Jobs are not replaced by a controller.
+	if w.kind == "Pod" || w.kind == "Job" {
+		return
+	}
+	t.departures[w] = append(t.departures[w], at)
+	sort.Slice(t.departures[w], func(i, j int) bool { return t.departures[w][i].Before(t.departures[w][j]) })
+}
+
+// matchDeparture consumes the oldest departure the pod could have replaced:
+// one before it became Ready and not long after it was created.
+func (t *restartTracker) matchDeparture(w workloadKey, created, readyAt time.Time) (time.Time, bool) {
+	pending := t.departures[w]
+	for i, d := range pending {
+		if d.After(readyAt) {
+			break
+		}
+		if created.Before(d.Add(-replacementCreationSlack)) {
+			continue
+		}
+		t.departures[w] = append(pending[:i:i], pending[i+1:]...)
+		if len(t.departures[w]) == 0 {
+			delete(t.departures, w)
+		}
+		return d, true
+	}
+	return time.Time{}, false
+}
+
+func (t *restartTracker) record(w workloadKey, seconds float64) {
+	samples := append(t.samples[w], seconds)
+	if len(samples) > restartSampleWindow {
+		samples = samples[len(samples)-restartSampleWindow:]
+	}
+	t.samples[w] = samples
+}
+
+func (t *restartTracker) expire(now time.Time) {
+	cutoff := now.Add(-maxReplacementWait)
+	for w, pending := range t.departures {
+		kept := pending[:0]
+		for _, d := range pending {
+			if d.After(cutoff) {
+				kept = append(kept, d)
+			}
+		}
+		if len(kept) == 0 {
+			delete(t.departures, w)
+			continue
+		}
+		t.departures[w] = kept
+	}
+}
+
+// replicaSetOwnerLookup resolves ReplicaSet controller owners from the
+// informer cache when synced, otherwise with a Get per ReplicaSet not yet
+// seen. Owners are remembered across polls.
+func (t *restartTracker) replicaSetOwnerLookup(ctx context.Context, k8s kubernetes.Interface, cache *kubecache.Cache) func(namespace, name string) *metav1.OwnerReference {
+	return func(namespace, name string) *metav1.OwnerReference {
+		key := namespace + "/" + name
+		if owner, ok := t.rsOwners[key]; ok {
+			return owner
+		}
+		if cache.HasSynced() {
+			rss, err := cache.ReplicaSets()
+			if err != nil {
+				return nil
+			}
+			for _, rs := range rss {
+				t.rsOwners[rs.Namespace+"/"+rs.Name] = collector.ControllerOf(rs.OwnerReferences)
+			}
+			if _, ok := t.rsOwners[key]; !ok {
+				t.rsOwners[key] = nil
+			}
+			return t.rsOwners[key]
+		}
+		rs, err := k8s.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				t.rsOwners[key] = nil
+			}
+			return nil
+		}
+		owner := collector.ControllerOf(rs.OwnerReferences)
+		t.rsOwners[key] = owner
+		return owner
+	}
+}
+
+// pruneReplicaSetOwners forgets ReplicaSets no current pod references.
+func (t *restartTracker) pruneReplicaSetOwners(pods []*corev1.Pod) {
+	referenced := make(map[string]struct{})
+	for _, pod := range pods {
+		if owner := collector.ControllerOf(pod.OwnerReferences); owner != nil && owner.Kind == "ReplicaSet" {
+			referenced[pod.Namespace+"/"+owner.Name] = struct{}{}
+		}
+	}
+	for key := range t.rsOwners {
+		if _, ok := referenced[key]; !ok {
+			delete(t.rsOwners, key)
+		}
+	}
+}
+
+// podDeparting reports whether the pod is being deleted or has finished.
+func podDeparting(pod *corev1.Pod) bool {
+	return pod.DeletionTimestamp != nil ||
+		pod.Status.Phase == corev1.PodFailed ||
+		pod.Status.Phase == corev1.PodSucceeded
+}
+
+// departureTime is when the pod was evicted or deleted: its deletion
+// timestamp minus the grace period, or its disruption condition, or now.
+func departureTime(pod *corev1.Pod, now time.Time) time.Time {
+	if pod.DeletionTimestamp != nil {
+		at := pod.DeletionTimestamp.Time
+		if pod.DeletionGracePeriodSeconds != nil {
+			at = at.Add(-time.Duration(*pod.DeletionGracePeriodSeconds) * time.Second)
+		}
+		if at.After(now) {
+			return now
+		}
+		return at
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.DisruptionTarget && cond.Status == corev1.ConditionTrue && !cond.LastTransitionTime.IsZero() {
+			return cond.LastTransitionTime.Time
+		}
+	}
+	return now
+}
+
+// podReadySince reports whether the pod is Ready and since when.
+func podReadySince(pod *corev1.Pod, now time.Time) (bool, time.Time) {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type != corev1.PodReady {
+			continue
+		}
+		if cond.Status != corev1.ConditionTrue {
+			return false, time.Time{}
+		}
+		if cond.LastTransitionTime.IsZero() || cond.LastTransitionTime.After(now) {
+			return true, now
+		}
+		return true, cond.LastTransitionTime.Time
+	}
+	return false, time.Time{}
+}
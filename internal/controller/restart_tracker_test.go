@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func replicaPod(name string, created time.Time, readyAt *time.Time) *corev1.Pod {
+	controller := true
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              name,
+			UID:               types.UID("uid-" + name),
+			CreationTimestamp: metav1.NewTime(created),
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-abc", Controller: &controller,
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if readyAt != nil {
+		pod.Status.Conditions = []corev1.PodCondition{{
+			Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(*readyAt),
+		}}
+	}
+	return pod
+}
+
+func webReplicaSet() *appsv1.ReplicaSet {
+	controller := true
+	return &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "default",
+		Name:      "web-abc",
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Controller: &controller,
+		}},
+	}}
+}
+
+func TestRestartTracker_LearnsEvictionToReadyPerWorkload(t *testing.T) {
+	ctx := context.Background()
+	client := k8sfake.NewSimpleClientset(webReplicaSet())
+	tracker := newRestartTracker()
+	web := workloadKey{namespace: "default", kind: "Deployment", name: "web"}
+
+	base := time.Unix(1700000000, 0).UTC()
+	ready := base
+	current := replicaPod("web-0", base, &ready)
+	now := base.Add(time.Minute)
+	tracker.observe(ctx, client, nil, []*corev1.Pod{current}, now)
+
+	for i := 1; i <= minRestartSamples; i++ {
+		// Evicted with a 30s grace period; the ReplicaSet creates the
+		// replacement right away and it turns Ready i*10s after eviction.
+		evictedAt := now.Add(5 * time.Second)
+		grace := int64(30)
+		deleting := current.DeepCopy()
+		deletion := metav1.NewTime(evictedAt.Add(30 * time.Second))
+		deleting.DeletionTimestamp = &deletion
+		deleting.DeletionGracePeriodSeconds = &grace
+		replacement := replicaPod(fmt.Sprintf("web-%d", i), evictedAt.Add(time.Second), nil)
+		now = now.Add(10 * time.Second)
+		tracker.observe(ctx, client, nil, []*corev1.Pod{deleting, replacement}, now)
+
+		readyAt := evictedAt.Add(time.Duration(i*10) * time.Second)
+		now = readyAt.Add(2 * time.Second)
+		replacement = replicaPod(replacement.Name, replacement.CreationTimestamp.Time, &readyAt)
+		completed := tracker.observe(ctx, client, nil, []*corev1.Pod{replacement}, now)
+		if len(completed) != 1 || completed[0] != float64(i*10) {
+			t.Fatalf("replacement %d: completed=%v, want [%d]", i, completed, i*10)
+		}
+		if _, ok := tracker.restartP95(web); ok != (i == minRestartSamples) {
+			t.Fatalf("replacement %d: learned P95 available=%v", i, ok)
+		}
+		current = replacement
+	}
+
+	p95, _ := tracker.restartP95(web)
+	if p95 != 50 {
+		t.Fatalf("P95=%v, want 50", p95)
+	}
+}
+
+func TestRestartTracker_IgnoresScaleUpsAndUnreplacedDepartures(t *testing.T) {
+	ctx := context.Background()
+	client := k8sfake.NewSimpleClientset(webReplicaSet())
+	tracker := newRestartTracker()
+	web := workloadKey{namespace: "default", kind: "Deployment", name: "web"}
+
+	base := time.Unix(1700000000, 0).UTC()
+	ready := base
+	old := replicaPod("web-old", base, &ready)
+	surge := replicaPod("web-surge", base.Add(10*time.Second), nil)
+	now := base.Add(20 * time.Second)
+	tracker.observe(ctx, client, nil, []*corev1.Pod{old, surge}, now)
+
+	// The old replica vanishes after the surge pod was created: the surge
+	// pod predates the departure, so it is not a replacement.
+	surgeReady := base.Add(40 * time.Second)
+	surge = replicaPod("web-surge", surge.CreationTimestamp.Time, &surgeReady)
+	now = base.Add(50 * time.Second)
+	if completed := tracker.observe(ctx, client, nil, []*corev1.Pod{surge}, now); len(completed) != 0 {
+		t.Fatalf("surge pod counted as replacement: %v", completed)
+	}
+
+	// With no replacement, the departure expires.
+	now = now.Add(maxReplacementWait + time.Minute)
+	tracker.observe(ctx, client, nil, []*corev1.Pod{surge}, now)
+	if pending := tracker.departures[web]; len(pending) != 0 {
+		t.Fatalf("pending departures=%v, want expired", pending)
+	}
+}
+
+func TestKubernetesReliabilityTelemetryCollector_ServesLearnedRestartP95(t *testing.T) {
+	client := k8sfake.NewSimpleClientset(webReplicaSet())
+	c := NewKubernetesReliabilityTelemetryCollector(client, slog.Default())
+	web := workloadKey{namespace: "default", kind: "Deployment", name: "web"}
+	for i := 0; i < minRestartSamples; i++ {
+		c.restarts.record(web, 90)
+	}
+	if got, ok := c.RestartP95("default", "Deployment", "web"); !ok || got != 90 {
+		t.Fatalf("RestartP95=%v,%v, want 90,true", got, ok)
+	}
+	if _, ok := c.RestartP95("default", "Deployment", "other"); ok {
+		t.Fatal("expected no learned restart time for an unobserved workload")
+	}
+}
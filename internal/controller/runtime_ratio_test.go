@@ -1,11 +1,17 @@
 package controller
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/softcane/spot-vortex-agent/internal/config"
 	"github.com/softcane/spot-vortex-agent/internal/inference"
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 )
 
 func TestApplyTargetSpotRatioWithRuntimeKillSwitch(t *testing.T) {
@@ -77,6 +83,103 @@ func TestApplyTargetSpotRatioWithRuntimeTargetLerpUsesConfiguredAlpha(t *testing
 	}
 }
 
+func poolOverrideRuntimeConfig() *config.RuntimeConfig {
+	cfg := deterministicRuntimeConfigShadowTest()
+	cfg.MinSpotRatio = 0.1
+	cfg.MaxSpotRatio = 0.8
+	cfg.TargetSpotRatio = 0.5
+	apiMax, batchMin, batchMax := 0.4, 0.5, 1.0
+	cfg.PoolOverrides = []config.PoolRuntimeOverride{
+		{Name: "api", Pools: []string{"api"}, MaxSpotRatio: &apiMax},
+		{Name: "batch", NodeSelector: map[string]string{"team": "batch"}, MinSpotRatio: &batchMin, MaxSpotRatio: &batchMax},
+	}
+	return cfg
+}
+
+func TestApplyTargetSpotRatioWithPoolOverride(t *testing.T) {
+	c := &Controller{
+		targetSpotRatio: map[string]float64{
+			"api:m5.large:us-east-1a":   0.30,
+			"batch:m5.large:us-east-1a": 0.70,
+		},
+	}
+	runtimeCfg := poolOverrideRuntimeConfig()
+
+	c.applyTargetSpotRatioWithConfig("api:m5.large:us-east-1a", inference.ActionIncrease30,
+		c.runtimeConfigForPool(runtimeCfg, "api", nil), false)
+	if got := c.targetSpotRatio["api:m5.large:us-east-1a"]; got != 0.4 {
+		t.Fatalf("api target=%.4f, want pool max 0.4", got)
+	}
+
+	c.applyTargetSpotRatioWithConfig("batch:m5.large:us-east-1a", inference.ActionIncrease30,
+		c.runtimeConfigForPool(runtimeCfg, "batch", map[string]string{"team": "batch"}), false)
+	if got := c.targetSpotRatio["batch:m5.large:us-east-1a"]; got != 1.0 {
+		t.Fatalf("batch target=%.4f, want pool max 1.0 above the global 0.8", got)
+	}
+}
+
+func TestRunInference_ResolvesPoolOverridesAndExportsBounds(t *testing.T) {
+	k8sClient := k8sfake.NewSimpleClientset()
+	for name, labels := range map[string]map[string]string{
+		"node-api":   {"spotvortex.io/pool": "api"},
+		"node-batch": {"spotvortex.io/pool": "etl", "team": "batch"},
+		"node-web":   {"spotvortex.io/pool": "web"},
+	} {
+		labels["karpenter.sh/capacity-type"] = "spot"
+		labels["topology.kubernetes.io/zone"] = "us-east-1a"
+		labels["node.kubernetes.io/instance-type"] = "m5.large"
+		_, err := k8sClient.CoreV1().Nodes().Create(context.Background(), &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			t.Fatalf("create node: %v", err)
+		}
+	}
+
+	cfg := baseControllerConfig(&noopCloudProvider{dryRun: true})
+	cfg.K8sClient = k8sClient
+	cfg.Karpenter.UseExtendedPoolID = true
+	ctrl, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctrl.runtimeConfigLoader = poolOverrideRuntimeConfig
+	ctrl.predictDetailedOverride = func(ctx context.Context, nodeID string, state inference.NodeState, riskMultiplier float64) (inference.Action, float32, float32, float32, error) {
+		return inference.ActionHold, 0.10, 0.10, 0.90, nil
+	}
+
+	if _, err := ctrl.runInference(context.Background(), []metrics.NodeMetrics{
+		{NodeID: "node-api", CPUUsagePercent: 35, MemoryUsagePercent: 50},
+		{NodeID: "node-batch", CPUUsagePercent: 35, MemoryUsagePercent: 50},
+		{NodeID: "node-web", CPUUsagePercent: 35, MemoryUsagePercent: 50},
+	}); err != nil {
+		t.Fatalf("runInference failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		pool          string
+		min, max, tgt float64
+	}{
+		{"api:m5.large:us-east-1a", 0.1, 0.4, 0.4},
+		{"etl:m5.large:us-east-1a", 0.5, 1.0, 0.5},
+		{"web:m5.large:us-east-1a", 0.1, 0.8, 0.5},
+	} {
+		exp, ok := ctrl.DecisionExplanations().Get(tc.pool)
+		if !ok || exp.Cap.MinSpotRatio != tc.min || exp.Cap.MaxSpotRatio != tc.max {
+			t.Fatalf("%s explanation cap=%+v, want min %.1f max %.1f", tc.pool, exp.Cap, tc.min, tc.max)
+		}
+		if got := testutil.ToFloat64(metrics.PoolMinSpotRatio.WithLabelValues(tc.pool)); got != tc.min {
+			t.Fatalf("%s min gauge=%v, want %v", tc.pool, got, tc.min)
+		}
+		if got := testutil.ToFloat64(metrics.PoolMaxSpotRatio.WithLabelValues(tc.pool)); got != tc.max {
+			t.Fatalf("%s max gauge=%v, want %v", tc.pool, got, tc.max)
+		}
+		if got := testutil.ToFloat64(metrics.PoolTargetSpotRatio.WithLabelValues(tc.pool)); got != tc.tgt {
+			t.Fatalf("%s target gauge=%v, want %v", tc.pool, got, tc.tgt)
+		}
+	}
+}
+
 func TestStepsSinceMigration_UsesConfiguredStepMinutes(t *testing.T) {
 	last := time.Now().Add(-95 * time.Minute)
 	got := stepsSinceMigration(last, 30)
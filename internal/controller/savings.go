@@ -206,7 +206,7 @@ func emitDryRunMetrics(report *SavingsReport) {
 		// Node-level metrics
 		for _, ns := range ps.NodeSavings {
 			metrics.PotentialSavingsHourly.WithLabelValues(ns.NodeID, poolID, ns.InstanceType).Set(ns.SavingsHourly)
-			metrics.RecommendedAction.WithLabelValues(ns.NodeID, poolID).Set(float64(ns.Action))
+			metrics.RecommendedAction.WithLabelValues(ns.NodeID, poolID, metrics.ActiveBundleVersion()).Set(float64(ns.Action))
 		}
 	}
 
@@ -0,0 +1,353 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/config"
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
+)
+
+// ShadowPredictor scores a scope with a candidate model bundle for shadow
+// evaluators that name it. The inference engine satisfies it.
+type ShadowPredictor interface {
+	PredictDetailed(ctx context.Context, nodeID string, state inference.NodeState, riskMultiplier float64) (inference.Action, float32, float32, float32, error)
+}
+
+// ShadowReport is the counterfactual comparison of every shadow evaluator
+// with the active policy over the report window.
+type ShadowReport struct {
+	GeneratedAt   time.Time       `json:"generated_at"`
+	WindowMinutes int             `json:"window_minutes"`
+	Shadows       []ShadowSummary `json:"shadows"`
+}
+
+// ShadowSummary is one shadow's record over the report window. The active
+// figures cover the same pool decisions, so the two are directly comparable.
+type ShadowSummary struct {
+	Name   string `json:"name"`
+	Policy string `json:"policy"`
+	Bundle string `json:"bundle,omitempty"`
+
+	Decisions      int     `json:"decisions"`
+	Agreements     int     `json:"agreements"`
+	AgreementRatio float64 `json:"agreement_ratio"`
+
+	ProjectedSavingsUSD       float64 `json:"projected_savings_usd"`
+	ActiveProjectedSavingsUSD float64 `json:"active_projected_savings_usd"`
+	SavingsDeltaUSD           float64 `json:"savings_delta_usd"`
+	Migrations                int     `json:"migrations"`
+	ActiveMigrations          int     `json:"active_migrations"`
+
+	// VirtualSpotRatio is the shadow's current virtual target per pool.
+	VirtualSpotRatio map[string]float64 `json:"virtual_spot_ratio"`
+}
+
+// shadowSample is one shadow's decision for one pool on one tick, projected
+// forward by one step for both the shadow and the active trajectory.
+type shadowSample struct {
+	at               time.Time
+	agree            bool
+	savingsUSD       float64
+	activeSavingsUSD float64
+	migrations       int
+	activeMigrations int
+}
+
+// shadowTrack is one shadow evaluator's virtual trajectory and history.
+type shadowTrack struct {
+	evaluator config.ShadowEvaluator
+	ratios    map[string]float64 // pool -> virtual target spot ratio
+	samples   []shadowSample     // oldest first
+	advanced  map[string]struct{}
+}
+
+// shadowLedger keeps every shadow's virtual spot-ratio trajectory. Each
+// trajectory starts from the pool's real target the first time the shadow
+// sees the pool and then follows only the shadow's own actions. A pool
+// advances at most once per tick; under per-node inference the first node
+// decided for the pool moves it.
+type shadowLedger struct {
+	mu     sync.Mutex
+	window time.Duration
+	tracks map[string]*shadowTrack
+}
+
+func newShadowLedger() *shadowLedger {
+	return &shadowLedger{tracks: make(map[string]*shadowTrack)}
+}
+
+// begin starts a tick. Tracks for removed shadows are dropped and a shadow
+// whose definition changed restarts its trajectory.
+func (l *shadowLedger) begin(evaluators []config.ShadowEvaluator, window time.Duration) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.window = window
+	keep := make(map[string]struct{}, len(evaluators))
+	for _, e := range evaluators {
+		keep[e.Name] = struct{}{}
+		t, ok := l.tracks[e.Name]
+		if !ok || !reflect.DeepEqual(t.evaluator, e) {
+			t = &shadowTrack{evaluator: e, ratios: make(map[string]float64)}
+			l.tracks[e.Name] = t
+		}
+		t.advanced = make(map[string]struct{})
+	}
+	for name := range l.tracks {
+		if _, ok := keep[name]; !ok {
+			delete(l.tracks, name)
+			deleteShadowSeries(name)
+		}
+	}
+}
+
+// ratio returns the shadow's virtual target for the pool, seeding it from
+// the pool's real target on first sight.
+func (l *shadowLedger) ratio(name, pool string, seed float64) float64 {
+	if l == nil {
+		return seed
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	t, ok := l.tracks[name]
+	if !ok {
+		return seed
+	}
+	if r, ok := t.ratios[pool]; ok {
+		return r
+	}
+	t.ratios[pool] = seed
+	return seed
+}
+
+// advance moves the shadow's trajectory for the pool and records the sample,
+// once per pool per tick.
+func (l *shadowLedger) advance(name, pool string, next float64, sample shadowSample) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	t, ok := l.tracks[name]
+	if !ok {
+		return
+	}
+	if _, done := t.advanced[pool]; done {
+		return
+	}
+	t.advanced[pool] = struct{}{}
+	t.ratios[pool] = next
+	t.samples = append(t.samples, sample)
+}
+
+// finish drops samples older than the window and publishes the report
+// gauges.
+func (l *shadowLedger) finish(now time.Time) {
+	if l == nil {
+		return
+	}
+	report := l.report(now)
+	for _, s := range report.Shadows {
+		metrics.ShadowAgreementRatio.WithLabelValues(s.Name).Set(s.AgreementRatio)
+		metrics.ShadowCounterfactualSavingsUSD.WithLabelValues(s.Name, "shadow").Set(s.ProjectedSavingsUSD)
+		metrics.ShadowCounterfactualSavingsUSD.WithLabelValues(s.Name, "active").Set(s.ActiveProjectedSavingsUSD)
+		metrics.ShadowCounterfactualMigrations.WithLabelValues(s.Name, "shadow").Set(float64(s.Migrations))
+		metrics.ShadowCounterfactualMigrations.WithLabelValues(s.Name, "active").Set(float64(s.ActiveMigrations))
+	}
+}
+
+// report prunes samples outside the window and summarizes each shadow.
+func (l *shadowLedger) report(now time.Time) ShadowReport {
+	out := ShadowReport{GeneratedAt: now, Shadows: []ShadowSummary{}}
+	if l == nil {
+		return out
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out.WindowMinutes = int(l.window / time.Minute)
+	cutoff := now.Add(-l.window)
+	for name, t := range l.tracks {
+		kept := t.samples[:0]
+		for _, s := range t.samples {
+			if s.at.After(cutoff) {
+				kept = append(kept, s)
+			}
+		}
+		t.samples = kept
+
+		sum := ShadowSummary{
+			Name:             name,
+			Policy:           t.evaluator.Policy,
+			Bundle:           t.evaluator.Bundle,
+			Decisions:        len(kept),
+			VirtualSpotRatio: make(map[string]float64, len(t.ratios)),
+		}
+		for _, s := range kept {
+			if s.agree {
+				sum.Agreements++
+			}
+			sum.ProjectedSavingsUSD += s.savingsUSD
+			sum.ActiveProjectedSavingsUSD += s.activeSavingsUSD
+			sum.Migrations += s.migrations
+			sum.ActiveMigrations += s.activeMigrations
+		}
+		if sum.Decisions > 0 {
+			sum.AgreementRatio = float64(sum.Agreements) / float64(sum.Decisions)
+		}
+		sum.SavingsDeltaUSD = sum.ProjectedSavingsUSD - sum.ActiveProjectedSavingsUSD
+		for pool, r := range t.ratios {
+			sum.VirtualSpotRatio[pool] = r
+		}
+		out.Shadows = append(out.Shadows, sum)
+	}
+	sort.Slice(out.Shadows, func(i, j int) bool { return out.Shadows[i].Name < out.Shadows[j].Name })
+	return out
+}
+
+func deleteShadowSeries(name string) {
+	metrics.ShadowAgreementRatio.DeleteLabelValues(name)
+	for _, trajectory := range []string{"shadow", "active"} {
+		metrics.ShadowCounterfactualSavingsUSD.DeleteLabelValues(name, trajectory)
+		metrics.ShadowCounterfactualMigrations.DeleteLabelValues(name, trajectory)
+	}
+}
+
+// ShadowReport returns the counterfactual report for every shadow evaluator.
+func (c *Controller) ShadowReport() ShadowReport {
+	return c.shadows.report(c.clock())
+}
+
+// ShadowReportHandler serves ShadowReport as JSON.
+func (c *Controller) ShadowReportHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(c.ShadowReport())
+	})
+}
+
+// beginShadowTick resets per-tick shadow bookkeeping for the tick's config.
+func (c *Controller) beginShadowTick(tickCfg *config.RuntimeConfig) {
+	window := time.Duration(tickCfg.ResolvedShadowReportWindowMinutes()) * time.Minute
+	c.shadows.begin(tickCfg.ResolvedShadowEvaluators(), window)
+}
+
+// evaluateShadow runs one shadow evaluator for the scope on its own virtual
+// trajectory and advances that trajectory. Scores are recomputed when the
+// shadow's state or bundle differs from the active one's.
+func (c *Controller) evaluateShadow(ctx context.Context, e config.ShadowEvaluator, in PolicyInput, active PolicyDecision) (PolicyDecision, error) {
+	policy, ok := LookupPolicy(e.Policy)
+	if !ok {
+		return PolicyDecision{}, fmt.Errorf("policy %q is not registered", e.Policy)
+	}
+	shadowCfg, err := in.Config.ForShadow(e)
+	if err != nil {
+		return PolicyDecision{}, err
+	}
+	shadowIn := in
+	shadowIn.Config = shadowCfg
+
+	before := c.shadows.ratio(e.Name, in.PoolID, in.State.TargetSpotRatio)
+	shadowIn.State.TargetSpotRatio = before
+	if e.Bundle != "" || before != in.State.TargetSpotRatio || shadowCfg.RiskMultiplier != in.Config.RiskMultiplier {
+		if err := c.rescoreShadow(ctx, e, &shadowIn); err != nil {
+			return PolicyDecision{}, err
+		}
+	}
+
+	decision, err := policy.Decide(ctx, shadowIn)
+	if err != nil {
+		return PolicyDecision{}, err
+	}
+
+	next, _ := nextTargetSpotRatio(before, decision.Action, shadowCfg, c.riskLow(shadowIn.CapacityScore))
+	activeBefore := in.State.TargetSpotRatio
+	activeNext, _ := nextTargetSpotRatio(activeBefore, active.Action, in.Config, c.riskLow(in.CapacityScore))
+	nodes := float64(in.PoolNodes)
+	stepHours := float64(in.Config.StepMinutes) / 60
+	spread := in.State.OnDemandPrice - in.State.SpotPrice
+	c.shadows.advance(e.Name, in.PoolID, next, shadowSample{
+		at:               c.clock(),
+		agree:            decision.Action == active.Action,
+		savingsUSD:       next * nodes * spread * stepHours,
+		activeSavingsUSD: activeNext * nodes * spread * stepHours,
+		migrations:       projectedMigrations(before, next, nodes),
+		activeMigrations: projectedMigrations(activeBefore, activeNext, nodes),
+	})
+	return decision, nil
+}
+
+// rescoreShadow recomputes the scores and RL recommendation for the shadow's
+// state. Shadows keep their own price history under a per-shadow scope.
+func (c *Controller) rescoreShadow(ctx context.Context, e config.ShadowEvaluator, in *PolicyInput) error {
+	scope := in.NodeID
+	if scope == "" {
+		scope = in.PoolID
+	}
+	scope = e.Name + "/" + scope
+
+	predict := c.predictDetailed
+	if e.Bundle != "" {
+		bundle, ok := c.shadowBundles[e.Bundle]
+		if !ok {
+			return fmt.Errorf("shadow bundle %q is not loaded", e.Bundle)
+		}
+		predict = bundle.PredictDetailed
+	}
+
+	if err := c.rescore(ctx, predict, scope, in); err != nil {
+		if _, ok := inference.AsRLFallbackError(err); !ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// rescore recomputes in's scores and RL recommendation under scope and
+// returns the prediction error. After an RL fallback the TFT scores are still
+// applied; after any other error in is left unchanged.
+func (c *Controller) rescore(ctx context.Context, predict predictDetailedFunc, scope string, in *PolicyInput) error {
+	action, capacityScore, runtimeScore, confidence, err := predict(ctx, scope, in.State, in.Config.RiskMultiplier)
+	if err != nil {
+		rlFallback, ok := inference.AsRLFallbackError(err)
+		if !ok {
+			return err
+		}
+		capacityScore = rlFallback.CapacityScore
+		runtimeScore = rlFallback.RuntimeScore
+	}
+	in.RLAvailable = err == nil
+	in.RLAction = action
+	in.RLConfidence = float64(confidence)
+	in.CapacityScore = float64(capacityScore)
+	in.RuntimeScore = float64(c.applyInterruptionRisk(in.PoolID, runtimeScore))
+	return err
+}
+
+// riskLow mirrors executeAction: risk below half the threshold lets HOLD
+// drift toward the target spot ratio.
+func (c *Controller) riskLow(capacityScore float64) bool {
+	return capacityScore < c.riskThreshold*0.5
+}
+
+// projectedMigrations is the node moves needed to go from one target spot
+// ratio to another.
+func projectedMigrations(from, to, nodes float64) int {
+	return int(math.Ceil(math.Abs(to-from)*nodes - 1e-9))
+}
@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/config"
+	"github.com/softcane/spot-vortex-agent/internal/inference"
+	svmetrics "github.com/softcane/spot-vortex-agent/internal/metrics"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// candidateBundle is a shadow bundle that always recommends DECREASE_10 at
+// low risk.
+type candidateBundle struct {
+	scopes []string
+}
+
+func (b *candidateBundle) PredictDetailed(_ context.Context, nodeID string, _ inference.NodeState, _ float64) (inference.Action, float32, float32, float32, error) {
+	b.scopes = append(b.scopes, nodeID)
+	return inference.ActionDecrease10, 0.10, 0.05, 0.8, nil
+}
+
+func TestRunInference_ShadowEvaluatorsFollowOwnTrajectories(t *testing.T) {
+	k8sClient := k8sfake.NewSimpleClientset()
+	createNode(k8sClient, "node-1", "spot", "us-east-1a", "m5.large")
+	bundle := &candidateBundle{}
+	ctrl, err := New(Config{
+		Cloud:               &MockCloudProvider{DryRun: true},
+		PriceProvider:       fixedPriceProvider(),
+		K8sClient:           k8sClient,
+		Inference:           &inference.InferenceEngine{},
+		PrometheusClient:    &svmetrics.Client{},
+		Logger:              slog.Default(),
+		RiskThreshold:       0.95,
+		MaxDrainRatio:       0.2,
+		ReconcileInterval:   10 * time.Second,
+		ConfidenceThreshold: 0.5,
+		ShadowBundles:       map[string]ShadowPredictor{"cand": bundle},
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	ctrl.runtimeConfigLoader = func() *config.RuntimeConfig {
+		cfg := deterministicRuntimeConfigShadowTest()
+		cfg.ShadowEvaluators = []config.ShadowEvaluator{
+			{Name: "candidate", Policy: config.PolicyModeRL, Bundle: "cand"},
+			{Name: "capped", Policy: config.PolicyModeDeterministic, RuntimeConfig: map[string]interface{}{"max_spot_ratio": 0.5}},
+		}
+		return cfg
+	}
+	ctrl.predictDetailedOverride = func(ctx context.Context, nodeID string, state inference.NodeState, riskMultiplier float64) (inference.Action, float32, float32, float32, error) {
+		// High capacity risk: the active deterministic policy decreases by 30%.
+		return inference.ActionIncrease30, 0.70, 0.10, 0.40, nil
+	}
+	beforeDifferent := counterVecValue(t, svmetrics.ShadowPolicyAgreement, "candidate", "different")
+
+	node := []svmetrics.NodeMetrics{{NodeID: "node-1", InstanceType: "m5.large", Zone: "us-east-1a", IsSpot: true, CPUUsagePercent: 35}}
+	for tick := 0; tick < 2; tick++ {
+		if _, err := ctrl.runInference(context.Background(), node); err != nil {
+			t.Fatalf("runInference tick %d: %v", tick, err)
+		}
+	}
+
+	if len(bundle.scopes) != 2 || !strings.HasPrefix(bundle.scopes[0], "candidate/") {
+		t.Fatalf("bundle scopes=%v, want one per-shadow scope per tick", bundle.scopes)
+	}
+	if delta := counterVecValue(t, svmetrics.ShadowPolicyAgreement, "candidate", "different") - beforeDifferent; delta != 2 {
+		t.Fatalf("candidate disagreement delta=%v, want 2", delta)
+	}
+
+	report := ctrl.ShadowReport()
+	if report.WindowMinutes != 24*60 {
+		t.Fatalf("window=%d, want default 24h", report.WindowMinutes)
+	}
+	byName := make(map[string]ShadowSummary)
+	for _, s := range report.Shadows {
+		byName[s.Name] = s
+	}
+	if _, ok := byName[config.PolicyModeRL]; !ok {
+		t.Fatalf("shadows=%v, want rl shadow alongside named evaluators", report.Shadows)
+	}
+
+	const pool = "m5.large:us-east-1a"
+	cand := byName["candidate"]
+	if cand.Decisions != 2 || cand.Agreements != 0 {
+		t.Fatalf("candidate decisions=%d agreements=%d, want 2 and 0", cand.Decisions, cand.Agreements)
+	}
+	// The real target never moves (nothing executes), so the active baseline
+	// projects 1.0 -> 0.7 twice while the candidate walks 1.0 -> 0.9 -> 0.8.
+	if got := cand.VirtualSpotRatio[pool]; math.Abs(got-0.8) > 1e-9 {
+		t.Fatalf("candidate virtual ratio=%v, want 0.8", got)
+	}
+	spread, stepHours := 0.8, 0.5
+	wantShadow := (0.9 + 0.8) * spread * stepHours
+	wantActive := (0.7 + 0.7) * spread * stepHours
+	if math.Abs(cand.ProjectedSavingsUSD-wantShadow) > 1e-9 || math.Abs(cand.ActiveProjectedSavingsUSD-wantActive) > 1e-9 {
+		t.Fatalf("candidate savings=%v active=%v, want %v and %v", cand.ProjectedSavingsUSD, cand.ActiveProjectedSavingsUSD, wantShadow, wantActive)
+	}
+	if cand.SavingsDeltaUSD <= 0 || cand.Migrations != 2 || cand.ActiveMigrations != 2 {
+		t.Fatalf("candidate delta=%v migrations=%d active=%d, want positive, 2 and 2", cand.SavingsDeltaUSD, cand.Migrations, cand.ActiveMigrations)
+	}
+
+	if got := byName["capped"].VirtualSpotRatio[pool]; got > 0.5 {
+		t.Fatalf("capped virtual ratio=%v, want the overlay's max_spot_ratio bound", got)
+	}
+}
+
+func TestShadowLedger_WindowAndRedefinition(t *testing.T) {
+	l := newShadowLedger()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	e := config.ShadowEvaluator{Name: "alt", Policy: config.PolicyModeDeterministic}
+
+	l.begin([]config.ShadowEvaluator{e}, time.Hour)
+	l.ratio("alt", "pool", 0.5)
+	l.advance("alt", "pool", 0.6, shadowSample{at: start, agree: true, migrations: 1})
+	l.advance("alt", "pool", 0.9, shadowSample{at: start, migrations: 3})
+	if got := l.report(start).Shadows[0]; got.Decisions != 1 || got.VirtualSpotRatio["pool"] != 0.6 {
+		t.Fatalf("report=%+v, want one decision per pool per tick", got)
+	}
+
+	l.begin([]config.ShadowEvaluator{e}, time.Hour)
+	l.advance("alt", "pool", 0.7, shadowSample{at: start.Add(90 * time.Minute)})
+	if got := l.report(start.Add(90 * time.Minute)).Shadows[0]; got.Decisions != 1 || got.Agreements != 0 {
+		t.Fatalf("report=%+v, want the first sample aged out of the window", got)
+	}
+
+	e.RuntimeConfig = map[string]interface{}{"max_spot_ratio": 0.4}
+	l.begin([]config.ShadowEvaluator{e}, time.Hour)
+	if got := l.ratio("alt", "pool", 0.2); got != 0.2 {
+		t.Fatalf("ratio=%v, want a redefined shadow to restart from the real target", got)
+	}
+
+	l.begin(nil, time.Hour)
+	if got := l.report(start).Shadows; len(got) != 0 {
+		t.Fatalf("shadows=%v, want removed shadows dropped", got)
+	}
+}
@@ -8,22 +8,60 @@ import (
 	"github.com/softcane/spot-vortex-agent/internal/config"
 	"github.com/softcane/spot-vortex-agent/internal/inference"
 	"github.com/softcane/spot-vortex-agent/internal/metrics"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type predictDetailedFunc func(context.Context, string, inference.NodeState, float64) (inference.Action, float32, float32, float32, error)
 type supportsInstanceTypeFunc func(string) (bool, string)
 type runtimeConfigLoaderFunc func() *config.RuntimeConfig
 
+// RuntimeConfigSource supplies runtime config from outside the local file,
+// e.g. SpotVortexPolicy custom resources.
+type RuntimeConfigSource interface {
+	// RuntimeConfig returns the cluster runtime config; ok=false falls back
+	// to config/runtime.json.
+	RuntimeConfig() (*config.RuntimeConfig, bool)
+	// PoolRuntimeConfig returns base with any workload-pool policy applied.
+	PoolRuntimeConfig(base *config.RuntimeConfig, workloadPool string) *config.RuntimeConfig
+}
+
 func (c *Controller) runtimeConfigForTick() *config.RuntimeConfig {
 	if c != nil && c.runtimeConfigLoader != nil {
 		if cfg := c.runtimeConfigLoader(); cfg != nil {
 			return cfg
 		}
 	}
+	if c != nil && c.runtimeSource != nil {
+		if cfg, ok := c.runtimeSource.RuntimeConfig(); ok {
+			return cfg
+		}
+	}
 	return c.loadRuntimeConfig()
 }
 
+// runtimeConfigForPool resolves the tick's runtime config for one workload
+// pool: pool_overrides from the runtime config first, then any pool policy
+// from the runtime source.
+func (c *Controller) runtimeConfigForPool(base *config.RuntimeConfig, workloadPool string, nodeLabels map[string]string) *config.RuntimeConfig {
+	cfg := base.ForPool(config.PoolSelector{WorkloadPool: workloadPool, NodeLabels: nodeLabels})
+	if c == nil || c.runtimeSource == nil || workloadPool == "" {
+		return cfg
+	}
+	if pooled := c.runtimeSource.PoolRuntimeConfig(cfg, workloadPool); pooled != nil {
+		return pooled
+	}
+	return cfg
+}
+
+// recordPoolSpotRatioBounds exports the effective per-pool ratio bounds.
+func recordPoolSpotRatioBounds(poolID string, cfg *config.RuntimeConfig) {
+	if cfg == nil || poolID == "" {
+		return
+	}
+	metrics.PoolMinSpotRatio.WithLabelValues(poolID).Set(cfg.MinSpotRatio)
+	metrics.PoolMaxSpotRatio.WithLabelValues(poolID).Set(cfg.MaxSpotRatio)
+	metrics.PoolTargetSpotRatio.WithLabelValues(poolID).Set(cfg.TargetSpotRatio)
+}
+
 func (c *Controller) supportsInstanceType(instanceType string) (bool, string) {
 	if c != nil && c.supportsInstanceTypeOverride != nil {
 		return c.supportsInstanceTypeOverride(instanceType)
@@ -36,7 +74,9 @@ func (c *Controller) supportsInstanceType(instanceType string) (bool, string) {
 
 func (c *Controller) predictDetailed(ctx context.Context, nodeID string, state inference.NodeState, riskMultiplier float64) (inference.Action, float32, float32, float32, error) {
 	start := time.Now()
-	defer metrics.InferenceLatency.WithLabelValues("pipeline").Observe(time.Since(start).Seconds())
+	defer func() {
+		metrics.InferenceLatency.WithLabelValues("pipeline").Observe(time.Since(start).Seconds())
+	}()
 
 	if c != nil && c.predictDetailedOverride != nil {
 		return c.predictDetailedOverride(ctx, nodeID, state, riskMultiplier)
@@ -47,42 +87,67 @@ func (c *Controller) predictDetailed(ctx context.Context, nodeID string, state i
 	return c.inf.PredictDetailed(ctx, nodeID, state, riskMultiplier)
 }
 
+// predictBatch scores all of a tick's pools with one batched engine call.
+// With predictDetailedOverride set, pools are scored one at a time through it.
+func (c *Controller) predictBatch(ctx context.Context, items []inference.BatchItem, riskMultiplier float64) []inference.BatchResult {
+	start := time.Now()
+	defer func() {
+		metrics.InferenceLatency.WithLabelValues("batch").Observe(time.Since(start).Seconds())
+	}()
+
+	if c == nil || c.inf == nil || c.predictDetailedOverride != nil {
+		results := make([]inference.BatchResult, len(items))
+		for i, item := range items {
+			r := &results[i]
+			r.Action, r.CapacityScore, r.RuntimeScore, r.Confidence, r.Err = c.predictDetailed(ctx, item.NodeID, item.State, riskMultiplier)
+		}
+		return results
+	}
+	return c.inf.PredictBatch(ctx, items, riskMultiplier)
+}
+
+// recordShadowDecisionComparison records how one shadow's action
+// compares with the active action and returns the projected hourly savings
+// delta of following the shadow instead.
 func (c *Controller) recordShadowDecisionComparison(
 	ctx context.Context,
+	shadowName string,
 	poolID string,
 	scopeNodeID string,
 	state inference.NodeState,
-	deterministicAction inference.Action,
-	rlAction inference.Action,
+	activeAction inference.Action,
+	shadowAction inference.Action,
 	capacityScore float32,
-	rlConfidence float32,
+	shadowConfidence float32,
 	multiplier float64,
 ) float64 {
-	metrics.ShadowActionRecommended.WithLabelValues("rl", inference.ActionToString(rlAction)).Inc()
-	if deterministicAction == rlAction {
-		metrics.ShadowActionAgreement.WithLabelValues("same").Inc()
-	} else {
-		metrics.ShadowActionAgreement.WithLabelValues("different").Inc()
+	metrics.ShadowActionRecommended.WithLabelValues(shadowName, inference.ActionToString(shadowAction)).Inc()
+	agreement := "different"
+	if activeAction == shadowAction {
+		agreement = "same"
 	}
+	metrics.ShadowActionAgreement.WithLabelValues(agreement).Inc()
+	metrics.ShadowPolicyAgreement.WithLabelValues(shadowName, agreement).Inc()
 	metrics.ShadowActionDelta.WithLabelValues(
-		inference.ActionToString(deterministicAction),
-		inference.ActionToString(rlAction),
+		inference.ActionToString(activeAction),
+		inference.ActionToString(shadowAction),
 	).Inc()
 
-	if guardrail, blocked := c.shadowGuardrailBlock(ctx, scopeNodeID, rlAction, state, capacityScore, rlConfidence); blocked {
+	if guardrail, blocked := c.shadowGuardrailBlock(ctx, scopeNodeID, shadowAction, state, capacityScore, shadowConfidence); blocked {
 		metrics.ShadowGuardrailBlocked.WithLabelValues(guardrail).Inc()
 	}
 
 	if c != nil && c.logger != nil {
-		c.logger.Debug("rl shadow comparison",
+		c.logger.Debug("shadow policy comparison",
+			"shadow_policy", shadowName,
 			"scope", scopeNodeID,
 			"pool", poolID,
-			"deterministic_action", inference.ActionToString(deterministicAction),
-			"rl_shadow_action", inference.ActionToString(rlAction),
+			"active_action", inference.ActionToString(activeAction),
+			"shadow_action", inference.ActionToString(shadowAction),
 		)
 	}
 
-	return projectedHourlySavingsDeltaProxyUSD(state, deterministicAction, rlAction, multiplier)
+	return projectedHourlySavingsDeltaProxyUSD(state, activeAction, shadowAction, multiplier)
 }
 
 func (c *Controller) shadowGuardrailBlock(
@@ -100,12 +165,12 @@ func (c *Controller) shadowGuardrailBlock(
 	if !ok {
 		return "", false
 	}
-	node, err := c.k8s.CoreV1().Nodes().Get(ctx, nodeID, metav1.GetOptions{})
+	node, err := c.getNode(ctx, nodeID)
 	if err != nil {
 		return "", false
 	}
 
-	checker := NewGuardrailChecker(c.k8s, c.logger, 0)
+	checker := NewGuardrailChecker(c.k8s, c.logger, 0).WithCache(c.cache)
 	result, err := checker.Check(ctx, node, action, NodeState{
 		NodeName:           nodeID,
 		InstanceType:       "",
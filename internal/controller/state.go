@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// StateStore persists the controller checkpoint. The state package provides
+// ConfigMap (default), Lease annotation and local file backends.
+type StateStore interface {
+	Save(ctx context.Context, data []byte) error
+	Load(ctx context.Context) ([]byte, error)
+}
+
+// stateSchemaVersion is bumped whenever persistedState changes shape.
+// Checkpoints from newer agents are ignored rather than half-applied;
+// unversioned (0) checkpoints carry the same fields as version 1.
+const stateSchemaVersion = 1
+
+// stateRestoreTimeout bounds the checkpoint read in New.
+const stateRestoreTimeout = 10 * time.Second
+
+// persistedState is the checkpoint written after each leader tick and read
+// on startup and by followers: what a fresh process needs so it does not
+// reset cooldowns, target ratios, or the TFT price history window.
+type persistedState struct {
+	Version          int                  `json:"version"`
+	TargetSpotRatio  map[string]float64   `json:"target_spot_ratio,omitempty"`
+	LastMigration    map[string]time.Time `json:"last_migration,omitempty"`
+	LastWeightChange map[string]time.Time `json:"last_weight_change,omitempty"`
+	PriceHistory     map[string][]float64 `json:"price_history,omitempty"`
+}
+
+func (c *Controller) snapshotState() persistedState {
+	c.historyLock.Lock()
+	defer c.historyLock.Unlock()
+
+	ps := persistedState{
+		Version:          stateSchemaVersion,
+		TargetSpotRatio:  make(map[string]float64, len(c.targetSpotRatio)),
+		LastMigration:    make(map[string]time.Time, len(c.lastMigration)),
+		LastWeightChange: make(map[string]time.Time, len(c.lastWeightChange)),
+		PriceHistory:     make(map[string][]float64, len(c.priceHistory)),
+	}
+	for k, v := range c.targetSpotRatio {
+		ps.TargetSpotRatio[k] = v
+	}
+	for k, v := range c.lastMigration {
+		ps.LastMigration[k] = v
+	}
+	for k, v := range c.lastWeightChange {
+		ps.LastWeightChange[k] = v
+	}
+	for k, v := range c.priceHistory {
+		ps.PriceHistory[k] = append([]float64(nil), v...)
+	}
+	return ps
+}
+
+// mergeState adopts a checkpoint. Target ratios are the leader's decisions
+// and replace local values; timestamps keep the later of the two; price
+// history keeps whichever window is longer.
+func (c *Controller) mergeState(ps persistedState) {
+	c.historyLock.Lock()
+	defer c.historyLock.Unlock()
+
+	if c.targetSpotRatio == nil {
+		c.targetSpotRatio = make(map[string]float64)
+	}
+	if c.lastMigration == nil {
+		c.lastMigration = make(map[string]time.Time)
+	}
+	if c.lastWeightChange == nil {
+		c.lastWeightChange = make(map[string]time.Time)
+	}
+	if c.priceHistory == nil {
+		c.priceHistory = make(map[string][]float64)
+	}
+
+	for k, v := range ps.TargetSpotRatio {
+		if v < 0 || v > 1 {
+			continue
+		}
+		c.targetSpotRatio[k] = v
+	}
+	for k, v := range ps.LastMigration {
+		if v.After(c.lastMigration[k]) {
+			c.lastMigration[k] = v
+		}
+	}
+	for k, v := range ps.LastWeightChange {
+		if v.After(c.lastWeightChange[k]) {
+			c.lastWeightChange[k] = v
+		}
+	}
+	for k, v := range ps.PriceHistory {
+		if len(v) > len(c.priceHistory[k]) {
+			c.priceHistory[k] = append([]float64(nil), v...)
+		}
+	}
+}
+
+// decodeState parses a checkpoint. ok is false for checkpoints this agent
+// cannot apply (undecodable or written by a newer schema).
+func decodeState(data []byte) (ps persistedState, ok bool, reason string) {
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return persistedState{}, false, "undecodable: " + err.Error()
+	}
+	if header.Version > stateSchemaVersion {
+		return persistedState{}, false, "written by a newer schema version"
+	}
+	if err := json.Unmarshal(data, &ps); err != nil {
+		return persistedState{}, false, "undecodable: " + err.Error()
+	}
+	ps.Version = stateSchemaVersion
+	return ps, true, ""
+}
+
+// saveState checkpoints the leader's state. Failures are logged; the next
+// tick retries.
+func (c *Controller) saveState(ctx context.Context) {
+	if c.stateStore == nil || !c.IsLeading() {
+		return
+	}
+	data, err := json.Marshal(c.snapshotState())
+	if err != nil {
+		c.logger.Warn("failed to encode controller state", "error", err)
+		return
+	}
+	if err := c.stateStore.Save(ctx, data); err != nil {
+		c.logger.Warn("failed to checkpoint controller state", "error", err)
+	}
+}
+
+// restoreState merges the last checkpoint. A missing, unreadable or
+// incompatible checkpoint leaves the in-memory state as is.
+func (c *Controller) restoreState(ctx context.Context) bool {
+	if c.stateStore == nil {
+		return false
+	}
+	data, err := c.stateStore.Load(ctx)
+	if err != nil {
+		c.logger.Warn("failed to load controller state", "error", err)
+		return false
+	}
+	if len(data) == 0 {
+		return false
+	}
+	ps, ok, reason := decodeState(data)
+	if !ok {
+		c.logger.Warn("ignoring controller state checkpoint", "reason", reason, "schema_version", stateSchemaVersion)
+		return false
+	}
+	c.mergeState(ps)
+	return true
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestNew_RestoresStateCheckpoint(t *testing.T) {
+	poolID := "m5.large:us-east-1a"
+	migratedAt := time.Now().Add(-2 * time.Minute).UTC().Truncate(time.Second)
+	data, _ := json.Marshal(persistedState{
+		Version:          stateSchemaVersion,
+		TargetSpotRatio:  map[string]float64{poolID: 0.6, "bogus": 1.7},
+		LastMigration:    map[string]time.Time{poolID: migratedAt},
+		LastWeightChange: map[string]time.Time{"workers:us-east-1a": migratedAt},
+		PriceHistory:     map[string][]float64{poolID: {0.2, 0.21}},
+	})
+
+	ctrl := newLeadershipTestController(t, &memoryStateStore{data: data}, false)
+
+	if got := ctrl.targetSpotRatio[poolID]; got != 0.6 {
+		t.Fatalf("restored target ratio=%v, want 0.6", got)
+	}
+	if _, ok := ctrl.targetSpotRatio["bogus"]; ok {
+		t.Fatal("out-of-range target ratio should not be restored")
+	}
+	if !ctrl.lastMigration[poolID].Equal(migratedAt) {
+		t.Fatalf("restored migration=%v, want %v", ctrl.lastMigration[poolID], migratedAt)
+	}
+	if !ctrl.lastWeightChange["workers:us-east-1a"].Equal(migratedAt) {
+		t.Fatal("weight-change cooldown was not restored")
+	}
+	if len(ctrl.priceHistory[poolID]) != 2 {
+		t.Fatalf("restored price history=%v", ctrl.priceHistory[poolID])
+	}
+}
+
+func TestDecodeState(t *testing.T) {
+	if _, ok, _ := decodeState([]byte(`{"version":99,"target_spot_ratio":{"a":0.5}}`)); ok {
+		t.Fatal("checkpoint from a newer schema should be ignored")
+	}
+	if _, ok, _ := decodeState([]byte(`not json`)); ok {
+		t.Fatal("undecodable checkpoint should be ignored")
+	}
+	ps, ok, reason := decodeState([]byte(`{"target_spot_ratio":{"a":0.5}}`))
+	if !ok {
+		t.Fatalf("unversioned checkpoint rejected: %s", reason)
+	}
+	if ps.Version != stateSchemaVersion || ps.TargetSpotRatio["a"] != 0.5 {
+		t.Fatalf("unexpected decoded state: %+v", ps)
+	}
+}
+
+func TestSaveState_RoundTrip(t *testing.T) {
+	store := &memoryStateStore{}
+	now := time.Now().UTC().Truncate(time.Second)
+	src := &Controller{
+		logger:           slog.Default(),
+		stateStore:       store,
+		targetSpotRatio:  map[string]float64{"p": 0.3},
+		lastMigration:    map[string]time.Time{"p": now},
+		lastWeightChange: map[string]time.Time{},
+		priceHistory:     map[string][]float64{"p": {0.1, 0.2, 0.3}},
+	}
+	src.saveState(context.Background())
+	if len(store.data) == 0 {
+		t.Fatal("leader did not save state")
+	}
+
+	dst := &Controller{
+		logger:          slog.Default(),
+		stateStore:      store,
+		lastMigration:   map[string]time.Time{"p": now.Add(time.Minute)},
+		priceHistory:    map[string][]float64{"p": {0.1, 0.2, 0.3, 0.4}},
+		targetSpotRatio: map[string]float64{"p": 0.9},
+	}
+	if !dst.restoreState(context.Background()) {
+		t.Fatal("restoreState returned false")
+	}
+	if dst.targetSpotRatio["p"] != 0.3 {
+		t.Fatalf("target ratio=%v, want checkpoint value 0.3", dst.targetSpotRatio["p"])
+	}
+	if !dst.lastMigration["p"].Equal(now.Add(time.Minute)) {
+		t.Fatal("restore should keep the later migration timestamp")
+	}
+	if len(dst.priceHistory["p"]) != 4 {
+		t.Fatal("restore should keep the longer price history")
+	}
+
+	src.following.Store(true)
+	store.data = nil
+	src.saveState(context.Background())
+	if store.data != nil {
+		t.Fatal("follower must not write the checkpoint")
+	}
+}
@@ -10,7 +10,6 @@ import (
 
 	"github.com/softcane/spot-vortex-agent/internal/capacity"
 	"github.com/softcane/spot-vortex-agent/internal/metrics"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type nodeInfo struct {
@@ -22,18 +21,19 @@ type nodeInfo struct {
 	zone         string
 	instanceType string
 	workloadPool string // spotvortex.io/pool label for extended pool ID
+	labels       map[string]string
 }
 
 func (c *Controller) nodeInfoMap(ctx context.Context) (map[string]nodeInfo, error) {
 	if c.k8s == nil {
 		return nil, fmt.Errorf("k8s client required")
 	}
-	nodes, err := c.k8s.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	nodes, err := c.listNodes(ctx)
 	if err != nil {
 		return nil, err
 	}
-	info := make(map[string]nodeInfo, len(nodes.Items))
-	for _, node := range nodes.Items {
+	info := make(map[string]nodeInfo, len(nodes))
+	for _, node := range nodes {
 		labels := node.Labels
 		isFake := false
 		for _, taint := range node.Spec.Taints {
@@ -64,6 +64,7 @@ func (c *Controller) nodeInfoMap(ctx context.Context) (map[string]nodeInfo, erro
 			zone:         labelValue(labels, "topology.kubernetes.io/zone", "unknown"),
 			instanceType: labelValue(labels, "node.kubernetes.io/instance-type", "unknown"),
 			workloadPool: labelValue(labels, "spotvortex.io/pool", ""),
+			labels:       labels,
 		}
 		info[node.Name] = base
 		for _, addr := range node.Status.Addresses {
@@ -86,14 +87,14 @@ func (c *Controller) syntheticNodeMetrics(ctx context.Context) ([]metrics.NodeMe
 		c.metric = newMetricSynth(time.Now().UnixNano() + 2)
 	}
 
-	nodes, err := c.k8s.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	nodes, err := c.listNodes(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	now := time.Now()
-	results := make([]metrics.NodeMetrics, 0, len(nodes.Items))
-	for _, node := range nodes.Items {
+	results := make([]metrics.NodeMetrics, 0, len(nodes))
+	for _, node := range nodes {
 		labels := node.Labels
 
 		cpu, mem := c.metric.Next(node.Name)
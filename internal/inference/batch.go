@@ -0,0 +1,61 @@
+package inference
+
+import "context"
+
+// BatchItem is one scope scored by PredictBatch.
+type BatchItem struct {
+	NodeID string // price-history scope, as for PredictDetailed
+	State  NodeState
+}
+
+// BatchResult is the PredictDetailed result for one BatchItem.
+type BatchResult struct {
+	Action        Action
+	CapacityScore float32
+	RuntimeScore  float32
+	Confidence    float32
+	Err           error
+}
+
+// PredictBatch scores items like PredictDetailed, but without allocating
+// tensors per item: the shipped TFT has a fixed batch of one, so each scope
+// runs through one reused [1, TFTHistorySteps, TFTFeatureCount] tensor, and
+// the RL policy then runs once over a [len(items), RLFeatureCount] tensor.
+// Results are in item order; per-item failures are reported in
+// BatchResult.Err, as PredictDetailed would return them.
+//
+// If the RL model rejects a batched run, the engine warns once and scores
+// items one at a time until its bundle is swapped. Fallback engines
+// always score one at a time.
+func (e *InferenceEngine) PredictBatch(ctx context.Context, items []BatchItem, riskMultiplier float64) []BatchResult {
+	results := make([]BatchResult, len(items))
+	if len(items) == 0 || e.predictBatched(items, riskMultiplier, results) {
+		return results
+	}
+	for i, item := range items {
+		r := &results[i]
+		r.Action, r.CapacityScore, r.RuntimeScore, r.Confidence, r.Err = e.PredictDetailed(ctx, item.NodeID, item.State, riskMultiplier)
+	}
+	return results
+}
+
+// predictBatched fills results from one batched run and reports whether it
+// could.
+func (e *InferenceEngine) predictBatched(items []BatchItem, riskMultiplier float64, results []BatchResult) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.tftModel == nil || e.rlModel == nil || e.batchUnsupported {
+		return false
+	}
+	if err := e.runBatch(items, riskMultiplier, results); err != nil {
+		e.batchUnsupported = true
+		e.batch.destroy()
+		e.batch = nil
+		e.logger.Warn("batched inference failed; scoring scopes one at a time",
+			"rows", len(items),
+			"error", err,
+		)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,190 @@
+//go:build cgo
+
+package inference
+
+import (
+	"fmt"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// batchTensors are PredictBatch's reusable tensors. The TFT tensors hold one
+// scope and live as long as the engine's models; the RL tensors are reused
+// while the batch size stays the same. Outputs start nil and are allocated
+// by ONNX Runtime on the first run.
+type batchTensors struct {
+	rows   int
+	tftIn  *ort.Tensor[float32]
+	rlIn   *ort.Tensor[float32]
+	tftOut []ort.Value
+	rlOut  []ort.Value
+}
+
+func (b *batchTensors) destroy() {
+	if b == nil {
+		return
+	}
+	if b.tftIn != nil {
+		b.tftIn.Destroy()
+	}
+	if b.rlIn != nil {
+		b.rlIn.Destroy()
+	}
+	destroyValues(b.tftOut)
+	destroyValues(b.rlOut)
+}
+
+func destroyValues(values []ort.Value) {
+	for _, v := range values {
+		if v != nil {
+			v.Destroy()
+		}
+	}
+}
+
+// runBatch runs the TFT per item and the RL model once over items. It returns
+// an error only when the batched RL run fails, before any price history is
+// recorded, so the caller can score the items one at a time instead. Callers
+// hold e.mu.
+func (e *InferenceEngine) runBatch(items []BatchItem, riskMultiplier float64, results []BatchResult) error {
+	rows := len(items)
+	buf, err := e.batchTensorsFor(rows)
+	if err != nil {
+		return err
+	}
+
+	// 1. TFT per scope through the reused single-row tensor; calibrate each
+	// row and build its RL state.
+	rlData := buf.rlIn.GetData()
+	for i, item := range items {
+		row := rlData[i*RLFeatureCount : (i+1)*RLFeatureCount]
+		rawScore, rawRuntimeScore, err := e.runTFT(buf, item)
+		if err != nil {
+			results[i] = BatchResult{Err: err}
+			clear(row)
+			continue
+		}
+		finalScore, runtimeScore := e.calibrate(float64(rawScore), float64(rawRuntimeScore), item.State, riskMultiplier)
+		state := item.State
+		state.RuntimeScore = runtimeScore
+		copy(row, e.builder.BuildRLInput(state, finalScore))
+		results[i].CapacityScore = float32(finalScore)
+		results[i].RuntimeScore = float32(runtimeScore)
+	}
+
+	// 2. One RL run; split Q-values per row.
+	if err := e.rlModel.Run([]ort.Value{buf.rlIn}, buf.rlOut); err != nil {
+		return fmt.Errorf("RL inference failed: %w", err)
+	}
+	for _, item := range items {
+		e.builder.UpdatePriceHistory(item.NodeID, item.State.SpotPrice)
+	}
+
+	qValues, width, qErr := batchQValues(e.rlModel.outputs, buf.rlOut, rows)
+	for i := range results {
+		r := &results[i]
+		if r.Err != nil {
+			continue
+		}
+		if qErr != nil {
+			r.Err = &RLFallbackError{
+				CapacityScore: r.CapacityScore,
+				RuntimeScore:  r.RuntimeScore,
+				Cause:         qErr,
+			}
+			continue
+		}
+		var maxQ float32
+		r.Action, maxQ = selectAction(qValues[i*width : (i+1)*width])
+		r.Confidence = qConfidence(maxQ)
+	}
+
+	e.logger.Debug("Batch inference complete", "rows", rows)
+	return nil
+}
+
+// runTFT scores one item through the reusable TFT tensors. Callers hold e.mu.
+func (e *InferenceEngine) runTFT(buf *batchTensors, item BatchItem) (float32, float32, error) {
+	copy(buf.tftIn.GetData(), e.builder.BuildTFTInput(item.NodeID, item.State))
+	if err := e.tftModel.Run([]ort.Value{buf.tftIn}, buf.tftOut); err != nil {
+		return 0, 0, fmt.Errorf("TFT inference failed: %w", err)
+	}
+	outputs, err := tensorMap(e.tftModel.outputs, buf.tftOut)
+	if err != nil {
+		return 0, 0, fmt.Errorf("TFT inference failed: %w", err)
+	}
+	return extractRiskScores(outputs)
+}
+
+// batchTensorsFor returns the single-row TFT tensor and an RL tensor sized
+// for rows, reusing the previous batch's RL tensor when the size matches.
+// Callers hold e.mu.
+func (e *InferenceEngine) batchTensorsFor(rows int) (*batchTensors, error) {
+	if e.batch == nil {
+		tftIn, err := ort.NewEmptyTensor[float32](ort.NewShape(1, TFTHistorySteps, TFTFeatureCount))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create TFT tensor: %w", err)
+		}
+		e.batch = &batchTensors{
+			tftIn:  tftIn,
+			tftOut: make([]ort.Value, len(e.tftModel.outputs)),
+		}
+	}
+	b := e.batch
+	if b.rlIn != nil && b.rows == rows {
+		return b, nil
+	}
+
+	if b.rlIn != nil {
+		b.rlIn.Destroy()
+	}
+	destroyValues(b.rlOut)
+	b.rows, b.rlIn, b.rlOut = 0, nil, nil
+	rlIn, err := ort.NewEmptyTensor[float32](ort.NewShape(int64(rows), RLFeatureCount))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RL batch tensor: %w", err)
+	}
+	b.rows = rows
+	b.rlIn = rlIn
+	b.rlOut = make([]ort.Value, len(e.rlModel.outputs))
+	return b, nil
+}
+
+// tensorMap keys a run's float32 outputs by name.
+func tensorMap(names []string, values []ort.Value) (map[string]*ort.Tensor[float32], error) {
+	result := make(map[string]*ort.Tensor[float32], len(names))
+	for i, name := range names {
+		t, ok := values[i].(*ort.Tensor[float32])
+		if !ok {
+			return nil, fmt.Errorf("unexpected output type for %s", name)
+		}
+		result[name] = t
+	}
+	return result, nil
+}
+
+// batchQValues returns the [rows, width] q_values of a batched RL run.
+func batchQValues(names []string, values []ort.Value, rows int) ([]float32, int, error) {
+	outputs, err := tensorMap(names, values)
+	if err != nil {
+		return nil, 0, fmt.Errorf("RL inference failed: %w", err)
+	}
+	qTensor, ok := outputs["q_values"]
+	if !ok || qTensor == nil {
+		return nil, 0, fmt.Errorf("RL inference failed: missing q_values output")
+	}
+	return splitQValues(qTensor.GetData(), qTensor.GetShape(), rows)
+}
+
+// splitQValues checks that data holds rows rows of Q-values and returns the
+// row width.
+func splitQValues(data []float32, shape ort.Shape, rows int) ([]float32, int, error) {
+	if len(shape) == 0 || shape[len(shape)-1] <= 0 {
+		return nil, 0, fmt.Errorf("RL inference failed: unexpected q_values shape %v", shape)
+	}
+	width := int(shape[len(shape)-1])
+	if len(data) < rows*width {
+		return nil, 0, fmt.Errorf("RL inference failed: q_values shape %v holds fewer than %d rows", shape, rows)
+	}
+	return data, width, nil
+}
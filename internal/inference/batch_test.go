@@ -0,0 +1,198 @@
+//go:build cgo
+
+package inference
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+func TestRiskScoreAt_SplitsBatchRows(t *testing.T) {
+	// [2, 8, 3] quantile head: row r, lead step 6, median quantile.
+	quantiles := make([]float32, 2*8*3)
+	quantiles[0*24+6*3+1] = 0.2
+	quantiles[1*24+6*3+1] = 0.7
+	for row, want := range []float32{0.2, 0.7} {
+		got, err := riskScoreAt(quantiles, ort.NewShape(2, 8, 3), row)
+		if err != nil || got != want {
+			t.Fatalf("3-D row %d = %v, %v; want %v", row, got, err, want)
+		}
+	}
+
+	// [3, 3] head: the middle column of each row.
+	columns := []float32{0, 0.1, 0, 0, 0.5, 0, 0, 0.9, 0}
+	for row, want := range []float32{0.1, 0.5, 0.9} {
+		got, err := riskScoreAt(columns, ort.NewShape(3, 3), row)
+		if err != nil || got != want {
+			t.Fatalf("2-D row %d = %v, %v; want %v", row, got, err, want)
+		}
+	}
+
+	if _, err := riskScoreAt([]float32{0.3}, ort.NewShape(1), 1); err == nil {
+		t.Fatal("expected an error for a row past the batch")
+	}
+}
+
+func TestSplitQValues_SelectsActionPerRow(t *testing.T) {
+	data := []float32{
+		0, 1, 0, 0, 0, 0, // DECREASE_10
+		0, 0, 0, 0, 0, 5, // EMERGENCY_EXIT
+	}
+	q, width, err := splitQValues(data, ort.NewShape(2, 6), 2)
+	if err != nil || width != 6 {
+		t.Fatalf("splitQValues = width %d, %v; want 6", width, err)
+	}
+	for row, want := range []Action{ActionDecrease10, ActionEmergencyExit} {
+		if got, _ := selectAction(q[row*width : (row+1)*width]); got != want {
+			t.Fatalf("row %d action=%s, want %s", row, ActionToString(got), ActionToString(want))
+		}
+	}
+	if _, _, err := splitQValues(data, ort.NewShape(2, 6), 3); err == nil {
+		t.Fatal("expected an error when q_values hold fewer rows than the batch")
+	}
+}
+
+// Fallback-surrogate engines score one item at a time; the batched ONNX path
+// is covered by TestPredictBatch_MatchesPredictDetailedOnONNXRuntime.
+func TestPredictBatch_SurrogateScoresOneAtATime(t *testing.T) {
+	withoutORT(t)
+	engine, err := NewInferenceEngine(writeFallbackBundle(t, true))
+	if err != nil {
+		t.Fatalf("NewInferenceEngine: %v", err)
+	}
+
+	items := []BatchItem{
+		{NodeID: "pool-a", State: NodeState{SpotPrice: 0.3, OnDemandPrice: 1.0, Timestamp: time.Unix(1700000000, 0).UTC()}},
+		{NodeID: "pool-b", State: NodeState{SpotPrice: 0.4, OnDemandPrice: 1.0, Timestamp: time.Unix(1700000000, 0).UTC()}},
+	}
+	results := engine.PredictBatch(context.Background(), items, 1.5)
+	if len(results) != len(items) {
+		t.Fatalf("got %d results, want %d", len(results), len(items))
+	}
+	for i, item := range items {
+		action, capacity, runtime, confidence, err := engine.PredictDetailed(context.Background(), item.NodeID, item.State, 1.5)
+		r := results[i]
+		if r.Action != action || r.CapacityScore != capacity || r.RuntimeScore != runtime || r.Confidence != confidence {
+			t.Fatalf("%s batch=%+v, want PredictDetailed's %v %v %v %v", item.NodeID, r, action, capacity, runtime, confidence)
+		}
+		if _, ok := AsRLFallbackError(r.Err); !ok || err == nil {
+			t.Fatalf("%s err=%v, want the surrogate RL fallback", item.NodeID, r.Err)
+		}
+	}
+}
+
+func TestPredictBatch_MatchesPredictDetailedOnONNXRuntime(t *testing.T) {
+	engine := loadShippedEngine(t)
+	items := benchmarkItems()[:16]
+	ctx := context.Background()
+
+	results := engine.PredictBatch(ctx, items, 1.5)
+	if engine.batchUnsupported {
+		t.Fatal("shipped models rejected the batched run")
+	}
+	const tolerance = 1e-4
+	for i, item := range items {
+		action, capacity, runtime, confidence, err := engine.PredictDetailed(ctx, item.NodeID, item.State, 1.5)
+		r := results[i]
+		if (r.Err == nil) != (err == nil) {
+			t.Fatalf("%s batch err=%v, PredictDetailed err=%v", item.NodeID, r.Err, err)
+		}
+		if r.Action != action {
+			t.Errorf("%s batch action=%s, want %s", item.NodeID, ActionToString(r.Action), ActionToString(action))
+		}
+		if math.Abs(float64(r.CapacityScore-capacity)) > tolerance ||
+			math.Abs(float64(r.RuntimeScore-runtime)) > tolerance ||
+			math.Abs(float64(r.Confidence-confidence)) > tolerance {
+			t.Errorf("%s batch=%+v, want PredictDetailed's %v %v %v", item.NodeID, r, capacity, runtime, confidence)
+		}
+	}
+}
+
+// benchmarkPools is the pool count of a large cluster's reconcile tick.
+const benchmarkPools = 200
+
+// loadShippedEngine loads the shipped models on ONNX Runtime, skipping when
+// either is unavailable.
+func loadShippedEngine(b testing.TB) *InferenceEngine {
+	b.Helper()
+	modelsDir := filepath.Clean(filepath.Join("..", "..", "models"))
+	engine, err := NewInferenceEngine(EngineConfig{
+		TFTModelPath:        filepath.Join(modelsDir, "tft.onnx"),
+		RLModelPath:         filepath.Join(modelsDir, "rl_policy.onnx"),
+		PySRCalibrationPath: filepath.Join(modelsDir, "pysr", "calibration_equation.txt"),
+		PySRFusionPath:      filepath.Join(modelsDir, "pysr", "context_equation.txt"),
+		ModelManifestPath:   filepath.Join(modelsDir, "MODEL_MANIFEST.json"),
+		Logger:              slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	if err != nil {
+		b.Skipf("shipped models unavailable: %v", err)
+	}
+	if engine.fallback {
+		b.Skip("ONNX Runtime unavailable: engine serves the fallback estimator")
+	}
+	b.Cleanup(engine.release)
+	return engine
+}
+
+func benchmarkItems() []BatchItem {
+	history := []float64{0.31, 0.32, 0.30, 0.29, 0.31, 0.33, 0.34, 0.33, 0.32, 0.31, 0.30, 0.29}
+	items := make([]BatchItem, benchmarkPools)
+	for i := range items {
+		items[i] = BatchItem{
+			NodeID: fmt.Sprintf("pool-%03d", i),
+			State: NodeState{
+				SpotPrice:          0.30 + float64(i%10)*0.01,
+				OnDemandPrice:      0.97,
+				PriceHistory:       history,
+				CPUUsage:           0.5,
+				MemoryUsage:        0.5,
+				PodStartupTime:     30,
+				ClusterUtilization: 0.6,
+				OutagePenaltyHours: 1,
+				IsSpot:             true,
+				CurrentSpotRatio:   0.6,
+				TargetSpotRatio:    0.6,
+				Timestamp:          time.Unix(1700000000, 0).UTC(),
+			},
+		}
+	}
+	return items
+}
+
+// BenchmarkPredictDetailedPerPool is the per-pool baseline for
+// BenchmarkPredictBatch: one TFT and one RL run per pool per tick.
+func BenchmarkPredictDetailedPerPool(b *testing.B) {
+	engine := loadShippedEngine(b)
+	items := benchmarkItems()
+	ctx := context.Background()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, item := range items {
+			_, _, _, _, _ = engine.PredictDetailed(ctx, item.NodeID, item.State, 1.0)
+		}
+	}
+}
+
+// BenchmarkPredictBatch scores the same pools with reused TFT tensors and one
+// RL run per tick.
+func BenchmarkPredictBatch(b *testing.B) {
+	engine := loadShippedEngine(b)
+	items := benchmarkItems()
+	ctx := context.Background()
+	engine.PredictBatch(ctx, items, 1.0) // allocate the reusable tensors
+	if engine.batchUnsupported {
+		b.Fatal("shipped models rejected the batched run")
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		engine.PredictBatch(ctx, items, 1.0)
+	}
+}
@@ -0,0 +1,456 @@
+package inference
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
+)
+
+// Bundle file names expected in every subdirectory of a watched bundle
+// directory.
+const (
+	BundleTFTFile      = "tft.onnx"
+	BundleRLFile       = "rl_policy.onnx"
+	BundleManifestFile = "MODEL_MANIFEST.json"
+)
+
+// BundlePhase is where the bundle manager is in a rollout.
+type BundlePhase string
+
+const (
+	// BundlePhaseIdle serves the active bundle with nothing staged.
+	BundlePhaseIdle BundlePhase = "idle"
+	// BundlePhaseShadow runs a candidate in shadow next to the active bundle.
+	BundlePhaseShadow BundlePhase = "shadow"
+	// BundlePhaseProbation serves a freshly promoted bundle with the previous
+	// one kept in shadow for rollback.
+	BundlePhaseProbation BundlePhase = "probation"
+)
+
+// BundleManagerConfig configures hot swapping of the active model bundle.
+type BundleManagerConfig struct {
+	// Engine is the active engine. Promotions and rollbacks swap its bundle in
+	// place, so holders of the pointer always see the serving bundle.
+	Engine *InferenceEngine
+	// Dir is watched for bundles, one per subdirectory.
+	Dir          string
+	PollInterval time.Duration
+	// ShadowTicks is how many ticks with decisions a candidate runs in shadow
+	// before promotion; ProbationTicks how many ticks a promoted bundle is
+	// watched before the previous one is released.
+	ShadowTicks    int
+	ProbationTicks int
+	// MaxDisagreementRatio and MaxErrorRatio bound the share of decisions
+	// where the two bundles disagreed, or the bundle under test failed.
+	MaxDisagreementRatio float64
+	MaxErrorRatio        float64
+	ExpectedCloud        string
+	Logger               *slog.Logger
+	// Load validates and loads a bundle directory. Defaults to
+	// NewInferenceEngine with manifest checksums and output contracts
+	// enforced.
+	Load func(dir string) (*InferenceEngine, error)
+}
+
+// BundleStatus is the bundle manager's current rollout state.
+type BundleStatus struct {
+	Active    string      `json:"active"`
+	Phase     BundlePhase `json:"phase"`
+	Staged    string      `json:"staged,omitempty"`
+	Dir       string      `json:"dir,omitempty"`
+	Ticks     int         `json:"ticks"`
+	Compared  int         `json:"compared"`
+	Disagreed int         `json:"disagreed"`
+	Attempts  int         `json:"attempts"`
+	Failed    int         `json:"failed"`
+}
+
+// BundleManager watches a bundle directory and rolls new bundles out through
+// shadow, promotion and probation, rolling back on excess disagreement or
+// inference errors.
+type BundleManager struct {
+	cfg    BundleManagerConfig
+	engine *InferenceEngine
+	load   func(dir string) (*InferenceEngine, error)
+	logger *slog.Logger
+
+	mu sync.Mutex
+	// tried holds directories already considered (rejected, rolled back or
+	// matching the active version), keyed to the manifest mtime seen; a
+	// rewritten manifest is tried again.
+	tried map[string]time.Time
+	// floor is the manifest mtime of the newest bundle considered so far.
+	// Older bundles are never staged, so rejecting or rolling back a bundle
+	// cannot fall back to an earlier one and downgrade the model.
+	floor time.Time
+	phase BundlePhase
+	// other is the candidate while in shadow, and the previous bundle while
+	// in probation.
+	other        *InferenceEngine
+	otherVersion string
+	otherDir     string
+	otherMTime   time.Time
+
+	ticks     int
+	tickSeen  bool
+	compared  int
+	disagreed int
+	attempts  int
+	failed    int
+}
+
+// NewBundleManager creates a manager for the active engine.
+func NewBundleManager(cfg BundleManagerConfig) (*BundleManager, error) {
+	if cfg.Engine == nil {
+		return nil, fmt.Errorf("bundle manager requires an active engine")
+	}
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("bundle manager requires a bundle directory")
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Minute
+	}
+	if cfg.ShadowTicks <= 0 {
+		cfg.ShadowTicks = 1
+	}
+	if cfg.ProbationTicks <= 0 {
+		cfg.ProbationTicks = cfg.ShadowTicks
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	m := &BundleManager{
+		cfg:    cfg,
+		engine: cfg.Engine,
+		load:   cfg.Load,
+		logger: logger,
+		tried:  make(map[string]time.Time),
+		phase:  BundlePhaseIdle,
+	}
+	if m.load == nil {
+		m.load = m.loadEngine
+	}
+	metrics.SetActiveBundleVersion(m.engine.Version())
+	return m, nil
+}
+
+func (m *BundleManager) loadEngine(dir string) (*InferenceEngine, error) {
+	return NewInferenceEngine(EngineConfig{
+		TFTModelPath:         filepath.Join(dir, BundleTFTFile),
+		RLModelPath:          filepath.Join(dir, BundleRLFile),
+		ModelManifestPath:    filepath.Join(dir, BundleManifestFile),
+		ExpectedCloud:        m.cfg.ExpectedCloud,
+		RequireModelContract: true,
+		Logger:               m.logger.With("bundle_dir", dir),
+	})
+}
+
+// Run polls the bundle directory until ctx is cancelled.
+func (m *BundleManager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := m.Poll(); err != nil {
+			m.logger.Warn("bundle directory poll failed", "dir", m.cfg.Dir, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll stages the newest untried bundle when no rollout is in progress.
+// Bundles older than one already considered are ignored.
+func (m *BundleManager) Poll() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.phase != BundlePhaseIdle {
+		return nil
+	}
+
+	dir, mtime, err := m.newestBundle()
+	if err != nil || dir == "" {
+		return err
+	}
+	m.floor = mtime
+	contract, err := LoadModelContract(filepath.Join(dir, BundleManifestFile))
+	if err != nil {
+		m.reject(dir, mtime, "", err)
+		return nil
+	}
+	version := ""
+	if contract != nil {
+		version = contract.Version
+	}
+	if version == m.engine.Version() {
+		m.tried[dir] = mtime
+		return nil
+	}
+
+	candidate, err := m.load(dir)
+	if err != nil {
+		m.reject(dir, mtime, version, err)
+		return nil
+	}
+	m.phase = BundlePhaseShadow
+	m.other, m.otherVersion, m.otherDir, m.otherMTime = candidate, version, dir, mtime
+	m.resetCounts()
+	metrics.BundleRolloutEvents.WithLabelValues(version, "staged").Inc()
+	m.logger.Info("staged model bundle in shadow",
+		"bundle_version", version,
+		"active_version", m.engine.Version(),
+		"dir", dir,
+		"shadow_ticks", m.cfg.ShadowTicks,
+	)
+	return nil
+}
+
+// newestBundle returns the untried bundle subdirectory with the most recent
+// manifest, if it is not older than the floor. Publishers should write the
+// manifest last.
+func (m *BundleManager) newestBundle() (string, time.Time, error) {
+	entries, err := os.ReadDir(m.cfg.Dir)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("read bundle directory %s: %w", m.cfg.Dir, err)
+	}
+	var newest string
+	var newestMTime time.Time
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(m.cfg.Dir, entry.Name())
+		info, err := os.Stat(filepath.Join(dir, BundleManifestFile))
+		if err != nil {
+			continue
+		}
+		mtime := info.ModTime()
+		if mtime.Before(m.floor) {
+			continue
+		}
+		if seen, ok := m.tried[dir]; ok && seen.Equal(mtime) {
+			continue
+		}
+		if newest == "" || mtime.After(newestMTime) {
+			newest, newestMTime = dir, mtime
+		}
+	}
+	return newest, newestMTime, nil
+}
+
+// Staged returns the bundle to evaluate next to the active one: the candidate
+// in shadow, or the previous bundle in probation.
+func (m *BundleManager) Staged() (string, Predictor, bool) {
+	if m == nil {
+		return "", nil, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.phase == BundlePhaseIdle {
+		return "", nil, false
+	}
+	return m.otherVersion, m.other, true
+}
+
+// RecordShadow records one decision of the staged bundle against the active
+// decision. Results for a bundle that is no longer staged are ignored.
+func (m *BundleManager) RecordShadow(version string, agree bool, err error) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.phase == BundlePhaseIdle || version != m.otherVersion {
+		return
+	}
+	m.tickSeen = true
+	outcome := "agree"
+	switch {
+	case err != nil:
+		outcome = "error"
+	case !agree:
+		outcome = "disagree"
+	}
+	metrics.BundleShadowDecisions.WithLabelValues(version, outcome).Inc()
+
+	if err == nil {
+		m.compared++
+		if !agree {
+			m.disagreed++
+		}
+	}
+	// In shadow the candidate is under test; in probation the previous
+	// bundle's own failures say nothing about the promoted one.
+	if m.phase == BundlePhaseShadow {
+		m.attempts++
+		if err != nil {
+			m.failed++
+		}
+	}
+}
+
+// RecordActive records one inference call of the active bundle. Only a
+// bundle in probation is judged on it.
+func (m *BundleManager) RecordActive(err error) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.phase != BundlePhaseProbation {
+		return
+	}
+	m.tickSeen = true
+	m.attempts++
+	if err != nil {
+		m.failed++
+	}
+}
+
+// EndTick closes an inference tick. Once the shadow or probation window has
+// seen enough ticks with decisions, the bundle under test is promoted,
+// released, rejected or rolled back.
+func (m *BundleManager) EndTick() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.phase == BundlePhaseIdle || !m.tickSeen {
+		return
+	}
+	m.tickSeen = false
+	m.ticks++
+
+	window := m.cfg.ShadowTicks
+	if m.phase == BundlePhaseProbation {
+		window = m.cfg.ProbationTicks
+	}
+	if m.ticks < window {
+		return
+	}
+
+	disagreement := ratio(m.disagreed, m.compared)
+	errorRate := ratio(m.failed, m.attempts)
+	healthy := disagreement <= m.cfg.MaxDisagreementRatio && errorRate <= m.cfg.MaxErrorRatio
+	reason := fmt.Errorf("disagreement ratio %.3f (max %.3f), error ratio %.3f (max %.3f)",
+		disagreement, m.cfg.MaxDisagreementRatio, errorRate, m.cfg.MaxErrorRatio)
+
+	switch {
+	case m.phase == BundlePhaseShadow && healthy:
+		m.promote()
+	case m.phase == BundlePhaseShadow:
+		m.other.release()
+		m.reject(m.otherDir, m.otherMTime, m.otherVersion, reason)
+		m.clear()
+	case healthy:
+		metrics.BundleRolloutEvents.WithLabelValues(m.otherVersion, "released").Inc()
+		m.logger.Info("model bundle passed probation; released previous bundle",
+			"bundle_version", m.engine.Version(),
+			"previous_version", m.otherVersion,
+		)
+		m.other.release()
+		m.clear()
+	default:
+		m.rollback(reason)
+	}
+}
+
+// promote swaps the candidate into the active engine and keeps the previous
+// bundle in shadow for probation.
+func (m *BundleManager) promote() {
+	previous := m.engine.Version()
+	m.engine.swap(m.other)
+	promoted := m.otherVersion
+	m.otherVersion = previous
+	m.phase = BundlePhaseProbation
+	m.resetCounts()
+	metrics.SetActiveBundleVersion(promoted)
+	metrics.BundleRolloutEvents.WithLabelValues(promoted, "promoted").Inc()
+	m.logger.Info("promoted model bundle",
+		"bundle_version", promoted,
+		"previous_version", previous,
+		"probation_ticks", m.cfg.ProbationTicks,
+	)
+}
+
+// rollback restores the previous bundle and rejects the promoted one.
+func (m *BundleManager) rollback(reason error) {
+	failed := m.engine.Version()
+	m.engine.swap(m.other)
+	m.other.release()
+	metrics.SetActiveBundleVersion(m.otherVersion)
+	metrics.BundleRolloutEvents.WithLabelValues(failed, "rolled_back").Inc()
+	m.tried[m.otherDir] = m.otherMTime
+	m.logger.Warn("rolled back model bundle",
+		"bundle_version", failed,
+		"restored_version", m.otherVersion,
+		"reason", reason,
+	)
+	m.clear()
+}
+
+func (m *BundleManager) reject(dir string, mtime time.Time, version string, reason error) {
+	m.tried[dir] = mtime
+	if version == "" {
+		version = metrics.UnknownBundleVersion
+	}
+	metrics.BundleRolloutEvents.WithLabelValues(version, "rejected").Inc()
+	m.logger.Warn("rejected model bundle", "bundle_version", version, "dir", dir, "reason", reason)
+}
+
+func (m *BundleManager) clear() {
+	m.phase = BundlePhaseIdle
+	m.other, m.otherVersion, m.otherDir, m.otherMTime = nil, "", "", time.Time{}
+	m.resetCounts()
+}
+
+func (m *BundleManager) resetCounts() {
+	m.ticks, m.tickSeen = 0, false
+	m.compared, m.disagreed, m.attempts, m.failed = 0, 0, 0, 0
+}
+
+// Status returns the current rollout state.
+func (m *BundleManager) Status() BundleStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return BundleStatus{
+		Active:    m.engine.Version(),
+		Phase:     m.phase,
+		Staged:    m.otherVersion,
+		Dir:       m.otherDir,
+		Ticks:     m.ticks,
+		Compared:  m.compared,
+		Disagreed: m.disagreed,
+		Attempts:  m.attempts,
+		Failed:    m.failed,
+	}
+}
+
+// Handler serves Status as JSON.
+func (m *BundleManager) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(m.Status())
+	})
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
@@ -0,0 +1,203 @@
+package inference
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
+)
+
+// writeBundle publishes a bundle directory whose manifest declares version.
+func writeBundle(t *testing.T, root, version string, mtime time.Time) {
+	t.Helper()
+	dir := filepath.Join(root, version)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir bundle: %v", err)
+	}
+	manifest := filepath.Join(dir, BundleManifestFile)
+	if err := os.WriteFile(manifest, []byte(fmt.Sprintf(`{"bundle_version": %q}`, version)), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	if err := os.Chtimes(manifest, mtime, mtime); err != nil {
+		t.Fatalf("chtimes manifest: %v", err)
+	}
+}
+
+// newTestBundleManager returns a manager whose loader builds model-less
+// engines from the manifest version, failing for versions in broken.
+func newTestBundleManager(t *testing.T, root string, broken ...string) (*BundleManager, *InferenceEngine, *int) {
+	t.Helper()
+	active := &InferenceEngine{scope: &ModelContract{Version: "v1"}}
+	loads := 0
+	m, err := NewBundleManager(BundleManagerConfig{
+		Engine:               active,
+		Dir:                  root,
+		ShadowTicks:          2,
+		ProbationTicks:       2,
+		MaxDisagreementRatio: 0.25,
+		MaxErrorRatio:        0.1,
+		Logger:               slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Load: func(dir string) (*InferenceEngine, error) {
+			loads++
+			version := filepath.Base(dir)
+			for _, b := range broken {
+				if b == version {
+					return nil, fmt.Errorf("checksum mismatch for %s", version)
+				}
+			}
+			return &InferenceEngine{scope: &ModelContract{Version: version}}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewBundleManager: %v", err)
+	}
+	return m, active, &loads
+}
+
+func TestBundleManager_PromotesThenRollsBackOnActiveErrors(t *testing.T) {
+	root := t.TempDir()
+	base := time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)
+	writeBundle(t, root, "v1", base)
+	writeBundle(t, root, "v2", base.Add(time.Hour))
+	m, active, _ := newTestBundleManager(t, root)
+
+	if err := m.Poll(); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	version, staged, ok := m.Staged()
+	if !ok || version != "v2" || staged == nil {
+		t.Fatalf("Staged() = %q, %v, %v; want v2 in shadow", version, staged, ok)
+	}
+
+	for tick := 0; tick < 2; tick++ {
+		m.RecordShadow("v2", true, nil)
+		m.RecordShadow("v2", tick == 0, nil) // 1 of 4 disagrees: at the limit
+		m.RecordShadow("stale", false, errors.New("ignored"))
+		m.EndTick()
+	}
+	if got := active.Version(); got != "v2" {
+		t.Fatalf("active version=%q, want v2 promoted", got)
+	}
+	if got := metrics.ActiveBundleVersion(); got != "v2" {
+		t.Fatalf("bundle_version label=%q, want v2", got)
+	}
+	if version, _, _ := m.Staged(); version != "v1" || m.Status().Phase != BundlePhaseProbation {
+		t.Fatalf("staged=%q phase=%s, want v1 kept in probation", version, m.Status().Phase)
+	}
+
+	// A tick without decisions does not count toward probation.
+	m.EndTick()
+	m.RecordActive(nil)
+	m.EndTick()
+	m.RecordActive(errors.New("TFT inference failed"))
+	m.EndTick()
+	if got := active.Version(); got != "v1" {
+		t.Fatalf("active version=%q, want rollback to v1 after errors", got)
+	}
+	if got := metrics.ActiveBundleVersion(); got != "v1" {
+		t.Fatalf("bundle_version label=%q, want v1", got)
+	}
+	if st := m.Status(); st.Phase != BundlePhaseIdle {
+		t.Fatalf("phase=%s, want idle after rollback", st.Phase)
+	}
+
+	if err := m.Poll(); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if _, _, ok := m.Staged(); ok {
+		t.Fatal("a rolled-back bundle must not be restaged")
+	}
+	writeBundle(t, root, "v2", base.Add(2*time.Hour))
+	if err := m.Poll(); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if version, _, ok := m.Staged(); !ok || version != "v2" {
+		t.Fatalf("Staged() = %q, %v; want a republished bundle restaged", version, ok)
+	}
+}
+
+func TestBundleManager_RejectsCandidates(t *testing.T) {
+	root := t.TempDir()
+	base := time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)
+	writeBundle(t, root, "v2", base)
+	writeBundle(t, root, "v3", base.Add(time.Hour))
+	m, active, loads := newTestBundleManager(t, root, "v3")
+
+	// v3 fails validation and is not retried; the older v2 is not staged in
+	// its place.
+	for i := 0; i < 2; i++ {
+		if err := m.Poll(); err != nil {
+			t.Fatalf("Poll: %v", err)
+		}
+		if _, _, ok := m.Staged(); ok {
+			t.Fatal("an invalid bundle, or one older than it, must not be staged")
+		}
+	}
+	if *loads != 1 {
+		t.Fatalf("loads=%d, want 1", *loads)
+	}
+
+	writeBundle(t, root, "v4", base.Add(2*time.Hour))
+	if err := m.Poll(); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if version, _, ok := m.Staged(); !ok || version != "v4" {
+		t.Fatalf("Staged() = %q, %v; want v4", version, ok)
+	}
+
+	for tick := 0; tick < 2; tick++ {
+		m.RecordShadow("v4", false, nil)
+		m.RecordShadow("v4", true, nil)
+		m.EndTick()
+	}
+	if got := active.Version(); got != "v1" {
+		t.Fatalf("active version=%q, want v1 kept after disagreement", got)
+	}
+	if st := m.Status(); st.Phase != BundlePhaseIdle || st.Staged != "" {
+		t.Fatalf("status=%+v, want candidate rejected", st)
+	}
+}
+
+func TestBundleManager_NeverStagesOlderBundleAfterPromotion(t *testing.T) {
+	root := t.TempDir()
+	base := time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)
+	writeBundle(t, root, "v0", base)
+	writeBundle(t, root, "v2", base.Add(time.Hour))
+	m, active, loads := newTestBundleManager(t, root)
+
+	if err := m.Poll(); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	for tick := 0; tick < 2; tick++ {
+		m.RecordShadow("v2", true, nil)
+		m.EndTick()
+	}
+	for tick := 0; tick < 2; tick++ {
+		m.RecordActive(nil)
+		m.RecordShadow("v1", true, nil)
+		m.EndTick()
+	}
+	if st := m.Status(); active.Version() != "v2" || st.Phase != BundlePhaseIdle {
+		t.Fatalf("active=%q phase=%s, want v2 released from probation", active.Version(), st.Phase)
+	}
+
+	// The promoted bundle's own directory matches the active version, and v0
+	// predates it: neither is staged.
+	for i := 0; i < 2; i++ {
+		if err := m.Poll(); err != nil {
+			t.Fatalf("Poll: %v", err)
+		}
+		if version, _, ok := m.Staged(); ok {
+			t.Fatalf("Staged() = %q after promotion, want nothing (no downgrade)", version)
+		}
+	}
+	if *loads != 1 {
+		t.Fatalf("loads=%d, want only v2 loaded", *loads)
+	}
+}
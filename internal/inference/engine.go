@@ -9,9 +9,6 @@ import (
 	"os"
 	"strings"
 	"sync"
-	"time"
-
-	ort "github.com/yalue/onnxruntime_go"
 )
 
 // Action represents the decision from the RL policy.
@@ -46,6 +43,12 @@ func ActionToString(a Action) string {
 	}
 }
 
+// Predictor scores one scope with a model bundle. InferenceEngine satisfies
+// it.
+type Predictor interface {
+	PredictDetailed(ctx context.Context, nodeID string, state NodeState, riskMultiplier float64) (Action, float32, float32, float32, error)
+}
+
 // InferenceEngine coordinates the TFT and RL models.
 type InferenceEngine struct {
 	mu     sync.RWMutex
@@ -56,6 +59,17 @@ type InferenceEngine struct {
 	pysr     *PySREngine
 	scope    *ModelContract
 
+	// fallback engines serve risk scores without ONNX Runtime, from the
+	// surrogate when the bundle declares one; see newFallbackEngine.
+	fallback   bool
+	surrogate  *Surrogate
+	riskMargin float64
+
+	// batch holds PredictBatch's reusable tensors; batchUnsupported is set
+	// once the RL model rejects a batched run.
+	batch            *batchTensors
+	batchUnsupported bool
+
 	builder *FeatureBuilder
 }
 
@@ -144,23 +158,6 @@ func NewInferenceEngine(cfg EngineConfig) (*InferenceEngine, error) {
 		}
 	}
 
-	// Initialize ONNX runtime
-	SetSharedLibraryPath()
-	if err := ort.InitializeEnvironment(); err != nil {
-		logger.Warn("ONNX Runtime already initialized or failed", "error", err)
-	}
-
-	// Load models using the shipped dual-head TFT contract.
-	tft, err := NewModel(cfg.TFTModelPath, []string{"input"}, []string{"capacity_score", "runtime_score"})
-	if err != nil {
-		return nil, fmt.Errorf("failed to load TFT model with outputs capacity_score,runtime_score: %w", err)
-	}
-
-	rl, err := NewModel(cfg.RLModelPath, []string{"state"}, []string{"q_values"})
-	if err != nil {
-		return nil, fmt.Errorf("failed to load RL model: %w", err)
-	}
-
 	pysrCalibPath := cfg.PySRCalibrationPath
 	if pysrCalibPath == "" {
 		pysrCalibPath = "models/pysr/calibration_equation.txt"
@@ -176,6 +173,25 @@ func NewInferenceEngine(cfg EngineConfig) (*InferenceEngine, error) {
 		pysrFusionPath,
 	)
 
+	// Initialize ONNX runtime
+	if err := initializeORT(); err != nil {
+		if scope == nil {
+			return nil, fmt.Errorf("ONNX Runtime unavailable and no model contract declares a fallback: %w", err)
+		}
+		return newFallbackEngine(cfg, manifestPath, scope, pysrEngine, logger, err)
+	}
+
+	// Load models using the shipped dual-head TFT contract.
+	tft, err := NewModel(cfg.TFTModelPath, []string{"input"}, []string{"capacity_score", "runtime_score"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TFT model with outputs capacity_score,runtime_score: %w", err)
+	}
+
+	rl, err := NewModel(cfg.RLModelPath, []string{"state"}, []string{"q_values"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load RL model: %w", err)
+	}
+
 	if scope != nil {
 		logger.Info("model contract loaded",
 			"manifest", manifestPath,
@@ -218,41 +234,52 @@ func (e *InferenceEngine) Predict(ctx context.Context, nodeID string, state Node
 func (e *InferenceEngine) PredictDetailed(ctx context.Context, nodeID string, state NodeState, riskMultiplier float64) (Action, float32, float32, float32, error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
-
-	// 1. Build TFT Input (TFTHistorySteps x TFTFeatureCount features)
-	// Note: We use the builder to maintain price history
-	e.builder.UpdatePriceHistory(nodeID, state.SpotPrice)
-	tftFeatures := e.builder.BuildTFTInput(nodeID, state)
-
-	tftInputShape := ort.NewShape(1, TFTHistorySteps, TFTFeatureCount)
-	tftInputTensor, err := ort.NewTensor(tftInputShape, tftFeatures)
-	if err != nil {
-		return ActionHold, 0, 0, 0, fmt.Errorf("failed to create TFT tensor: %w", err)
+	if e.tftModel == nil || e.rlModel == nil {
+		if e.fallback {
+			return e.predictFallback(nodeID, state, riskMultiplier)
+		}
+		// A bundle released after a rollout no longer serves.
+		return ActionHold, 0, 0, 0, fmt.Errorf("models not loaded")
 	}
-	defer tftInputTensor.Destroy()
 
-	// 2. Run TFT for Capacity Score (Risk Tracking)
-	tftInputs := map[string]*ort.Tensor[float32]{"input": tftInputTensor}
-	tftOutputs, err := e.tftModel.Predict(tftInputs)
-	if err != nil {
-		return ActionHold, 0, 0, 0, fmt.Errorf("TFT inference failed: %w", err)
+	return e.predictModels(nodeID, state, riskMultiplier)
+}
+
+// selectAction returns the argmax action of one row of Q-values and its value.
+func selectAction(qValues []float32) (Action, float32) {
+	bestAction := ActionHold
+	maxQ := qValues[0]
+	for i := 1; i < len(qValues); i++ {
+		if qValues[i] > maxQ {
+			maxQ = qValues[i]
+			bestAction = Action(i)
+		}
 	}
-	defer destroyTensorMap(tftOutputs)
+	return bestAction, maxQ
+}
 
-	rawScore, rawRuntimeScore, err := extractRiskScores(tftOutputs)
-	if err != nil {
-		return ActionHold, 0, 0, 0, err
+// qConfidence maps the best Q-value to a confidence.
+func qConfidence(maxQ float32) float32 {
+	// In DQN, we'll normalize maxQ to a 0-1 confidence proxy if needed
+	// For now, if maxQ > -100 (not total garbage), we call it confident
+	if maxQ < -1000 {
+		return 0.1
 	}
+	return 1.0
+}
 
+// calibrate applies the PySR calibration and fusion equations and the
+// runtime risk multiplier to the raw TFT heads.
+func (e *InferenceEngine) calibrate(rawScore, rawRuntime float64, state NodeState, riskMultiplier float64) (float64, float64) {
 	// 2.5 Apply PySR Symbolic Regression (Gap 3)
-	calibrationScore := float64(rawScore)
+	calibrationScore := rawScore
 	priceVolatility := 0.0
 	if len(state.PriceHistory) > 1 {
 		priceVolatility = calculateStdDev(state.PriceHistory)
 	}
 	if e.pysr != nil {
 		if score, ok := e.pysr.ApplyCalibration(map[string]float64{
-			"capacity_score":   float64(rawScore),
+			"capacity_score":   rawScore,
 			"price_volatility": priceVolatility,
 		}); ok {
 			calibrationScore = score
@@ -285,7 +312,7 @@ func (e *InferenceEngine) PredictDetailed(ctx context.Context, nodeID string, st
 		finalScore = adjustedOdds / (1.0 + adjustedOdds)
 	}
 
-	runtimeScore := float64(rawRuntimeScore)
+	runtimeScore := rawRuntime
 	if riskMultiplier != 1.0 {
 		if runtimeScore < 1e-6 {
 			runtimeScore = 1e-6
@@ -297,234 +324,82 @@ func (e *InferenceEngine) PredictDetailed(ctx context.Context, nodeID string, st
 		runtimeScore = adjustedOdds / (1.0 + adjustedOdds)
 	}
 
-	// Ensure RL input reflects runtime risk from the TFT runtime head.
-	state.RuntimeScore = runtimeScore
-
-	// 3. Build RL Input (13 features)
-	rlFeatures := e.builder.BuildRLInput(state, finalScore)
-	wrapRLFallback := func(cause error) error {
-		return &RLFallbackError{
-			CapacityScore: float32(finalScore),
-			RuntimeScore:  float32(runtimeScore),
-			Cause:         cause,
-		}
-	}
-
-	rlInputShape := ort.NewShape(1, int64(len(rlFeatures)))
-	rlInputTensor, err := ort.NewTensor(rlInputShape, rlFeatures)
-	if err != nil {
-		return ActionHold, float32(finalScore), float32(runtimeScore), 0, wrapRLFallback(fmt.Errorf("failed to create RL tensor: %w", err))
-	}
-	defer rlInputTensor.Destroy()
-
-	// 4. Run RL for Action selection
-	rlInputs := map[string]*ort.Tensor[float32]{"state": rlInputTensor}
-	rlOutputs, err := e.rlModel.Predict(rlInputs)
-	if err != nil {
-		return ActionHold, float32(finalScore), float32(runtimeScore), 0, wrapRLFallback(fmt.Errorf("RL inference failed: %w", err))
-	}
-	defer destroyTensorMap(rlOutputs)
-
-	// Q-values output [batch, 6] -> find argmax (V2 spec)
-	qTensor, ok := rlOutputs["q_values"]
-	if !ok || qTensor == nil {
-		return ActionHold, float32(finalScore), float32(runtimeScore), 0, wrapRLFallback(fmt.Errorf("RL inference failed: missing q_values output"))
-	}
-	qValues := qTensor.GetData()
-	if len(qValues) == 0 {
-		return ActionHold, float32(finalScore), float32(runtimeScore), 0, wrapRLFallback(fmt.Errorf("RL inference failed: empty q_values output"))
-	}
-
-	bestAction := ActionHold
-	maxQ := qValues[0]
-	for i := 1; i < len(qValues); i++ {
-		if qValues[i] > maxQ {
-			maxQ = qValues[i]
-			bestAction = Action(i)
-		}
-	}
-
-	e.logger.Info("Inference complete",
-		"node_id", nodeID,
-		"capacity_score", finalScore,
-		"raw_score", rawScore,
-		"action", ActionToString(bestAction),
-		"confidence", maxQ,
-	)
-
-	// In DQN, we'll normalize maxQ to a 0-1 confidence proxy if needed
-	// For now, if maxQ > -100 (not total garbage), we call it confident
-	confidence := float32(1.0)
-	if maxQ < -1000 {
-		confidence = 0.1
-	}
-
-	return bestAction, float32(finalScore), float32(runtimeScore), confidence, nil
-}
-
-func (e *InferenceEngine) validateModelContracts() error {
-	seedHistory := []float64{0.31, 0.32, 0.30, 0.29, 0.31, 0.33, 0.34, 0.33, 0.32, 0.31, 0.30, 0.29}
-	contractState := NodeState{
-		SpotPrice:          0.31,
-		OnDemandPrice:      0.97,
-		PriceHistory:       seedHistory,
-		CPUUsage:           0.50,
-		MemoryUsage:        0.55,
-		PodStartupTime:     30,
-		MigrationCost:      1.0,
-		ClusterUtilization: 0.60,
-		OutagePenaltyHours: 1.0,
-		TimeSinceMigration: 5,
-		RuntimeScore:       0.15,
-		IsSpot:             true,
-		CurrentSpotRatio:   0.60,
-		TargetSpotRatio:    0.60,
-		Timestamp:          time.Unix(1700000000, 0).UTC(),
-	}
-
-	tftTensor, err := ort.NewTensor(
-		ort.NewShape(1, TFTHistorySteps, TFTFeatureCount),
-		e.builder.BuildTFTInput("__contract__", contractState),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create TFT contract tensor: %w", err)
-	}
-	defer tftTensor.Destroy()
-
-	tftOutputs, err := e.tftModel.Predict(map[string]*ort.Tensor[float32]{"input": tftTensor})
-	if err != nil {
-		return fmt.Errorf("TFT model contract check failed: %w", err)
-	}
-	defer destroyTensorMap(tftOutputs)
-
-	if err := validateTFTOutputContract(tftOutputs); err != nil {
-		return err
-	}
-
-	rlTensor, err := ort.NewTensor(
-		ort.NewShape(1, RLFeatureCount),
-		e.builder.BuildRLInput(contractState, 0.25),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create RL contract tensor: %w", err)
-	}
-	defer rlTensor.Destroy()
-
-	rlOutputs, err := e.rlModel.Predict(map[string]*ort.Tensor[float32]{"state": rlTensor})
-	if err != nil {
-		return fmt.Errorf("RL model contract check failed: %w", err)
-	}
-	defer destroyTensorMap(rlOutputs)
-
-	if err := validateRLQValuesOutputContract(rlOutputs); err != nil {
-		return err
-	}
-
-	return nil
+	return finalScore, runtimeScore
 }
 
-func validateTFTOutputContract(tftOutputs map[string]*ort.Tensor[float32]) error {
-	_, _, err := extractRiskScores(tftOutputs)
-	if err != nil {
-		return fmt.Errorf("TFT model contract check failed: %w", err)
-	}
-	return nil
+// Close releases model resources.
+func (e *InferenceEngine) Close() {
+	e.release()
+	destroyORT()
 }
 
-func validateRLQValuesOutputContract(rlOutputs map[string]*ort.Tensor[float32]) error {
-	qValuesTensor, ok := rlOutputs["q_values"]
-	if !ok || qValuesTensor == nil {
-		return fmt.Errorf("RL model contract check failed: missing q_values output")
-	}
-
-	qShape := qValuesTensor.GetShape()
-	if len(qShape) == 0 || qShape[len(qShape)-1] != 6 {
-		return fmt.Errorf("RL model contract check failed: expected q_values last dimension=6, got shape=%v", qShape)
+// release closes the engine's models but leaves the shared ONNX Runtime
+// environment to the engines still serving.
+func (e *InferenceEngine) release() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.tftModel != nil {
+		e.tftModel.Close()
+		e.tftModel = nil
 	}
-	if len(qValuesTensor.GetData()) < 6 {
-		return fmt.Errorf("RL model contract check failed: q_values output contains fewer than 6 values")
+	if e.rlModel != nil {
+		e.rlModel.Close()
+		e.rlModel = nil
 	}
-	return nil
+	e.batch.destroy()
+	e.batch = nil
+	e.fallback = false
+	e.surrogate = nil
 }
 
-func destroyTensorMap(tensors map[string]*ort.Tensor[float32]) {
-	for _, tensor := range tensors {
-		if tensor != nil {
-			tensor.Destroy()
-		}
-	}
+// swap exchanges the model bundle (models, symbolic layers and contract) of e
+// and other. Each engine keeps its feature builder, so price history survives
+// a promotion. Predictions on either engine never see a half-swapped bundle.
+func (e *InferenceEngine) swap(other *InferenceEngine) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	other.mu.Lock()
+	defer other.mu.Unlock()
+	e.tftModel, other.tftModel = other.tftModel, e.tftModel
+	e.rlModel, other.rlModel = other.rlModel, e.rlModel
+	e.pysr, other.pysr = other.pysr, e.pysr
+	e.scope, other.scope = other.scope, e.scope
+	e.fallback, other.fallback = other.fallback, e.fallback
+	e.surrogate, other.surrogate = other.surrogate, e.surrogate
+	e.riskMargin, other.riskMargin = other.riskMargin, e.riskMargin
+	e.batch, other.batch = other.batch, e.batch
+	e.batchUnsupported, other.batchUnsupported = other.batchUnsupported, e.batchUnsupported
 }
 
-func extractRiskScores(outputs map[string]*ort.Tensor[float32]) (float32, float32, error) {
-	capacityTensor, ok := outputs["capacity_score"]
-	if !ok || capacityTensor == nil {
-		return 0, 0, fmt.Errorf("TFT output missing: capacity_score")
-	}
-
-	runtimeTensor, ok := outputs["runtime_score"]
-	if !ok || runtimeTensor == nil {
-		return 0, 0, fmt.Errorf("TFT output missing: runtime_score")
+// Version reports the loaded bundle's version, or "" when the engine has no
+// model contract.
+func (e *InferenceEngine) Version() string {
+	if e == nil {
+		return ""
 	}
-
-	capacity, err := extractRiskScoreValue(capacityTensor)
-	if err != nil {
-		return 0, 0, fmt.Errorf("capacity_score: %w", err)
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.scope == nil {
+		return ""
 	}
-
-	runtime, err := extractRiskScoreValue(runtimeTensor)
-	if err != nil {
-		return 0, 0, fmt.Errorf("runtime_score: %w", err)
-	}
-
-	return capacity, runtime, nil
+	return e.scope.Version
 }
 
-func extractRiskScoreValue(tensor *ort.Tensor[float32]) (float32, error) {
-	if tensor == nil {
-		return 0, fmt.Errorf("tensor is nil")
-	}
-
-	data := tensor.GetData()
-	if len(data) == 0 {
-		return 0, fmt.Errorf("tensor is empty")
+// Ready reports whether both models (or a fallback estimator) and the model
+// contract are loaded.
+func (e *InferenceEngine) Ready() error {
+	if e == nil {
+		return fmt.Errorf("inference engine not initialized")
 	}
-
-	shape := tensor.GetShape()
-	if len(shape) == 3 && shape[1] > 0 && shape[2] > 0 {
-		horizon := int(shape[1])
-		quantiles := int(shape[2])
-		leadSteps := 6 // 60m / 10m
-		if leadSteps >= horizon {
-			leadSteps = horizon - 1
-		}
-		offset := leadSteps*quantiles + quantiles/2
-		if offset >= 0 && offset < len(data) {
-			return data[offset], nil
-		}
-		return 0, fmt.Errorf("tensor shape %v produced invalid offset %d", shape, offset)
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if (e.tftModel == nil || e.rlModel == nil) && !e.fallback {
+		return fmt.Errorf("models not loaded")
 	}
-
-	if len(shape) == 2 && shape[1] > 0 {
-		width := int(shape[1])
-		idx := width / 2
-		if idx >= 0 && idx < len(data) {
-			return data[idx], nil
-		}
-		return 0, fmt.Errorf("tensor shape %v produced invalid index %d", shape, idx)
+	if e.scope == nil {
+		return fmt.Errorf("model contract not loaded")
 	}
-
-	return data[0], nil
-}
-
-// Close releases model resources.
-func (e *InferenceEngine) Close() {
-	if e.tftModel != nil {
-		e.tftModel.Close()
-	}
-	if e.rlModel != nil {
-		e.rlModel.Close()
-	}
-	ort.DestroyEnvironment()
+	return nil
 }
 
 // SupportsInstanceType checks model scope restrictions (if provided).
@@ -532,5 +407,7 @@ func (e *InferenceEngine) SupportsInstanceType(instanceType string) (bool, strin
 	if e == nil {
 		return true, ""
 	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	return e.scope.SupportsInstanceType(instanceType)
 }
@@ -0,0 +1,46 @@
+//go:build !cgo
+
+package inference
+
+import "errors"
+
+// Without cgo the agent cannot load ONNX Runtime: initializeORT always fails,
+// so NewInferenceEngine builds a fallback engine and the model paths below
+// are never reached.
+
+var errORTNotBuilt = errors.New("built without cgo: ONNX Runtime is unavailable")
+
+// initializeORT reports that ONNX Runtime is not compiled in. Tests replace
+// it like the cgo build's.
+var initializeORT = func() error {
+	return errORTNotBuilt
+}
+
+func destroyORT() {}
+
+// Model is an ONNX model; without cgo none can be loaded.
+type Model struct{}
+
+// NewModel always fails without cgo.
+func NewModel(path string, inputNames, outputNames []string) (*Model, error) {
+	return nil, errORTNotBuilt
+}
+
+// Close releases resources.
+func (m *Model) Close() {}
+
+type batchTensors struct{}
+
+func (b *batchTensors) destroy() {}
+
+func (e *InferenceEngine) predictModels(nodeID string, state NodeState, riskMultiplier float64) (Action, float32, float32, float32, error) {
+	return ActionHold, 0, 0, 0, errORTNotBuilt
+}
+
+func (e *InferenceEngine) runBatch(items []BatchItem, riskMultiplier float64, results []BatchResult) error {
+	return errORTNotBuilt
+}
+
+func (e *InferenceEngine) validateModelContracts() error {
+	return errORTNotBuilt
+}
@@ -0,0 +1,274 @@
+//go:build cgo
+
+package inference
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// initializeORT loads the ONNX Runtime shared library. Tests replace it to
+// simulate a node without the library.
+var initializeORT = func() error {
+	SetSharedLibraryPath()
+	err := ort.InitializeEnvironment()
+	if ort.IsInitialized() {
+		return nil
+	}
+	if err == nil {
+		err = errors.New("environment not initialized")
+	}
+	return err
+}
+
+// destroyORT tears down the shared ONNX Runtime environment.
+func destroyORT() {
+	ort.DestroyEnvironment()
+}
+
+// SetSharedLibraryPath points ONNX Runtime at the first shared library found
+// by sharedLibraryPath.
+func SetSharedLibraryPath() {
+	ort.SetSharedLibraryPath(sharedLibraryPath())
+}
+
+// predictModels runs the TFT and RL models for one scope. Callers hold e.mu.
+func (e *InferenceEngine) predictModels(nodeID string, state NodeState, riskMultiplier float64) (Action, float32, float32, float32, error) {
+	// 1. Build TFT Input (TFTHistorySteps x TFTFeatureCount features)
+	// Note: We use the builder to maintain price history
+	e.builder.UpdatePriceHistory(nodeID, state.SpotPrice)
+	tftFeatures := e.builder.BuildTFTInput(nodeID, state)
+
+	tftInputShape := ort.NewShape(1, TFTHistorySteps, TFTFeatureCount)
+	tftInputTensor, err := ort.NewTensor(tftInputShape, tftFeatures)
+	if err != nil {
+		return ActionHold, 0, 0, 0, fmt.Errorf("failed to create TFT tensor: %w", err)
+	}
+	defer tftInputTensor.Destroy()
+
+	// 2. Run TFT for Capacity Score (Risk Tracking)
+	tftInputs := map[string]*ort.Tensor[float32]{"input": tftInputTensor}
+	tftOutputs, err := e.tftModel.Predict(tftInputs)
+	if err != nil {
+		return ActionHold, 0, 0, 0, fmt.Errorf("TFT inference failed: %w", err)
+	}
+	defer destroyTensorMap(tftOutputs)
+
+	rawScore, rawRuntimeScore, err := extractRiskScores(tftOutputs)
+	if err != nil {
+		return ActionHold, 0, 0, 0, err
+	}
+
+	finalScore, runtimeScore := e.calibrate(float64(rawScore), float64(rawRuntimeScore), state, riskMultiplier)
+
+	// Ensure RL input reflects runtime risk from the TFT runtime head.
+	state.RuntimeScore = runtimeScore
+
+	// 3. Build RL Input (13 features)
+	rlFeatures := e.builder.BuildRLInput(state, finalScore)
+	wrapRLFallback := func(cause error) error {
+		return &RLFallbackError{
+			CapacityScore: float32(finalScore),
+			RuntimeScore:  float32(runtimeScore),
+			Cause:         cause,
+		}
+	}
+
+	rlInputShape := ort.NewShape(1, int64(len(rlFeatures)))
+	rlInputTensor, err := ort.NewTensor(rlInputShape, rlFeatures)
+	if err != nil {
+		return ActionHold, float32(finalScore), float32(runtimeScore), 0, wrapRLFallback(fmt.Errorf("failed to create RL tensor: %w", err))
+	}
+	defer rlInputTensor.Destroy()
+
+	// 4. Run RL for Action selection
+	rlInputs := map[string]*ort.Tensor[float32]{"state": rlInputTensor}
+	rlOutputs, err := e.rlModel.Predict(rlInputs)
+	if err != nil {
+		return ActionHold, float32(finalScore), float32(runtimeScore), 0, wrapRLFallback(fmt.Errorf("RL inference failed: %w", err))
+	}
+	defer destroyTensorMap(rlOutputs)
+
+	// Q-values output [batch, 6] -> find argmax (V2 spec)
+	qTensor, ok := rlOutputs["q_values"]
+	if !ok || qTensor == nil {
+		return ActionHold, float32(finalScore), float32(runtimeScore), 0, wrapRLFallback(fmt.Errorf("RL inference failed: missing q_values output"))
+	}
+	qValues := qTensor.GetData()
+	if len(qValues) == 0 {
+		return ActionHold, float32(finalScore), float32(runtimeScore), 0, wrapRLFallback(fmt.Errorf("RL inference failed: empty q_values output"))
+	}
+
+	bestAction, maxQ := selectAction(qValues)
+
+	e.logger.Info("Inference complete",
+		"node_id", nodeID,
+		"capacity_score", finalScore,
+		"raw_score", rawScore,
+		"action", ActionToString(bestAction),
+		"confidence", maxQ,
+	)
+
+	confidence := qConfidence(maxQ)
+
+	return bestAction, float32(finalScore), float32(runtimeScore), confidence, nil
+}
+
+func (e *InferenceEngine) validateModelContracts() error {
+	seedHistory := []float64{0.31, 0.32, 0.30, 0.29, 0.31, 0.33, 0.34, 0.33, 0.32, 0.31, 0.30, 0.29}
+	contractState := NodeState{
+		SpotPrice:          0.31,
+		OnDemandPrice:      0.97,
+		PriceHistory:       seedHistory,
+		CPUUsage:           0.50,
+		MemoryUsage:        0.55,
+		PodStartupTime:     30,
+		MigrationCost:      1.0,
+		ClusterUtilization: 0.60,
+		OutagePenaltyHours: 1.0,
+		TimeSinceMigration: 5,
+		RuntimeScore:       0.15,
+		IsSpot:             true,
+		CurrentSpotRatio:   0.60,
+		TargetSpotRatio:    0.60,
+		Timestamp:          time.Unix(1700000000, 0).UTC(),
+	}
+
+	tftTensor, err := ort.NewTensor(
+		ort.NewShape(1, TFTHistorySteps, TFTFeatureCount),
+		e.builder.BuildTFTInput("__contract__", contractState),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create TFT contract tensor: %w", err)
+	}
+	defer tftTensor.Destroy()
+
+	tftOutputs, err := e.tftModel.Predict(map[string]*ort.Tensor[float32]{"input": tftTensor})
+	if err != nil {
+		return fmt.Errorf("TFT model contract check failed: %w", err)
+	}
+	defer destroyTensorMap(tftOutputs)
+
+	if err := validateTFTOutputContract(tftOutputs); err != nil {
+		return err
+	}
+
+	rlTensor, err := ort.NewTensor(
+		ort.NewShape(1, RLFeatureCount),
+		e.builder.BuildRLInput(contractState, 0.25),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create RL contract tensor: %w", err)
+	}
+	defer rlTensor.Destroy()
+
+	rlOutputs, err := e.rlModel.Predict(map[string]*ort.Tensor[float32]{"state": rlTensor})
+	if err != nil {
+		return fmt.Errorf("RL model contract check failed: %w", err)
+	}
+	defer destroyTensorMap(rlOutputs)
+
+	if err := validateRLQValuesOutputContract(rlOutputs); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func validateTFTOutputContract(tftOutputs map[string]*ort.Tensor[float32]) error {
+	_, _, err := extractRiskScores(tftOutputs)
+	if err != nil {
+		return fmt.Errorf("TFT model contract check failed: %w", err)
+	}
+	return nil
+}
+
+func validateRLQValuesOutputContract(rlOutputs map[string]*ort.Tensor[float32]) error {
+	qValuesTensor, ok := rlOutputs["q_values"]
+	if !ok || qValuesTensor == nil {
+		return fmt.Errorf("RL model contract check failed: missing q_values output")
+	}
+
+	qShape := qValuesTensor.GetShape()
+	if len(qShape) == 0 || qShape[len(qShape)-1] != 6 {
+		return fmt.Errorf("RL model contract check failed: expected q_values last dimension=6, got shape=%v", qShape)
+	}
+	if len(qValuesTensor.GetData()) < 6 {
+		return fmt.Errorf("RL model contract check failed: q_values output contains fewer than 6 values")
+	}
+	return nil
+}
+
+func destroyTensorMap(tensors map[string]*ort.Tensor[float32]) {
+	for _, tensor := range tensors {
+		if tensor != nil {
+			tensor.Destroy()
+		}
+	}
+}
+
+func extractRiskScores(outputs map[string]*ort.Tensor[float32]) (float32, float32, error) {
+	return extractRiskScoresAt(outputs, 0)
+}
+
+// extractRiskScoresAt reads both TFT heads for one batch row.
+func extractRiskScoresAt(outputs map[string]*ort.Tensor[float32], row int) (float32, float32, error) {
+	capacityTensor, ok := outputs["capacity_score"]
+	if !ok || capacityTensor == nil {
+		return 0, 0, fmt.Errorf("TFT output missing: capacity_score")
+	}
+	runtimeTensor, ok := outputs["runtime_score"]
+	if !ok || runtimeTensor == nil {
+		return 0, 0, fmt.Errorf("TFT output missing: runtime_score")
+	}
+
+	capacity, err := riskScoreAt(capacityTensor.GetData(), capacityTensor.GetShape(), row)
+	if err != nil {
+		return 0, 0, fmt.Errorf("capacity_score: %w", err)
+	}
+	runtime, err := riskScoreAt(runtimeTensor.GetData(), runtimeTensor.GetShape(), row)
+	if err != nil {
+		return 0, 0, fmt.Errorf("runtime_score: %w", err)
+	}
+	return capacity, runtime, nil
+}
+
+// riskScoreAt picks one batch row's risk score: the median quantile at the
+// 60m lead for [batch, horizon, quantiles] heads, the middle column for
+// [batch, width] heads, and the row's value otherwise.
+func riskScoreAt(data []float32, shape ort.Shape, row int) (float32, error) {
+	if len(data) == 0 {
+		return 0, fmt.Errorf("tensor is empty")
+	}
+
+	if len(shape) == 3 && shape[1] > 0 && shape[2] > 0 {
+		horizon := int(shape[1])
+		quantiles := int(shape[2])
+		leadSteps := 6 // 60m / 10m
+		if leadSteps >= horizon {
+			leadSteps = horizon - 1
+		}
+		offset := row*horizon*quantiles + leadSteps*quantiles + quantiles/2
+		if offset >= 0 && offset < len(data) {
+			return data[offset], nil
+		}
+		return 0, fmt.Errorf("tensor shape %v produced invalid offset %d", shape, offset)
+	}
+
+	if len(shape) == 2 && shape[1] > 0 {
+		width := int(shape[1])
+		idx := row*width + width/2
+		if idx >= 0 && idx < len(data) {
+			return data[idx], nil
+		}
+		return 0, fmt.Errorf("tensor shape %v produced invalid index %d", shape, idx)
+	}
+
+	if row >= len(data) {
+		return 0, fmt.Errorf("tensor shape %v has no row %d", shape, row)
+	}
+	return data[row], nil
+}
@@ -0,0 +1,99 @@
+package inference
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
+)
+
+// ErrSurrogateFallback is the RL fallback cause while the engine serves risk
+// from the Go surrogate: there is no RL recommendation, so only policies that
+// work from the risk scores (deterministic) can decide.
+var ErrSurrogateFallback = errors.New("ONNX Runtime unavailable: risk from the fallback surrogate, no RL recommendation")
+
+// ErrPySRFallback is the RL fallback cause while the engine serves risk from
+// the PySR estimator because the bundle declares no surrogate.
+var ErrPySRFallback = errors.New("ONNX Runtime unavailable: risk from the PySR estimator, no RL recommendation")
+
+// pysrFallbackPrior is the raw TFT risk the PySR estimator assumes for both
+// heads: with no model to score the scope it starts at even odds, and the
+// calibration, fusion and risk margin move it from there.
+const pysrFallbackPrior = 0.5
+
+// newFallbackEngine builds an engine that scores risk without the ONNX
+// models: with the bundle's declared surrogate, or else with the PySR
+// equations alone from a conservative prior. Its scores carry the manifest's
+// risk margin so the deterministic policy errs toward On-Demand.
+func newFallbackEngine(cfg EngineConfig, manifestPath string, scope *ModelContract, pysr *PySREngine, logger *slog.Logger, ortErr error) (*InferenceEngine, error) {
+	if scope.FallbackRiskMargin < 0 || scope.FallbackRiskMargin >= 1 {
+		return nil, fmt.Errorf("fallback risk_margin must be in [0, 1), got %v", scope.FallbackRiskMargin)
+	}
+
+	var surrogate *Surrogate
+	if scope.FallbackSurrogatePath != "" {
+		if cfg.RequireModelContract {
+			if err := VerifyManifestArtifacts(manifestPath, scope.FallbackSurrogatePath); err != nil {
+				return nil, fmt.Errorf("fallback surrogate verification failed: %w", err)
+			}
+		}
+		var err error
+		surrogate, err = LoadSurrogate(scope.FallbackSurrogatePath)
+		if err != nil {
+			return nil, fmt.Errorf("ONNX Runtime unavailable (%v) and fallback surrogate failed to load: %w", ortErr, err)
+		}
+	} else if !pysr.Enabled() {
+		return nil, fmt.Errorf("ONNX Runtime unavailable, the bundle declares no fallback surrogate and no PySR equations loaded: %w", ortErr)
+	}
+
+	metrics.InferenceFallbackActive.Set(1)
+	if surrogate != nil {
+		logger.Error("ONNX Runtime unavailable; serving risk from the fallback surrogate without RL",
+			"error", ortErr,
+			"surrogate", scope.FallbackSurrogatePath,
+			"risk_margin", scope.FallbackRiskMargin,
+			"hint", "set ORT_SHARED_LIBRARY_PATH or ship libonnxruntime in the image",
+		)
+	} else {
+		logger.Error("ONNX Runtime unavailable; serving risk from the PySR estimator without RL",
+			"error", ortErr,
+			"prior", pysrFallbackPrior,
+			"risk_margin", scope.FallbackRiskMargin,
+			"hint", "set ORT_SHARED_LIBRARY_PATH or ship libonnxruntime in the image",
+		)
+	}
+	return &InferenceEngine{
+		logger:     logger,
+		pysr:       pysr,
+		scope:      scope,
+		fallback:   true,
+		surrogate:  surrogate,
+		riskMargin: scope.FallbackRiskMargin,
+		builder:    NewFeatureBuilder(),
+	}, nil
+}
+
+// predictFallback scores the latest TFT feature step with the surrogate, or
+// starts from pysrFallbackPrior without one, and always returns an RL
+// fallback error carrying the scores. Callers hold e.mu.
+func (e *InferenceEngine) predictFallback(nodeID string, state NodeState, riskMultiplier float64) (Action, float32, float32, float32, error) {
+	e.builder.UpdatePriceHistory(nodeID, state.SpotPrice)
+	rawScore, rawRuntime, cause := pysrFallbackPrior, pysrFallbackPrior, ErrPySRFallback
+	if e.surrogate != nil {
+		features := e.builder.BuildTFTInput(nodeID, state)
+		rawScore, rawRuntime = e.surrogate.Predict(features[(TFTHistorySteps-1)*TFTFeatureCount:])
+		cause = ErrSurrogateFallback
+	}
+
+	finalScore, runtimeScore := e.calibrate(rawScore, rawRuntime, state, riskMultiplier)
+	finalScore = clamp01(finalScore + e.riskMargin)
+	runtimeScore = clamp01(runtimeScore + e.riskMargin)
+	metrics.InferenceFallbackPredictions.Inc()
+
+	return ActionHold, float32(finalScore), float32(runtimeScore), 0, &RLFallbackError{
+		CapacityScore: float32(finalScore),
+		RuntimeScore:  float32(runtimeScore),
+		Cause:         cause,
+	}
+}
@@ -0,0 +1,164 @@
+package inference
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
+)
+
+// constantSurrogate predicts sigmoid(0)=0.5 capacity and sigmoid(-2) runtime
+// risk regardless of the features.
+const constantSurrogate = `{"layers": [{
+	"weights": [[0,0,0,0,0,0,0,0,0,0], [0,0,0,0,0,0,0,0,0,0]],
+	"bias": [0, -2],
+	"activation": "sigmoid"
+}]}`
+
+// writeFallbackBundle writes a bundle whose ONNX files are placeholders and
+// whose manifest declares the surrogate when withFallback is set.
+func writeFallbackBundle(t *testing.T, withFallback bool) EngineConfig {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"tft.onnx":       "tft-model",
+		"rl_policy.onnx": "rl-model",
+		"surrogate.json": constantSurrogate,
+	}
+	artifacts := make(map[string]any, len(files))
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		sum, err := hashFileSHA256(path)
+		if err != nil {
+			t.Fatalf("hash %s: %v", name, err)
+		}
+		artifacts[name] = map[string]any{"path": name, "sha256": sum}
+	}
+	manifest := map[string]any{"cloud": "aws", "artifacts": artifacts}
+	if withFallback {
+		manifest["fallback"] = map[string]any{"surrogate": "surrogate.json", "risk_margin": 0.1}
+	}
+	payload, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("marshal manifest: %v", err)
+	}
+	manifestPath := filepath.Join(dir, BundleManifestFile)
+	if err := os.WriteFile(manifestPath, payload, 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	return EngineConfig{
+		TFTModelPath:         filepath.Join(dir, "tft.onnx"),
+		RLModelPath:          filepath.Join(dir, "rl_policy.onnx"),
+		PySRCalibrationPath:  filepath.Join(dir, "missing_calibration.txt"),
+		PySRFusionPath:       filepath.Join(dir, "missing_fusion.txt"),
+		ModelManifestPath:    manifestPath,
+		RequireModelContract: true,
+		Logger:               slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+}
+
+func withoutORT(t *testing.T) {
+	t.Helper()
+	orig := initializeORT
+	initializeORT = func() error { return errors.New("libonnxruntime.so: cannot open shared object file") }
+	t.Cleanup(func() { initializeORT = orig })
+}
+
+func TestNewInferenceEngine_FallsBackToSurrogateWithoutORT(t *testing.T) {
+	withoutORT(t)
+	before := testutil.ToFloat64(metrics.InferenceFallbackPredictions)
+
+	engine, err := NewInferenceEngine(writeFallbackBundle(t, true))
+	if err != nil {
+		t.Fatalf("NewInferenceEngine: %v", err)
+	}
+	if err := engine.Ready(); err != nil {
+		t.Fatalf("Ready() = %v, want a fallback engine to be ready", err)
+	}
+	if testutil.ToFloat64(metrics.InferenceFallbackActive) != 1 {
+		t.Fatal("inference_fallback_active must be set")
+	}
+
+	_, capacity, runtime, _, err := engine.PredictDetailed(context.Background(), "node-1", NodeState{
+		SpotPrice:     0.3,
+		OnDemandPrice: 1.0,
+		Timestamp:     time.Unix(1700000000, 0).UTC(),
+	}, 1.0)
+	fallback, ok := AsRLFallbackError(err)
+	if !ok || !errors.Is(err, ErrSurrogateFallback) {
+		t.Fatalf("err=%v, want an RL fallback caused by the surrogate", err)
+	}
+	wantRuntime := 1/(1+math.Exp(2)) + 0.1
+	if math.Abs(float64(capacity)-0.6) > 1e-6 || math.Abs(float64(runtime)-wantRuntime) > 1e-6 {
+		t.Fatalf("capacity=%v runtime=%v, want 0.6 and %v with the risk margin", capacity, runtime, wantRuntime)
+	}
+	if fallback.CapacityScore != capacity || fallback.RuntimeScore != runtime {
+		t.Fatal("fallback error must carry the surrogate scores")
+	}
+	if got := testutil.ToFloat64(metrics.InferenceFallbackPredictions) - before; got != 1 {
+		t.Fatalf("fallback predictions delta=%v, want 1", got)
+	}
+}
+
+func TestNewInferenceEngine_FailsWithoutORTSurrogateOrPySR(t *testing.T) {
+	withoutORT(t)
+	_, err := NewInferenceEngine(writeFallbackBundle(t, false))
+	if err == nil || !strings.Contains(err.Error(), "declares no fallback surrogate") {
+		t.Fatalf("err=%v, want a missing-surrogate error", err)
+	}
+}
+
+func TestNewInferenceEngine_FallsBackToPySRWithoutSurrogate(t *testing.T) {
+	withoutORT(t)
+	cfg := writeFallbackBundle(t, false)
+	if err := os.WriteFile(cfg.PySRCalibrationPath, []byte("capacity_score"), 0o644); err != nil {
+		t.Fatalf("write calibration: %v", err)
+	}
+	if err := os.WriteFile(cfg.PySRFusionPath, []byte("pysr_calibrated_risk"), 0o644); err != nil {
+		t.Fatalf("write fusion: %v", err)
+	}
+
+	engine, err := NewInferenceEngine(cfg)
+	if err != nil {
+		t.Fatalf("NewInferenceEngine: %v", err)
+	}
+	if err := engine.Ready(); err != nil {
+		t.Fatalf("Ready() = %v, want a PySR fallback engine to be ready", err)
+	}
+
+	_, capacity, runtime, _, err := engine.PredictDetailed(context.Background(), "node-1", NodeState{
+		SpotPrice:     0.3,
+		OnDemandPrice: 1.0,
+		Timestamp:     time.Unix(1700000000, 0).UTC(),
+	}, 1.0)
+	if !errors.Is(err, ErrPySRFallback) {
+		t.Fatalf("err=%v, want an RL fallback caused by the PySR estimator", err)
+	}
+	if capacity != pysrFallbackPrior || runtime != pysrFallbackPrior {
+		t.Fatalf("capacity=%v runtime=%v, want the %v prior through identity equations", capacity, runtime, pysrFallbackPrior)
+	}
+}
+
+func TestLoadSurrogate_RejectsShapeMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "surrogate.json")
+	bad := `{"layers": [{"weights": [[1, 2]], "bias": [0], "activation": "relu"}]}`
+	if err := os.WriteFile(path, []byte(bad), 0o644); err != nil {
+		t.Fatalf("write surrogate: %v", err)
+	}
+	if _, err := LoadSurrogate(path); err == nil {
+		t.Fatal("expected a surrogate with the wrong input width to be rejected")
+	}
+}
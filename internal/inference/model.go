@@ -1,3 +1,5 @@
+//go:build cgo
+
 package inference
 
 import (
@@ -62,3 +64,18 @@ func (m *Model) Close() {
 		m.session.Destroy()
 	}
 }
+
+// Run runs inference with inputs and outputs ordered as the model's names.
+// Nil outputs are allocated by ONNX Runtime and stored in place; non-nil
+// outputs are written into, so callers can reuse them across runs of the same
+// shape.
+func (m *Model) Run(inputs, outputs []ort.Value) error {
+	if len(inputs) != len(m.inputs) || len(outputs) != len(m.outputs) {
+		return fmt.Errorf("model expects %d inputs and %d outputs, got %d and %d",
+			len(m.inputs), len(m.outputs), len(inputs), len(outputs))
+	}
+	if err := m.session.Run(inputs, outputs); err != nil {
+		return fmt.Errorf("inference failed: %w", err)
+	}
+	return nil
+}
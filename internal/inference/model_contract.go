@@ -20,6 +20,16 @@ type ModelContract struct {
 	Cloud                     string
 	SupportedInstanceFamilies []string
 	ArtifactChecksums         map[string]string
+	// Version identifies the bundle: the manifest's bundle_version, else a
+	// digest of the manifest itself.
+	Version string
+	// FallbackSurrogatePath is the Go-evaluated TFT surrogate used when ONNX
+	// Runtime cannot load, resolved against the manifest directory. Empty when
+	// the bundle declares no fallback.
+	FallbackSurrogatePath string
+	// FallbackRiskMargin is added to both fallback risk heads, from the
+	// surrogate or the PySR estimator.
+	FallbackRiskMargin float64
 }
 
 type manifestArtifact struct {
@@ -28,6 +38,7 @@ type manifestArtifact struct {
 }
 
 type modelManifest struct {
+	BundleVersion             string                      `json:"bundle_version"`
 	Cloud                     string                      `json:"cloud"`
 	SupportedInstanceFamilies []string                    `json:"supported_instance_families"`
 	Artifacts                 map[string]manifestArtifact `json:"artifacts"`
@@ -36,6 +47,10 @@ type modelManifest struct {
 		Cloud                     string   `json:"cloud"`
 		SupportedInstanceFamilies []string `json:"supported_instance_families"`
 	} `json:"model_scope"`
+	Fallback struct {
+		Surrogate  string  `json:"surrogate"`
+		RiskMargin float64 `json:"risk_margin"`
+	} `json:"fallback"`
 }
 
 // LoadModelContract loads an optional model contract from manifest and env.
@@ -77,6 +92,14 @@ func LoadModelContract(manifestPath string) (*ModelContract, error) {
 
 			collectArtifactChecksums(contract.ArtifactChecksums, manifest.Artifacts)
 			collectArtifactChecksums(contract.ArtifactChecksums, manifest.Models)
+			contract.Version = manifestVersion(manifest, payload)
+			if surrogate := strings.TrimSpace(manifest.Fallback.Surrogate); surrogate != "" {
+				if !filepath.IsAbs(surrogate) {
+					surrogate = filepath.Join(filepath.Dir(manifestPath), surrogate)
+				}
+				contract.FallbackSurrogatePath = surrogate
+			}
+			contract.FallbackRiskMargin = manifest.Fallback.RiskMargin
 			found = true
 		}
 	}
@@ -97,6 +120,16 @@ func LoadModelContract(manifestPath string) (*ModelContract, error) {
 	return contract, nil
 }
 
+// manifestVersion returns the manifest's bundle_version, falling back to a
+// short digest of the manifest so every bundle has a stable label value.
+func manifestVersion(manifest modelManifest, payload []byte) string {
+	if v := strings.TrimSpace(manifest.BundleVersion); v != "" {
+		return v
+	}
+	sum := sha256.Sum256(payload)
+	return "sha256-" + hex.EncodeToString(sum[:])[:12]
+}
+
 func collectArtifactChecksums(dst map[string]string, artifacts map[string]manifestArtifact) {
 	for key, art := range artifacts {
 		path := strings.TrimSpace(art.Path)
@@ -243,6 +276,9 @@ func InstanceFamilyLabel(instanceType string) string {
 	if instanceType == "" || instanceType == "unknown" {
 		return "unknown"
 	}
+	if sku, ok := strings.CutPrefix(instanceType, "standard_"); ok {
+		return azureFamilyLabel(sku)
+	}
 	family, _, _ := strings.Cut(instanceType, ".")
 	if family == "" {
 		return "unknown"
@@ -250,6 +286,25 @@ func InstanceFamilyLabel(instanceType string) string {
 	return family
 }
 
+// azureFamilyLabel maps an Azure VM size (without "Standard_") to its series
+// and version: "d4s_v5" -> "ds_v5", "e4-2as_v4" -> "eas_v4", "b2ms" -> "bms".
+func azureFamilyLabel(sku string) string {
+	size, version, _ := strings.Cut(sku, "_")
+	series := strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '-' {
+			return -1
+		}
+		return r
+	}, size)
+	if series == "" {
+		return "unknown"
+	}
+	if version == "" {
+		return series
+	}
+	return series + "_" + version
+}
+
 // SupportsInstanceType checks whether instanceType is in contract scope.
 func (m *ModelContract) SupportsInstanceType(instanceType string) (bool, string) {
 	if m == nil || len(m.SupportedInstanceFamilies) == 0 {
@@ -43,6 +43,25 @@ func TestLoadModelContractFromManifest(t *testing.T) {
 	if contract.ArtifactChecksums["tft.onnx"] != "abc" {
 		t.Fatalf("expected artifact checksum to be loaded")
 	}
+	if !strings.HasPrefix(contract.Version, "sha256-") {
+		t.Fatalf("expected manifest digest version without bundle_version, got %q", contract.Version)
+	}
+
+	manifest["bundle_version"] = "2026-03-11"
+	payload, err = json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("marshal manifest: %v", err)
+	}
+	if err := os.WriteFile(manifestPath, payload, 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	contract, err = LoadModelContract(manifestPath)
+	if err != nil {
+		t.Fatalf("LoadModelContract failed: %v", err)
+	}
+	if contract.Version != "2026-03-11" {
+		t.Fatalf("expected bundle_version 2026-03-11, got %q", contract.Version)
+	}
 }
 
 func TestLoadModelContractEnvOverrides(t *testing.T) {
@@ -196,3 +215,27 @@ func TestShippedModelBundleManifest_VerifiesCurrentArtifacts(t *testing.T) {
 		t.Fatalf("VerifyManifestArtifacts(%s) failed: %v", manifestPath, err)
 	}
 }
+
+func TestInstanceFamilyLabel_AzureSizes(t *testing.T) {
+	cases := map[string]string{
+		"m5.large":          "m5",
+		"Standard_D4s_v5":   "ds_v5",
+		"standard_d16as_v4": "das_v4",
+		"Standard_E4-2s_v5": "es_v5",
+		"Standard_B2ms":     "bms",
+		"":                  "unknown",
+	}
+	for in, want := range cases {
+		if got := InstanceFamilyLabel(in); got != want {
+			t.Errorf("InstanceFamilyLabel(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	contract := &ModelContract{SupportedInstanceFamilies: []string{"ds_v5"}}
+	if ok, reason := contract.SupportsInstanceType("Standard_D8s_v5"); !ok {
+		t.Errorf("expected Standard_D8s_v5 in scope: %s", reason)
+	}
+	if ok, _ := contract.SupportsInstanceType("Standard_D8as_v5"); ok {
+		t.Error("expected Standard_D8as_v5 out of scope")
+	}
+}
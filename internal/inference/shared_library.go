@@ -5,11 +5,12 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
-
-	ort "github.com/yalue/onnxruntime_go"
 )
 
-func SetSharedLibraryPath() {
+// sharedLibraryPath returns the first ONNX Runtime shared library found in the
+// environment overrides, local virtualenvs and system locations, or the bare
+// library name for the dynamic loader to resolve.
+func sharedLibraryPath() string {
 	paths := []string{}
 	if env := os.Getenv("ORT_SHARED_LIBRARY_PATH"); env != "" {
 		paths = appendSharedLibraryCandidates(paths, env)
@@ -31,12 +32,11 @@ func SetSharedLibraryPath() {
 		}
 		seen[p] = struct{}{}
 		if _, err := os.Stat(p); err == nil {
-			ort.SetSharedLibraryPath(p)
-			return
+			return p
 		}
 	}
 
-	ort.SetSharedLibraryPath("onnxruntime")
+	return "onnxruntime"
 }
 
 func appendSharedLibraryCandidates(paths []string, rawPath string) []string {
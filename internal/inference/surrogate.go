@@ -0,0 +1,107 @@
+package inference
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+)
+
+// Surrogate is a small dense network distilled from the TFT. It maps the
+// latest step of the TFT input window (TFTFeatureCount features) to the
+// capacity and runtime risk heads, and is evaluated in pure Go so it needs no
+// ONNX Runtime.
+type Surrogate struct {
+	Layers []SurrogateLayer `json:"layers"`
+}
+
+// SurrogateLayer is one fully connected layer: out = act(W·in + b), with W
+// stored as [out][in].
+type SurrogateLayer struct {
+	Weights    [][]float64 `json:"weights"`
+	Bias       []float64   `json:"bias"`
+	Activation string      `json:"activation"` // relu, tanh, sigmoid or linear
+}
+
+// LoadSurrogate reads and validates a surrogate network.
+func LoadSurrogate(path string) (*Surrogate, error) {
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read surrogate %s: %w", path, err)
+	}
+	var s Surrogate
+	if err := json.Unmarshal(payload, &s); err != nil {
+		return nil, fmt.Errorf("parse surrogate %s: %w", path, err)
+	}
+	if err := s.validate(); err != nil {
+		return nil, fmt.Errorf("surrogate %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+func (s *Surrogate) validate() error {
+	if len(s.Layers) == 0 {
+		return fmt.Errorf("no layers")
+	}
+	width := TFTFeatureCount
+	for i, layer := range s.Layers {
+		if len(layer.Weights) == 0 || len(layer.Bias) != len(layer.Weights) {
+			return fmt.Errorf("layer %d: %d weight rows and %d biases", i, len(layer.Weights), len(layer.Bias))
+		}
+		for j, row := range layer.Weights {
+			if len(row) != width {
+				return fmt.Errorf("layer %d row %d: %d inputs, want %d", i, j, len(row), width)
+			}
+		}
+		switch layer.Activation {
+		case "relu", "tanh", "sigmoid", "linear", "":
+		default:
+			return fmt.Errorf("layer %d: unknown activation %q", i, layer.Activation)
+		}
+		width = len(layer.Weights)
+	}
+	if width != 2 {
+		return fmt.Errorf("output width %d, want 2 (capacity_score, runtime_score)", width)
+	}
+	return nil
+}
+
+// Predict returns the capacity and runtime risk scores, clamped to [0, 1].
+func (s *Surrogate) Predict(features []float32) (float64, float64) {
+	in := make([]float64, len(features))
+	for i, v := range features {
+		in[i] = float64(v)
+	}
+	for _, layer := range s.Layers {
+		out := make([]float64, len(layer.Weights))
+		for j, row := range layer.Weights {
+			sum := layer.Bias[j]
+			for k, w := range row {
+				sum += w * in[k]
+			}
+			out[j] = activate(layer.Activation, sum)
+		}
+		in = out
+	}
+	return clamp01(in[0]), clamp01(in[1])
+}
+
+func activate(name string, x float64) float64 {
+	switch name {
+	case "relu":
+		return math.Max(0, x)
+	case "tanh":
+		return math.Tanh(x)
+	case "sigmoid":
+		return 1 / (1 + math.Exp(-x))
+	default:
+		return x
+	}
+}
+
+func clamp01(v float64) float64 {
+	if math.IsNaN(v) {
+		return 1
+	}
+	return math.Min(1, math.Max(0, v))
+}
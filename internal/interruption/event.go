@@ -0,0 +1,125 @@
+// Package interruption turns cloud interruption signals into events the
+// controller can act on immediately, outside the reconcile tick.
+//
+// Sources:
+//   - EventBridge -> SQS queue (EC2 Spot Instance Interruption Warning and
+//     EC2 Instance Rebalance Recommendation)
+//   - a local JSON-lines file or HTTP endpoint carrying the same EventBridge
+//     payloads (stand-ins for tests and kind clusters)
+//   - node taints/conditions written by aws-node-termination-handler style
+//     agents
+package interruption
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Kind identifies the interruption signal.
+type Kind string
+
+const (
+	// KindSpotInterruption is the EC2 two-minute Spot interruption warning.
+	KindSpotInterruption Kind = "spot_interruption"
+	// KindRebalanceRecommendation is the EC2 rebalance recommendation, an
+	// early signal that the instance is at elevated interruption risk.
+	KindRebalanceRecommendation Kind = "rebalance_recommendation"
+)
+
+const (
+	detailTypeSpotInterruption = "EC2 Spot Instance Interruption Warning"
+	detailTypeRebalance        = "EC2 Instance Rebalance Recommendation"
+
+	// SpotInterruptionNotice is how long EC2 waits after the warning before
+	// reclaiming the instance.
+	SpotInterruptionNotice = 2 * time.Minute
+)
+
+// Event is a single interruption signal for one instance or node.
+type Event struct {
+	Kind Kind
+	// InstanceID is the cloud instance ID (e.g. i-0abc). Empty when the
+	// source already knows the node name.
+	InstanceID string
+	// NodeName is the Kubernetes node name when the source knows it.
+	NodeName string
+	// Time is when the cloud emitted the signal.
+	Time time.Time
+	// Deadline is when the instance will be reclaimed. Zero for rebalance
+	// recommendations.
+	Deadline time.Time
+	// Source names the source that produced the event ("sqs", "file", ...).
+	Source string
+}
+
+// Expired reports whether the event's deadline has passed, i.e. the
+// instance has already been reclaimed. Rebalance recommendations carry no
+// deadline and never expire.
+func (e Event) Expired(now time.Time) bool {
+	return !e.Deadline.IsZero() && now.After(e.Deadline)
+}
+
+// Source produces interruption events until ctx is cancelled.
+type Source interface {
+	Name() string
+	Run(ctx context.Context, out chan<- Event) error
+}
+
+// eventBridgeMessage is the subset of an EventBridge EC2 event we consume.
+type eventBridgeMessage struct {
+	DetailType string    `json:"detail-type"`
+	Source     string    `json:"source"`
+	Time       time.Time `json:"time"`
+	Detail     struct {
+		InstanceID     string `json:"instance-id"`
+		InstanceAction string `json:"instance-action"`
+		// NodeName is not part of the AWS payload; local stand-ins may set it
+		// to target a node that has no EC2 instance behind it.
+		NodeName string `json:"node-name"`
+	} `json:"detail"`
+}
+
+// ParseEventBridge decodes an EventBridge EC2 event. ok is false for valid
+// JSON that is not an interruption or rebalance event.
+func ParseEventBridge(data []byte) (ev Event, ok bool, err error) {
+	var msg eventBridgeMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return Event{}, false, fmt.Errorf("decode EventBridge event: %w", err)
+	}
+
+	switch strings.TrimSpace(msg.DetailType) {
+	case detailTypeSpotInterruption:
+		ev.Kind = KindSpotInterruption
+	case detailTypeRebalance:
+		ev.Kind = KindRebalanceRecommendation
+	default:
+		return Event{}, false, nil
+	}
+
+	ev.InstanceID = strings.TrimSpace(msg.Detail.InstanceID)
+	ev.NodeName = strings.TrimSpace(msg.Detail.NodeName)
+	if ev.InstanceID == "" && ev.NodeName == "" {
+		return Event{}, false, fmt.Errorf("%s event has no instance-id", msg.DetailType)
+	}
+	ev.Time = msg.Time
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	if ev.Kind == KindSpotInterruption {
+		ev.Deadline = ev.Time.Add(SpotInterruptionNotice)
+	}
+	return ev, true, nil
+}
+
+// send delivers ev unless ctx is cancelled first.
+func send(ctx context.Context, out chan<- Event, ev Event) bool {
+	select {
+	case out <- ev:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
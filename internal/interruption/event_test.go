@@ -0,0 +1,72 @@
+package interruption
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+const spotWarning = `{"version":"0","detail-type":"EC2 Spot Instance Interruption Warning","source":"aws.ec2","time":"2026-01-02T03:04:05Z","detail":{"instance-id":"i-0abc","instance-action":"terminate"}}`
+
+// spotWarningAt is spotWarning emitted at t, for sources that drop events
+// whose deadline has passed.
+func spotWarningAt(t time.Time) string {
+	return fmt.Sprintf(`{"version":"0","detail-type":"EC2 Spot Instance Interruption Warning","source":"aws.ec2","time":%q,"detail":{"instance-id":"i-0abc","instance-action":"terminate"}}`,
+		t.UTC().Format(time.RFC3339))
+}
+
+func TestParseEventBridge_SpotInterruption(t *testing.T) {
+	ev, ok, err := ParseEventBridge([]byte(spotWarning))
+	if err != nil || !ok {
+		t.Fatalf("ParseEventBridge() ok=%v err=%v", ok, err)
+	}
+	if ev.Kind != KindSpotInterruption || ev.InstanceID != "i-0abc" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	want := time.Date(2026, 1, 2, 3, 6, 5, 0, time.UTC)
+	if !ev.Deadline.Equal(want) {
+		t.Fatalf("deadline=%v, want %v", ev.Deadline, want)
+	}
+}
+
+func TestParseEventBridge_Rebalance(t *testing.T) {
+	ev, ok, err := ParseEventBridge([]byte(`{"detail-type":"EC2 Instance Rebalance Recommendation","detail":{"instance-id":"i-1","node-name":"node-a"}}`))
+	if err != nil || !ok {
+		t.Fatalf("ParseEventBridge() ok=%v err=%v", ok, err)
+	}
+	if ev.Kind != KindRebalanceRecommendation || ev.NodeName != "node-a" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	if !ev.Deadline.IsZero() {
+		t.Fatalf("rebalance deadline=%v, want zero", ev.Deadline)
+	}
+	if ev.Time.IsZero() {
+		t.Fatal("missing time should default to now")
+	}
+}
+
+func TestParseEventBridge_IgnoresAndRejects(t *testing.T) {
+	if _, ok, err := ParseEventBridge([]byte(`{"detail-type":"EC2 Instance State-change Notification","detail":{"instance-id":"i-1"}}`)); ok || err != nil {
+		t.Fatalf("state-change event: ok=%v err=%v, want ignored", ok, err)
+	}
+	if _, _, err := ParseEventBridge([]byte(`{not json`)); err == nil {
+		t.Fatal("expected error for malformed JSON")
+	}
+	if _, _, err := ParseEventBridge([]byte(`{"detail-type":"EC2 Spot Instance Interruption Warning","detail":{}}`)); err == nil {
+		t.Fatal("expected error for interruption without instance-id")
+	}
+}
+
+func TestEvent_Expired(t *testing.T) {
+	now := time.Now()
+	spot := Event{Kind: KindSpotInterruption, Deadline: now.Add(time.Minute)}
+	if spot.Expired(now) {
+		t.Fatal("spot event before its deadline reported expired")
+	}
+	if !spot.Expired(now.Add(2 * time.Minute)) {
+		t.Fatal("spot event past its deadline not reported expired")
+	}
+	if (Event{Kind: KindRebalanceRecommendation}).Expired(now.Add(time.Hour)) {
+		t.Fatal("rebalance recommendation has no deadline and never expires")
+	}
+}
@@ -0,0 +1,243 @@
+package interruption
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileSourceConfig configures the JSON-lines file stand-in.
+type FileSourceConfig struct {
+	// Path is a file of EventBridge EC2 events, one JSON object per line.
+	// Lines appended while the source runs are picked up on the next poll.
+	Path string
+
+	// OffsetPath persists how far the file has been read, so a restart does
+	// not replay old events. Default: Path + ".offset".
+	OffsetPath string
+
+	// PollInterval is how often the file is checked for new lines. Default: 1s.
+	PollInterval time.Duration
+
+	Logger *slog.Logger
+}
+
+// FileSource tails a JSON-lines file of EventBridge events. It stands in for
+// SQS in tests and on clusters without an interruption queue.
+type FileSource struct {
+	cfg    FileSourceConfig
+	logger *slog.Logger
+	offset int64
+}
+
+// NewFileSource creates a file source. The file does not need to exist yet.
+// Reading resumes from the persisted offset, if any.
+func NewFileSource(cfg FileSourceConfig) (*FileSource, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("interruption event file path is required")
+	}
+	if cfg.OffsetPath == "" {
+		cfg.OffsetPath = cfg.Path + ".offset"
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	s := &FileSource{cfg: cfg, logger: logger}
+	s.offset = s.loadOffset()
+	return s, nil
+}
+
+// Name implements Source.
+func (s *FileSource) Name() string { return "file" }
+
+// Run implements Source.
+func (s *FileSource) Run(ctx context.Context, out chan<- Event) error {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if !s.poll(ctx, out) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll emits events for complete lines appended since the last poll. It
+// returns false when ctx was cancelled while sending.
+func (s *FileSource) poll(ctx context.Context, out chan<- Event) bool {
+	f, err := os.Open(s.cfg.Path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			s.logger.Warn("failed to open interruption event file", "path", s.cfg.Path, "error", err)
+		}
+		return true
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return true
+	}
+	if info.Size() < s.offset {
+		// Truncated or replaced: start over.
+		s.offset = 0
+	}
+	if _, err := f.Seek(s.offset, io.SeekStart); err != nil {
+		return true
+	}
+
+	start := s.offset
+	defer func() {
+		if s.offset != start {
+			s.saveOffset()
+		}
+	}()
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// Partial trailing line: wait for the writer to finish it.
+			return true
+		}
+		s.offset += int64(len(line))
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		ev, ok, err := ParseEventBridge(line)
+		if err != nil {
+			s.logger.Warn("skipping malformed interruption event", "path", s.cfg.Path, "error", err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if ev.Expired(time.Now()) {
+			s.logger.Info("dropping expired interruption event",
+				"path", s.cfg.Path,
+				"instance_id", ev.InstanceID,
+				"node", ev.NodeName,
+				"deadline", ev.Deadline,
+			)
+			continue
+		}
+		ev.Source = s.Name()
+		if !send(ctx, out, ev) {
+			return false
+		}
+	}
+}
+
+// loadOffset returns the persisted read offset, or 0 when there is none.
+func (s *FileSource) loadOffset() int64 {
+	data, err := os.ReadFile(s.cfg.OffsetPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			s.logger.Warn("failed to read interruption event file offset", "path", s.cfg.OffsetPath, "error", err)
+		}
+		return 0
+	}
+	offset, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil || offset < 0 {
+		s.logger.Warn("ignoring invalid interruption event file offset", "path", s.cfg.OffsetPath)
+		return 0
+	}
+	return offset
+}
+
+// saveOffset persists the read offset through a rename, so a crash never
+// leaves a partial value behind.
+func (s *FileSource) saveOffset() {
+	tmp, err := os.CreateTemp(filepath.Dir(s.cfg.OffsetPath), filepath.Base(s.cfg.OffsetPath)+".tmp-*")
+	if err != nil {
+		s.logger.Warn("failed to persist interruption event file offset", "path", s.cfg.OffsetPath, "error", err)
+		return
+	}
+	_, err = tmp.WriteString(strconv.FormatInt(s.offset, 10) + "\n")
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.cfg.OffsetPath)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		s.logger.Warn("failed to persist interruption event file offset", "path", s.cfg.OffsetPath, "error", err)
+	}
+}
+
+// HTTPSource accepts EventBridge EC2 events via POST. It stands in for SQS
+// in tests. It is unauthenticated, so mount it on a loopback-only listener.
+type HTTPSource struct {
+	events chan Event
+}
+
+// NewHTTPSource creates an HTTP source that buffers up to 64 pending events.
+func NewHTTPSource() *HTTPSource {
+	return &HTTPSource{events: make(chan Event, 64)}
+}
+
+// Name implements Source.
+func (s *HTTPSource) Name() string { return "http" }
+
+// Run implements Source.
+func (s *HTTPSource) Run(ctx context.Context, out chan<- Event) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev := <-s.events:
+			if !send(ctx, out, ev) {
+				return nil
+			}
+		}
+	}
+}
+
+// ServeHTTP implements http.Handler. Responds 202 for interruption events,
+// 204 for other EC2 events, and 503 when the buffer is full.
+func (s *HTTPSource) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ev, ok, err := ParseEventBridge(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	ev.Source = s.Name()
+	select {
+	case s.events <- ev:
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		http.Error(w, "interruption event buffer full", http.StatusServiceUnavailable)
+	}
+}
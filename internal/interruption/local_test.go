@@ -0,0 +1,133 @@
+package interruption
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileSource_TailsAppendedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	src, err := NewFileSource(FileSourceConfig{Path: path, PollInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewFileSource() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := make(chan Event, 4)
+	go func() { _ = src.Run(ctx, out) }()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	// Malformed, unrelated and expired lines are skipped; a partial line waits.
+	warning := spotWarningAt(time.Now())
+	if _, err := f.WriteString("garbage\n{\"detail-type\":\"other\"}\n" + spotWarning + "\n" + warning[:20]); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	select {
+	case ev := <-out:
+		t.Fatalf("unexpected event before line completed: %+v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if _, err := f.WriteString(warning[20:] + "\n"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	select {
+	case ev := <-out:
+		if ev.InstanceID != "i-0abc" || ev.Source != "file" {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for file event")
+	}
+}
+
+func TestFileSource_ResumesFromPersistedOffset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	first := spotWarningAt(time.Now()) + "\n"
+	if err := os.WriteFile(path, []byte(first), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	src, err := NewFileSource(FileSourceConfig{Path: path})
+	if err != nil {
+		t.Fatalf("NewFileSource() error = %v", err)
+	}
+	out := make(chan Event, 4)
+	src.poll(context.Background(), out)
+	if len(out) != 1 {
+		t.Fatalf("events=%d, want 1", len(out))
+	}
+	<-out
+
+	// A restarted source skips what was already read.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if _, err := f.WriteString(`{"detail-type":"EC2 Instance Rebalance Recommendation","detail":{"instance-id":"i-1"}}` + "\n"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	f.Close()
+
+	restarted, err := NewFileSource(FileSourceConfig{Path: path})
+	if err != nil {
+		t.Fatalf("NewFileSource() error = %v", err)
+	}
+	if restarted.offset != int64(len(first)) {
+		t.Fatalf("restored offset=%d, want %d", restarted.offset, len(first))
+	}
+	restarted.poll(context.Background(), out)
+	if len(out) != 1 {
+		t.Fatalf("events after restart=%d, want 1", len(out))
+	}
+	if ev := <-out; ev.Kind != KindRebalanceRecommendation {
+		t.Fatalf("replayed event after restart: %+v", ev)
+	}
+}
+
+func TestHTTPSource_ServeHTTP(t *testing.T) {
+	src := NewHTTPSource()
+
+	post := func(body string) int {
+		rec := httptest.NewRecorder()
+		src.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/interruptions", strings.NewReader(body)))
+		return rec.Code
+	}
+	if code := post(spotWarning); code != http.StatusAccepted {
+		t.Fatalf("interruption status=%d, want 202", code)
+	}
+	if code := post(`{"detail-type":"other"}`); code != http.StatusNoContent {
+		t.Fatalf("other event status=%d, want 204", code)
+	}
+	if code := post(`{`); code != http.StatusBadRequest {
+		t.Fatalf("malformed status=%d, want 400", code)
+	}
+	rec := httptest.NewRecorder()
+	src.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/interruptions", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("GET status=%d, want 405", rec.Code)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := make(chan Event, 1)
+	go func() { _ = src.Run(ctx, out) }()
+	select {
+	case ev := <-out:
+		if ev.Kind != KindSpotInterruption || ev.Source != "http" {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for http event")
+	}
+}
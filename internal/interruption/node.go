@@ -0,0 +1,163 @@
+package interruption
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/kubecache"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// TaintSpotInterruption is set by aws-node-termination-handler when it
+	// receives a Spot interruption notice for the node.
+	TaintSpotInterruption = "aws-node-termination-handler/spot-itn"
+	// TaintRebalanceRecommendation is set by aws-node-termination-handler for
+	// rebalance recommendations.
+	TaintRebalanceRecommendation = "aws-node-termination-handler/rebalance-recommendation"
+
+	// ConditionSpotInterruption and ConditionRebalanceRecommendation are node
+	// condition types a node-problem-detector style agent may set instead of
+	// taints. The condition counts while its status is True.
+	ConditionSpotInterruption        corev1.NodeConditionType = "SpotInterruption"
+	ConditionRebalanceRecommendation corev1.NodeConditionType = "RebalanceRecommendation"
+)
+
+// NodeSourceConfig configures the node taint/condition source.
+type NodeSourceConfig struct {
+	K8sClient kubernetes.Interface
+
+	// Cache serves nodes from the shared informers once synced, so polling
+	// does not list every node from the API server. Nil keeps API reads.
+	Cache *kubecache.Cache
+
+	// PollInterval is how often nodes are checked. Default: 5s.
+	PollInterval time.Duration
+
+	Logger *slog.Logger
+}
+
+// NodeSource watches nodes for interruption taints and conditions written by
+// node-termination-handler style agents. Each node emits at most one event
+// per kind until the marker is removed.
+type NodeSource struct {
+	cfg    NodeSourceConfig
+	logger *slog.Logger
+	seen   map[string]Kind // node/kind -> emitted
+}
+
+// NewNodeSource creates a node source.
+func NewNodeSource(cfg NodeSourceConfig) (*NodeSource, error) {
+	if cfg.K8sClient == nil {
+		return nil, fmt.Errorf("k8s client is required")
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &NodeSource{cfg: cfg, logger: logger, seen: make(map[string]Kind)}, nil
+}
+
+// Name implements Source.
+func (s *NodeSource) Name() string { return "node" }
+
+// Run implements Source.
+func (s *NodeSource) Run(ctx context.Context, out chan<- Event) error {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if !s.poll(ctx, out) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *NodeSource) poll(ctx context.Context, out chan<- Event) bool {
+	nodes, err := s.listNodes(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Warn("failed to list nodes for interruption markers", "error", err)
+		}
+		return ctx.Err() == nil
+	}
+
+	current := make(map[string]Kind)
+	for _, node := range nodes {
+		for _, kind := range nodeInterruptionKinds(node) {
+			key := node.Name + "/" + string(kind)
+			current[key] = kind
+			if _, emitted := s.seen[key]; emitted {
+				continue
+			}
+			ev := Event{Kind: kind, NodeName: node.Name, Time: time.Now(), Source: s.Name()}
+			if kind == KindSpotInterruption {
+				ev.Deadline = ev.Time.Add(SpotInterruptionNotice)
+			}
+			if !send(ctx, out, ev) {
+				return false
+			}
+		}
+	}
+	s.seen = current
+	return true
+}
+
+// listNodes reads the informer cache once it has synced and the API server
+// before that.
+func (s *NodeSource) listNodes(ctx context.Context) ([]*corev1.Node, error) {
+	if s.cfg.Cache.HasSynced() {
+		return s.cfg.Cache.Nodes()
+	}
+	list, err := s.cfg.K8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*corev1.Node, 0, len(list.Items))
+	for i := range list.Items {
+		nodes = append(nodes, &list.Items[i])
+	}
+	return nodes, nil
+}
+
+func nodeInterruptionKinds(node *corev1.Node) []Kind {
+	var spot, rebalance bool
+	for _, taint := range node.Spec.Taints {
+		switch taint.Key {
+		case TaintSpotInterruption:
+			spot = true
+		case TaintRebalanceRecommendation:
+			rebalance = true
+		}
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case ConditionSpotInterruption:
+			spot = true
+		case ConditionRebalanceRecommendation:
+			rebalance = true
+		}
+	}
+	var kinds []Kind
+	if spot {
+		kinds = append(kinds, KindSpotInterruption)
+	}
+	if rebalance {
+		kinds = append(kinds, KindRebalanceRecommendation)
+	}
+	return kinds
+}
@@ -0,0 +1,107 @@
+package interruption
+
+import (
+	"context"
+	"testing"
+
+	"github.com/softcane/spot-vortex-agent/internal/kubecache"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNodeSource_EmitsOncePerMarker(t *testing.T) {
+	client := k8sfake.NewSimpleClientset(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "tainted"},
+			Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+				{Key: TaintSpotInterruption, Effect: corev1.TaintEffectNoSchedule},
+			}},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "conditioned"},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: ConditionRebalanceRecommendation, Status: corev1.ConditionTrue},
+				{Type: ConditionSpotInterruption, Status: corev1.ConditionFalse},
+			}},
+		},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "healthy"}},
+	)
+	src, err := NewNodeSource(NodeSourceConfig{K8sClient: client})
+	if err != nil {
+		t.Fatalf("NewNodeSource() error = %v", err)
+	}
+
+	ctx := context.Background()
+	out := make(chan Event, 8)
+	if !src.poll(ctx, out) {
+		t.Fatal("poll returned false")
+	}
+	got := map[string]Kind{}
+	for len(out) > 0 {
+		ev := <-out
+		got[ev.NodeName] = ev.Kind
+		if ev.Kind == KindSpotInterruption && ev.Deadline.IsZero() {
+			t.Fatal("spot interruption event missing deadline")
+		}
+	}
+	if len(got) != 2 || got["tainted"] != KindSpotInterruption || got["conditioned"] != KindRebalanceRecommendation {
+		t.Fatalf("events=%v", got)
+	}
+
+	// Markers still present: no repeat.
+	src.poll(ctx, out)
+	if len(out) != 0 {
+		t.Fatalf("repeat events emitted: %d", len(out))
+	}
+
+	// Marker cleared then re-added: emitted again.
+	node, _ := client.CoreV1().Nodes().Get(ctx, "tainted", metav1.GetOptions{})
+	taints := node.Spec.Taints
+	node.Spec.Taints = nil
+	_, _ = client.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+	src.poll(ctx, out)
+	node.Spec.Taints = taints
+	_, _ = client.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+	src.poll(ctx, out)
+	if len(out) != 1 {
+		t.Fatalf("events after re-taint=%d, want 1", len(out))
+	}
+}
+
+func TestNodeSource_ReadsSyncedCache(t *testing.T) {
+	client := k8sfake.NewSimpleClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "tainted"},
+		Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+			{Key: TaintRebalanceRecommendation, Effect: corev1.TaintEffectNoSchedule},
+		}},
+	})
+	kc, err := kubecache.New(client, kubecache.Config{})
+	if err != nil {
+		t.Fatalf("kubecache.New: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kc.Start(ctx)
+	if err := kc.WaitForSync(ctx); err != nil {
+		t.Fatalf("WaitForSync: %v", err)
+	}
+
+	src, err := NewNodeSource(NodeSourceConfig{K8sClient: client, Cache: kc})
+	if err != nil {
+		t.Fatalf("NewNodeSource() error = %v", err)
+	}
+	client.ClearActions()
+	out := make(chan Event, 8)
+	if !src.poll(ctx, out) {
+		t.Fatal("poll returned false")
+	}
+	if len(out) != 1 {
+		t.Fatalf("events=%d, want 1", len(out))
+	}
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "list" {
+			t.Fatalf("poll listed %s from the API server despite a synced cache", action.GetResource().Resource)
+		}
+	}
+}
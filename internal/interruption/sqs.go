@@ -0,0 +1,142 @@
+package interruption
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// SQSAPI is the subset of the SQS client used by SQSSource.
+type SQSAPI interface {
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error)
+}
+
+// SQSSourceConfig configures the EventBridge -> SQS source.
+type SQSSourceConfig struct {
+	// Client is the SQS client. Required; see NewAWSSQSClient.
+	Client SQSAPI
+
+	// QueueURL is the queue that EventBridge delivers EC2 events to.
+	QueueURL string
+
+	// WaitTimeSeconds is the long-poll duration. Default: 20 (the SQS max).
+	WaitTimeSeconds int32
+
+	// MaxMessages per receive call. Default: 10 (the SQS max).
+	MaxMessages int32
+
+	// ErrorBackoff is how long to wait after a failed receive. Default: 5s.
+	ErrorBackoff time.Duration
+
+	Logger *slog.Logger
+}
+
+// SQSSource long-polls an SQS queue fed by EventBridge rules for EC2 Spot
+// interruption warnings and rebalance recommendations.
+type SQSSource struct {
+	cfg    SQSSourceConfig
+	logger *slog.Logger
+}
+
+// NewAWSSQSClient creates a real SQS client for the given region.
+func NewAWSSQSClient(ctx context.Context, region string) (*sqs.Client, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return sqs.NewFromConfig(awsCfg), nil
+}
+
+// NewSQSSource creates an SQS source.
+func NewSQSSource(cfg SQSSourceConfig) (*SQSSource, error) {
+	if cfg.Client == nil {
+		return nil, fmt.Errorf("sqs client is required")
+	}
+	if cfg.QueueURL == "" {
+		return nil, fmt.Errorf("sqs queue URL is required")
+	}
+	if cfg.WaitTimeSeconds <= 0 || cfg.WaitTimeSeconds > 20 {
+		cfg.WaitTimeSeconds = 20
+	}
+	if cfg.MaxMessages <= 0 || cfg.MaxMessages > 10 {
+		cfg.MaxMessages = 10
+	}
+	if cfg.ErrorBackoff <= 0 {
+		cfg.ErrorBackoff = 5 * time.Second
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SQSSource{cfg: cfg, logger: logger}, nil
+}
+
+// Name implements Source.
+func (s *SQSSource) Name() string { return "sqs" }
+
+// Run implements Source. Messages are deleted once their event is handed
+// off; messages that are not interruption events, or whose deadline has
+// passed, are deleted too so they do not loop back through the queue.
+func (s *SQSSource) Run(ctx context.Context, out chan<- Event) error {
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+		resp, err := s.cfg.Client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(s.cfg.QueueURL),
+			MaxNumberOfMessages: s.cfg.MaxMessages,
+			WaitTimeSeconds:     s.cfg.WaitTimeSeconds,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			s.logger.Warn("failed to receive interruption messages", "queue_url", s.cfg.QueueURL, "error", err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(s.cfg.ErrorBackoff):
+			}
+			continue
+		}
+
+		for _, msg := range resp.Messages {
+			ev, ok, err := ParseEventBridge([]byte(aws.ToString(msg.Body)))
+			if err != nil {
+				s.logger.Warn("dropping malformed interruption message",
+					"message_id", aws.ToString(msg.MessageId),
+					"error", err,
+				)
+			}
+			if ok && ev.Expired(time.Now()) {
+				s.logger.Info("dropping expired interruption message",
+					"message_id", aws.ToString(msg.MessageId),
+					"instance_id", ev.InstanceID,
+					"deadline", ev.Deadline,
+				)
+				ok = false
+			}
+			if ok {
+				ev.Source = s.Name()
+				if !send(ctx, out, ev) {
+					return nil
+				}
+			}
+			if _, err := s.cfg.Client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+				QueueUrl:      aws.String(s.cfg.QueueURL),
+				ReceiptHandle: msg.ReceiptHandle,
+			}); err != nil {
+				s.logger.Warn("failed to delete interruption message",
+					"message_id", aws.ToString(msg.MessageId),
+					"error", err,
+				)
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package interruption
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+type fakeSQS struct {
+	mu       sync.Mutex
+	batches  [][]types.Message
+	deleted  []string
+	received int
+}
+
+func (f *fakeSQS) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, _ ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	f.mu.Lock()
+	f.received++
+	if len(f.batches) > 0 {
+		batch := f.batches[0]
+		f.batches = f.batches[1:]
+		f.mu.Unlock()
+		return &sqs.ReceiveMessageOutput{Messages: batch}, nil
+	}
+	f.mu.Unlock()
+	// Simulate an empty long poll.
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(10 * time.Millisecond):
+		return &sqs.ReceiveMessageOutput{}, nil
+	}
+}
+
+func (f *fakeSQS) DeleteMessage(_ context.Context, params *sqs.DeleteMessageInput, _ ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deleted = append(f.deleted, aws.ToString(params.ReceiptHandle))
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func (f *fakeSQS) deletedHandles() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.deleted...)
+}
+
+func TestSQSSource_EmitsEventsAndDeletesMessages(t *testing.T) {
+	client := &fakeSQS{batches: [][]types.Message{{
+		// Expired: its two-minute deadline passed long ago.
+		{MessageId: aws.String("m0"), ReceiptHandle: aws.String("r0"), Body: aws.String(spotWarning)},
+		{MessageId: aws.String("m1"), ReceiptHandle: aws.String("r1"), Body: aws.String(spotWarningAt(time.Now()))},
+		{MessageId: aws.String("m2"), ReceiptHandle: aws.String("r2"), Body: aws.String(`{"detail-type":"other"}`)},
+		{MessageId: aws.String("m3"), ReceiptHandle: aws.String("r3"), Body: aws.String(`not json`)},
+	}}}
+	src, err := NewSQSSource(SQSSourceConfig{Client: client, QueueURL: "https://sqs.example/queue"})
+	if err != nil {
+		t.Fatalf("NewSQSSource() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := make(chan Event, 4)
+	done := make(chan struct{})
+	go func() {
+		_ = src.Run(ctx, out)
+		close(done)
+	}()
+
+	select {
+	case ev := <-out:
+		if ev.InstanceID != "i-0abc" || ev.Source != "sqs" {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for sqs event")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(client.deletedHandles()) < 4 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := client.deletedHandles(); len(got) != 4 {
+		t.Fatalf("deleted=%v, want all 4 messages deleted", got)
+	}
+	if len(out) != 0 {
+		t.Fatalf("expired message emitted: %+v", <-out)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+func TestNewSQSSource_RequiresClientAndQueue(t *testing.T) {
+	if _, err := NewSQSSource(SQSSourceConfig{QueueURL: "q"}); err == nil {
+		t.Fatal("expected error without client")
+	}
+	if _, err := NewSQSSource(SQSSourceConfig{Client: &fakeSQS{}}); err == nil {
+		t.Fatal("expected error without queue URL")
+	}
+}
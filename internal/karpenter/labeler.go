@@ -1,9 +1,12 @@
 // Package karpenter provides Karpenter-specific integration for SpotVortex.
 //
-// When Karpenter is detected, SpotVortex operates in "Label-Only Mode":
-// - Instead of cordoning/draining nodes directly
-// - It applies the spotvortex.io/risk label
-// - Karpenter's Drift mechanism handles the rest
+// By default SpotVortex steers NodePools and drains nodes itself. In handoff
+// mode (karpenter.disruptionMode: handoff) Karpenter does the replacement:
+//   - NodeClaims already drifted by NodePool requirement changes are left to
+//     Karpenter's disruption controller and budgets
+//   - Other NodeClaims are deleted, and Karpenter's termination flow drains them
+//   - High-risk nodes hosting critical pods get karpenter.sh/do-not-disrupt so
+//     Karpenter's own disruption leaves them to SpotVortex
 //
 // Architecture: architecture.md (Karpenter Provider)
 // Guardrails: mission_guardrail.md (Karpenter First)
@@ -11,10 +14,12 @@ package karpenter
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -36,6 +41,13 @@ const (
 
 	// MarketVolatile indicates the spot market is volatile.
 	MarketVolatile = "volatile"
+
+	// DoNotDisruptAnnotation blocks Karpenter's voluntary disruption of a node.
+	DoNotDisruptAnnotation = "karpenter.sh/do-not-disrupt"
+
+	// ProtectedAnnotation marks a do-not-disrupt annotation SpotVortex set, so
+	// it only ever removes its own.
+	ProtectedAnnotation = "spotvortex.io/do-not-disrupt"
 )
 
 // Labeler manages Karpenter-compatible node labels.
@@ -150,6 +162,82 @@ func (l *Labeler) ResetNodeRisk(ctx context.Context, nodeName string) error {
 	return nil
 }
 
+// ProtectNode sets karpenter.sh/do-not-disrupt on a node. A node already
+// carrying the annotation is left as is.
+func (l *Labeler) ProtectNode(ctx context.Context, nodeName, reason string) error {
+	if l.dryRun {
+		l.logger.Info("DRY-RUN: would protect node from Karpenter disruption",
+			"node", nodeName,
+			"reason", reason,
+		)
+		return nil
+	}
+
+	node, err := l.k8s.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+	if _, ok := node.Annotations[DoNotDisruptAnnotation]; ok {
+		return nil
+	}
+
+	if err := l.patchAnnotations(ctx, nodeName, map[string]interface{}{
+		DoNotDisruptAnnotation: "true",
+		ProtectedAnnotation:    reason,
+	}); err != nil {
+		return err
+	}
+
+	l.logger.Info("node protected from Karpenter disruption",
+		"node", nodeName,
+		"reason", reason,
+	)
+	return nil
+}
+
+// UnprotectNode removes a karpenter.sh/do-not-disrupt annotation that
+// ProtectNode set. Annotations set by anyone else are kept.
+func (l *Labeler) UnprotectNode(ctx context.Context, nodeName string) error {
+	if l.dryRun {
+		l.logger.Info("DRY-RUN: would unprotect node", "node", nodeName)
+		return nil
+	}
+
+	node, err := l.k8s.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+	if _, ok := node.Annotations[ProtectedAnnotation]; !ok {
+		return nil
+	}
+
+	if err := l.patchAnnotations(ctx, nodeName, map[string]interface{}{
+		DoNotDisruptAnnotation: nil,
+		ProtectedAnnotation:    nil,
+	}); err != nil {
+		return err
+	}
+
+	l.logger.Info("node protection from Karpenter disruption removed", "node", nodeName)
+	return nil
+}
+
+// patchAnnotations merge-patches node annotations; a nil value removes the
+// key. Unlike Get+Update it cannot overwrite concurrent node changes, such as
+// Karpenter's or the kubelet's.
+func (l *Labeler) patchAnnotations(ctx context.Context, nodeName string, annotations map[string]interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": annotations},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal patch: %w", err)
+	}
+	if _, err := l.k8s.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to patch node %s: %w", nodeName, err)
+	}
+	return nil
+}
+
 // IsKarpenterInstalled checks if Karpenter CRDs are present in the cluster.
 func (l *Labeler) IsKarpenterInstalled(ctx context.Context) bool {
 	// Check for NodePool CRD (Karpenter v1+)
@@ -159,7 +247,7 @@ func (l *Labeler) IsKarpenterInstalled(ctx context.Context) bool {
 		DoRaw(ctx)
 
 	if err == nil {
-		l.logger.Info("Karpenter detected: enabling Label-Only mode")
+		l.logger.Info("Karpenter detected")
 		return true
 	}
 
@@ -170,7 +258,7 @@ func (l *Labeler) IsKarpenterInstalled(ctx context.Context) bool {
 		DoRaw(ctx)
 
 	if err == nil {
-		l.logger.Info("Karpenter (v1beta1) detected: enabling Label-Only mode")
+		l.logger.Info("Karpenter (v1beta1) detected")
 		return true
 	}
 
@@ -115,3 +115,45 @@ func TestLabeler_Wrappers(t *testing.T) {
 		t.Error("MarkNodeLowRisk failed")
 	}
 }
+
+func TestLabeler_ProtectNodeOnlyRemovesItsOwnAnnotation(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:        "worker-2",
+			Annotations: map[string]string{DoNotDisruptAnnotation: "true"},
+		}},
+	)
+	labeler := NewLabeler(client, slog.Default(), false)
+	ctx := context.Background()
+
+	for _, name := range []string{"worker-1", "worker-2"} {
+		if err := labeler.ProtectNode(ctx, name, "critical pod"); err != nil {
+			t.Fatalf("ProtectNode(%s): %v", name, err)
+		}
+	}
+	node, _ := client.CoreV1().Nodes().Get(ctx, "worker-1", metav1.GetOptions{})
+	if node.Annotations[DoNotDisruptAnnotation] != "true" || node.Annotations[ProtectedAnnotation] != "critical pod" {
+		t.Fatalf("annotations=%v, want do-not-disrupt set by SpotVortex", node.Annotations)
+	}
+
+	for _, name := range []string{"worker-1", "worker-2"} {
+		if err := labeler.UnprotectNode(ctx, name); err != nil {
+			t.Fatalf("UnprotectNode(%s): %v", name, err)
+		}
+	}
+	node, _ = client.CoreV1().Nodes().Get(ctx, "worker-1", metav1.GetOptions{})
+	if _, ok := node.Annotations[DoNotDisruptAnnotation]; ok {
+		t.Fatal("expected SpotVortex's do-not-disrupt to be removed")
+	}
+	node, _ = client.CoreV1().Nodes().Get(ctx, "worker-2", metav1.GetOptions{})
+	if node.Annotations[DoNotDisruptAnnotation] != "true" {
+		t.Fatal("a do-not-disrupt SpotVortex did not set must be kept")
+	}
+	// Patches only: a full Update could overwrite concurrent node changes.
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "update" {
+			t.Fatalf("unexpected node update: %v", action)
+		}
+	}
+}
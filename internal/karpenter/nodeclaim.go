@@ -0,0 +1,310 @@
+package karpenter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+)
+
+// NodeClaim GVR for Karpenter v1
+var nodeClaimGVR = schema.GroupVersionResource{
+	Group:    "karpenter.sh",
+	Version:  "v1",
+	Resource: "nodeclaims",
+}
+
+const (
+	// NodePoolLabel names the NodePool that owns a NodeClaim or node.
+	NodePoolLabel = "karpenter.sh/nodepool"
+	// PreProvisionedLabel marks NodeClaims SpotVortex created ahead of a drain.
+	PreProvisionedLabel = "spotvortex.io/pre-provisioned"
+)
+
+// NodeClaim is the part of a Karpenter NodeClaim that replacement readiness
+// needs.
+type NodeClaim struct {
+	Name         string
+	NodePool     string
+	CapacityType string // launched capacity type, or the single requested one
+	NodeName     string // set once the node registers
+	Initialized  bool
+	Drifted      bool // Karpenter will replace it under the NodePool's disruption budgets
+}
+
+// ListNodeClaims returns the NodeClaims owned by a NodePool.
+func (m *NodePoolManager) ListNodeClaims(ctx context.Context, poolName string) ([]NodeClaim, error) {
+	if m.dynamicClient == nil {
+		return nil, fmt.Errorf("dynamic client not configured")
+	}
+	list, err := m.dynamicClient.Resource(nodeClaimGVR).List(ctx, metav1.ListOptions{
+		LabelSelector: NodePoolLabel + "=" + poolName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list NodeClaims for NodePool %s: %w", poolName, err)
+	}
+
+	claims := make([]NodeClaim, 0, len(list.Items))
+	for _, item := range list.Items {
+		claims = append(claims, NodeClaim{
+			Name:         item.GetName(),
+			NodePool:     poolName,
+			CapacityType: nodeClaimCapacityType(item.Object),
+			NodeName:     nestedString(item.Object, "status", "nodeName"),
+			Initialized:  nodeClaimCondition(item.Object, "Initialized"),
+			Drifted:      nodeClaimCondition(item.Object, "Drifted"),
+		})
+	}
+	return claims, nil
+}
+
+// NodeClaimForNode returns the NodeClaim of a NodePool that launched nodeName.
+func (m *NodePoolManager) NodeClaimForNode(ctx context.Context, poolName, nodeName string) (NodeClaim, error) {
+	claims, err := m.ListNodeClaims(ctx, poolName)
+	if err != nil {
+		return NodeClaim{}, err
+	}
+	for _, claim := range claims {
+		if claim.NodeName == nodeName {
+			return claim, nil
+		}
+	}
+	return NodeClaim{}, fmt.Errorf("no NodeClaim in NodePool %s for node %s", poolName, nodeName)
+}
+
+// CreateNodeClaim pre-provisions one NodeClaim from the NodePool's template,
+// restricted to capacityType, and returns its name. Karpenter launches it
+// like any NodeClaim of the pool, and it is owned by the NodePool.
+func (m *NodePoolManager) CreateNodeClaim(ctx context.Context, poolName, capacityType string) (string, error) {
+	if m.dynamicClient == nil {
+		return "", fmt.Errorf("dynamic client not configured")
+	}
+	nodePool, err := m.dynamicClient.Resource(nodePoolGVR).Get(ctx, poolName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get NodePool %s: %w", poolName, err)
+	}
+
+	spec, found, err := unstructured.NestedMap(nodePool.Object, "spec", "template", "spec")
+	if err != nil || !found {
+		return "", fmt.Errorf("template spec not found in NodePool %s", poolName)
+	}
+	requirements, _, _ := unstructured.NestedSlice(spec, "requirements")
+	spec["requirements"] = withCapacityTypes(requirements, []string{capacityType})
+
+	labels, _, _ := unstructured.NestedStringMap(nodePool.Object, "spec", "template", "metadata", "labels")
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[NodePoolLabel] = poolName
+	labels[PreProvisionedLabel] = "true"
+	metadata := map[string]interface{}{
+		"name":   poolName + "-" + utilrand.String(5),
+		"labels": stringMapToInterface(labels),
+	}
+	if uid := nodePool.GetUID(); uid != "" {
+		metadata["ownerReferences"] = []interface{}{map[string]interface{}{
+			"apiVersion":         "karpenter.sh/v1",
+			"kind":               "NodePool",
+			"name":               poolName,
+			"uid":                string(uid),
+			"blockOwnerDeletion": true,
+		}}
+	}
+
+	claim := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "karpenter.sh/v1",
+		"kind":       "NodeClaim",
+		"metadata":   metadata,
+		"spec":       spec,
+	}}
+	created, err := m.dynamicClient.Resource(nodeClaimGVR).Create(ctx, claim, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to create NodeClaim for NodePool %s: %w", poolName, err)
+	}
+
+	m.logger.Info("pre-provisioned NodeClaim",
+		"nodepool", poolName,
+		"nodeclaim", created.GetName(),
+		"capacity_type", capacityType,
+	)
+	return created.GetName(), nil
+}
+
+// DeleteNodeClaim deletes a NodeClaim; Karpenter terminates its instance.
+func (m *NodePoolManager) DeleteNodeClaim(ctx context.Context, name string) error {
+	if m.dynamicClient == nil {
+		return fmt.Errorf("dynamic client not configured")
+	}
+	if err := m.dynamicClient.Resource(nodeClaimGVR).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("failed to delete NodeClaim %s: %w", name, err)
+	}
+	return nil
+}
+
+// WaitForNodeClaim waits until a NodeClaim of capacityType in the NodePool
+// that was not already Initialized when the wait began reaches Initialized,
+// and returns it. A launch already in flight is waited for, including one
+// whose capacity type is not yet known; otherwise one NodeClaim is
+// pre-provisioned, and deleted again on timeout.
+func (m *NodePoolManager) WaitForNodeClaim(ctx context.Context, poolName, capacityType string, timeout, pollInterval time.Duration) (NodeClaim, error) {
+	claims, err := m.ListNodeClaims(ctx, poolName)
+	if err != nil {
+		return NodeClaim{}, err
+	}
+	existing := make(map[string]bool, len(claims))
+	inFlight := false
+	for _, claim := range claims {
+		if claim.Initialized {
+			existing[claim.Name] = true
+		} else if claim.CapacityType == capacityType || claim.CapacityType == "" {
+			inFlight = true
+		}
+	}
+
+	created := ""
+	if !inFlight {
+		created, err = m.CreateNodeClaim(ctx, poolName, capacityType)
+		if err != nil {
+			return NodeClaim{}, err
+		}
+	}
+
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.discardNodeClaim(created)
+			return NodeClaim{}, ctx.Err()
+		case <-deadline:
+			m.discardNodeClaim(created)
+			return NodeClaim{}, fmt.Errorf("timeout after %v waiting for an Initialized %s NodeClaim in NodePool %q",
+				timeout, capacityType, poolName)
+		case <-ticker.C:
+			claims, err := m.ListNodeClaims(ctx, poolName)
+			if err != nil {
+				m.logger.Warn("failed to list NodeClaims during wait", "nodepool", poolName, "error", err)
+				continue
+			}
+			for _, claim := range claims {
+				if claim.Initialized && !existing[claim.Name] && claim.CapacityType == capacityType {
+					return claim, nil
+				}
+			}
+		}
+	}
+}
+
+// ReplacementWait is replacement capacity a drain waits for: an Initialized
+// NodeClaim of CapacityType in NodePool.
+type ReplacementWait struct {
+	NodePool     string
+	CapacityType string
+}
+
+// ReplacementResult is the outcome of one ReplacementWait.
+type ReplacementResult struct {
+	Claim    NodeClaim
+	Err      error
+	Duration time.Duration
+}
+
+// WaitForNodeClaims runs WaitForNodeClaim for every wait concurrently, all
+// bounded by one deadline timeout from now, and returns the results in the
+// order of waits.
+func (m *NodePoolManager) WaitForNodeClaims(ctx context.Context, waits []ReplacementWait, timeout, pollInterval time.Duration) []ReplacementResult {
+	deadline := time.Now().Add(timeout)
+	results := make([]ReplacementResult, len(waits))
+
+	var wg sync.WaitGroup
+	for i, w := range waits {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			claim, err := m.WaitForNodeClaim(ctx, w.NodePool, w.CapacityType, time.Until(deadline), pollInterval)
+			results[i] = ReplacementResult{Claim: claim, Err: err, Duration: time.Since(start)}
+			if err != nil {
+				m.logger.Warn("replacement NodeClaim not initialized",
+					"nodepool", w.NodePool,
+					"capacity_type", w.CapacityType,
+					"error", err,
+				)
+				return
+			}
+			m.logger.Info("replacement NodeClaim initialized",
+				"nodepool", w.NodePool,
+				"capacity_type", w.CapacityType,
+				"nodeclaim", claim.Name,
+				"replacement_node", claim.NodeName,
+				"duration", results[i].Duration,
+			)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// discardNodeClaim deletes a pre-provisioned NodeClaim nobody will use.
+func (m *NodePoolManager) discardNodeClaim(name string) {
+	if name == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := m.DeleteNodeClaim(ctx, name); err != nil {
+		m.logger.Warn("failed to delete pre-provisioned NodeClaim", "nodeclaim", name, "error", err)
+	}
+}
+
+// nodeClaimCapacityType reads the launched capacity type from the NodeClaim's
+// labels, falling back to its requirement when it allows exactly one type.
+func nodeClaimCapacityType(obj map[string]interface{}) string {
+	if labels, _, _ := unstructured.NestedStringMap(obj, "metadata", "labels"); labels[CapacityTypeLabel] != "" {
+		return labels[CapacityTypeLabel]
+	}
+	requirements, _, _ := unstructured.NestedSlice(obj, "spec", "requirements")
+	for _, req := range requirements {
+		reqMap, ok := req.(map[string]interface{})
+		if !ok || reqMap["key"] != CapacityTypeLabel {
+			continue
+		}
+		values, _, _ := unstructured.NestedStringSlice(reqMap, "values")
+		if len(values) == 1 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// nodeClaimCondition reports whether the named status condition is True.
+func nodeClaimCondition(obj map[string]interface{}, conditionType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if ok && cond["type"] == conditionType && cond["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
+
+func nestedString(obj map[string]interface{}, fields ...string) string {
+	v, _, _ := unstructured.NestedString(obj, fields...)
+	return v
+}
+
+func stringMapToInterface(in map[string]string) map[string]interface{} {
+	out := make(map[string]interface{}, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
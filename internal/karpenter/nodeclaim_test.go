@@ -0,0 +1,167 @@
+package karpenter
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func newNodeClaimTestClient(t *testing.T) *fake.FakeDynamicClient {
+	t.Helper()
+	pool := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "karpenter.sh/v1",
+		"kind":       "NodePool",
+		"metadata":   map[string]interface{}{"name": "general-od", "uid": "np-uid"},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": map[string]interface{}{"spotvortex.io/pool": "general"}},
+				"spec": map[string]interface{}{
+					"nodeClassRef": map[string]interface{}{"group": "karpenter.k8s.aws", "kind": "EC2NodeClass", "name": "default"},
+					"requirements": []interface{}{
+						map[string]interface{}{"key": "karpenter.k8s.aws/instance-category", "operator": "In", "values": []interface{}{"m"}},
+						map[string]interface{}{"key": CapacityTypeLabel, "operator": "In", "values": []interface{}{"spot", "on-demand"}},
+					},
+				},
+			},
+		},
+	}}
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		nodePoolGVR:  "NodePoolList",
+		nodeClaimGVR: "NodeClaimList",
+	}, pool)
+}
+
+// initializeFirstNodeClaim marks the first NodeClaim that appears as launched
+// and Initialized, as Karpenter would.
+func initializeFirstNodeClaim(t *testing.T, client *fake.FakeDynamicClient) {
+	t.Helper()
+	ctx := context.Background()
+	for i := 0; i < 200; i++ {
+		list, err := client.Resource(nodeClaimGVR).List(ctx, metav1.ListOptions{})
+		if err == nil && len(list.Items) > 0 {
+			claim := list.Items[0]
+			labels := claim.GetLabels()
+			labels[CapacityTypeLabel] = CapacityTypeOnDemand
+			claim.SetLabels(labels)
+			_ = unstructured.SetNestedField(claim.Object, "ip-10-0-0-1", "status", "nodeName")
+			_ = unstructured.SetNestedSlice(claim.Object, []interface{}{
+				map[string]interface{}{"type": "Initialized", "status": "True"},
+			}, "status", "conditions")
+			if _, err := client.Resource(nodeClaimGVR).Update(ctx, &claim, metav1.UpdateOptions{}); err != nil {
+				t.Errorf("update NodeClaim: %v", err)
+			}
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Error("no NodeClaim was pre-provisioned")
+}
+
+func TestWaitForNodeClaim_PreProvisionsFromTemplate(t *testing.T) {
+	client := newNodeClaimTestClient(t)
+	manager := NewNodePoolManager(client, slog.Default())
+	go initializeFirstNodeClaim(t, client)
+
+	ready, err := manager.WaitForNodeClaim(context.Background(), "general-od", CapacityTypeOnDemand, 2*time.Second, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForNodeClaim: %v", err)
+	}
+	if ready.NodeName != "ip-10-0-0-1" {
+		t.Fatalf("node name=%q, want the registered node", ready.NodeName)
+	}
+
+	claim, err := client.Resource(nodeClaimGVR).Get(context.Background(), ready.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get NodeClaim: %v", err)
+	}
+	if l := claim.GetLabels(); l[NodePoolLabel] != "general-od" || l["spotvortex.io/pool"] != "general" || l[PreProvisionedLabel] != "true" {
+		t.Fatalf("labels=%v, want the template labels plus NodePool ownership", l)
+	}
+	if owners := claim.GetOwnerReferences(); len(owners) != 1 || owners[0].Kind != "NodePool" || owners[0].UID != "np-uid" {
+		t.Fatalf("ownerReferences=%v, want the NodePool", owners)
+	}
+	requirements, _, _ := unstructured.NestedSlice(claim.Object, "spec", "requirements")
+	if len(requirements) != 2 {
+		t.Fatalf("requirements=%v, want instance-category kept", requirements)
+	}
+	if got := nodeClaimCapacityType(map[string]interface{}{"spec": map[string]interface{}{"requirements": requirements}}); got != CapacityTypeOnDemand {
+		t.Fatalf("requested capacity type=%q, want on-demand only", got)
+	}
+}
+
+func TestWaitForNodeClaim_TimeoutDeletesPreProvisionedClaim(t *testing.T) {
+	client := newNodeClaimTestClient(t)
+	manager := NewNodePoolManager(client, slog.Default())
+
+	if _, err := manager.WaitForNodeClaim(context.Background(), "general-od", CapacityTypeOnDemand, 50*time.Millisecond, 10*time.Millisecond); err == nil {
+		t.Fatal("expected a timeout when the NodeClaim never initializes")
+	}
+	claims, err := manager.ListNodeClaims(context.Background(), "general-od")
+	if err != nil {
+		t.Fatalf("ListNodeClaims: %v", err)
+	}
+	if len(claims) != 0 {
+		t.Fatalf("claims=%v, want the pre-provisioned NodeClaim deleted", claims)
+	}
+}
+
+func TestWaitForNodeClaim_UnlaunchedClaimIsInFlight(t *testing.T) {
+	client := newNodeClaimTestClient(t)
+	manager := NewNodePoolManager(client, slog.Default())
+	// Karpenter created this NodeClaim but has not launched it, so its
+	// capacity type is still open.
+	launching := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "karpenter.sh/v1",
+		"kind":       "NodeClaim",
+		"metadata": map[string]interface{}{
+			"name":   "general-od-abcde",
+			"labels": map[string]interface{}{NodePoolLabel: "general-od"},
+		},
+	}}
+	if _, err := client.Resource(nodeClaimGVR).Create(context.Background(), launching, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create NodeClaim: %v", err)
+	}
+
+	if _, err := manager.WaitForNodeClaim(context.Background(), "general-od", CapacityTypeOnDemand, 50*time.Millisecond, 10*time.Millisecond); err == nil {
+		t.Fatal("expected a timeout when the NodeClaim never initializes")
+	}
+	claims, err := manager.ListNodeClaims(context.Background(), "general-od")
+	if err != nil {
+		t.Fatalf("ListNodeClaims: %v", err)
+	}
+	if len(claims) != 1 || claims[0].Name != "general-od-abcde" {
+		t.Fatalf("claims=%v, want only Karpenter's launch and nothing pre-provisioned", claims)
+	}
+}
+
+func TestWaitForNodeClaims_SharesOneDeadline(t *testing.T) {
+	client := newNodeClaimTestClient(t)
+	manager := NewNodePoolManager(client, slog.Default())
+	waits := []ReplacementWait{
+		{NodePool: "general-od", CapacityType: CapacityTypeOnDemand},
+		{NodePool: "general-od", CapacityType: CapacityTypeSpot},
+		{NodePool: "missing", CapacityType: CapacityTypeOnDemand},
+	}
+
+	const timeout = 200 * time.Millisecond
+	start := time.Now()
+	results := manager.WaitForNodeClaims(context.Background(), waits, timeout, 10*time.Millisecond)
+	if elapsed := time.Since(start); elapsed >= 2*timeout {
+		t.Fatalf("waits took %v, want them concurrent under one %v deadline", elapsed, timeout)
+	}
+	if len(results) != len(waits) {
+		t.Fatalf("results=%d, want %d", len(results), len(waits))
+	}
+	for i, result := range results {
+		if result.Err == nil {
+			t.Errorf("wait %d: expected an error when no NodeClaim initializes", i)
+		}
+	}
+}
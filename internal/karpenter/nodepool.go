@@ -6,8 +6,13 @@ package karpenter
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -25,10 +30,22 @@ var nodePoolGVR = schema.GroupVersionResource{
 
 // CapacityType constants
 const (
+	CapacityTypeLabel    = "karpenter.sh/capacity-type"
 	CapacityTypeSpot     = "spot"
 	CapacityTypeOnDemand = "on-demand"
 )
 
+const (
+	// DriftBudgetAnnotation records the disruption budgets SpotVortex added
+	// to a NodePool while spot is removed from it, so only those are taken
+	// out again.
+	DriftBudgetAnnotation = "spotvortex.io/drift-budgets"
+
+	// defaultDisruptionBudget is Karpenter's budget for a NodePool that
+	// declares none.
+	defaultDisruptionBudget = "10%"
+)
+
 // NodePoolManager manages Karpenter NodePool resources.
 type NodePoolManager struct {
 	dynamicClient dynamic.Interface
@@ -55,10 +72,19 @@ func (m *NodePoolManager) SetCapacityTypes(ctx context.Context, poolName string,
 		"capacity_types", capacityTypes,
 	)
 
-	// Build the JSON patch for the requirements
+	// A merge patch replaces the whole requirements list, so rebuild it from
+	// the NodePool's current requirements with only capacity-type changed.
 	// Karpenter NodePool structure:
 	// spec.template.spec.requirements[].key=karpenter.sh/capacity-type
-	patch := buildCapacityTypePatch(capacityTypes)
+	nodePool, err := m.dynamicClient.Resource(nodePoolGVR).Get(ctx, poolName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get NodePool %s: %w", poolName, err)
+	}
+	requirements, _, err := unstructured.NestedSlice(nodePool.Object, "spec", "template", "spec", "requirements")
+	if err != nil {
+		return fmt.Errorf("failed to read requirements from NodePool %s: %w", poolName, err)
+	}
+	patch := buildCapacityTypePatch(requirements, capacityTypes)
 
 	patchBytes, err := json.Marshal(patch)
 	if err != nil {
@@ -96,6 +122,238 @@ func (m *NodePoolManager) RecoverToSpot(ctx context.Context, poolName string) er
 	return m.SetCapacityTypes(ctx, poolName, []string{CapacityTypeSpot, CapacityTypeOnDemand})
 }
 
+// MixedSteering is how to steer a NodePool that serves a workload pool with
+// both capacity types.
+type MixedSteering struct {
+	FavorSpot bool
+	// SpotWeight and OnDemandWeight bound the NodePool weight.
+	SpotWeight     int32
+	OnDemandWeight int32
+	// With RatioKnown the weight is graduated by the gap between the current
+	// and target spot ratio; otherwise it follows FavorSpot.
+	RatioKnown       bool
+	CurrentSpotRatio float64
+	TargetSpotRatio  float64
+	// MaxDriftNodes caps how many drifted NodeClaims Karpenter replaces at
+	// once while spot is removed from the NodePool. Default: 1.
+	MaxDriftNodes int
+}
+
+// Weight returns the NodePool weight for the steering.
+func (s MixedSteering) Weight() int32 {
+	if s.RatioKnown {
+		return GraduatedWeight(s.CurrentSpotRatio, s.TargetSpotRatio, s.OnDemandWeight, s.SpotWeight)
+	}
+	if s.FavorSpot {
+		return s.SpotWeight
+	}
+	return s.OnDemandWeight
+}
+
+// SteerMixedNodePool steers a NodePool that serves a workload pool with both
+// capacity types: spot stays allowed only when favoring spot, and the weight
+// sets its priority over other NodePools matching the same pods.
+//
+// Removing spot marks every spot NodeClaim Drifted, so a Drifted disruption
+// budget of MaxDriftNodes is added first, and spot is only removed once it is
+// in place; Karpenter then replaces spot nodes a few at a time and the
+// controller can restore spot when the pool reaches its target. The budget
+// is taken out again when spot is restored. All patches are attempted.
+func (m *NodePoolManager) SteerMixedNodePool(ctx context.Context, poolName string, s MixedSteering) error {
+	weight := s.Weight()
+	m.logger.Info("steering single Karpenter NodePool",
+		"nodepool", poolName,
+		"favor_spot", s.FavorSpot,
+		"weight", weight,
+		"current_spot_ratio", s.CurrentSpotRatio,
+		"target_spot_ratio", s.TargetSpotRatio,
+	)
+
+	var capacityErr error
+	if s.FavorSpot {
+		capacityErr = m.RecoverToSpot(ctx, poolName)
+		if capacityErr == nil {
+			capacityErr = m.removeDriftBudget(ctx, poolName)
+		}
+	} else {
+		capacityErr = m.addDriftBudget(ctx, poolName, s.MaxDriftNodes)
+		if capacityErr == nil {
+			capacityErr = m.FallbackToOnDemand(ctx, poolName)
+		}
+	}
+	return errors.Join(capacityErr, m.SetWeight(ctx, poolName, weight))
+}
+
+// addDriftBudget appends a Drifted disruption budget of maxNodes to a
+// NodePool and records it in DriftBudgetAnnotation. A NodePool without
+// budgets also gets Karpenter's default budget, which an explicit list would
+// otherwise replace.
+func (m *NodePoolManager) addDriftBudget(ctx context.Context, poolName string, maxNodes int) error {
+	if m.dynamicClient == nil {
+		return fmt.Errorf("dynamic client not configured")
+	}
+	if maxNodes <= 0 {
+		maxNodes = 1
+	}
+	nodePool, err := m.dynamicClient.Resource(nodePoolGVR).Get(ctx, poolName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get NodePool %s: %w", poolName, err)
+	}
+	if _, ok := nodePool.GetAnnotations()[DriftBudgetAnnotation]; ok {
+		return nil
+	}
+	budgets, _, err := unstructured.NestedSlice(nodePool.Object, "spec", "disruption", "budgets")
+	if err != nil {
+		return fmt.Errorf("failed to read disruption budgets from NodePool %s: %w", poolName, err)
+	}
+
+	added := []interface{}{map[string]interface{}{
+		"nodes":   strconv.Itoa(maxNodes),
+		"reasons": []interface{}{"Drifted"},
+	}}
+	if len(budgets) == 0 {
+		added = append([]interface{}{map[string]interface{}{"nodes": defaultDisruptionBudget}}, added...)
+	}
+	record, err := json.Marshal(added)
+	if err != nil {
+		return fmt.Errorf("failed to marshal drift budget: %w", err)
+	}
+	if err := m.patchBudgets(ctx, poolName, append(budgets, added...), string(record)); err != nil {
+		return err
+	}
+	m.logger.Info("capped NodePool drift disruption",
+		"nodepool", poolName,
+		"max_drift_nodes", maxNodes,
+	)
+	return nil
+}
+
+// removeDriftBudget takes the budgets recorded by addDriftBudget out of a
+// NodePool, leaving any others.
+func (m *NodePoolManager) removeDriftBudget(ctx context.Context, poolName string) error {
+	if m.dynamicClient == nil {
+		return fmt.Errorf("dynamic client not configured")
+	}
+	nodePool, err := m.dynamicClient.Resource(nodePoolGVR).Get(ctx, poolName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get NodePool %s: %w", poolName, err)
+	}
+	record, ok := nodePool.GetAnnotations()[DriftBudgetAnnotation]
+	if !ok {
+		return nil
+	}
+	budgets, _, err := unstructured.NestedSlice(nodePool.Object, "spec", "disruption", "budgets")
+	if err != nil {
+		return fmt.Errorf("failed to read disruption budgets from NodePool %s: %w", poolName, err)
+	}
+	var added []interface{}
+	if err := json.Unmarshal([]byte(record), &added); err != nil {
+		m.logger.Warn("ignoring unreadable drift budget record", "nodepool", poolName, "error", err)
+	}
+	for _, a := range added {
+		for i, b := range budgets {
+			if reflect.DeepEqual(a, b) {
+				budgets = append(budgets[:i], budgets[i+1:]...)
+				break
+			}
+		}
+	}
+	if err := m.patchBudgets(ctx, poolName, budgets, nil); err != nil {
+		return err
+	}
+	m.logger.Info("removed NodePool drift disruption cap", "nodepool", poolName)
+	return nil
+}
+
+// patchBudgets sets a NodePool's disruption budgets (an empty list removes
+// the field) and DriftBudgetAnnotation (nil removes it).
+func (m *NodePoolManager) patchBudgets(ctx context.Context, poolName string, budgets []interface{}, record interface{}) error {
+	var budgetsValue interface{}
+	if len(budgets) > 0 {
+		budgetsValue = budgets
+	}
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{DriftBudgetAnnotation: record},
+		},
+		"spec": map[string]interface{}{
+			"disruption": map[string]interface{}{"budgets": budgetsValue},
+		},
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal patch: %w", err)
+	}
+	_, err = m.dynamicClient.Resource(nodePoolGVR).Patch(ctx, poolName, types.MergePatchType, patchBytes, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to patch NodePool %s disruption budgets: %w", poolName, err)
+	}
+	return nil
+}
+
+// GraduatedWeight returns a NodePool weight between low and high in
+// proportion to the gap between the target and current spot ratio: the
+// midpoint on target, high once spot is short by half the pool or more, and
+// low once it is over by as much.
+func GraduatedWeight(currentSpotRatio, targetSpotRatio float64, low, high int32) int32 {
+	f := math.Min(1, math.Max(0, 0.5+targetSpotRatio-currentSpotRatio))
+	return low + int32(math.Round(f*float64(high-low)))
+}
+
+// SteeringSnapshot is the weight and capacity types of a NodePool before it
+// was steered, so a swap that never got its replacement can be undone.
+type SteeringSnapshot struct {
+	NodePool      string
+	Weight        int32    // 0 when unset
+	CapacityTypes []string // nil when the NodePool has no capacity-type requirement
+}
+
+// SnapshotSteering records a NodePool's current weight and capacity types.
+func (m *NodePoolManager) SnapshotSteering(ctx context.Context, poolName string) (SteeringSnapshot, error) {
+	weight, err := m.GetWeight(ctx, poolName)
+	if err != nil {
+		return SteeringSnapshot{}, err
+	}
+	// A missing capacity-type requirement is left missing on restore.
+	capacityTypes, _ := m.GetCapacityTypes(ctx, poolName)
+	return SteeringSnapshot{NodePool: poolName, Weight: weight, CapacityTypes: capacityTypes}, nil
+}
+
+// RestoreSteering puts a NodePool's weight and capacity types back to a
+// snapshot, removing any drift cap once spot is allowed again. All patches
+// are attempted.
+func (m *NodePoolManager) RestoreSteering(ctx context.Context, snapshot SteeringSnapshot) error {
+	var capacityErr error
+	if snapshot.CapacityTypes != nil {
+		capacityErr = m.SetCapacityTypes(ctx, snapshot.NodePool, snapshot.CapacityTypes)
+		if capacityErr == nil && slices.Contains(snapshot.CapacityTypes, CapacityTypeSpot) {
+			capacityErr = m.removeDriftBudget(ctx, snapshot.NodePool)
+		}
+	}
+	return errors.Join(capacityErr, m.restoreWeight(ctx, snapshot.NodePool, snapshot.Weight))
+}
+
+// restoreWeight sets weight, or removes it when the snapshot had none.
+func (m *NodePoolManager) restoreWeight(ctx context.Context, poolName string, weight int32) error {
+	if weight != 0 {
+		return m.SetWeight(ctx, poolName, weight)
+	}
+	if m.dynamicClient == nil {
+		return fmt.Errorf("dynamic client not configured")
+	}
+	_, err := m.dynamicClient.Resource(nodePoolGVR).Patch(
+		ctx,
+		poolName,
+		types.MergePatchType,
+		[]byte(`{"spec":{"weight":null}}`),
+		metav1.PatchOptions{},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove NodePool %s weight: %w", poolName, err)
+	}
+	return nil
+}
+
 // GetCapacityTypes returns the current capacity types for a NodePool.
 func (m *NodePoolManager) GetCapacityTypes(ctx context.Context, poolName string) ([]string, error) {
 	if m.dynamicClient == nil {
@@ -119,7 +377,7 @@ func (m *NodePoolManager) GetCapacityTypes(ctx context.Context, poolName string)
 		}
 
 		key, _, _ := unstructured.NestedString(reqMap, "key")
-		if key == "karpenter.sh/capacity-type" {
+		if key == CapacityTypeLabel {
 			values, _, _ := unstructured.NestedStringSlice(reqMap, "values")
 			return values, nil
 		}
@@ -146,25 +404,42 @@ func (m *NodePoolManager) ListNodePools(ctx context.Context) ([]string, error) {
 	return names, nil
 }
 
-// buildCapacityTypePatch creates a JSON merge patch for capacity types.
-func buildCapacityTypePatch(capacityTypes []string) map[string]interface{} {
+// buildCapacityTypePatch creates a JSON merge patch that sets the
+// capacity-type requirement and keeps the other requirements.
+func buildCapacityTypePatch(requirements []interface{}, capacityTypes []string) map[string]interface{} {
 	return map[string]interface{}{
 		"spec": map[string]interface{}{
 			"template": map[string]interface{}{
 				"spec": map[string]interface{}{
-					"requirements": []map[string]interface{}{
-						{
-							"key":      "karpenter.sh/capacity-type",
-							"operator": "In",
-							"values":   capacityTypes,
-						},
-					},
+					"requirements": withCapacityTypes(requirements, capacityTypes),
 				},
 			},
 		},
 	}
 }
 
+// withCapacityTypes returns requirements with the capacity-type requirement
+// replaced by one allowing capacityTypes.
+func withCapacityTypes(requirements []interface{}, capacityTypes []string) []interface{} {
+	values := make([]interface{}, len(capacityTypes))
+	for i, v := range capacityTypes {
+		values[i] = v
+	}
+
+	updated := make([]interface{}, 0, len(requirements)+1)
+	for _, req := range requirements {
+		if reqMap, ok := req.(map[string]interface{}); ok && reqMap["key"] == CapacityTypeLabel {
+			continue
+		}
+		updated = append(updated, req)
+	}
+	return append(updated, map[string]interface{}{
+		"key":      CapacityTypeLabel,
+		"operator": "In",
+		"values":   values,
+	})
+}
+
 // IsKarpenterAvailable checks if Karpenter NodePool CRD exists.
 func (m *NodePoolManager) IsKarpenterAvailable(ctx context.Context) bool {
 	if m.dynamicClient == nil {
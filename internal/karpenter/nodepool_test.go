@@ -3,6 +3,7 @@ package karpenter
 import (
 	"context"
 	"log/slog"
+	"reflect"
 	"testing"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -266,3 +267,157 @@ func TestNodePoolManager_GetEffectiveDisruptionLimit(t *testing.T) {
 		t.Errorf("expected limit 8 (20%% of 40), got %d", limit)
 	}
 }
+
+func TestNodePoolManager_SetCapacityTypesKeepsOtherRequirements(t *testing.T) {
+	pool := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "karpenter.sh/v1",
+			"kind":       "NodePool",
+			"metadata":   map[string]interface{}{"name": "mixed"},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"requirements": []interface{}{
+							map[string]interface{}{"key": "karpenter.k8s.aws/instance-category", "operator": "In", "values": []interface{}{"c", "m"}},
+							map[string]interface{}{"key": CapacityTypeLabel, "operator": "In", "values": []interface{}{"spot", "on-demand"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme(), pool)
+	manager := NewNodePoolManager(client, slog.Default())
+
+	if err := manager.SteerMixedNodePool(context.Background(), "mixed", MixedSteering{SpotWeight: 100, OnDemandWeight: 35}); err != nil {
+		t.Fatalf("SteerMixedNodePool failed: %v", err)
+	}
+
+	got, err := client.Resource(nodePoolGVR).Get(context.Background(), "mixed", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get NodePool: %v", err)
+	}
+	requirements, _, _ := unstructured.NestedSlice(got.Object, "spec", "template", "spec", "requirements")
+	if len(requirements) != 2 {
+		t.Fatalf("requirements=%v, want instance-category kept and capacity-type replaced", requirements)
+	}
+	if types, _ := manager.GetCapacityTypes(context.Background(), "mixed"); len(types) != 1 || types[0] != CapacityTypeOnDemand {
+		t.Fatalf("capacity types=%v, want [on-demand]", types)
+	}
+	if weight, _ := manager.GetWeight(context.Background(), "mixed"); weight != 35 {
+		t.Fatalf("weight=%d, want 35", weight)
+	}
+}
+
+func TestNodePoolManager_SteerMixedNodePoolCapsDrift(t *testing.T) {
+	customerBudget := map[string]interface{}{"nodes": "0", "schedule": "0 9 * * mon-fri", "duration": "8h"}
+	pool := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "karpenter.sh/v1",
+			"kind":       "NodePool",
+			"metadata":   map[string]interface{}{"name": "mixed"},
+			"spec": map[string]interface{}{
+				"disruption": map[string]interface{}{
+					"budgets": []interface{}{customerBudget},
+				},
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"requirements": []interface{}{
+							map[string]interface{}{"key": CapacityTypeLabel, "operator": "In", "values": []interface{}{"spot", "on-demand"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme(), pool)
+	manager := NewNodePoolManager(client, slog.Default())
+	ctx := context.Background()
+	budgets := func() []interface{} {
+		t.Helper()
+		got, err := client.Resource(nodePoolGVR).Get(ctx, "mixed", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("get NodePool: %v", err)
+		}
+		budgets, _, _ := unstructured.NestedSlice(got.Object, "spec", "disruption", "budgets")
+		return budgets
+	}
+
+	toOnDemand := MixedSteering{SpotWeight: 100, OnDemandWeight: 10, MaxDriftNodes: 2}
+	for i := 0; i < 2; i++ {
+		if err := manager.SteerMixedNodePool(ctx, "mixed", toOnDemand); err != nil {
+			t.Fatalf("SteerMixedNodePool to on-demand: %v", err)
+		}
+	}
+	want := []interface{}{
+		customerBudget,
+		map[string]interface{}{"nodes": "2", "reasons": []interface{}{"Drifted"}},
+	}
+	if got := budgets(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("budgets=%v, want %v (added once)", got, want)
+	}
+
+	if err := manager.SteerMixedNodePool(ctx, "mixed", MixedSteering{FavorSpot: true, SpotWeight: 100, OnDemandWeight: 10}); err != nil {
+		t.Fatalf("SteerMixedNodePool to spot: %v", err)
+	}
+	if got := budgets(); !reflect.DeepEqual(got, []interface{}{customerBudget}) {
+		t.Fatalf("budgets=%v, want only the customer budget", got)
+	}
+	got, _ := client.Resource(nodePoolGVR).Get(ctx, "mixed", metav1.GetOptions{})
+	if _, ok := got.GetAnnotations()[DriftBudgetAnnotation]; ok {
+		t.Fatalf("annotations=%v, want %s removed", got.GetAnnotations(), DriftBudgetAnnotation)
+	}
+}
+
+func TestNodePoolManager_DriftCapKeepsDefaultBudget(t *testing.T) {
+	pool := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "karpenter.sh/v1",
+			"kind":       "NodePool",
+			"metadata":   map[string]interface{}{"name": "mixed"},
+			"spec":       map[string]interface{}{},
+		},
+	}
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme(), pool)
+	manager := NewNodePoolManager(client, slog.Default())
+	ctx := context.Background()
+
+	if err := manager.SteerMixedNodePool(ctx, "mixed", MixedSteering{SpotWeight: 100, OnDemandWeight: 10}); err != nil {
+		t.Fatalf("SteerMixedNodePool to on-demand: %v", err)
+	}
+	got, _ := client.Resource(nodePoolGVR).Get(ctx, "mixed", metav1.GetOptions{})
+	budgets, _, _ := unstructured.NestedSlice(got.Object, "spec", "disruption", "budgets")
+	want := []interface{}{
+		map[string]interface{}{"nodes": "10%"},
+		map[string]interface{}{"nodes": "1", "reasons": []interface{}{"Drifted"}},
+	}
+	if !reflect.DeepEqual(budgets, want) {
+		t.Fatalf("budgets=%v, want Karpenter's default kept and drift capped at 1", budgets)
+	}
+
+	snapshot := SteeringSnapshot{NodePool: "mixed", CapacityTypes: []string{CapacityTypeSpot, CapacityTypeOnDemand}}
+	if err := manager.RestoreSteering(ctx, snapshot); err != nil {
+		t.Fatalf("RestoreSteering: %v", err)
+	}
+	got, _ = client.Resource(nodePoolGVR).Get(ctx, "mixed", metav1.GetOptions{})
+	if _, found, _ := unstructured.NestedSlice(got.Object, "spec", "disruption", "budgets"); found {
+		t.Fatalf("spec.disruption=%v, want budgets removed", got.Object["spec"].(map[string]interface{})["disruption"])
+	}
+}
+
+func TestGraduatedWeight(t *testing.T) {
+	tests := []struct {
+		current, target float64
+		want            int32
+	}{
+		{current: 0.5, target: 0.5, want: 50},
+		{current: 0.2, target: 0.5, want: 80},
+		{current: 0.0, target: 1.0, want: 100},
+		{current: 0.9, target: 0.3, want: 0},
+	}
+	for _, tt := range tests {
+		if got := GraduatedWeight(tt.current, tt.target, 0, 100); got != tt.want {
+			t.Errorf("GraduatedWeight(%v, %v) = %d, want %d", tt.current, tt.target, got, tt.want)
+		}
+	}
+}
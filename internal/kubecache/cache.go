@@ -0,0 +1,290 @@
+// Package kubecache keeps shared-informer caches of the cluster objects the
+// reconcile loop reads every tick (nodes, pods, PDBs, ReplicaSets and
+// StatefulSets), so the collector, guardrails and controller stop issuing
+// full List calls against the API server.
+//
+// Objects returned by the cache are shared with the informers and must be
+// treated as read-only.
+package kubecache
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	policylisters "k8s.io/client-go/listers/policy/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Resource names, used as the metric label and for AddEventHandler.
+const (
+	ResourceNodes        = "nodes"
+	ResourcePods         = "pods"
+	ResourcePDBs         = "poddisruptionbudgets"
+	ResourceReplicaSets  = "replicasets"
+	ResourceStatefulSets = "statefulsets"
+)
+
+// podNodeIndex indexes scheduled pods by spec.nodeName.
+const podNodeIndex = "spec.nodeName"
+
+// lagInterval is how often the sync lag gauges are refreshed.
+const lagInterval = 15 * time.Second
+
+// Config configures the cache.
+type Config struct {
+	// ResyncPeriod re-delivers every cached object to handlers so a quiet
+	// informer still reports progress. Default: 10 minutes.
+	ResyncPeriod time.Duration
+
+	Logger *slog.Logger
+}
+
+// Cache is the shared informer layer. A nil *Cache is valid and reports
+// HasSynced() == false, so callers fall back to direct API reads.
+type Cache struct {
+	factory informers.SharedInformerFactory
+	logger  *slog.Logger
+
+	informers map[string]cache.SharedIndexInformer
+	nodes     corelisters.NodeLister
+	pods      corelisters.PodLister
+	pdbs      policylisters.PodDisruptionBudgetLister
+	rss       appslisters.ReplicaSetLister
+	stss      appslisters.StatefulSetLister
+
+	started   atomic.Int64 // unix nanos
+	lastEvent map[string]*atomic.Int64
+
+	startOnce sync.Once
+}
+
+// New builds the informers. Call AddEventHandler before Start to receive
+// the initial adds.
+func New(client kubernetes.Interface, cfg Config) (*Cache, error) {
+	if client == nil {
+		return nil, fmt.Errorf("k8s client is required")
+	}
+	if cfg.ResyncPeriod <= 0 {
+		cfg.ResyncPeriod = 10 * time.Minute
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+
+	factory := informers.NewSharedInformerFactoryWithOptions(client, cfg.ResyncPeriod,
+		informers.WithTransform(stripManagedFields),
+	)
+	c := &Cache{
+		factory:   factory,
+		logger:    cfg.Logger,
+		nodes:     factory.Core().V1().Nodes().Lister(),
+		pods:      factory.Core().V1().Pods().Lister(),
+		pdbs:      factory.Policy().V1().PodDisruptionBudgets().Lister(),
+		rss:       factory.Apps().V1().ReplicaSets().Lister(),
+		stss:      factory.Apps().V1().StatefulSets().Lister(),
+		lastEvent: make(map[string]*atomic.Int64),
+	}
+	c.informers = map[string]cache.SharedIndexInformer{
+		ResourceNodes:        factory.Core().V1().Nodes().Informer(),
+		ResourcePods:         factory.Core().V1().Pods().Informer(),
+		ResourcePDBs:         factory.Policy().V1().PodDisruptionBudgets().Informer(),
+		ResourceReplicaSets:  factory.Apps().V1().ReplicaSets().Informer(),
+		ResourceStatefulSets: factory.Apps().V1().StatefulSets().Informer(),
+	}
+
+	if err := c.informers[ResourcePods].AddIndexers(cache.Indexers{podNodeIndex: podNodeName}); err != nil {
+		return nil, fmt.Errorf("failed to index pods by node: %w", err)
+	}
+
+	for resource, informer := range c.informers {
+		last := &atomic.Int64{}
+		c.lastEvent[resource] = last
+		touch := func() { last.Store(time.Now().UnixNano()) }
+		if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(any) { touch() },
+			UpdateFunc: func(any, any) { touch() },
+			DeleteFunc: func(any) { touch() },
+		}); err != nil {
+			return nil, fmt.Errorf("failed to watch %s: %w", resource, err)
+		}
+	}
+	return c, nil
+}
+
+// AddEventHandler registers a handler on one resource's informer.
+func (c *Cache) AddEventHandler(resource string, handler cache.ResourceEventHandler) error {
+	informer, ok := c.informers[resource]
+	if !ok {
+		return fmt.Errorf("unknown cache resource %q", resource)
+	}
+	_, err := informer.AddEventHandler(handler)
+	return err
+}
+
+// Start runs the informers and the sync lag reporter until ctx is done.
+func (c *Cache) Start(ctx context.Context) {
+	c.startOnce.Do(func() {
+		c.started.Store(time.Now().UnixNano())
+		c.factory.Start(ctx.Done())
+		go c.reportLag(ctx)
+	})
+}
+
+// WaitForSync blocks until every informer has completed its initial List.
+func (c *Cache) WaitForSync(ctx context.Context) error {
+	synced := c.factory.WaitForCacheSync(ctx.Done())
+	for resource, ok := range synced {
+		if !ok {
+			return fmt.Errorf("informer cache for %s did not sync: %w", resource, ctx.Err())
+		}
+	}
+	c.logger.Info("informer caches synced",
+		"duration", time.Since(time.Unix(0, c.started.Load())).Round(time.Millisecond),
+	)
+	return nil
+}
+
+// HasSynced reports whether every informer has completed its initial List.
+func (c *Cache) HasSynced() bool {
+	if c == nil || c.started.Load() == 0 {
+		return false
+	}
+	for _, informer := range c.informers {
+		if !informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// Ready is a readiness check: nil once all caches have synced.
+func (c *Cache) Ready(context.Context) error {
+	if c.HasSynced() {
+		return nil
+	}
+	return fmt.Errorf("informer caches not synced")
+}
+
+// SyncLag returns how long ago the resource's informer last delivered an
+// event or resync; before its initial sync, how long ago the cache started.
+func (c *Cache) SyncLag(resource string, now time.Time) time.Duration {
+	informer, ok := c.informers[resource]
+	if !ok {
+		return 0
+	}
+	since := c.started.Load()
+	if informer.HasSynced() {
+		if last := c.lastEvent[resource].Load(); last > since {
+			since = last
+		}
+	}
+	if since == 0 {
+		return 0
+	}
+	return now.Sub(time.Unix(0, since))
+}
+
+func (c *Cache) reportLag(ctx context.Context) {
+	ticker := time.NewTicker(lagInterval)
+	defer ticker.Stop()
+	c.recordLag(time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			c.recordLag(now)
+		}
+	}
+}
+
+func (c *Cache) recordLag(now time.Time) {
+	for resource, informer := range c.informers {
+		metrics.InformerSyncLag.WithLabelValues(resource).Set(c.SyncLag(resource, now).Seconds())
+		synced := 0.0
+		if informer.HasSynced() {
+			synced = 1
+		}
+		metrics.InformerSynced.WithLabelValues(resource).Set(synced)
+	}
+}
+
+// Nodes returns all cached nodes.
+func (c *Cache) Nodes() ([]*corev1.Node, error) {
+	return c.nodes.List(labels.Everything())
+}
+
+// Node returns a cached node by name.
+func (c *Cache) Node(name string) (*corev1.Node, error) {
+	return c.nodes.Get(name)
+}
+
+// Pods returns all cached pods.
+func (c *Cache) Pods() ([]*corev1.Pod, error) {
+	return c.pods.List(labels.Everything())
+}
+
+// PodsOnNode returns the cached pods scheduled to a node.
+func (c *Cache) PodsOnNode(nodeName string) ([]*corev1.Pod, error) {
+	objs, err := c.informers[ResourcePods].GetIndexer().ByIndex(podNodeIndex, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	pods := make([]*corev1.Pod, 0, len(objs))
+	for _, obj := range objs {
+		if pod, ok := obj.(*corev1.Pod); ok {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+// PDBs returns the cached PodDisruptionBudgets in a namespace, or in all
+// namespaces when namespace is empty.
+func (c *Cache) PDBs(namespace string) ([]*policyv1.PodDisruptionBudget, error) {
+	if namespace == "" {
+		return c.pdbs.List(labels.Everything())
+	}
+	return c.pdbs.PodDisruptionBudgets(namespace).List(labels.Everything())
+}
+
+// ReplicaSets returns all cached ReplicaSets.
+func (c *Cache) ReplicaSets() ([]*appsv1.ReplicaSet, error) {
+	return c.rss.List(labels.Everything())
+}
+
+// StatefulSets returns all cached StatefulSets.
+func (c *Cache) StatefulSets() ([]*appsv1.StatefulSet, error) {
+	return c.stss.List(labels.Everything())
+}
+
+func podNodeName(obj any) ([]string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok || pod.Spec.NodeName == "" {
+		return nil, nil
+	}
+	return []string{pod.Spec.NodeName}, nil
+}
+
+// stripManagedFields drops server-side apply bookkeeping, which is most of
+// a pod's size and never read by the agent.
+func stripManagedFields(obj any) (any, error) {
+	if accessor, err := meta.Accessor(obj); err == nil {
+		accessor.SetManagedFields(nil)
+	}
+	return obj, nil
+}
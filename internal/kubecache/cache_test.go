@@ -0,0 +1,151 @@
+package kubecache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func startTestCache(t *testing.T, client *fake.Clientset) *Cache {
+	t.Helper()
+	c, err := New(client, Config{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	c.Start(ctx)
+
+	syncCtx, syncCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer syncCancel()
+	if err := c.WaitForSync(syncCtx); err != nil {
+		t.Fatalf("WaitForSync: %v", err)
+	}
+	return c
+}
+
+func TestCache_ListsAndIndexes(t *testing.T) {
+	replicas := int32(3)
+	client := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-b"}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:          "web-1",
+				Namespace:     "default",
+				ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+			},
+			Spec: corev1.PodSpec{NodeName: "node-a"},
+		},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "default"}, Spec: corev1.PodSpec{NodeName: "node-b"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "default"}},
+		&policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+		&policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "data"}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-abc", Namespace: "default"}, Spec: appsv1.ReplicaSetSpec{Replicas: &replicas}},
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "data"}},
+	)
+	c := startTestCache(t, client)
+
+	if !c.HasSynced() {
+		t.Fatal("expected cache to be synced")
+	}
+	if err := c.Ready(context.Background()); err != nil {
+		t.Fatalf("Ready: %v", err)
+	}
+
+	nodes, _ := c.Nodes()
+	if len(nodes) != 2 {
+		t.Errorf("nodes=%d, want 2", len(nodes))
+	}
+	pods, _ := c.PodsOnNode("node-a")
+	if len(pods) != 1 || pods[0].Name != "web-1" {
+		t.Fatalf("pods on node-a=%v", pods)
+	}
+	if len(pods[0].ManagedFields) != 0 {
+		t.Error("expected managed fields to be stripped")
+	}
+	all, _ := c.Pods()
+	if len(all) != 3 {
+		t.Errorf("pods=%d, want 3", len(all))
+	}
+	if pdbs, _ := c.PDBs("default"); len(pdbs) != 1 {
+		t.Errorf("default PDBs=%d, want 1", len(pdbs))
+	}
+	if pdbs, _ := c.PDBs(""); len(pdbs) != 2 {
+		t.Errorf("all PDBs=%d, want 2", len(pdbs))
+	}
+	if rss, _ := c.ReplicaSets(); len(rss) != 1 {
+		t.Errorf("replicasets=%d, want 1", len(rss))
+	}
+	if stss, _ := c.StatefulSets(); len(stss) != 1 {
+		t.Errorf("statefulsets=%d, want 1", len(stss))
+	}
+}
+
+func TestCache_EventHandlerAndSyncLag(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	c, err := New(client, Config{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	added := make(chan string, 1)
+	if err := c.AddEventHandler(ResourcePods, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) { added <- obj.(*corev1.Pod).Spec.NodeName },
+	}); err != nil {
+		t.Fatalf("AddEventHandler: %v", err)
+	}
+	if err := c.AddEventHandler("secrets", cache.ResourceEventHandlerFuncs{}); err == nil {
+		t.Error("expected error for unknown resource")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.Start(ctx)
+	if err := c.WaitForSync(ctx); err != nil {
+		t.Fatalf("WaitForSync: %v", err)
+	}
+
+	start := time.Now()
+	if lag := c.SyncLag(ResourcePods, start.Add(time.Minute)); lag < time.Minute {
+		t.Errorf("quiet informer lag=%v, want >= 1m", lag)
+	}
+
+	_, err = client.CoreV1().Pods("default").Create(ctx, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: "node-a"},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("create pod: %v", err)
+	}
+
+	select {
+	case node := <-added:
+		if node != "node-a" {
+			t.Errorf("handler saw node %q", node)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("event handler not called")
+	}
+
+	if lag := c.SyncLag(ResourcePods, time.Now()); lag > 5*time.Second {
+		t.Errorf("lag after event=%v, want small", lag)
+	}
+}
+
+func TestCache_NilIsNotSynced(t *testing.T) {
+	var c *Cache
+	if c.HasSynced() {
+		t.Fatal("nil cache must not report synced")
+	}
+	if err := c.Ready(context.Background()); err == nil {
+		t.Fatal("nil cache must not be ready")
+	}
+}
@@ -0,0 +1,213 @@
+// Package leader runs Lease-based leader election so several agent replicas
+// can run for availability while only one of them actuates.
+//
+// Followers keep running observe-only ticks and read the leader's checkpoint
+// from the state store, so a failover does not reset cooldowns or price
+// history.
+package leader
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// Config configures the elector.
+type Config struct {
+	K8sClient kubernetes.Interface
+
+	// Namespace holds the Lease. Default: PodNamespace().
+	Namespace string
+
+	// LeaseName is the coordination.k8s.io Lease all replicas contend for.
+	LeaseName string
+
+	// Identity uniquely names this replica. Default: the hostname (pod name).
+	Identity string
+
+	// LeaseDuration is how long followers wait before taking over an
+	// unrenewed lease. Default: 15s.
+	LeaseDuration time.Duration
+
+	// RenewDeadline is how long the leader retries renewing before giving up
+	// leadership. Default: 10s.
+	RenewDeadline time.Duration
+
+	// RetryPeriod is the interval between acquire/renew attempts. Default: 2s.
+	RetryPeriod time.Duration
+
+	Logger *slog.Logger
+}
+
+// Callbacks are invoked on leadership transitions. The context passed to
+// OnStartedLeading is cancelled when leadership is lost.
+type Callbacks struct {
+	OnStartedLeading func(ctx context.Context)
+	OnStoppedLeading func()
+}
+
+// Elector contends for a Lease and reports leadership status.
+type Elector struct {
+	cfg    Config
+	logger *slog.Logger
+
+	leading atomic.Bool
+
+	mu     sync.RWMutex
+	holder string
+}
+
+// New creates an elector. It does not contend until Run is called.
+func New(cfg Config) (*Elector, error) {
+	if cfg.K8sClient == nil {
+		return nil, fmt.Errorf("k8s client is required")
+	}
+	if cfg.LeaseName == "" {
+		return nil, fmt.Errorf("lease name is required")
+	}
+	if cfg.Namespace == "" {
+		cfg.Namespace = PodNamespace()
+	}
+	if cfg.Identity == "" {
+		host, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine leader election identity: %w", err)
+		}
+		cfg.Identity = host
+	}
+	if cfg.LeaseDuration <= 0 {
+		cfg.LeaseDuration = 15 * time.Second
+	}
+	if cfg.RenewDeadline <= 0 {
+		cfg.RenewDeadline = 10 * time.Second
+	}
+	if cfg.RetryPeriod <= 0 {
+		cfg.RetryPeriod = 2 * time.Second
+	}
+	if cfg.RenewDeadline >= cfg.LeaseDuration {
+		return nil, fmt.Errorf("renew deadline (%s) must be shorter than lease duration (%s)", cfg.RenewDeadline, cfg.LeaseDuration)
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	metrics.LeaderIsLeader.Set(0)
+	return &Elector{cfg: cfg, logger: logger}, nil
+}
+
+// Identity returns this replica's identity.
+func (e *Elector) Identity() string { return e.cfg.Identity }
+
+// IsLeader reports whether this replica currently holds the lease.
+func (e *Elector) IsLeader() bool { return e.leading.Load() }
+
+// Leader returns the identity of the current lease holder, if known.
+func (e *Elector) Leader() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.holder
+}
+
+// Run contends for the lease until ctx is cancelled. After losing
+// leadership the replica rejoins as a follower instead of exiting, so it
+// keeps its warm caches. The lease is released on cancellation.
+func (e *Elector) Run(ctx context.Context, cb Callbacks) error {
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      e.cfg.LeaseName,
+			Namespace: e.cfg.Namespace,
+		},
+		Client: e.cfg.K8sClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: e.cfg.Identity,
+		},
+	}
+
+	le, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		Name:            e.cfg.LeaseName,
+		LeaseDuration:   e.cfg.LeaseDuration,
+		RenewDeadline:   e.cfg.RenewDeadline,
+		RetryPeriod:     e.cfg.RetryPeriod,
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaderCtx context.Context) {
+				e.setLeading(true)
+				e.logger.Info("acquired leadership", "identity", e.cfg.Identity, "lease", e.cfg.LeaseName)
+				if cb.OnStartedLeading != nil {
+					cb.OnStartedLeading(leaderCtx)
+				}
+			},
+			OnStoppedLeading: func() {
+				wasLeading := e.leading.Load()
+				e.setLeading(false)
+				if wasLeading {
+					e.logger.Warn("lost leadership", "identity", e.cfg.Identity, "lease", e.cfg.LeaseName)
+				}
+				if cb.OnStoppedLeading != nil {
+					cb.OnStoppedLeading()
+				}
+			},
+			OnNewLeader: func(identity string) {
+				e.mu.Lock()
+				e.holder = identity
+				e.mu.Unlock()
+				if identity != e.cfg.Identity {
+					e.logger.Info("following leader", "leader", identity, "lease", e.cfg.LeaseName)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create leader elector: %w", err)
+	}
+
+	e.logger.Info("contending for leadership",
+		"identity", e.cfg.Identity,
+		"lease", e.cfg.Namespace+"/"+e.cfg.LeaseName,
+	)
+	for {
+		le.Run(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+func (e *Elector) setLeading(leading bool) {
+	if e.leading.Swap(leading) != leading {
+		metrics.LeaderTransitions.Inc()
+	}
+	if leading {
+		metrics.LeaderIsLeader.Set(1)
+	} else {
+		metrics.LeaderIsLeader.Set(0)
+	}
+}
+
+// PodNamespace returns the namespace this process runs in: $POD_NAMESPACE,
+// then the service account namespace file, then "default".
+func PodNamespace() string {
+	if ns := strings.TrimSpace(os.Getenv("POD_NAMESPACE")); ns != "" {
+		return ns
+	}
+	if data, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+		if ns := strings.TrimSpace(string(data)); ns != "" {
+			return ns
+		}
+	}
+	return "default"
+}
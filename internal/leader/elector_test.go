@@ -0,0 +1,101 @@
+package leader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/softcane/spot-vortex-agent/internal/metrics"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func newTestElector(t *testing.T, client *k8sfake.Clientset, identity string) *Elector {
+	t.Helper()
+	e, err := New(Config{
+		K8sClient:     client,
+		Namespace:     "spotvortex",
+		LeaseName:     "spotvortex-agent",
+		Identity:      identity,
+		LeaseDuration: 1 * time.Second,
+		RenewDeadline: 500 * time.Millisecond,
+		RetryPeriod:   100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return e
+}
+
+func TestElector_FailsOverToFollower(t *testing.T) {
+	client := k8sfake.NewSimpleClientset()
+	a := newTestElector(t, client, "agent-a")
+	b := newTestElector(t, client, "agent-b")
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	defer cancelA()
+	ctxB, cancelB := context.WithCancel(context.Background())
+	defer cancelB()
+
+	startedA := make(chan struct{}, 1)
+	go func() {
+		_ = a.Run(ctxA, Callbacks{OnStartedLeading: func(context.Context) { startedA <- struct{}{} }})
+	}()
+	select {
+	case <-startedA:
+	case <-time.After(10 * time.Second):
+		t.Fatal("agent-a never acquired leadership")
+	}
+	if testutil.ToFloat64(metrics.LeaderIsLeader) != 1 {
+		t.Fatal("leader gauge not set")
+	}
+
+	stoppedB := make(chan struct{}, 1)
+	go func() {
+		_ = b.Run(ctxB, Callbacks{OnStoppedLeading: func() { stoppedB <- struct{}{} }})
+	}()
+	waitFor(t, "agent-b to observe leader", func() bool { return b.Leader() == "agent-a" })
+	if b.IsLeader() {
+		t.Fatal("agent-b leads while agent-a holds the lease")
+	}
+
+	// agent-a shuts down and releases the lease; agent-b takes over.
+	cancelA()
+	waitFor(t, "agent-a to step down", func() bool { return !a.IsLeader() })
+	waitFor(t, "agent-b to take over", b.IsLeader)
+	if b.Leader() != "agent-b" {
+		t.Fatalf("leader=%q, want agent-b", b.Leader())
+	}
+}
+
+func TestNew_Validates(t *testing.T) {
+	client := k8sfake.NewSimpleClientset()
+	if _, err := New(Config{LeaseName: "l"}); err == nil {
+		t.Fatal("expected error without client")
+	}
+	if _, err := New(Config{K8sClient: client}); err == nil {
+		t.Fatal("expected error without lease name")
+	}
+	if _, err := New(Config{K8sClient: client, LeaseName: "l", LeaseDuration: time.Second, RenewDeadline: 2 * time.Second}); err == nil {
+		t.Fatal("expected error when renew deadline exceeds lease duration")
+	}
+}
+
+func TestPodNamespace_FromEnv(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "spotvortex-system")
+	if got := PodNamespace(); got != "spotvortex-system" {
+		t.Fatalf("PodNamespace()=%q", got)
+	}
+}
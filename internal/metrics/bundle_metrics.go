@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// UnknownBundleVersion labels decisions made before a bundle version is
+// known, or by an engine without a model manifest.
+const UnknownBundleVersion = "unknown"
+
+var (
+	// ActiveBundleInfo is 1 for the model bundle currently serving decisions.
+	ActiveBundleInfo = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "spotvortex",
+			Name:      "active_bundle_info",
+			Help:      "1 for the model bundle version currently serving decisions",
+		},
+		[]string{"bundle_version"},
+	)
+
+	// BundleRolloutEvents counts candidate bundle lifecycle transitions.
+	// event=staged|promoted|rejected|rolled_back|released
+	BundleRolloutEvents = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "spotvortex",
+			Name:      "bundle_rollout_events_total",
+			Help:      "Model bundle rollout transitions grouped by bundle version and event",
+		},
+		[]string{"bundle_version", "event"},
+	)
+
+	// BundleShadowDecisions counts staged bundle decisions compared with the
+	// active bundle. outcome=agree|disagree|error
+	BundleShadowDecisions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "spotvortex",
+			Name:      "bundle_shadow_decisions_total",
+			Help:      "Staged model bundle decisions compared with the active bundle, grouped by outcome",
+		},
+		[]string{"bundle_version", "outcome"},
+	)
+)
+
+var activeBundle = struct {
+	sync.RWMutex
+	version string
+}{version: UnknownBundleVersion}
+
+// SetActiveBundleVersion records the bundle version serving decisions. Every
+// decision metric carries it as the bundle_version label.
+func SetActiveBundleVersion(version string) {
+	if version == "" {
+		version = UnknownBundleVersion
+	}
+	activeBundle.Lock()
+	defer activeBundle.Unlock()
+	if activeBundle.version == version {
+		ActiveBundleInfo.WithLabelValues(version).Set(1)
+		return
+	}
+	ActiveBundleInfo.DeleteLabelValues(activeBundle.version)
+	ActiveBundleInfo.WithLabelValues(version).Set(1)
+	// Per-node recommendations are republished every tick; drop the old
+	// bundle's series rather than leave them frozen.
+	RecommendedAction.Reset()
+	activeBundle.version = version
+}
+
+// ActiveBundleVersion returns the bundle_version label for decision metrics.
+func ActiveBundleVersion() string {
+	activeBundle.RLock()
+	defer activeBundle.RUnlock()
+	return activeBundle.version
+}
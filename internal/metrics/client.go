@@ -178,6 +178,14 @@ func (c *Client) mergeMetrics(cpu, mem map[string]float64) []NodeMetrics {
 	return result
 }
 
+// Ping runs a trivial query to confirm Prometheus is reachable.
+func (c *Client) Ping(ctx context.Context) error {
+	if _, _, err := c.api.Query(ctx, "vector(1)", time.Now()); err != nil {
+		return fmt.Errorf("prometheus unreachable: %w", err)
+	}
+	return nil
+}
+
 // GetClusterUtilization returns the average cluster-wide CPU utilization (0.0 to 1.0).
 // This is used by the RL model to make migration decisions:
 // - Low utilization (<40%): More aggressive spot migration, plenty of headroom
@@ -199,3 +199,15 @@ func TestGetPoolUtilization(t *testing.T) {
 		t.Errorf("expected 0.75, got %f", val)
 	}
 }
+
+func TestPing(t *testing.T) {
+	client, _ := NewClient(ClientConfig{API: &MockAPI{QueryResult: &model.Scalar{Value: 1}}})
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	client, _ = NewClient(ClientConfig{API: &MockAPI{QueryErr: fmt.Errorf("connection refused")}})
+	if err := client.Ping(context.Background()); err == nil {
+		t.Fatal("Ping() expected error for unreachable Prometheus")
+	}
+}
@@ -20,14 +20,14 @@ var (
 	)
 
 	// ActionTaken counts RL actions executed.
-	// Per phase.md: spotvortex_action_taken{action}
+	// Per phase.md: spotvortex_action_taken{action}, plus the serving bundle_version.
 	ActionTaken = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "spotvortex",
 			Name:      "action_taken_total",
 			Help:      "Total number of RL actions executed",
 		},
-		[]string{"action"},
+		[]string{"action", "bundle_version"},
 	)
 
 	// SpotPriceUSD tracks current spot price per instance type and zone.
@@ -89,7 +89,7 @@ var (
 			Help:      "Latency of ONNX model inference",
 			Buckets:   prometheus.DefBuckets,
 		},
-		[]string{"model"}, // "tft", "rl", or "pipeline"
+		[]string{"model"}, // "tft", "rl", "pipeline", or "batch" (one pool-level tick)
 	)
 
 	// ReconcileLoopDuration tracks the 5-minute event loop cycle time.
@@ -161,7 +161,7 @@ var (
 			Name:      "recommended_action",
 			Help:      "Recommended action for node (0=hold, 1-2=decrease, 3-4=increase, 5=emergency)",
 		},
-		[]string{"node", "pool"},
+		[]string{"node", "pool", "bundle_version"},
 	)
 
 	// NodesOptimizable tracks the number of nodes that could benefit from migration.
@@ -223,6 +223,123 @@ var (
 		[]string{"pool"},
 	)
 
+	// PoolMinSpotRatio tracks the effective min spot ratio per pool after
+	// pool overrides are applied.
+	PoolMinSpotRatio = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "spotvortex",
+			Name:      "pool_min_spot_ratio",
+			Help:      "Effective minimum spot ratio for pool after runtime pool overrides",
+		},
+		[]string{"pool"},
+	)
+
+	// PoolMaxSpotRatio tracks the effective max spot ratio per pool after
+	// pool overrides are applied.
+	PoolMaxSpotRatio = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "spotvortex",
+			Name:      "pool_max_spot_ratio",
+			Help:      "Effective maximum spot ratio for pool after runtime pool overrides",
+		},
+		[]string{"pool"},
+	)
+
+	// PoolTargetSpotRatio tracks the effective preferred spot ratio per pool
+	// after pool overrides are applied.
+	PoolTargetSpotRatio = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "spotvortex",
+			Name:      "pool_target_spot_ratio",
+			Help:      "Effective preferred spot ratio for pool after runtime pool overrides",
+		},
+		[]string{"pool"},
+	)
+
+	// PoolSpareODHeadroomNodes tracks how many of a pool's densest spot
+	// nodes could be lost with their pods fitting on On-Demand capacity.
+	PoolSpareODHeadroomNodes = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "spotvortex",
+			Name:      "pool_spare_od_headroom_nodes",
+			Help:      "Densest spot nodes (at most 2) the pool could lose with every pod fitting on existing On-Demand capacity",
+		},
+		[]string{"pool"},
+	)
+
+	// PoolSpotLossPendingPods tracks how many pods would go Pending if the
+	// pool's densest nodes_lost spot nodes vanished now.
+	PoolSpotLossPendingPods = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "spotvortex",
+			Name:      "pool_spot_loss_pending_pods",
+			Help:      "Pods that would not fit on On-Demand capacity if the pool's densest spot nodes were lost",
+		},
+		[]string{"pool", "nodes_lost"},
+	)
+
+	// PoolRecoveryBudgetMaxSpotRatio tracks the spot ratio cap from the
+	// pool's most constrained declared recovery budget.
+	PoolRecoveryBudgetMaxSpotRatio = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "spotvortex",
+			Name:      "pool_recovery_budget_max_spot_ratio",
+			Help:      "Spot ratio cap from the most constrained recovery budget declared by workloads in the pool",
+		},
+		[]string{"pool"},
+	)
+
+	// PoolRecoveryBudgetsAtRisk tracks declared recovery budgets that losing
+	// the pool's spot nodes would breach at the current placement.
+	PoolRecoveryBudgetsAtRisk = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "spotvortex",
+			Name:      "pool_recovery_budgets_at_risk",
+			Help:      "Declared recovery budgets a loss of the pool's spot nodes would breach",
+		},
+		[]string{"pool"},
+	)
+
+	// LeaderIsLeader is 1 while this replica holds the leader Lease.
+	LeaderIsLeader = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "spotvortex",
+			Name:      "leader_is_leader",
+			Help:      "1 if this replica is the elected leader, 0 if it is a follower",
+		},
+	)
+
+	// LeaderTransitions counts leadership gained or lost by this replica.
+	LeaderTransitions = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "spotvortex",
+			Name:      "leader_transitions_total",
+			Help:      "Leadership transitions (acquired or lost) observed by this replica",
+		},
+	)
+
+	// InformerSyncLag is how stale each shared-informer cache may be: seconds
+	// since it last delivered an event or resync, or since start while the
+	// initial List has not completed.
+	InformerSyncLag = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "spotvortex",
+			Name:      "informer_sync_lag_seconds",
+			Help:      "Seconds since the informer cache last observed an event or resync (time since start before initial sync)",
+		},
+		[]string{"resource"},
+	)
+
+	// InformerSynced is 1 once an informer cache has completed its initial List.
+	InformerSynced = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "spotvortex",
+			Name:      "informer_synced",
+			Help:      "1 if the informer cache has completed its initial sync, 0 otherwise",
+		},
+		[]string{"resource"},
+	)
+
 	// DecisionSource counts action recommendations by source policy.
 	// source=rl|deterministic
 	DecisionSource = promauto.NewCounterVec(
@@ -231,7 +348,50 @@ var (
 			Name:      "decision_source_total",
 			Help:      "Total action recommendations grouped by policy source and action",
 		},
-		[]string{"source", "action"},
+		[]string{"source", "action", "bundle_version"},
+	)
+
+	// InferenceFallbackActive is 1 while inference runs without ONNX Runtime.
+	// RL recommendations are unavailable and the TFT heads come from the
+	// distilled surrogate, or from the PySR estimator when none is declared.
+	InferenceFallbackActive = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "spotvortex",
+			Name:      "inference_fallback_active",
+			Help:      "1 if inference runs on the pure-Go fallback because ONNX Runtime is unavailable",
+		},
+	)
+
+	// InferenceFallbackPredictions counts risk predictions served without ONNX
+	// Runtime.
+	InferenceFallbackPredictions = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "spotvortex",
+			Name:      "inference_fallback_predictions_total",
+			Help:      "Total risk predictions served by the pure-Go fallback",
+		},
+	)
+
+	// KarpenterNodeClaimWaits counts waits for a replacement NodeClaim before
+	// draining Karpenter nodes, by outcome (ready, timeout).
+	KarpenterNodeClaimWaits = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "spotvortex",
+			Name:      "karpenter_nodeclaim_waits_total",
+			Help:      "Waits for an Initialized replacement NodeClaim before draining, by outcome",
+		},
+		[]string{"outcome"},
+	)
+
+	// KarpenterHandoffs counts nodes handed to Karpenter for replacement, by
+	// method (drift, delete) and outcome (started, completed, timeout).
+	KarpenterHandoffs = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "spotvortex",
+			Name:      "karpenter_handoffs_total",
+			Help:      "Nodes handed to Karpenter for replacement, by method and outcome",
+		},
+		[]string{"method", "outcome"},
 	)
 
 	// UnsupportedInstanceFamily counts forced on-demand fallbacks due to model scope mismatch.
@@ -251,7 +411,7 @@ var (
 			Name:      "deterministic_decision_reason_total",
 			Help:      "Deterministic policy decision counts grouped by reason",
 		},
-		[]string{"reason"},
+		[]string{"reason", "bundle_version"},
 	)
 
 	// WorkloadCap tracks the computed workload-based spot ratio cap per pool.
@@ -25,6 +25,17 @@ var (
 		},
 	)
 
+	// InterruptionHandled counts interruption events handled outside the
+	// reconcile tick, by kind and outcome (drained, blocked, skipped, failed).
+	InterruptionHandled = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "spotvortex",
+			Name:      "interruption_handled_total",
+			Help:      "Interruption events handled by the agent, by kind and outcome",
+		},
+		[]string{"kind", "outcome"},
+	)
+
 	NodeTerminationTotal = promauto.NewCounter(
 		prometheus.CounterOpts{
 			Namespace: "spotvortex",
@@ -74,6 +85,17 @@ var (
 			Buckets:   prometheus.DefBuckets,
 		},
 	)
+
+	// ReplacementReadySeconds observes how long evicted or terminated
+	// replicas took to be replaced by a Ready pod.
+	ReplacementReadySeconds = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "spotvortex",
+			Name:      "replacement_ready_seconds",
+			Help:      "Time from a workload replica's eviction or termination to its replacement becoming Ready",
+			Buckets:   []float64{5, 10, 20, 30, 60, 120, 300, 600, 1200, 1800},
+		},
+	)
 )
 
 // ReliabilityTelemetrySnapshot is a truthful container for real cluster/provider signals.
@@ -88,6 +110,10 @@ type ReliabilityTelemetrySnapshot struct {
 	PodRestarts                 uint64
 	PodPendingDurationsSeconds  []float64
 	RecoveryDurationsSeconds    []float64
+
+	// ReplacementReadyDurationsSeconds are eviction/termination to
+	// replacement-Ready times completed since the last snapshot.
+	ReplacementReadyDurationsSeconds []float64
 }
 
 // ReliabilityTelemetryCollector is the interface for real reliability signal collection.
@@ -132,4 +158,9 @@ func RecordReliabilityTelemetry(snapshot ReliabilityTelemetrySnapshot) {
 			RecoveryTimeSeconds.Observe(v)
 		}
 	}
+	for _, v := range snapshot.ReplacementReadyDurationsSeconds {
+		if v >= 0 {
+			ReplacementReadySeconds.Observe(v)
+		}
+	}
 }
@@ -57,6 +57,51 @@ var (
 		},
 		[]string{"guardrail"},
 	)
+
+	// ShadowPolicyAgreement counts, per shadow evaluator, whether its action
+	// matched the active action.
+	ShadowPolicyAgreement = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "spotvortex",
+			Name:      "shadow_policy_agreement_total",
+			Help:      "Total comparisons of each shadow evaluator's action with the active action, grouped by agreement outcome",
+		},
+		[]string{"shadow", "agreement"},
+	)
+
+	// ShadowAgreementRatio is each shadow's agreement with the active policy
+	// over the counterfactual report window.
+	ShadowAgreementRatio = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "spotvortex",
+			Name:      "shadow_agreement_ratio",
+			Help:      "Fraction of pool decisions where the shadow evaluator agreed with the active policy over the report window",
+		},
+		[]string{"shadow"},
+	)
+
+	// ShadowCounterfactualSavingsUSD is the projected Spot savings of following
+	// each shadow's virtual spot-ratio trajectory over the report window.
+	// trajectory=shadow|active; active is the baseline over the same decisions.
+	ShadowCounterfactualSavingsUSD = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "spotvortex",
+			Name:      "shadow_counterfactual_savings_usd",
+			Help:      "Projected Spot savings in USD over the report window for the shadow's virtual trajectory and the active baseline",
+		},
+		[]string{"shadow", "trajectory"},
+	)
+
+	// ShadowCounterfactualMigrations is the projected node migrations of each
+	// shadow's virtual trajectory and the active baseline over the report window.
+	ShadowCounterfactualMigrations = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "spotvortex",
+			Name:      "shadow_counterfactual_migrations",
+			Help:      "Projected node migrations over the report window for the shadow's virtual trajectory and the active baseline",
+		},
+		[]string{"shadow", "trajectory"},
+	)
 )
 
 func init() {
@@ -0,0 +1,449 @@
+package spotpolicy
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/config"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// SourceConfig configures a Source.
+type SourceConfig struct {
+	DynamicClient dynamic.Interface
+	// PolicyName selects the cluster-scoped SpotVortexPolicy to apply.
+	// Default: "default".
+	PolicyName string
+	// ResyncPeriod for the informers. Default: 10 minutes.
+	ResyncPeriod time.Duration
+	Logger       *slog.Logger
+}
+
+// Source watches SpotVortexPolicy and SpotVortexPoolPolicy resources and
+// serves the most recently applied runtime config. It also serves
+// RecoveryBudget resources by target workload.
+type Source struct {
+	cfg    SourceConfig
+	logger *slog.Logger
+	ctx    context.Context
+
+	mu      sync.RWMutex
+	global  *config.RuntimeConfig
+	version string
+	pools   map[string]poolOverride // keyed by namespace/name
+	budgets map[string]budgetEntry  // keyed by namespace/name
+}
+
+type budgetEntry struct {
+	target workloadKey
+	budget config.RecoveryBudget
+}
+
+type workloadKey struct {
+	namespace, kind, name string
+}
+
+type poolOverride struct {
+	pools     []string
+	overrides map[string]interface{}
+}
+
+// NewSource creates a policy source. Call Start before reading from it.
+func NewSource(cfg SourceConfig) (*Source, error) {
+	if cfg.DynamicClient == nil {
+		return nil, fmt.Errorf("dynamic client is required for SpotVortexPolicy watch")
+	}
+	if cfg.PolicyName == "" {
+		cfg.PolicyName = DefaultPolicyName
+	}
+	if cfg.ResyncPeriod <= 0 {
+		cfg.ResyncPeriod = 10 * time.Minute
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Source{
+		cfg:     cfg,
+		logger:  logger,
+		ctx:     context.Background(),
+		pools:   make(map[string]poolOverride),
+		budgets: make(map[string]budgetEntry),
+	}, nil
+}
+
+// Start probes for the CRDs and, when installed, runs informers until ctx is
+// cancelled. A missing CRD is not an error: the source stays empty and the
+// controller keeps reading the runtime config file.
+func (s *Source) Start(ctx context.Context) error {
+	s.ctx = ctx
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(s.cfg.DynamicClient, s.cfg.ResyncPeriod)
+
+	var synced []cache.InformerSynced
+	for _, w := range []struct {
+		gvr     schema.GroupVersionResource
+		handler cache.ResourceEventHandlerFuncs
+	}{
+		{PolicyGVR, cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { s.onPolicy(obj) },
+			UpdateFunc: func(_, obj interface{}) { s.onPolicy(obj) },
+			DeleteFunc: func(obj interface{}) { s.onPolicyDelete(obj) },
+		}},
+		{PoolPolicyGVR, cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { s.onPoolPolicy(obj) },
+			UpdateFunc: func(_, obj interface{}) { s.onPoolPolicy(obj) },
+			DeleteFunc: func(obj interface{}) { s.onPoolPolicyDelete(obj) },
+		}},
+		{RecoveryBudgetGVR, cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { s.onRecoveryBudget(obj) },
+			UpdateFunc: func(_, obj interface{}) { s.onRecoveryBudget(obj) },
+			DeleteFunc: func(obj interface{}) { s.onRecoveryBudgetDelete(obj) },
+		}},
+	} {
+		available, err := s.crdInstalled(ctx, w.gvr)
+		if err != nil {
+			return err
+		}
+		if !available {
+			s.logger.Info("SpotVortex policy CRD not installed; using runtime config file", "resource", w.gvr.Resource)
+			continue
+		}
+		informer := factory.ForResource(w.gvr).Informer()
+		if _, err := informer.AddEventHandler(w.handler); err != nil {
+			return fmt.Errorf("add %s event handler: %w", w.gvr.Resource, err)
+		}
+		synced = append(synced, informer.HasSynced)
+	}
+	if len(synced) == 0 {
+		return nil
+	}
+
+	factory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), synced...) {
+		return fmt.Errorf("timed out waiting for SpotVortexPolicy informers to sync")
+	}
+	return nil
+}
+
+func (s *Source) crdInstalled(ctx context.Context, gvr schema.GroupVersionResource) (bool, error) {
+	_, err := s.cfg.DynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{Limit: 1})
+	if err == nil {
+		return true, nil
+	}
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return false, fmt.Errorf("probe %s: %w", gvr.Resource, err)
+}
+
+// RuntimeConfig returns the applied cluster policy. ok is false when no
+// SpotVortexPolicy is applied and the caller should fall back to the file.
+// The returned config is shared and must not be mutated.
+func (s *Source) RuntimeConfig() (*config.RuntimeConfig, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.global, s.global != nil
+}
+
+// AppliedVersion returns "<name>@<generation>" of the applied cluster policy,
+// or "" when the file is in force.
+func (s *Source) AppliedVersion() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version
+}
+
+// PoolRuntimeConfig layers the first SpotVortexPoolPolicy (by namespace/name)
+// selecting workloadPool over base. It returns base unchanged when no pool
+// policy applies or the merged result is invalid.
+func (s *Source) PoolRuntimeConfig(base *config.RuntimeConfig, workloadPool string) *config.RuntimeConfig {
+	if base == nil || workloadPool == "" {
+		return base
+	}
+	override, ok := s.poolOverrideFor(workloadPool)
+	if !ok {
+		return base
+	}
+	merged, err := base.Overlay(override.overrides)
+	if err != nil {
+		s.logger.Warn("failed to apply pool policy; using cluster runtime config",
+			"workload_pool", workloadPool,
+			"error", err,
+		)
+		return base
+	}
+	return merged
+}
+
+// RecoveryBudget returns the budget declared for a workload. When several
+// RecoveryBudgets target the same workload the tightest one wins.
+func (s *Source) RecoveryBudget(namespace, kind, name string) (config.RecoveryBudget, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	target := workloadKey{namespace: namespace, kind: kind, name: name}
+	var (
+		found config.RecoveryBudget
+		ok    bool
+	)
+	for _, entry := range s.budgets {
+		if entry.target != target {
+			continue
+		}
+		if !ok || entry.budget.MaxUnavailableSeconds < found.MaxUnavailableSeconds ||
+			(entry.budget.MaxUnavailableSeconds == found.MaxUnavailableSeconds && entry.budget.MinAvailable > found.MinAvailable) {
+			found, ok = entry.budget, true
+		}
+	}
+	return found, ok
+}
+
+func (s *Source) poolOverrideFor(workloadPool string) (poolOverride, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0, len(s.pools))
+	for key := range s.pools {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		entry := s.pools[key]
+		for _, pool := range entry.pools {
+			if pool == workloadPool {
+				return entry, true
+			}
+		}
+	}
+	return poolOverride{}, false
+}
+
+func (s *Source) onPolicy(obj interface{}) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	if u.GetName() != s.cfg.PolicyName {
+		s.updateStatus(PolicyGVR, u, metav1.ConditionFalse, ReasonNotSelected,
+			fmt.Sprintf("agent applies SpotVortexPolicy %q", s.cfg.PolicyName))
+		return
+	}
+
+	var policy SpotVortexPolicy
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &policy); err != nil {
+		s.rejectPolicy(u, err)
+		return
+	}
+	cfg := policy.Spec
+	if err := config.NormalizeRuntimeConfig(&cfg); err != nil {
+		s.rejectPolicy(u, err)
+		return
+	}
+
+	version := fmt.Sprintf("%s@%d", u.GetName(), u.GetGeneration())
+	s.mu.Lock()
+	changed := s.version != version
+	s.global = &cfg
+	s.version = version
+	s.mu.Unlock()
+
+	if changed {
+		s.logger.Info("applied SpotVortexPolicy", "policy", u.GetName(), "generation", u.GetGeneration(), "policy_mode", cfg.PolicyMode)
+	}
+	s.updateStatus(PolicyGVR, u, metav1.ConditionTrue, ReasonApplied,
+		fmt.Sprintf("generation %d applied", u.GetGeneration()))
+}
+
+func (s *Source) rejectPolicy(u *unstructured.Unstructured, err error) {
+	s.logger.Warn("rejected SpotVortexPolicy; keeping previous runtime config",
+		"policy", u.GetName(),
+		"generation", u.GetGeneration(),
+		"error", err,
+	)
+	s.updateStatus(PolicyGVR, u, metav1.ConditionFalse, ReasonInvalid, err.Error())
+}
+
+func (s *Source) onPolicyDelete(obj interface{}) {
+	u := unstructuredFromDelete(obj)
+	if u == nil || u.GetName() != s.cfg.PolicyName {
+		return
+	}
+	s.mu.Lock()
+	s.global = nil
+	s.version = ""
+	s.mu.Unlock()
+	s.logger.Info("SpotVortexPolicy deleted; falling back to runtime config file", "policy", u.GetName())
+}
+
+func (s *Source) onPoolPolicy(obj interface{}) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	key := u.GetNamespace() + "/" + u.GetName()
+
+	var policy SpotVortexPoolPolicy
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &policy); err != nil {
+		s.rejectPoolPolicy(key, u, err)
+		return
+	}
+	if len(policy.Spec.Pools) == 0 {
+		s.rejectPoolPolicy(key, u, fmt.Errorf("spec.pools must select at least one workload pool"))
+		return
+	}
+	overrides, _, _ := unstructured.NestedMap(u.Object, "spec")
+	delete(overrides, "pools")
+	// Validate against defaults so a bad override is rejected up front rather
+	// than silently skipped every tick.
+	if _, err := config.DefaultRuntimeConfig().Overlay(overrides); err != nil {
+		s.rejectPoolPolicy(key, u, err)
+		return
+	}
+
+	s.mu.Lock()
+	s.pools[key] = poolOverride{pools: policy.Spec.Pools, overrides: overrides}
+	s.mu.Unlock()
+	s.updateStatus(PoolPolicyGVR, u, metav1.ConditionTrue, ReasonApplied,
+		fmt.Sprintf("generation %d applied to pools %v", u.GetGeneration(), policy.Spec.Pools))
+}
+
+func (s *Source) rejectPoolPolicy(key string, u *unstructured.Unstructured, err error) {
+	s.logger.Warn("rejected SpotVortexPoolPolicy", "policy", key, "error", err)
+	s.mu.Lock()
+	delete(s.pools, key)
+	s.mu.Unlock()
+	s.updateStatus(PoolPolicyGVR, u, metav1.ConditionFalse, ReasonInvalid, err.Error())
+}
+
+func (s *Source) onPoolPolicyDelete(obj interface{}) {
+	u := unstructuredFromDelete(obj)
+	if u == nil {
+		return
+	}
+	s.mu.Lock()
+	delete(s.pools, u.GetNamespace()+"/"+u.GetName())
+	s.mu.Unlock()
+}
+
+func (s *Source) onRecoveryBudget(obj interface{}) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	key := u.GetNamespace() + "/" + u.GetName()
+
+	var rb RecoveryBudget
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &rb); err != nil {
+		s.rejectRecoveryBudget(key, u, err)
+		return
+	}
+	target := rb.Spec.TargetRef
+	switch target.Kind {
+	case "Deployment", "StatefulSet", "Rollout":
+	default:
+		s.rejectRecoveryBudget(key, u, fmt.Errorf("spec.targetRef.kind %q must be Deployment, StatefulSet or Rollout", target.Kind))
+		return
+	}
+	if target.Name == "" {
+		s.rejectRecoveryBudget(key, u, fmt.Errorf("spec.targetRef.name is required"))
+		return
+	}
+	budget := rb.Spec.RecoveryBudget
+	if err := budget.Validate(); err != nil {
+		s.rejectRecoveryBudget(key, u, err)
+		return
+	}
+
+	s.mu.Lock()
+	s.budgets[key] = budgetEntry{
+		target: workloadKey{namespace: u.GetNamespace(), kind: target.Kind, name: target.Name},
+		budget: budget,
+	}
+	s.mu.Unlock()
+	s.updateStatus(RecoveryBudgetGVR, u, metav1.ConditionTrue, ReasonApplied,
+		fmt.Sprintf("generation %d applied to %s/%s", u.GetGeneration(), target.Kind, target.Name))
+}
+
+func (s *Source) rejectRecoveryBudget(key string, u *unstructured.Unstructured, err error) {
+	s.logger.Warn("rejected RecoveryBudget", "recovery_budget", key, "error", err)
+	s.mu.Lock()
+	delete(s.budgets, key)
+	s.mu.Unlock()
+	s.updateStatus(RecoveryBudgetGVR, u, metav1.ConditionFalse, ReasonInvalid, err.Error())
+}
+
+func (s *Source) onRecoveryBudgetDelete(obj interface{}) {
+	u := unstructuredFromDelete(obj)
+	if u == nil {
+		return
+	}
+	s.mu.Lock()
+	delete(s.budgets, u.GetNamespace()+"/"+u.GetName())
+	s.mu.Unlock()
+}
+
+// updateStatus writes the Applied condition when it differs from what the
+// resource already reports, so status writes do not loop through the informer.
+func (s *Source) updateStatus(gvr schema.GroupVersionResource, u *unstructured.Unstructured, status metav1.ConditionStatus, reason, message string) {
+	var current PolicyStatus
+	if raw, ok, _ := unstructured.NestedMap(u.Object, "status"); ok {
+		_ = runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &current)
+	}
+	existing := meta.FindStatusCondition(current.Conditions, ConditionApplied)
+	if existing != nil && existing.Status == status && existing.Reason == reason &&
+		existing.ObservedGeneration == u.GetGeneration() {
+		return
+	}
+
+	next := current
+	next.Conditions = append([]metav1.Condition(nil), current.Conditions...)
+	meta.SetStatusCondition(&next.Conditions, metav1.Condition{
+		Type:               ConditionApplied,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: u.GetGeneration(),
+	})
+	if status == metav1.ConditionTrue {
+		next.AppliedGeneration = u.GetGeneration()
+	}
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&next)
+	if err != nil {
+		s.logger.Warn("failed to encode policy status", "policy", u.GetName(), "error", err)
+		return
+	}
+
+	updated := u.DeepCopy()
+	if err := unstructured.SetNestedMap(updated.Object, raw, "status"); err != nil {
+		s.logger.Warn("failed to set policy status", "policy", u.GetName(), "error", err)
+		return
+	}
+	client := s.cfg.DynamicClient.Resource(gvr)
+	var updateErr error
+	if ns := u.GetNamespace(); ns != "" {
+		_, updateErr = client.Namespace(ns).UpdateStatus(s.ctx, updated, metav1.UpdateOptions{})
+	} else {
+		_, updateErr = client.UpdateStatus(s.ctx, updated, metav1.UpdateOptions{})
+	}
+	if updateErr != nil {
+		s.logger.Warn("failed to update policy status", "policy", u.GetName(), "error", updateErr)
+	}
+}
+
+func unstructuredFromDelete(obj interface{}) *unstructured.Unstructured {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	u, _ := obj.(*unstructured.Unstructured)
+	return u
+}
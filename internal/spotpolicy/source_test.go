@@ -0,0 +1,238 @@
+package spotpolicy
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/softcane/spot-vortex-agent/internal/config"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newFakeDynamic(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		PolicyGVR:         "SpotVortexPolicyList",
+		PoolPolicyGVR:     "SpotVortexPoolPolicyList",
+		RecoveryBudgetGVR: "RecoveryBudgetList",
+	}, objects...)
+}
+
+func policyObject(name string, generation int64, spec map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": Group + "/" + Version,
+		"kind":       "SpotVortexPolicy",
+		"metadata":   map[string]interface{}{"name": name},
+		"spec":       spec,
+	}}
+	u.SetGeneration(generation)
+	return u
+}
+
+func startSource(t *testing.T, client *fake.FakeDynamicClient) *Source {
+	t.Helper()
+	src, err := NewSource(SourceConfig{DynamicClient: client, Logger: slog.Default()})
+	if err != nil {
+		t.Fatalf("NewSource failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	if err := src.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	return src
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func TestSource_AppliesClusterPolicyAndReportsStatus(t *testing.T) {
+	client := newFakeDynamic(policyObject("default", 3, map[string]interface{}{
+		"policy_mode":    "deterministic",
+		"max_spot_ratio": 0.6,
+		"deterministic_policy": map[string]interface{}{
+			"high_risk_threshold": 0.7,
+		},
+	}))
+	src := startSource(t, client)
+
+	waitFor(t, "policy to apply", func() bool { _, ok := src.RuntimeConfig(); return ok })
+	cfg, _ := src.RuntimeConfig()
+	if cfg.MaxSpotRatio != 0.6 || cfg.DeterministicPolicy.HighRiskThreshold != 0.7 {
+		t.Fatalf("applied config max=%v high=%v, want 0.6/0.7", cfg.MaxSpotRatio, cfg.DeterministicPolicy.HighRiskThreshold)
+	}
+	if cfg.DeterministicPolicy.EmergencyRiskThreshold == 0 {
+		t.Fatal("expected runtime defaults to fill unset thresholds")
+	}
+	if got := src.AppliedVersion(); got != "default@3" {
+		t.Fatalf("applied version=%q, want default@3", got)
+	}
+
+	waitFor(t, "status update", func() bool {
+		u, err := client.Resource(PolicyGVR).Get(context.Background(), "default", metav1.GetOptions{})
+		if err != nil {
+			return false
+		}
+		var status PolicyStatus
+		raw, _, _ := unstructured.NestedMap(u.Object, "status")
+		_ = runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &status)
+		cond := meta.FindStatusCondition(status.Conditions, ConditionApplied)
+		return status.AppliedGeneration == 3 && cond != nil && cond.Status == metav1.ConditionTrue
+	})
+}
+
+func TestSource_RejectsInvalidPolicyAndKeepsPrevious(t *testing.T) {
+	client := newFakeDynamic(policyObject("default", 1, map[string]interface{}{
+		"policy_mode":    "deterministic",
+		"max_spot_ratio": 0.5,
+	}))
+	src := startSource(t, client)
+	waitFor(t, "initial policy", func() bool { _, ok := src.RuntimeConfig(); return ok })
+
+	bad := policyObject("default", 2, map[string]interface{}{
+		"policy_mode":       "rl",
+		"rl_shadow_enabled": true,
+	})
+	if _, err := client.Resource(PolicyGVR).Update(context.Background(), bad, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("update policy: %v", err)
+	}
+
+	waitFor(t, "invalid status", func() bool {
+		u, err := client.Resource(PolicyGVR).Get(context.Background(), "default", metav1.GetOptions{})
+		if err != nil {
+			return false
+		}
+		conds, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+		for _, c := range conds {
+			if m, ok := c.(map[string]interface{}); ok && m["reason"] == ReasonInvalid {
+				return true
+			}
+		}
+		return false
+	})
+	cfg, ok := src.RuntimeConfig()
+	if !ok || cfg.PolicyMode != config.PolicyModeDeterministic || cfg.MaxSpotRatio != 0.5 {
+		t.Fatalf("config after invalid update=%+v, want previous deterministic policy", cfg)
+	}
+}
+
+func TestSource_PoolPolicyOverridesOnlySetFields(t *testing.T) {
+	pool := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": Group + "/" + Version,
+		"kind":       "SpotVortexPoolPolicy",
+		"metadata":   map[string]interface{}{"name": "api", "namespace": "team-api"},
+		"spec": map[string]interface{}{
+			"pools":          []interface{}{"api"},
+			"max_spot_ratio": 0.4,
+			"deterministic_policy": map[string]interface{}{
+				"medium_risk_threshold": 0.3,
+			},
+		},
+	}}
+	client := newFakeDynamic(pool)
+	src := startSource(t, client)
+
+	base := config.DefaultRuntimeConfig()
+	base.DeterministicPolicy.HighRiskThreshold = 0.77
+	waitFor(t, "pool policy", func() bool { return src.PoolRuntimeConfig(base, "api") != base })
+
+	got := src.PoolRuntimeConfig(base, "api")
+	if got.MaxSpotRatio != 0.4 || got.DeterministicPolicy.MediumRiskThreshold != 0.3 {
+		t.Fatalf("pool config max=%v medium=%v, want 0.4/0.3", got.MaxSpotRatio, got.DeterministicPolicy.MediumRiskThreshold)
+	}
+	if got.DeterministicPolicy.HighRiskThreshold != 0.77 {
+		t.Fatalf("pool config high=%v, want cluster value 0.77 preserved", got.DeterministicPolicy.HighRiskThreshold)
+	}
+	if other := src.PoolRuntimeConfig(base, "batch"); other != base {
+		t.Fatal("unselected pool should use the cluster runtime config")
+	}
+}
+
+func TestSource_MissingCRDFallsBackToFile(t *testing.T) {
+	client := newFakeDynamic()
+	client.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		gvr := action.GetResource()
+		return true, nil, apierrors.NewNotFound(gvr.GroupResource(), "")
+	})
+	src := startSource(t, client)
+	if _, ok := src.RuntimeConfig(); ok {
+		t.Fatal("expected no policy when CRD is not installed")
+	}
+}
+
+func recoveryBudgetObject(name, kind, target string, spec map[string]interface{}) *unstructured.Unstructured {
+	spec["targetRef"] = map[string]interface{}{"kind": kind, "name": target}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": Group + "/" + Version,
+		"kind":       "RecoveryBudget",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "shop"},
+		"spec":       spec,
+	}}
+}
+
+func TestSource_ServesRecoveryBudgetsByTarget(t *testing.T) {
+	client := newFakeDynamic(
+		recoveryBudgetObject("checkout", "Deployment", "checkout", map[string]interface{}{
+			"maxUnavailableSeconds": int64(120),
+		}),
+		recoveryBudgetObject("checkout-strict", "Deployment", "checkout", map[string]interface{}{
+			"maxUnavailableSeconds": int64(30),
+			"minAvailable":          int64(2),
+		}),
+		recoveryBudgetObject("bad-kind", "DaemonSet", "agent", map[string]interface{}{
+			"maxUnavailableSeconds": int64(30),
+		}),
+	)
+	src := startSource(t, client)
+
+	waitFor(t, "recovery budgets", func() bool {
+		b, ok := src.RecoveryBudget("shop", "Deployment", "checkout")
+		return ok && b.MaxUnavailableSeconds == 30
+	})
+	got, _ := src.RecoveryBudget("shop", "Deployment", "checkout")
+	if got.MinAvailable != 2 {
+		t.Fatalf("minAvailable=%d, want 2 from the tightest budget", got.MinAvailable)
+	}
+	if _, ok := src.RecoveryBudget("other", "Deployment", "checkout"); ok {
+		t.Fatal("budget must not apply to a workload in another namespace")
+	}
+	if _, ok := src.RecoveryBudget("shop", "DaemonSet", "agent"); ok {
+		t.Fatal("budget targeting an unsupported kind must be rejected")
+	}
+
+	waitFor(t, "invalid status", func() bool {
+		u, err := client.Resource(RecoveryBudgetGVR).Namespace("shop").Get(context.Background(), "bad-kind", metav1.GetOptions{})
+		if err != nil {
+			return false
+		}
+		var status PolicyStatus
+		raw, _, _ := unstructured.NestedMap(u.Object, "status")
+		_ = runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &status)
+		cond := meta.FindStatusCondition(status.Conditions, ConditionApplied)
+		return cond != nil && cond.Reason == ReasonInvalid
+	})
+
+	if err := client.Resource(RecoveryBudgetGVR).Namespace("shop").Delete(context.Background(), "checkout-strict", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	waitFor(t, "budget after delete", func() bool {
+		b, ok := src.RecoveryBudget("shop", "Deployment", "checkout")
+		return ok && b.MaxUnavailableSeconds == 120 && b.MinAvailable == 1
+	})
+}
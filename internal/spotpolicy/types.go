@@ -0,0 +1,109 @@
+// Package spotpolicy serves the runtime config from SpotVortexPolicy custom
+// resources so tuning changes apply on the next tick without a ConfigMap sync.
+// When no policy resource (or no CRD) exists the controller keeps using
+// config/runtime.json. The package also serves RecoveryBudget resources,
+// which declare per-workload recovery objectives for the collector.
+package spotpolicy
+
+import (
+	"github.com/softcane/spot-vortex-agent/internal/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// Group is the API group for SpotVortex custom resources.
+	Group = "spotvortex.io"
+	// Version is the served API version.
+	Version = "v1alpha1"
+
+	// DefaultPolicyName is the cluster-scoped SpotVortexPolicy applied when
+	// no name is configured.
+	DefaultPolicyName = "default"
+
+	// ConditionApplied reports whether the controller applied the resource.
+	ConditionApplied = "Applied"
+
+	ReasonApplied     = "Applied"
+	ReasonInvalid     = "InvalidSpec"
+	ReasonNotSelected = "NotSelected"
+)
+
+// PolicyGVR is the cluster-scoped SpotVortexPolicy resource.
+var PolicyGVR = schema.GroupVersionResource{
+	Group:    Group,
+	Version:  Version,
+	Resource: "spotvortexpolicies",
+}
+
+// PoolPolicyGVR is the namespaced SpotVortexPoolPolicy resource.
+var PoolPolicyGVR = schema.GroupVersionResource{
+	Group:    Group,
+	Version:  Version,
+	Resource: "spotvortexpoolpolicies",
+}
+
+// RecoveryBudgetGVR is the namespaced RecoveryBudget resource.
+var RecoveryBudgetGVR = schema.GroupVersionResource{
+	Group:    Group,
+	Version:  Version,
+	Resource: "recoverybudgets",
+}
+
+// SpotVortexPolicy carries the cluster-wide runtime config. Its spec uses the
+// same field names as config/runtime.json so files can be pasted verbatim.
+type SpotVortexPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   config.RuntimeConfig `json:"spec"`
+	Status PolicyStatus         `json:"status,omitempty"`
+}
+
+// SpotVortexPoolPolicy overrides the cluster runtime config for the workload
+// pools it selects. Only fields set in the spec override the cluster values.
+type SpotVortexPoolPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PoolPolicySpec `json:"spec"`
+	Status PolicyStatus   `json:"status,omitempty"`
+}
+
+// PoolPolicySpec selects workload pools (spotvortex.io/pool label values) and
+// carries partial runtime config overrides for them.
+type PoolPolicySpec struct {
+	Pools []string `json:"pools"`
+
+	config.RuntimeConfig `json:",inline"`
+}
+
+// RecoveryBudget declares how long a Deployment, StatefulSet or Argo Rollout
+// in its namespace may run below its minimum available replicas after a
+// spot loss.
+type RecoveryBudget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RecoveryBudgetSpec `json:"spec"`
+	Status PolicyStatus       `json:"status,omitempty"`
+}
+
+// RecoveryBudgetSpec names the target workload and its budget.
+type RecoveryBudgetSpec struct {
+	TargetRef WorkloadReference `json:"targetRef"`
+
+	config.RecoveryBudget `json:",inline"`
+}
+
+// WorkloadReference names a workload in the RecoveryBudget's namespace.
+type WorkloadReference struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// PolicyStatus reports which generation the controller has applied.
+type PolicyStatus struct {
+	AppliedGeneration int64              `json:"appliedGeneration,omitempty"`
+	Conditions        []metav1.Condition `json:"conditions,omitempty"`
+}
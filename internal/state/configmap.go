@@ -0,0 +1,87 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// configMapKey is the ConfigMap data key holding the checkpoint.
+const configMapKey = "state.json"
+
+// ConfigMapStore keeps the checkpoint in a ConfigMap data key. It is the
+// default backend and is shared by all replicas in the namespace.
+type ConfigMapStore struct {
+	k8s       kubernetes.Interface
+	namespace string
+	name      string
+}
+
+// NewConfigMapStore creates a store backed by the named ConfigMap.
+func NewConfigMapStore(k8s kubernetes.Interface, namespace, name string) (*ConfigMapStore, error) {
+	if k8s == nil {
+		return nil, fmt.Errorf("k8s client is required")
+	}
+	if namespace == "" || name == "" {
+		return nil, fmt.Errorf("state configmap namespace and name are required")
+	}
+	return &ConfigMapStore{k8s: k8s, namespace: namespace, name: name}, nil
+}
+
+// Name implements Store.
+func (s *ConfigMapStore) Name() string { return "configmap" }
+
+// Save implements Store, creating the ConfigMap on first use.
+func (s *ConfigMapStore) Save(ctx context.Context, data []byte) error {
+	cms := s.k8s.CoreV1().ConfigMaps(s.namespace)
+	cm, err := cms.Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = cms.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.name,
+				Namespace: s.namespace,
+				Labels:    map[string]string{managedByLabel: managedByValue},
+			},
+			Data: map[string]string{configMapKey: string(data)},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to create state configmap %s/%s: %w", s.namespace, s.name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get state configmap %s/%s: %w", s.namespace, s.name, err)
+	}
+
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	if cm.Data[configMapKey] == string(data) {
+		return nil
+	}
+	cm.Data[configMapKey] = string(data)
+	if _, err := cms.Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update state configmap %s/%s: %w", s.namespace, s.name, err)
+	}
+	return nil
+}
+
+// Load implements Store.
+func (s *ConfigMapStore) Load(ctx context.Context) ([]byte, error) {
+	cm, err := s.k8s.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state configmap %s/%s: %w", s.namespace, s.name, err)
+	}
+	data, ok := cm.Data[configMapKey]
+	if !ok {
+		return nil, nil
+	}
+	return []byte(data), nil
+}
@@ -0,0 +1,88 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// leaseAnnotation holds the checkpoint on the state Lease.
+const leaseAnnotation = "spotvortex.io/state"
+
+// LeaseStore keeps the checkpoint in an annotation on a dedicated Lease, for
+// clusters where the agent may not write ConfigMaps. Use a different Lease
+// than the leader election one: the elector rewrites its Lease on renew.
+type LeaseStore struct {
+	k8s       kubernetes.Interface
+	namespace string
+	name      string
+}
+
+// NewLeaseStore creates a store backed by the named Lease.
+func NewLeaseStore(k8s kubernetes.Interface, namespace, name string) (*LeaseStore, error) {
+	if k8s == nil {
+		return nil, fmt.Errorf("k8s client is required")
+	}
+	if namespace == "" || name == "" {
+		return nil, fmt.Errorf("state lease namespace and name are required")
+	}
+	return &LeaseStore{k8s: k8s, namespace: namespace, name: name}, nil
+}
+
+// Name implements Store.
+func (s *LeaseStore) Name() string { return "lease" }
+
+// Save implements Store, creating the Lease on first use.
+func (s *LeaseStore) Save(ctx context.Context, data []byte) error {
+	leases := s.k8s.CoordinationV1().Leases(s.namespace)
+	lease, err := leases.Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = leases.Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        s.name,
+				Namespace:   s.namespace,
+				Labels:      map[string]string{managedByLabel: managedByValue},
+				Annotations: map[string]string{leaseAnnotation: string(data)},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to create state lease %s/%s: %w", s.namespace, s.name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get state lease %s/%s: %w", s.namespace, s.name, err)
+	}
+
+	if lease.Annotations == nil {
+		lease.Annotations = make(map[string]string)
+	}
+	if lease.Annotations[leaseAnnotation] == string(data) {
+		return nil
+	}
+	lease.Annotations[leaseAnnotation] = string(data)
+	if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update state lease %s/%s: %w", s.namespace, s.name, err)
+	}
+	return nil
+}
+
+// Load implements Store.
+func (s *LeaseStore) Load(ctx context.Context) ([]byte, error) {
+	lease, err := s.k8s.CoordinationV1().Leases(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state lease %s/%s: %w", s.namespace, s.name, err)
+	}
+	data, ok := lease.Annotations[leaseAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	return []byte(data), nil
+}
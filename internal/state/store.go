@@ -0,0 +1,74 @@
+// Package state checkpoints controller state (target ratios, cooldown
+// timestamps, price history) so it survives restarts and leader failover.
+//
+// Stores move opaque bytes; the controller owns the schema and its version.
+package state
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByValue = "spotvortex"
+)
+
+// Store persists a single state checkpoint.
+type Store interface {
+	// Name identifies the backend in logs ("configmap", "lease", "file").
+	Name() string
+	// Save replaces the checkpoint.
+	Save(ctx context.Context, data []byte) error
+	// Load returns the checkpoint, or nil if none has been saved.
+	Load(ctx context.Context) ([]byte, error)
+}
+
+// Backend names accepted by New.
+const (
+	BackendConfigMap = "configmap"
+	BackendLease     = "lease"
+	BackendFile      = "file"
+	BackendNone      = "none"
+)
+
+// Config selects and configures a backend.
+type Config struct {
+	// Backend is one of BackendConfigMap, BackendLease, BackendFile or
+	// BackendNone.
+	Backend string
+
+	// K8sClient, Namespace and Name locate the ConfigMap or Lease.
+	K8sClient kubernetes.Interface
+	Namespace string
+	Name      string
+
+	// Path is the file for BackendFile.
+	Path string
+}
+
+// New builds the configured store. BackendNone returns a nil Store.
+func New(cfg Config) (Store, error) {
+	var (
+		store Store
+		err   error
+	)
+	switch cfg.Backend {
+	case BackendConfigMap, "":
+		store, err = NewConfigMapStore(cfg.K8sClient, cfg.Namespace, cfg.Name)
+	case BackendLease:
+		store, err = NewLeaseStore(cfg.K8sClient, cfg.Namespace, cfg.Name)
+	case BackendFile:
+		store, err = NewFileStore(cfg.Path)
+	case BackendNone:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown state backend %q", cfg.Backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
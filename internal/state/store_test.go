@@ -0,0 +1,91 @@
+package state
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func assertRoundTrip(t *testing.T, store Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	data, err := store.Load(ctx)
+	if err != nil || data != nil {
+		t.Fatalf("%s: Load() before save = %q, %v; want nil, nil", store.Name(), data, err)
+	}
+	if err := store.Save(ctx, []byte(`{"version":1}`)); err != nil {
+		t.Fatalf("%s: first Save() error = %v", store.Name(), err)
+	}
+	if err := store.Save(ctx, []byte(`{"version":2}`)); err != nil {
+		t.Fatalf("%s: second Save() error = %v", store.Name(), err)
+	}
+	data, err = store.Load(ctx)
+	if err != nil || string(data) != `{"version":2}` {
+		t.Fatalf("%s: Load() = %q, %v", store.Name(), data, err)
+	}
+}
+
+func TestConfigMapStore_RoundTrip(t *testing.T) {
+	client := k8sfake.NewSimpleClientset()
+	store, err := New(Config{K8sClient: client, Namespace: "spotvortex", Name: "agent-state"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if store.Name() != "configmap" {
+		t.Fatalf("default backend=%q, want configmap", store.Name())
+	}
+	assertRoundTrip(t, store)
+
+	cm, err := client.CoreV1().ConfigMaps("spotvortex").Get(context.Background(), "agent-state", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("configmap not created: %v", err)
+	}
+	if cm.Labels[managedByLabel] != managedByValue {
+		t.Fatalf("labels=%v", cm.Labels)
+	}
+}
+
+func TestLeaseStore_RoundTrip(t *testing.T) {
+	client := k8sfake.NewSimpleClientset()
+	store, err := New(Config{Backend: BackendLease, K8sClient: client, Namespace: "spotvortex", Name: "agent-state"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	assertRoundTrip(t, store)
+
+	lease, err := client.CoordinationV1().Leases("spotvortex").Get(context.Background(), "agent-state", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("lease not created: %v", err)
+	}
+	if lease.Annotations[leaseAnnotation] != `{"version":2}` {
+		t.Fatalf("annotations=%v", lease.Annotations)
+	}
+}
+
+func TestFileStore_RoundTrip(t *testing.T) {
+	store, err := New(Config{Backend: BackendFile, Path: filepath.Join(t.TempDir(), "nested", "state.json")})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	assertRoundTrip(t, store)
+}
+
+func TestNew_Backends(t *testing.T) {
+	store, err := New(Config{Backend: BackendNone})
+	if err != nil || store != nil {
+		t.Fatalf("none backend = %v, %v; want nil store", store, err)
+	}
+	if _, err := New(Config{Backend: "etcd"}); err == nil {
+		t.Fatal("expected error for unknown backend")
+	}
+	if _, err := New(Config{Backend: BackendConfigMap, K8sClient: k8sfake.NewSimpleClientset()}); err == nil {
+		t.Fatal("expected error without configmap name")
+	}
+	if _, err := New(Config{Backend: BackendFile}); err == nil {
+		t.Fatal("expected error without file path")
+	}
+}